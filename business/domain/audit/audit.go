@@ -0,0 +1,36 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+	"gorm.io/gorm"
+)
+
+//go:generate mockgen -source=business/domain/audit/audit.go -destination=mocks/domain/audit/mock_audit.go -package=mocks
+type DomainItf interface {
+	InsertAuditLog(ctx context.Context, data entity.AuditLog) (entity.AuditLog, error)
+	GetAuditLogs(ctx context.Context, data entity.GetAuditLogs) ([]entity.AuditLog, error)
+}
+
+type audit struct {
+	db *gorm.DB
+}
+
+type Option struct {
+	DB *gorm.DB
+
+	// Memory switches the domain to the in-memory backend.
+	Memory *memstore.Store
+}
+
+func InitAuditDomain(opt Option) DomainItf {
+	if opt.Memory != nil {
+		return initAuditMemory(opt)
+	}
+
+	return &audit{
+		db: opt.DB,
+	}
+}
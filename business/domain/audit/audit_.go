@@ -0,0 +1,61 @@
+package audit
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+func (a *audit) InsertAuditLog(ctx context.Context, data entity.AuditLog) (entity.AuditLog, error) {
+	db := pkg.GetTransactionFromCtx(ctx, a.db)
+
+	data.ID = 0
+	data.CreatedAt = time.Now()
+
+	if err := db.WithContext(ctx).Create(&data).Error; err != nil {
+		return data, x.WrapWithCode(err, http.StatusInternalServerError, "failed to insert audit log")
+	}
+
+	return data, nil
+}
+
+func (a *audit) GetAuditLogs(ctx context.Context, data entity.GetAuditLogs) ([]entity.AuditLog, error) {
+	var (
+		result []entity.AuditLog
+		db     = pkg.GetTransactionFromCtx(ctx, a.db)
+	)
+
+	db = db.WithContext(ctx).Model(&entity.AuditLog{})
+
+	if data.LotID > 0 {
+		db = db.Where("lot_id = ?", data.LotID)
+	}
+
+	if data.Action != "" {
+		db = db.Where("action = ?", data.Action)
+	}
+
+	if data.Resource != "" {
+		db = db.Where("resource = ?", data.Resource)
+	}
+
+	if data.ResourceID != "" {
+		db = db.Where("resource_id = ?", data.ResourceID)
+	}
+
+	if data.Limit > 0 {
+		db = db.Limit(data.Limit)
+	}
+
+	// newest first
+	if err := db.Order("id DESC").Find(&result).Error; err != nil {
+		return result, x.WrapWithCode(err, http.StatusInternalServerError, "failed get audit logs")
+	}
+
+	return result, nil
+}
@@ -0,0 +1,82 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+)
+
+type auditMemory struct {
+	store  *memstore.Store
+	tables *auditTables
+}
+
+type auditTables struct {
+	logs   []entity.AuditLog
+	nextID uint
+}
+
+func (t *auditTables) Snapshot() func() {
+	logs := append([]entity.AuditLog(nil), t.logs...)
+	nextID := t.nextID
+
+	return func() {
+		t.logs = logs
+		t.nextID = nextID
+	}
+}
+
+func initAuditMemory(opt Option) DomainItf {
+	tables := &auditTables{}
+	opt.Memory.Register(tables)
+
+	return &auditMemory{
+		store:  opt.Memory,
+		tables: tables,
+	}
+}
+
+func (a *auditMemory) InsertAuditLog(ctx context.Context, data entity.AuditLog) (entity.AuditLog, error) {
+	err := a.store.Do(ctx, func() error {
+		a.tables.nextID++
+		data.ID = a.tables.nextID
+		data.CreatedAt = time.Now()
+		a.tables.logs = append(a.tables.logs, data)
+		return nil
+	})
+
+	return data, err
+}
+
+func (a *auditMemory) GetAuditLogs(ctx context.Context, data entity.GetAuditLogs) ([]entity.AuditLog, error) {
+	result := []entity.AuditLog{}
+
+	_ = a.store.Do(ctx, func() error {
+		// newest first, like ORDER BY id DESC
+		for i := len(a.tables.logs) - 1; i >= 0; i-- {
+			v := a.tables.logs[i]
+			if data.LotID > 0 && v.LotID != data.LotID {
+				continue
+			}
+			if data.Action != "" && v.Action != data.Action {
+				continue
+			}
+			if data.Resource != "" && v.Resource != data.Resource {
+				continue
+			}
+			if data.ResourceID != "" && v.ResourceID != data.ResourceID {
+				continue
+			}
+
+			result = append(result, v)
+			if data.Limit > 0 && len(result) == data.Limit {
+				break
+			}
+		}
+		return nil
+	})
+
+	return result, nil
+}
@@ -0,0 +1,52 @@
+package audit_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zuhrulumam/go-parking-lot/business/domain/audit"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+)
+
+func TestGetAuditLogs(t *testing.T) {
+	db, mock, cleanup := pkg.SetupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "audit_logs" WHERE lot_id = $1 AND resource = $2 AND resource_id = $3 ORDER BY id DESC LIMIT $4`)).
+		WithArgs(1, "spot", "1-1-1-1", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "action"}).AddRow(2, entity.AuditSpotUpdated))
+
+	d := audit.InitAuditDomain(audit.Option{DB: db})
+	logs, err := d.GetAuditLogs(context.Background(), entity.GetAuditLogs{LotID: 1, Resource: "spot", ResourceID: "1-1-1-1", Limit: 10})
+
+	assert.NoError(t, err)
+	assert.Len(t, logs, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMemoryAuditLogs(t *testing.T) {
+	ctx := context.Background()
+	d := audit.InitAuditDomain(audit.Option{Memory: memstore.New()})
+
+	for _, action := range []string{entity.AuditFloorAdded, entity.AuditSpotUpdated, entity.AuditSpotUpdated} {
+		_, err := d.InsertAuditLog(ctx, entity.AuditLog{LotID: 1, Actor: "ops", Action: action})
+		assert.NoError(t, err)
+	}
+
+	_, err := d.InsertAuditLog(ctx, entity.AuditLog{LotID: 2, Actor: "ops", Action: entity.AuditSpotUpdated})
+	assert.NoError(t, err)
+
+	logs, err := d.GetAuditLogs(ctx, entity.GetAuditLogs{LotID: 1, Action: entity.AuditSpotUpdated, Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, uint(3), logs[0].ID, "newest first")
+
+	logs, err = d.GetAuditLogs(ctx, entity.GetAuditLogs{LotID: 2})
+	assert.NoError(t, err)
+	assert.Len(t, logs, 1)
+}
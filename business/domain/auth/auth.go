@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+	"gorm.io/gorm"
+)
+
+//go:generate mockgen -source=business/domain/auth/auth.go -destination=mocks/domain/auth/mock_auth.go -package=mocks
+type DomainItf interface {
+	InsertAPIKey(ctx context.Context, data entity.APIKey) (entity.APIKey, error)
+	GetAPIKeys(ctx context.Context, data entity.GetAPIKeys) ([]entity.APIKey, error)
+	UpdateAPIKey(ctx context.Context, data entity.UpdateAPIKey) error
+
+	InsertUser(ctx context.Context, data entity.User) (entity.User, error)
+	GetUsers(ctx context.Context, data entity.GetUsers) ([]entity.User, error)
+	UpdateUser(ctx context.Context, data entity.UpdateUser) error
+}
+
+type auth struct {
+	db *gorm.DB
+}
+
+type Option struct {
+	DB *gorm.DB
+
+	// Memory switches the domain to the in-memory backend.
+	Memory *memstore.Store
+}
+
+func InitAuthDomain(opt Option) DomainItf {
+	if opt.Memory != nil {
+		return initAuthMemory(opt)
+	}
+
+	return &auth{
+		db: opt.DB,
+	}
+}
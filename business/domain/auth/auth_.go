@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+func (a *auth) InsertAPIKey(ctx context.Context, data entity.APIKey) (entity.APIKey, error) {
+	db := pkg.GetTransactionFromCtx(ctx, a.db)
+
+	data.ID = 0
+	data.CreatedAt = time.Now()
+
+	if err := db.WithContext(ctx).Create(&data).Error; err != nil {
+		if pkg.IsUniqueViolation(err, "unique_api_key_hash") {
+			return data, x.WrapWithCode(err, http.StatusConflict, "api key already exists")
+		}
+		return data, x.WrapWithCode(err, http.StatusInternalServerError, "failed to insert api key")
+	}
+
+	return data, nil
+}
+
+func (a *auth) GetAPIKeys(ctx context.Context, data entity.GetAPIKeys) ([]entity.APIKey, error) {
+	var (
+		result []entity.APIKey
+		db     = pkg.GetTransactionFromCtx(ctx, a.db)
+	)
+
+	db = db.WithContext(ctx).Model(&entity.APIKey{})
+
+	if data.ID > 0 {
+		db = db.Where("id = ?", data.ID)
+	}
+
+	if data.Hash != "" {
+		db = db.Where("hash = ?", data.Hash)
+	}
+
+	if data.Revoked != nil {
+		if *data.Revoked {
+			db = db.Where("revoked_at IS NOT NULL")
+		} else {
+			db = db.Where("revoked_at IS NULL")
+		}
+	}
+
+	if err := db.Order("id").Find(&result).Error; err != nil {
+		return result, x.WrapWithCode(err, http.StatusInternalServerError, "failed get api keys")
+	}
+
+	return result, nil
+}
+
+func (a *auth) UpdateAPIKey(ctx context.Context, data entity.UpdateAPIKey) error {
+	db := pkg.GetTransactionFromCtx(ctx, a.db)
+
+	if data.ID < 1 {
+		return x.NewWithCode(http.StatusBadRequest, "api key id is required")
+	}
+
+	updates := map[string]interface{}{}
+	if data.LastUsedAt != nil {
+		updates["last_used_at"] = *data.LastUsedAt
+	}
+	if data.RevokedAt != nil {
+		updates["revoked_at"] = *data.RevokedAt
+	}
+
+	if len(updates) == 0 {
+		return x.NewWithCode(http.StatusBadRequest, "no updates provided")
+	}
+
+	res := db.WithContext(ctx).Model(&entity.APIKey{}).Where("id = ?", data.ID).Updates(updates)
+	if res.Error != nil {
+		return x.WrapWithCode(res.Error, http.StatusInternalServerError, "failed to update api key")
+	}
+
+	if res.RowsAffected == 0 {
+		return x.NewWithCode(http.StatusNotFound, "api key not found")
+	}
+
+	return nil
+}
+
+func (a *auth) InsertUser(ctx context.Context, data entity.User) (entity.User, error) {
+	db := pkg.GetTransactionFromCtx(ctx, a.db)
+
+	data.ID = 0
+	data.CreatedAt = time.Now()
+	data.UpdatedAt = data.CreatedAt
+
+	if err := db.WithContext(ctx).Create(&data).Error; err != nil {
+		if pkg.IsUniqueViolation(err, "unique_username") {
+			return data, x.WrapWithCode(err, http.StatusConflict, "username is taken")
+		}
+		return data, x.WrapWithCode(err, http.StatusInternalServerError, "failed to insert user")
+	}
+
+	return data, nil
+}
+
+func (a *auth) GetUsers(ctx context.Context, data entity.GetUsers) ([]entity.User, error) {
+	var (
+		result []entity.User
+		db     = pkg.GetTransactionFromCtx(ctx, a.db)
+	)
+
+	db = db.WithContext(ctx).Model(&entity.User{})
+
+	if data.ID > 0 {
+		db = db.Where("id = ?", data.ID)
+	}
+
+	if data.Username != "" {
+		db = db.Where("username = ?", data.Username)
+	}
+
+	if err := db.Order("id").Find(&result).Error; err != nil {
+		return result, x.WrapWithCode(err, http.StatusInternalServerError, "failed get users")
+	}
+
+	return result, nil
+}
+
+func (a *auth) UpdateUser(ctx context.Context, data entity.UpdateUser) error {
+	db := pkg.GetTransactionFromCtx(ctx, a.db)
+
+	if data.ID < 1 {
+		return x.NewWithCode(http.StatusBadRequest, "user id is required")
+	}
+
+	updates := map[string]interface{}{}
+	if data.PasswordHash != nil {
+		updates["password_hash"] = *data.PasswordHash
+	}
+	if data.Role != nil {
+		updates["role"] = *data.Role
+	}
+	if data.Active != nil {
+		updates["active"] = *data.Active
+	}
+
+	if len(updates) == 0 {
+		return x.NewWithCode(http.StatusBadRequest, "no updates provided")
+	}
+	updates["updated_at"] = time.Now()
+
+	res := db.WithContext(ctx).Model(&entity.User{}).Where("id = ?", data.ID).Updates(updates)
+	if res.Error != nil {
+		return x.WrapWithCode(res.Error, http.StatusInternalServerError, "failed to update user")
+	}
+
+	if res.RowsAffected == 0 {
+		return x.NewWithCode(http.StatusNotFound, "user not found")
+	}
+
+	return nil
+}
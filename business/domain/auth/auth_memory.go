@@ -0,0 +1,192 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+type authMemory struct {
+	store  *memstore.Store
+	tables *authTables
+}
+
+type authTables struct {
+	keys       []entity.APIKey
+	users      []entity.User
+	nextKeyID  uint
+	nextUserID uint
+}
+
+func (t *authTables) Snapshot() func() {
+	keys := append([]entity.APIKey(nil), t.keys...)
+	users := append([]entity.User(nil), t.users...)
+	nextKeyID, nextUserID := t.nextKeyID, t.nextUserID
+
+	return func() {
+		t.keys = keys
+		t.users = users
+		t.nextKeyID, t.nextUserID = nextKeyID, nextUserID
+	}
+}
+
+func initAuthMemory(opt Option) DomainItf {
+	tables := &authTables{}
+	opt.Memory.Register(tables)
+
+	return &authMemory{
+		store:  opt.Memory,
+		tables: tables,
+	}
+}
+
+func (a *authMemory) InsertAPIKey(ctx context.Context, data entity.APIKey) (entity.APIKey, error) {
+	err := a.store.Do(ctx, func() error {
+		for _, v := range a.tables.keys {
+			if v.Hash == data.Hash {
+				return x.NewWithCode(http.StatusConflict, "api key already exists")
+			}
+		}
+
+		a.tables.nextKeyID++
+		data.ID = a.tables.nextKeyID
+		data.CreatedAt = time.Now()
+		a.tables.keys = append(a.tables.keys, data)
+		return nil
+	})
+
+	return data, err
+}
+
+func (a *authMemory) GetAPIKeys(ctx context.Context, data entity.GetAPIKeys) ([]entity.APIKey, error) {
+	result := []entity.APIKey{}
+
+	_ = a.store.Do(ctx, func() error {
+		for _, v := range a.tables.keys {
+			if data.ID > 0 && v.ID != data.ID {
+				continue
+			}
+			if data.Hash != "" && v.Hash != data.Hash {
+				continue
+			}
+			if data.Revoked != nil && (v.RevokedAt != nil) != *data.Revoked {
+				continue
+			}
+
+			result = append(result, v)
+		}
+		return nil
+	})
+
+	return result, nil
+}
+
+func (a *authMemory) UpdateAPIKey(ctx context.Context, data entity.UpdateAPIKey) error {
+	if data.ID < 1 {
+		return x.NewWithCode(http.StatusBadRequest, "api key id is required")
+	}
+
+	if data.LastUsedAt == nil && data.RevokedAt == nil {
+		return x.NewWithCode(http.StatusBadRequest, "no updates provided")
+	}
+
+	return a.store.Do(ctx, func() error {
+		for i, v := range a.tables.keys {
+			if v.ID != data.ID {
+				continue
+			}
+
+			k := &a.tables.keys[i]
+			if data.LastUsedAt != nil {
+				t := *data.LastUsedAt
+				k.LastUsedAt = &t
+			}
+			if data.RevokedAt != nil {
+				t := *data.RevokedAt
+				k.RevokedAt = &t
+			}
+
+			return nil
+		}
+
+		return x.NewWithCode(http.StatusNotFound, "api key not found")
+	})
+}
+
+func (a *authMemory) InsertUser(ctx context.Context, data entity.User) (entity.User, error) {
+	err := a.store.Do(ctx, func() error {
+		for _, v := range a.tables.users {
+			if v.Username == data.Username {
+				return x.NewWithCode(http.StatusConflict, "username is taken")
+			}
+		}
+
+		a.tables.nextUserID++
+		data.ID = a.tables.nextUserID
+		data.CreatedAt = time.Now()
+		data.UpdatedAt = data.CreatedAt
+		a.tables.users = append(a.tables.users, data)
+		return nil
+	})
+
+	return data, err
+}
+
+func (a *authMemory) GetUsers(ctx context.Context, data entity.GetUsers) ([]entity.User, error) {
+	result := []entity.User{}
+
+	_ = a.store.Do(ctx, func() error {
+		for _, v := range a.tables.users {
+			if data.ID > 0 && v.ID != data.ID {
+				continue
+			}
+			if data.Username != "" && v.Username != data.Username {
+				continue
+			}
+
+			result = append(result, v)
+		}
+		return nil
+	})
+
+	return result, nil
+}
+
+func (a *authMemory) UpdateUser(ctx context.Context, data entity.UpdateUser) error {
+	if data.ID < 1 {
+		return x.NewWithCode(http.StatusBadRequest, "user id is required")
+	}
+
+	if data.PasswordHash == nil && data.Role == nil && data.Active == nil {
+		return x.NewWithCode(http.StatusBadRequest, "no updates provided")
+	}
+
+	return a.store.Do(ctx, func() error {
+		for i, v := range a.tables.users {
+			if v.ID != data.ID {
+				continue
+			}
+
+			u := &a.tables.users[i]
+			if data.PasswordHash != nil {
+				u.PasswordHash = *data.PasswordHash
+			}
+			if data.Role != nil {
+				u.Role = *data.Role
+			}
+			if data.Active != nil {
+				u.Active = *data.Active
+			}
+			u.UpdatedAt = time.Now()
+
+			return nil
+		}
+
+		return x.NewWithCode(http.StatusNotFound, "user not found")
+	})
+}
@@ -0,0 +1,96 @@
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zuhrulumam/go-parking-lot/business/domain/auth"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+func TestGetAPIKeys(t *testing.T) {
+	db, mock, cleanup := pkg.SetupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "api_keys" WHERE hash = $1 AND revoked_at IS NULL ORDER BY id`)).
+		WithArgs("abc").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "role", "lot_id"}).AddRow(1, "gate-1", entity.RoleGate, 1))
+
+	d := auth.InitAuthDomain(auth.Option{DB: db})
+	keys, err := d.GetAPIKeys(context.Background(), entity.GetAPIKeys{Hash: "abc", Revoked: pkg.BoolPtr(false)})
+
+	assert.NoError(t, err)
+	assert.Len(t, keys, 1)
+	assert.Equal(t, entity.RoleGate, keys[0].Role)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateUserNotFound(t *testing.T) {
+	db, mock, cleanup := pkg.SetupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "active"=$1,"updated_at"=$2 WHERE id = $3`)).
+		WithArgs(false, sqlmock.AnyArg(), 9).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	d := auth.InitAuthDomain(auth.Option{DB: db})
+	err := d.UpdateUser(context.Background(), entity.UpdateUser{ID: 9, Active: pkg.BoolPtr(false)})
+
+	assert.EqualValues(t, http.StatusNotFound, x.ErrCode(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMemoryAPIKeys(t *testing.T) {
+	ctx := context.Background()
+	d := auth.InitAuthDomain(auth.Option{Memory: memstore.New()})
+
+	key, err := d.InsertAPIKey(ctx, entity.APIKey{Name: "gate-1", Hash: "abc", Role: entity.RoleGate})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), key.ID)
+
+	_, err = d.InsertAPIKey(ctx, entity.APIKey{Name: "gate-2", Hash: "abc", Role: entity.RoleGate})
+	assert.EqualValues(t, http.StatusConflict, x.ErrCode(err))
+
+	assert.NoError(t, d.UpdateAPIKey(ctx, entity.UpdateAPIKey{ID: 1, RevokedAt: pkg.TimePtr(time.Now())}))
+	assert.EqualValues(t, http.StatusBadRequest, x.ErrCode(d.UpdateAPIKey(ctx, entity.UpdateAPIKey{ID: 1})))
+	assert.EqualValues(t, http.StatusNotFound, x.ErrCode(d.UpdateAPIKey(ctx, entity.UpdateAPIKey{ID: 9, RevokedAt: pkg.TimePtr(time.Now())})))
+
+	active, err := d.GetAPIKeys(ctx, entity.GetAPIKeys{Hash: "abc", Revoked: pkg.BoolPtr(false)})
+	assert.NoError(t, err)
+	assert.Empty(t, active)
+
+	revoked, err := d.GetAPIKeys(ctx, entity.GetAPIKeys{Revoked: pkg.BoolPtr(true)})
+	assert.NoError(t, err)
+	assert.Len(t, revoked, 1)
+	assert.NotNil(t, revoked[0].RevokedAt)
+}
+
+func TestMemoryUsers(t *testing.T) {
+	ctx := context.Background()
+	d := auth.InitAuthDomain(auth.Option{Memory: memstore.New()})
+
+	_, err := d.InsertUser(ctx, entity.User{Username: "ana", Role: entity.RoleAttendant, Active: true})
+	assert.NoError(t, err)
+
+	_, err = d.InsertUser(ctx, entity.User{Username: "ana", Role: entity.RoleAdmin, Active: true})
+	assert.EqualValues(t, http.StatusConflict, x.ErrCode(err))
+
+	assert.NoError(t, d.UpdateUser(ctx, entity.UpdateUser{ID: 1, Role: pkg.StringPtr(entity.RoleAdmin)}))
+
+	users, err := d.GetUsers(ctx, entity.GetUsers{Username: "ana"})
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, entity.RoleAdmin, users[0].Role)
+	assert.True(t, users[0].Active)
+}
@@ -1,27 +1,126 @@
 package domain
 
 import (
+	"github.com/zuhrulumam/go-parking-lot/business/domain/audit"
+	"github.com/zuhrulumam/go-parking-lot/business/domain/auth"
+	"github.com/zuhrulumam/go-parking-lot/business/domain/lot"
+	"github.com/zuhrulumam/go-parking-lot/business/domain/outbox"
 	"github.com/zuhrulumam/go-parking-lot/business/domain/parking"
+	"github.com/zuhrulumam/go-parking-lot/business/domain/payment"
+	"github.com/zuhrulumam/go-parking-lot/business/domain/reservation"
+	"github.com/zuhrulumam/go-parking-lot/business/domain/spotfeed"
+	"github.com/zuhrulumam/go-parking-lot/business/domain/tariff"
 	"github.com/zuhrulumam/go-parking-lot/business/domain/transaction"
+	"github.com/zuhrulumam/go-parking-lot/business/domain/waitlist"
+	"github.com/zuhrulumam/go-parking-lot/business/domain/webhook"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg/broker"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+	"github.com/zuhrulumam/go-parking-lot/pkg/metrics"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
+const (
+	StorePostgres = "postgres"
+	StoreMemory   = "memory"
+)
+
 type Domain struct {
+	Lot         lot.DomainItf
 	Parking     parking.DomainItf
+	Tariff      tariff.DomainItf
+	Payment     payment.DomainItf
+	Reservation reservation.DomainItf
+	Waitlist    waitlist.DomainItf
+	Audit       audit.DomainItf
 	Transaction transaction.DomainItf
+	SpotFeed    spotfeed.DomainItf
+	Outbox      outbox.DomainItf
+	Webhook     webhook.DomainItf
+	Auth        auth.DomainItf
 }
 
 type Option struct {
-	DB *gorm.DB
+	// Store selects the backend, StorePostgres (default) or StoreMemory.
+	Store string
+	DB    *gorm.DB
+	Log   *zap.Logger
+
+	// MemoryLots, MemorySpots and MemoryTariffs seed the in-memory tables
+	// when Store is StoreMemory.
+	MemoryLots    []entity.Lot
+	MemorySpots   []entity.ParkingSpot
+	MemoryTariffs []entity.Tariff
+
+	// Metrics times the Postgres transactions.
+	Metrics metrics.Metrics
 }
 
 func Init(opt Option) *Domain {
+	var mem *memstore.Store
+	if opt.Store == StoreMemory {
+		mem = memstore.New()
+	}
+
+	// spot changes of this process, and of the others through Postgres
+	changes := broker.New()
+
 	d := &Domain{
+		Lot: lot.InitLotDomain(lot.Option{
+			DB:     opt.DB,
+			Memory: mem,
+			Lots:   opt.MemoryLots,
+		}),
 		Parking: parking.InitParkingDomain(parking.Option{
-			DB: opt.DB,
+			DB:      opt.DB,
+			Memory:  mem,
+			Spots:   opt.MemorySpots,
+			Changes: changes,
+		}),
+		Tariff: tariff.InitTariffDomain(tariff.Option{
+			DB:      opt.DB,
+			Memory:  mem,
+			Tariffs: opt.MemoryTariffs,
+		}),
+		Payment: payment.InitPaymentDomain(payment.Option{
+			DB:     opt.DB,
+			Memory: mem,
+		}),
+		Reservation: reservation.InitReservationDomain(reservation.Option{
+			DB:     opt.DB,
+			Memory: mem,
+		}),
+		Waitlist: waitlist.InitWaitlistDomain(waitlist.Option{
+			DB:     opt.DB,
+			Memory: mem,
+		}),
+		Audit: audit.InitAuditDomain(audit.Option{
+			DB:     opt.DB,
+			Memory: mem,
 		}),
 		Transaction: transaction.Init(transaction.Option{
-			DB: opt.DB,
+			DB:      opt.DB,
+			Memory:  mem,
+			Metrics: opt.Metrics,
+		}),
+		Outbox: outbox.InitOutboxDomain(outbox.Option{
+			DB:     opt.DB,
+			Memory: mem,
+		}),
+		Webhook: webhook.InitWebhookDomain(webhook.Option{
+			DB:     opt.DB,
+			Memory: mem,
+		}),
+		Auth: auth.InitAuthDomain(auth.Option{
+			DB:     opt.DB,
+			Memory: mem,
+		}),
+		SpotFeed: spotfeed.InitSpotFeedDomain(spotfeed.Option{
+			DB:      opt.DB,
+			Log:     opt.Log,
+			Changes: changes,
+			Memory:  opt.Store == StoreMemory,
 		}),
 	}
 
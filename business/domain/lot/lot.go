@@ -0,0 +1,40 @@
+package lot
+
+import (
+	"context"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+	"gorm.io/gorm"
+)
+
+//go:generate mockgen -source=business/domain/lot/lot.go -destination=mocks/domain/lot/mock_lot.go -package=mocks
+type DomainItf interface {
+	GetLot(ctx context.Context, data entity.GetLot) (entity.Lot, error)
+	GetLots(ctx context.Context) ([]entity.Lot, error)
+	InsertLot(ctx context.Context, data entity.Lot) (entity.Lot, error)
+	UpdateLot(ctx context.Context, data entity.Lot) (entity.Lot, error)
+}
+
+type lot struct {
+	db *gorm.DB
+}
+
+type Option struct {
+	DB *gorm.DB
+
+	// Memory switches the domain to the in-memory backend. Lots seeds it
+	// and is ignored otherwise.
+	Memory *memstore.Store
+	Lots   []entity.Lot
+}
+
+func InitLotDomain(opt Option) DomainItf {
+	if opt.Memory != nil {
+		return initLotMemory(opt)
+	}
+
+	return &lot{
+		db: opt.DB,
+	}
+}
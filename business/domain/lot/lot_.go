@@ -0,0 +1,90 @@
+package lot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"gorm.io/gorm"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+func (l *lot) GetLot(ctx context.Context, data entity.GetLot) (entity.Lot, error) {
+	var (
+		result entity.Lot
+		db     = pkg.GetTransactionFromCtx(ctx, l.db)
+	)
+
+	err := db.WithContext(ctx).
+		Where("id = ?", data.ID).
+		First(&result).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return result, x.WrapWithCode(err, http.StatusNotFound, "lot not found")
+		}
+		return result, x.WrapWithCode(err, http.StatusInternalServerError, "failed get lot")
+	}
+
+	return result, nil
+}
+
+func (l *lot) GetLots(ctx context.Context) ([]entity.Lot, error) {
+	var (
+		result []entity.Lot
+		db     = pkg.GetTransactionFromCtx(ctx, l.db)
+	)
+
+	if err := db.WithContext(ctx).Order("id").Find(&result).Error; err != nil {
+		return result, x.WrapWithCode(err, http.StatusInternalServerError, "failed get lots")
+	}
+
+	return result, nil
+}
+
+func (l *lot) InsertLot(ctx context.Context, data entity.Lot) (entity.Lot, error) {
+	db := pkg.GetTransactionFromCtx(ctx, l.db)
+
+	data.ID = 0
+	data.CreatedAt = time.Now()
+	data.UpdatedAt = data.CreatedAt
+
+	if err := db.WithContext(ctx).Create(&data).Error; err != nil {
+		return data, x.WrapWithCode(err, http.StatusInternalServerError, "failed to insert lot")
+	}
+
+	return data, nil
+}
+
+func (l *lot) UpdateLot(ctx context.Context, data entity.Lot) (entity.Lot, error) {
+	db := pkg.GetTransactionFromCtx(ctx, l.db)
+
+	if data.ID < 1 {
+		return data, x.NewWithCode(http.StatusBadRequest, "lot id is required")
+	}
+
+	data.UpdatedAt = time.Now()
+
+	res := db.WithContext(ctx).Model(&entity.Lot{}).Where("id = ?", data.ID).Updates(map[string]interface{}{
+		"name":       data.Name,
+		"allocation": data.Allocation,
+		"opens_at":   data.OpensAt,
+		"closes_at":  data.ClosesAt,
+		"timezone":   data.Timezone,
+		"fallback":   data.Fallback,
+		"reserve":    data.Reserve,
+		"updated_at": data.UpdatedAt,
+	})
+	if res.Error != nil {
+		return data, x.WrapWithCode(res.Error, http.StatusInternalServerError, "failed to update lot")
+	}
+
+	if res.RowsAffected == 0 {
+		return data, x.NewWithCode(http.StatusNotFound, "lot not found")
+	}
+
+	return data, nil
+}
@@ -0,0 +1,124 @@
+package lot
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+type lotMemory struct {
+	store  *memstore.Store
+	tables *lotTables
+}
+
+type lotTables struct {
+	lots   map[uint]entity.Lot
+	nextID uint
+}
+
+func (t *lotTables) Snapshot() func() {
+	lots := make(map[uint]entity.Lot, len(t.lots))
+	for k, v := range t.lots {
+		lots[k] = v
+	}
+	nextID := t.nextID
+
+	return func() {
+		t.lots = lots
+		t.nextID = nextID
+	}
+}
+
+func initLotMemory(opt Option) DomainItf {
+	tables := &lotTables{lots: map[uint]entity.Lot{}}
+	for _, l := range opt.Lots {
+		if l.ID == 0 {
+			l.ID = tables.nextID + 1
+		}
+		if l.ID > tables.nextID {
+			tables.nextID = l.ID
+		}
+		tables.lots[l.ID] = l
+	}
+
+	opt.Memory.Register(tables)
+
+	return &lotMemory{
+		store:  opt.Memory,
+		tables: tables,
+	}
+}
+
+func (l *lotMemory) GetLot(ctx context.Context, data entity.GetLot) (entity.Lot, error) {
+	var (
+		result entity.Lot
+		found  bool
+	)
+
+	_ = l.store.Do(ctx, func() error {
+		result, found = l.tables.lots[data.ID]
+		return nil
+	})
+
+	if !found {
+		return result, x.NewWithCode(http.StatusNotFound, "lot not found")
+	}
+
+	return result, nil
+}
+
+func (l *lotMemory) GetLots(ctx context.Context) ([]entity.Lot, error) {
+	result := []entity.Lot{}
+
+	_ = l.store.Do(ctx, func() error {
+		for _, v := range l.tables.lots {
+			result = append(result, v)
+		}
+		return nil
+	})
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+
+	return result, nil
+}
+
+func (l *lotMemory) InsertLot(ctx context.Context, data entity.Lot) (entity.Lot, error) {
+	err := l.store.Do(ctx, func() error {
+		l.tables.nextID++
+		data.ID = l.tables.nextID
+		data.CreatedAt = time.Now()
+		data.UpdatedAt = data.CreatedAt
+		l.tables.lots[data.ID] = data
+		return nil
+	})
+
+	return data, err
+}
+
+func (l *lotMemory) UpdateLot(ctx context.Context, data entity.Lot) (entity.Lot, error) {
+	if data.ID < 1 {
+		return data, x.NewWithCode(http.StatusBadRequest, "lot id is required")
+	}
+
+	err := l.store.Do(ctx, func() error {
+		current, ok := l.tables.lots[data.ID]
+		if !ok {
+			return x.NewWithCode(http.StatusNotFound, "lot not found")
+		}
+
+		data.CreatedAt = current.CreatedAt
+		data.UpdatedAt = time.Now()
+		l.tables.lots[data.ID] = data
+		return nil
+	})
+
+	return data, err
+}
@@ -0,0 +1,117 @@
+package lot_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zuhrulumam/go-parking-lot/business/domain/lot"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+	"gorm.io/gorm"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+func TestGetLot(t *testing.T) {
+	tests := []struct {
+		name         string
+		mockRows     *sqlmock.Rows
+		mockError    error
+		expectedCode int
+	}{
+		{
+			name: "Success",
+			mockRows: sqlmock.NewRows([]string{"id", "name", "allocation"}).
+				AddRow(2, "Airport", "spread"),
+		},
+		{
+			name:         "Not found",
+			mockError:    gorm.ErrRecordNotFound,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "DB Error",
+			mockError:    errors.New("db error"),
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, cleanup := pkg.SetupMockDB(t)
+			defer cleanup()
+
+			q := mock.ExpectQuery(`SELECT \* FROM "lots" WHERE id = \$1`).WithArgs(2, 1)
+			if tt.mockError != nil {
+				q.WillReturnError(tt.mockError)
+			} else {
+				q.WillReturnRows(tt.mockRows)
+			}
+
+			d := lot.InitLotDomain(lot.Option{DB: db})
+			result, err := d.GetLot(context.Background(), entity.GetLot{ID: 2})
+
+			if tt.expectedCode != 0 {
+				assert.EqualValues(t, tt.expectedCode, x.ErrCode(err))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "spread", result.Allocation)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUpdateLot(t *testing.T) {
+	db, mock, cleanup := pkg.SetupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "lots" SET .* WHERE id = \$\d+`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	d := lot.InitLotDomain(lot.Option{DB: db})
+	_, err := d.UpdateLot(context.Background(), entity.Lot{ID: 9, Name: "Gone"})
+
+	assert.EqualValues(t, http.StatusNotFound, x.ErrCode(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMemoryLots(t *testing.T) {
+	ctx := context.Background()
+	d := lot.InitLotDomain(lot.Option{
+		Memory: memstore.New(),
+		Lots:   []entity.Lot{{ID: 1, Name: "Main"}},
+	})
+
+	created, err := d.InsertLot(ctx, entity.Lot{Name: "Airport", Fallback: true})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), created.ID)
+
+	created.Name = "Airport T1"
+	_, err = d.UpdateLot(ctx, created)
+	assert.NoError(t, err)
+
+	got, err := d.GetLot(ctx, entity.GetLot{ID: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, "Airport T1", got.Name)
+	assert.True(t, got.Fallback)
+
+	_, err = d.GetLot(ctx, entity.GetLot{ID: 3})
+	assert.EqualValues(t, http.StatusNotFound, x.ErrCode(err))
+
+	_, err = d.UpdateLot(ctx, entity.Lot{ID: 3, Name: "Nope"})
+	assert.EqualValues(t, http.StatusNotFound, x.ErrCode(err))
+
+	all, err := d.GetLots(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, all, 2)
+	assert.Equal(t, "Main", all[0].Name)
+}
@@ -0,0 +1,39 @@
+package outbox
+
+import (
+	"context"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+	"gorm.io/gorm"
+)
+
+//go:generate mockgen -source=business/domain/outbox/outbox.go -destination=mocks/domain/outbox/mock_outbox.go -package=mocks
+type DomainItf interface {
+	InsertEvent(ctx context.Context, data entity.OutboxEvent) (entity.OutboxEvent, error)
+	GetEvents(ctx context.Context, data entity.GetOutboxEvents) ([]entity.OutboxEvent, error)
+	UpdateEvent(ctx context.Context, data entity.UpdateOutboxEvent) error
+	InsertDelivery(ctx context.Context, data entity.EventDelivery) (entity.EventDelivery, error)
+	GetDeliveries(ctx context.Context, data entity.GetEventDeliveries) ([]entity.EventDelivery, error)
+}
+
+type outbox struct {
+	db *gorm.DB
+}
+
+type Option struct {
+	DB *gorm.DB
+
+	// Memory switches the domain to the in-memory backend.
+	Memory *memstore.Store
+}
+
+func InitOutboxDomain(opt Option) DomainItf {
+	if opt.Memory != nil {
+		return initOutboxMemory(opt)
+	}
+
+	return &outbox{
+		db: opt.DB,
+	}
+}
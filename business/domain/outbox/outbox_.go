@@ -0,0 +1,152 @@
+package outbox
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"gorm.io/gorm/clause"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+func (o *outbox) InsertEvent(ctx context.Context, data entity.OutboxEvent) (entity.OutboxEvent, error) {
+	db := pkg.GetTransactionFromCtx(ctx, o.db)
+
+	data.ID = 0
+	data.CreatedAt = time.Now()
+	data.NextAttemptAt = data.CreatedAt
+
+	if err := db.WithContext(ctx).Create(&data).Error; err != nil {
+		return data, x.WrapWithCode(err, http.StatusInternalServerError, "failed to insert outbox event")
+	}
+
+	return data, nil
+}
+
+func (o *outbox) GetEvents(ctx context.Context, data entity.GetOutboxEvents) ([]entity.OutboxEvent, error) {
+	var (
+		result []entity.OutboxEvent
+		db     = pkg.GetTransactionFromCtx(ctx, o.db)
+	)
+
+	db = db.WithContext(ctx).Model(&entity.OutboxEvent{})
+
+	if len(data.IDs) > 0 {
+		db = db.Where("id IN ?", data.IDs)
+	}
+
+	if data.Pending {
+		db = db.Where("dispatched_at IS NULL AND failed_at IS NULL")
+	}
+
+	if data.DueBy != nil {
+		db = db.Where("next_attempt_at <= ?", *data.DueBy)
+	}
+
+	if data.Limit > 0 {
+		db = db.Limit(data.Limit)
+	}
+
+	if data.UseLock {
+		locking := clause.Locking{Strength: "UPDATE"}
+		if data.SkipLocked {
+			locking.Options = "SKIP LOCKED"
+		}
+		db = db.Clauses(locking)
+	}
+
+	// oldest first
+	if err := db.Order("id").Find(&result).Error; err != nil {
+		return result, x.WrapWithCode(err, http.StatusInternalServerError, "failed get outbox events")
+	}
+
+	return result, nil
+}
+
+func (o *outbox) UpdateEvent(ctx context.Context, data entity.UpdateOutboxEvent) error {
+	db := pkg.GetTransactionFromCtx(ctx, o.db)
+
+	if data.ID < 1 {
+		return x.NewWithCode(http.StatusBadRequest, "outbox event id is required")
+	}
+
+	updates := map[string]interface{}{}
+	if data.Attempts != nil {
+		updates["attempts"] = data.Attempts
+	}
+	if data.NextAttemptAt != nil {
+		updates["next_attempt_at"] = data.NextAttemptAt
+	}
+	if data.DispatchedAt != nil {
+		updates["dispatched_at"] = data.DispatchedAt
+	}
+	if data.FailedAt != nil {
+		updates["failed_at"] = data.FailedAt
+	}
+	if data.LastError != nil {
+		updates["last_error"] = data.LastError
+	}
+
+	if len(updates) == 0 {
+		return x.NewWithCode(http.StatusBadRequest, "no updates provided")
+	}
+
+	err := db.WithContext(ctx).Model(&entity.OutboxEvent{}).Where("id = ?", data.ID).Updates(updates).Error
+	if err != nil {
+		return x.WrapWithCode(err, http.StatusInternalServerError, "failed to update outbox event")
+	}
+
+	return nil
+}
+
+func (o *outbox) InsertDelivery(ctx context.Context, data entity.EventDelivery) (entity.EventDelivery, error) {
+	db := pkg.GetTransactionFromCtx(ctx, o.db)
+
+	data.ID = 0
+	data.CreatedAt = time.Now()
+
+	if err := db.WithContext(ctx).Create(&data).Error; err != nil {
+		return data, x.WrapWithCode(err, http.StatusInternalServerError, "failed to insert event delivery")
+	}
+
+	return data, nil
+}
+
+func (o *outbox) GetDeliveries(ctx context.Context, data entity.GetEventDeliveries) ([]entity.EventDelivery, error) {
+	var (
+		result []entity.EventDelivery
+		db     = pkg.GetTransactionFromCtx(ctx, o.db)
+	)
+
+	db = db.WithContext(ctx).Model(&entity.EventDelivery{})
+
+	if len(data.IDs) > 0 {
+		db = db.Where("id IN ?", data.IDs)
+	}
+
+	if len(data.EventIDs) > 0 {
+		db = db.Where("event_id IN ?", data.EventIDs)
+	}
+
+	if data.Sink != "" {
+		db = db.Where("sink = ?", data.Sink)
+	}
+
+	if data.Status != "" {
+		db = db.Where("status = ?", data.Status)
+	}
+
+	if data.Limit > 0 {
+		db = db.Limit(data.Limit)
+	}
+
+	// newest first
+	if err := db.Order("id DESC").Find(&result).Error; err != nil {
+		return result, x.WrapWithCode(err, http.StatusInternalServerError, "failed get event deliveries")
+	}
+
+	return result, nil
+}
@@ -0,0 +1,166 @@
+package outbox
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+type outboxMemory struct {
+	store  *memstore.Store
+	tables *outboxTables
+}
+
+type outboxTables struct {
+	events         []entity.OutboxEvent
+	deliveries     []entity.EventDelivery
+	nextEventID    uint
+	nextDeliveryID uint
+}
+
+func (t *outboxTables) Snapshot() func() {
+	events := append([]entity.OutboxEvent(nil), t.events...)
+	deliveries := append([]entity.EventDelivery(nil), t.deliveries...)
+	nextEventID, nextDeliveryID := t.nextEventID, t.nextDeliveryID
+
+	return func() {
+		t.events = events
+		t.deliveries = deliveries
+		t.nextEventID, t.nextDeliveryID = nextEventID, nextDeliveryID
+	}
+}
+
+func initOutboxMemory(opt Option) DomainItf {
+	tables := &outboxTables{}
+	opt.Memory.Register(tables)
+
+	return &outboxMemory{
+		store:  opt.Memory,
+		tables: tables,
+	}
+}
+
+func (o *outboxMemory) InsertEvent(ctx context.Context, data entity.OutboxEvent) (entity.OutboxEvent, error) {
+	err := o.store.Do(ctx, func() error {
+		o.tables.nextEventID++
+		data.ID = o.tables.nextEventID
+		data.CreatedAt = time.Now()
+		data.NextAttemptAt = data.CreatedAt
+		o.tables.events = append(o.tables.events, data)
+		return nil
+	})
+
+	return data, err
+}
+
+func (o *outboxMemory) GetEvents(ctx context.Context, data entity.GetOutboxEvents) ([]entity.OutboxEvent, error) {
+	result := []entity.OutboxEvent{}
+
+	_ = o.store.Do(ctx, func() error {
+		for _, v := range o.tables.events {
+			if data.Limit > 0 && len(result) == data.Limit {
+				break
+			}
+			if len(data.IDs) > 0 && !slices.Contains(data.IDs, v.ID) {
+				continue
+			}
+			if data.Pending && (v.DispatchedAt != nil || v.FailedAt != nil) {
+				continue
+			}
+			if data.DueBy != nil && v.NextAttemptAt.After(*data.DueBy) {
+				continue
+			}
+
+			result = append(result, v)
+		}
+		return nil
+	})
+
+	return result, nil
+}
+
+func (o *outboxMemory) UpdateEvent(ctx context.Context, data entity.UpdateOutboxEvent) error {
+	if data.ID < 1 {
+		return x.NewWithCode(http.StatusBadRequest, "outbox event id is required")
+	}
+
+	if data.Attempts == nil && data.NextAttemptAt == nil && data.DispatchedAt == nil &&
+		data.FailedAt == nil && data.LastError == nil {
+		return x.NewWithCode(http.StatusBadRequest, "no updates provided")
+	}
+
+	return o.store.Do(ctx, func() error {
+		for i, v := range o.tables.events {
+			if v.ID != data.ID {
+				continue
+			}
+
+			e := &o.tables.events[i]
+			if data.Attempts != nil {
+				e.Attempts = *data.Attempts
+			}
+			if data.NextAttemptAt != nil {
+				e.NextAttemptAt = *data.NextAttemptAt
+			}
+			if data.DispatchedAt != nil {
+				e.DispatchedAt = data.DispatchedAt
+			}
+			if data.FailedAt != nil {
+				e.FailedAt = data.FailedAt
+			}
+			if data.LastError != nil {
+				e.LastError = *data.LastError
+			}
+		}
+		return nil
+	})
+}
+
+func (o *outboxMemory) InsertDelivery(ctx context.Context, data entity.EventDelivery) (entity.EventDelivery, error) {
+	err := o.store.Do(ctx, func() error {
+		o.tables.nextDeliveryID++
+		data.ID = o.tables.nextDeliveryID
+		data.CreatedAt = time.Now()
+		o.tables.deliveries = append(o.tables.deliveries, data)
+		return nil
+	})
+
+	return data, err
+}
+
+func (o *outboxMemory) GetDeliveries(ctx context.Context, data entity.GetEventDeliveries) ([]entity.EventDelivery, error) {
+	result := []entity.EventDelivery{}
+
+	_ = o.store.Do(ctx, func() error {
+		// newest first, like ORDER BY id DESC
+		for i := len(o.tables.deliveries) - 1; i >= 0; i-- {
+			v := o.tables.deliveries[i]
+			if len(data.IDs) > 0 && !slices.Contains(data.IDs, v.ID) {
+				continue
+			}
+			if len(data.EventIDs) > 0 && !slices.Contains(data.EventIDs, v.EventID) {
+				continue
+			}
+			if data.Sink != "" && v.Sink != data.Sink {
+				continue
+			}
+			if data.Status != "" && v.Status != data.Status {
+				continue
+			}
+
+			result = append(result, v)
+			if data.Limit > 0 && len(result) == data.Limit {
+				break
+			}
+		}
+		return nil
+	})
+
+	return result, nil
+}
@@ -0,0 +1,83 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zuhrulumam/go-parking-lot/business/domain/outbox"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+)
+
+func TestGetEvents(t *testing.T) {
+	db, mock, cleanup := pkg.SetupMockDB(t)
+	defer cleanup()
+
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "outbox_events" WHERE (dispatched_at IS NULL AND failed_at IS NULL) AND next_attempt_at <= $1 ORDER BY id LIMIT $2 FOR UPDATE SKIP LOCKED`)).
+		WithArgs(now, 100).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "lot_id", "type", "data"}).AddRow(1, 1, entity.EventVehicleParked, `{"ticket_id":"t1"}`))
+
+	d := outbox.InitOutboxDomain(outbox.Option{DB: db})
+	events, err := d.GetEvents(context.Background(), entity.GetOutboxEvents{Pending: true, DueBy: &now, Limit: 100, UseLock: true, SkipLocked: true})
+
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.JSONEq(t, `{"ticket_id":"t1"}`, string(events[0].Data))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateEvent(t *testing.T) {
+	db, mock, cleanup := pkg.SetupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "outbox_events" SET "attempts"=$1,"last_error"=$2 WHERE id = $3`)).
+		WithArgs(2, "webhook: down", 1).
+		WillReturnError(errors.New("db error"))
+	mock.ExpectRollback()
+
+	d := outbox.InitOutboxDomain(outbox.Option{DB: db})
+	err := d.UpdateEvent(context.Background(), entity.UpdateOutboxEvent{ID: 1, Attempts: pkg.IntPtr(2), LastError: pkg.StringPtr("webhook: down")})
+
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMemoryOutbox(t *testing.T) {
+	ctx := context.Background()
+	d := outbox.InitOutboxDomain(outbox.Option{Memory: memstore.New()})
+
+	for i := 0; i < 3; i++ {
+		_, err := d.InsertEvent(ctx, entity.NewOutboxEvent(1, entity.EventVehicleParked, nil))
+		assert.NoError(t, err)
+	}
+
+	now := time.Now()
+	later := now.Add(time.Minute)
+
+	assert.NoError(t, d.UpdateEvent(ctx, entity.UpdateOutboxEvent{ID: 1, DispatchedAt: &now}))
+	assert.NoError(t, d.UpdateEvent(ctx, entity.UpdateOutboxEvent{ID: 2, NextAttemptAt: &later}))
+
+	events, err := d.GetEvents(ctx, entity.GetOutboxEvents{Pending: true, DueBy: &now})
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, uint(3), events[0].ID)
+
+	for _, status := range []string{entity.DeliveryFailed, entity.DeliverySucceeded} {
+		_, err := d.InsertDelivery(ctx, entity.EventDelivery{EventID: 2, Sink: "log", Status: status})
+		assert.NoError(t, err)
+	}
+
+	deliveries, err := d.GetDeliveries(ctx, entity.GetEventDeliveries{EventIDs: []uint{2}, Status: entity.DeliverySucceeded})
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+	assert.Equal(t, uint(2), deliveries[0].ID)
+}
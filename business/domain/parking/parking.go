@@ -4,16 +4,22 @@ import (
 	"context"
 
 	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg/broker"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
 	"gorm.io/gorm"
 )
 
-//go:generate mockgen -source=business/domain/parking/parking.go -destination=mocks/mock_parking.go -package=mocks
+//go:generate mockgen -source=business/domain/parking/parking.go -destination=mocks/domain/parking/mock_parking.go -package=mocks
 type DomainItf interface {
 	GetAvailableParkingSpot(ctx context.Context, data entity.GetAvailableParkingSpot) ([]entity.ParkingSpot, error)
-	InsertVehicle(ctx context.Context, data entity.InsertVehicle) error
+	CountParkingSpots(ctx context.Context, lotID uint) ([]entity.SpotCount, error)
+	InsertParkingSpots(ctx context.Context, data []entity.ParkingSpot) ([]entity.ParkingSpot, error)
+	DeleteParkingSpots(ctx context.Context, data entity.DeleteParkingSpots) (int, error)
+	InsertVehicle(ctx context.Context, data entity.InsertVehicle) (entity.Vehicle, error)
 	UpdateParkingSpot(ctx context.Context, data entity.UpdateParkingSpot) error
 	UpdateVehicle(ctx context.Context, data entity.UpdateVehicle) error
 	GetVehicle(ctx context.Context, data entity.SearchVehicle) (entity.Vehicle, error)
+	GetSessions(ctx context.Context, data entity.GetSessions) ([]entity.Vehicle, error)
 }
 
 type parking struct {
@@ -22,9 +28,22 @@ type parking struct {
 
 type Option struct {
 	DB *gorm.DB
+
+	// Memory switches the domain to the in-memory backend. Spots seeds the
+	// in-memory spot grid and is ignored otherwise.
+	Memory *memstore.Store
+	Spots  []entity.ParkingSpot
+
+	// Changes receives every spot change of the in-memory backend, the
+	// Postgres one notifies through the spot_changes trigger instead.
+	Changes *broker.Broker
 }
 
 func InitParkingDomain(opt Option) DomainItf {
+	if opt.Memory != nil {
+		return initParkingMemory(opt)
+	}
+
 	p := &parking{
 		db: opt.DB,
 	}
@@ -3,6 +3,7 @@ package parking
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -21,13 +22,34 @@ func (p *parking) GetAvailableParkingSpot(ctx context.Context, data entity.GetAv
 		db     = pkg.GetTransactionFromCtx(ctx, p.db)
 	)
 
-	db = db.Model(&entity.ParkingSpot{})
+	db = db.WithContext(ctx).Model(&entity.ParkingSpot{})
+
+	// Filter by id
+	if data.ID > 0 {
+		db = db.Where("id = ?", data.ID)
+	}
+
+	// Filter by lot
+	if data.LotID > 0 {
+		db = db.Where("lot_id = ?", data.LotID)
+	}
 
 	// Filter by type
 	if data.VehicleType != "" {
 		db = db.Where("type = ?", data.VehicleType)
 	}
 
+	// Filter by position, a floor alone lists the whole floor
+	if data.Floor > 0 {
+		db = db.Where("floor = ?", data.Floor)
+	}
+	if data.Row > 0 {
+		db = db.Where("row = ?", data.Row)
+	}
+	if data.Col > 0 {
+		db = db.Where("col = ?", data.Col)
+	}
+
 	// Filter by active status
 	if data.Active != nil {
 		db = db.Where("active = ?", *data.Active)
@@ -38,13 +60,21 @@ func (p *parking) GetAvailableParkingSpot(ctx context.Context, data entity.GetAv
 		db = db.Where("occupied = ?", *data.Occupied)
 	}
 
+	// Filter by reserved status
+	if data.Reserved != nil {
+		db = db.Where("reserved = ?", *data.Reserved)
+	}
+
 	// if use lock
 	if data.UseLock {
-		db.Clauses(clause.Locking{Strength: "UPDATE"})
+		locking := clause.Locking{Strength: "UPDATE"}
+		if data.SkipLocked {
+			locking.Options = "SKIP LOCKED"
+		}
+		db = db.Clauses(locking)
 	}
 
-	// Only get the first match
-	err := db.Find(&result).Error
+	err := db.Order("id").Find(&result).Error
 	if err != nil {
 		return result, x.WrapWithCode(err, http.StatusInternalServerError, "error get available parking spot")
 	}
@@ -52,21 +82,91 @@ func (p *parking) GetAvailableParkingSpot(ctx context.Context, data entity.GetAv
 	return result, nil
 }
 
-func (p *parking) InsertVehicle(ctx context.Context, data entity.InsertVehicle) error {
+func (p *parking) InsertVehicle(ctx context.Context, data entity.InsertVehicle) (entity.Vehicle, error) {
 	db := pkg.GetTransactionFromCtx(ctx, p.db)
 
 	vehicle := entity.Vehicle{
+		LotID:         data.LotID,
 		VehicleNumber: data.VehicleNumber,
 		VehicleType:   data.VehicleType,
 		SpotID:        data.SpotID,
+		TicketID:      data.TicketID,
 		ParkedAt:      time.Now(),
+		Status:        entity.SessionParked,
 	}
 
 	if err := db.WithContext(ctx).Create(&vehicle).Error; err != nil {
-		return x.WrapWithCode(err, http.StatusInternalServerError, "failed to insert vehicle")
+		if pkg.IsUniqueViolation(err, "unique_open_vehicle") {
+			return vehicle, x.WrapWithCode(err, http.StatusConflict, "vehicle is already parked")
+		}
+		return vehicle, x.WrapWithCode(err, http.StatusInternalServerError, "failed to insert vehicle")
 	}
 
-	return nil
+	return vehicle, nil
+}
+
+func (p *parking) CountParkingSpots(ctx context.Context, lotID uint) ([]entity.SpotCount, error) {
+	var (
+		result []entity.SpotCount
+		db     = pkg.GetTransactionFromCtx(ctx, p.db)
+	)
+
+	err := db.WithContext(ctx).Model(&entity.ParkingSpot{}).
+		Select(`floor, type,
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE active) AS active,
+			COUNT(*) FILTER (WHERE occupied) AS occupied,
+			COUNT(*) FILTER (WHERE reserved) AS reserved,
+			COUNT(*) FILTER (WHERE active AND NOT occupied AND NOT reserved) AS free`).
+		Where("lot_id = ?", lotID).
+		Group("floor, type").
+		Order("floor, type").
+		Scan(&result).Error
+	if err != nil {
+		return result, x.WrapWithCode(err, http.StatusInternalServerError, "failed count parking spots")
+	}
+
+	return result, nil
+}
+
+func (p *parking) InsertParkingSpots(ctx context.Context, data []entity.ParkingSpot) ([]entity.ParkingSpot, error) {
+	db := pkg.GetTransactionFromCtx(ctx, p.db)
+
+	if len(data) == 0 {
+		return data, nil
+	}
+
+	for i := range data {
+		data[i].ID = 0
+	}
+
+	if err := db.WithContext(ctx).CreateInBatches(&data, 1000).Error; err != nil {
+		return data, x.WrapWithCode(err, http.StatusInternalServerError, "failed to insert parking spots")
+	}
+
+	return data, nil
+}
+
+func (p *parking) DeleteParkingSpots(ctx context.Context, data entity.DeleteParkingSpots) (int, error) {
+	db := pkg.GetTransactionFromCtx(ctx, p.db)
+
+	tx := db.WithContext(ctx)
+
+	switch {
+	case data.LotID > 0 && data.Floor > 0:
+		tx = tx.Where("lot_id = ? AND floor = ?", data.LotID, data.Floor)
+	case len(data.IDs) > 0:
+		tx = tx.Where("id IN ?", data.IDs)
+	default:
+		return 0, x.NewWithCode(http.StatusBadRequest, "lot and floor, or ids are required")
+	}
+
+	res := tx.Delete(&entity.ParkingSpot{})
+	if res.Error != nil {
+		return 0, x.WrapWithCode(res.Error, http.StatusInternalServerError, "failed to delete parking spots")
+	}
+
+	return int(res.RowsAffected), nil
 }
 
 func (p *parking) UpdateParkingSpot(ctx context.Context, data entity.UpdateParkingSpot) error {
@@ -78,16 +178,31 @@ func (p *parking) UpdateParkingSpot(ctx context.Context, data entity.UpdateParki
 	// Build conditional WHERE clause
 	if data.ID > 0 {
 		tx = tx.Where("id = ?", data.ID)
-	} else if data.Floor > 0 && data.Row > 0 && data.Col > 0 {
-		tx = tx.Where("floor = ? AND row = ? AND col = ?", data.Floor, data.Row, data.Col)
+	} else if data.LotID > 0 && data.Floor > 0 && data.Row > 0 && data.Col > 0 {
+		tx = tx.Where("lot_id = ? AND floor = ? AND row = ? AND col = ?", data.LotID, data.Floor, data.Row, data.Col)
 	} else {
-		return x.NewWithCode(http.StatusBadRequest, "must provide either spot_id or (floor, row, col)")
+		return x.NewWithCode(http.StatusBadRequest, "must provide either spot_id or (lot, floor, row, col)")
 	}
 
 	updates := map[string]interface{}{}
+	if data.Type != "" {
+		updates["type"] = data.Type
+	}
+	if data.Active != nil {
+		updates["active"] = data.Active
+	}
 	if data.Occupied != nil {
 		updates["occupied"] = data.Occupied
 	}
+	if data.Reserved != nil {
+		updates["reserved"] = data.Reserved
+	}
+	if data.EVCharger != nil {
+		updates["ev_charger"] = data.EVCharger
+	}
+	if data.Accessible != nil {
+		updates["accessible"] = data.Accessible
+	}
 
 	if len(updates) == 0 {
 		return x.NewWithCode(http.StatusBadRequest, "no updates provided")
@@ -111,6 +226,15 @@ func (p *parking) UpdateVehicle(ctx context.Context, data entity.UpdateVehicle)
 	if data.UnparkedAt != nil {
 		updates["unparked_at"] = data.UnparkedAt
 	}
+	if data.Fee != nil {
+		updates["fee"] = data.Fee
+	}
+	if data.Status != "" {
+		updates["status"] = data.Status
+	}
+	if data.ExitRequestedAt != nil {
+		updates["exit_requested_at"] = data.ExitRequestedAt
+	}
 
 	if len(updates) == 0 {
 		return x.NewWithCode(http.StatusBadRequest, "no updates provided")
@@ -131,13 +255,38 @@ func (p *parking) GetVehicle(ctx context.Context, data entity.SearchVehicle) (en
 		result entity.Vehicle
 	)
 
-	db := p.db.WithContext(ctx).Model(&entity.Vehicle{})
+	db := pkg.GetTransactionFromCtx(ctx, p.db).WithContext(ctx).Model(&entity.Vehicle{})
+
+	// Filter by lot
+	if data.LotID > 0 {
+		db = db.Where("lot_id = ?", data.LotID)
+	}
+
+	// Filter by spot
+	if data.SpotID != "" {
+		db = db.Where("spot_id = ?", data.SpotID)
+	}
 
 	// Filter by type
 	if data.VehicleNumber != "" {
 		db = db.Where("vehicle_number = ?", data.VehicleNumber)
 	}
 
+	// Filter by session id
+	if data.ID > 0 {
+		db = db.Where("id = ?", data.ID)
+	}
+
+	// Filter by ticket
+	if data.TicketID != "" {
+		db = db.Where("ticket_id = ?", data.TicketID)
+	}
+
+	// if use lock
+	if data.UseLock {
+		db = db.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+
 	// Only get the first match
 	err := db.Order("id DESC").First(&result).Error
 	if err != nil {
@@ -149,3 +298,95 @@ func (p *parking) GetVehicle(ctx context.Context, data entity.SearchVehicle) (en
 
 	return result, nil
 }
+
+// sessionDuration is how long a session lasted in microseconds, open ones
+// up to the time given.
+const sessionDuration = "CAST(EXTRACT(EPOCH FROM (COALESCE(unparked_at, ?) - parked_at)) * 1000000 AS BIGINT)"
+
+func (p *parking) GetSessions(ctx context.Context, data entity.GetSessions) ([]entity.Vehicle, error) {
+	var (
+		result []entity.Vehicle
+	)
+
+	db := p.db.WithContext(ctx).Model(&entity.Vehicle{})
+
+	// Filter by lot
+	if data.LotID > 0 {
+		db = db.Where("lot_id = ?", data.LotID)
+	}
+
+	if data.VehicleNumber != "" {
+		db = db.Where("vehicle_number = ?", data.VehicleNumber)
+	}
+
+	if data.VehicleType != "" {
+		db = db.Where("vehicle_type = ?", data.VehicleType)
+	}
+
+	// spot ids start with lot-floor-
+	if data.Floor > 0 {
+		db = db.Where("spot_id LIKE ?", fmt.Sprintf("%d-%d-%%", data.LotID, data.Floor))
+	}
+
+	// in the lot at some point between From and To
+	if data.From != nil {
+		db = db.Where("unparked_at IS NULL OR unparked_at >= ?", *data.From)
+	}
+
+	if data.To != nil {
+		db = db.Where("parked_at < ?", *data.To)
+	}
+
+	if data.Open != nil {
+		if *data.Open {
+			db = db.Where("unparked_at IS NULL")
+		} else {
+			db = db.Where("unparked_at IS NOT NULL")
+		}
+	}
+
+	if data.MinFee != nil {
+		db = db.Where("fee >= ?", *data.MinFee)
+	}
+
+	if data.MaxFee != nil {
+		db = db.Where("fee <= ?", *data.MaxFee)
+	}
+
+	// Keyset pagination on (key, id)
+	var (
+		key  = clause.Expr{SQL: "parked_at"}
+		cmp  = ">"
+		dir  = "ASC"
+		vars []interface{}
+	)
+	if data.Sort == entity.SessionSortDuration {
+		key = clause.Expr{SQL: sessionDuration, Vars: []interface{}{data.AsOf}}
+	}
+	if data.Desc {
+		cmp, dir = "<", "DESC"
+	}
+
+	if data.After != nil {
+		var after interface{} = data.After.Key
+		if data.Sort != entity.SessionSortDuration {
+			after = time.UnixMicro(data.After.Key)
+		}
+		vars = append(vars, key, after, data.After.ID)
+		db = db.Where(clause.Expr{SQL: "(?, id) " + cmp + " (?, ?)", Vars: vars})
+	}
+
+	if data.Limit > 0 {
+		db = db.Limit(data.Limit)
+	}
+
+	err := db.Order(clause.OrderBy{Expression: clause.Expr{
+		SQL:  "? " + dir + ", id " + dir,
+		Vars: []interface{}{key},
+	}}).Find(&result).Error
+	if err != nil {
+		return result, x.WrapWithCode(err, http.StatusInternalServerError, "failed get sessions")
+	}
+
+	return result, nil
+}
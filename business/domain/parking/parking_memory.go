@@ -0,0 +1,490 @@
+package parking
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg/broker"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+// parkingMemory is the in-memory counterpart of parking. Every method runs
+// through the shared memstore.Store, so calls made inside RunInTx see and
+// roll back together with the rest of the transaction.
+type parkingMemory struct {
+	store   *memstore.Store
+	tables  *parkingTables
+	changes *broker.Broker
+}
+
+type parkingTables struct {
+	spots         []entity.ParkingSpot
+	vehicles      []entity.Vehicle
+	nextSpotID    uint
+	nextVehicleID uint
+}
+
+func (t *parkingTables) Snapshot() func() {
+	spots := append([]entity.ParkingSpot(nil), t.spots...)
+	vehicles := append([]entity.Vehicle(nil), t.vehicles...)
+	nextSpotID, nextVehicleID := t.nextSpotID, t.nextVehicleID
+
+	return func() {
+		t.spots = spots
+		t.vehicles = vehicles
+		t.nextSpotID = nextSpotID
+		t.nextVehicleID = nextVehicleID
+	}
+}
+
+func initParkingMemory(opt Option) DomainItf {
+	tables := &parkingTables{}
+
+	for _, s := range opt.Spots {
+		if s.ID == 0 {
+			s.ID = tables.nextSpotID + 1
+		}
+		if s.ID > tables.nextSpotID {
+			tables.nextSpotID = s.ID
+		}
+		tables.spots = append(tables.spots, s)
+	}
+
+	sort.Slice(tables.spots, func(i, j int) bool {
+		return tables.spots[i].ID < tables.spots[j].ID
+	})
+
+	opt.Memory.Register(tables)
+
+	return &parkingMemory{
+		store:   opt.Memory,
+		tables:  tables,
+		changes: opt.Changes,
+	}
+}
+
+// publish hands spot changes to the broker once the transaction commits,
+// the way the spot_changes trigger notifies on commit.
+func (p *parkingMemory) publish(ctx context.Context, changes []entity.SpotChange) {
+	if p.changes == nil || len(changes) == 0 {
+		return
+	}
+
+	p.store.AfterCommit(ctx, func() {
+		for _, c := range changes {
+			p.changes.Publish(c)
+		}
+	})
+}
+
+func spotChange(before, after *entity.ParkingSpot) entity.SpotChange {
+	c := entity.SpotChange{Old: before, New: after}
+	if before != nil {
+		c.LotID = before.LotID
+	} else {
+		c.LotID = after.LotID
+	}
+	return c
+}
+
+func (p *parkingMemory) GetAvailableParkingSpot(ctx context.Context, data entity.GetAvailableParkingSpot) ([]entity.ParkingSpot, error) {
+	result := []entity.ParkingSpot{}
+
+	_ = p.store.Do(ctx, func() error {
+		for _, s := range p.tables.spots {
+			if data.ID > 0 && s.ID != data.ID {
+				continue
+			}
+
+			if data.LotID > 0 && s.LotID != data.LotID {
+				continue
+			}
+
+			if data.VehicleType != "" && s.Type != string(data.VehicleType) {
+				continue
+			}
+
+			if (data.Floor > 0 && s.Floor != data.Floor) ||
+				(data.Row > 0 && s.Row != data.Row) ||
+				(data.Col > 0 && s.Col != data.Col) {
+				continue
+			}
+
+			if data.Active != nil && s.Active != *data.Active {
+				continue
+			}
+
+			if data.Occupied != nil && s.Occupied != *data.Occupied {
+				continue
+			}
+
+			if data.Reserved != nil && s.Reserved != *data.Reserved {
+				continue
+			}
+
+			result = append(result, s)
+		}
+
+		return nil
+	})
+
+	return result, nil
+}
+
+func (p *parkingMemory) CountParkingSpots(ctx context.Context, lotID uint) ([]entity.SpotCount, error) {
+	result := []entity.SpotCount{}
+
+	_ = p.store.Do(ctx, func() error {
+		// like GROUP BY floor, type
+		groups := map[entity.SpotCount]*entity.SpotCount{}
+		for _, s := range p.tables.spots {
+			if s.LotID != lotID {
+				continue
+			}
+
+			k := entity.SpotCount{Floor: s.Floor, Type: s.Type}
+			c, ok := groups[k]
+			if !ok {
+				c = &entity.SpotCount{Floor: s.Floor, Type: s.Type}
+				groups[k] = c
+			}
+
+			c.Add(entity.CountSpot(s))
+		}
+
+		for _, c := range groups {
+			result = append(result, *c)
+		}
+		return nil
+	})
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Floor != result[j].Floor {
+			return result[i].Floor < result[j].Floor
+		}
+		return result[i].Type < result[j].Type
+	})
+
+	return result, nil
+}
+
+func (p *parkingMemory) InsertParkingSpots(ctx context.Context, data []entity.ParkingSpot) ([]entity.ParkingSpot, error) {
+	err := p.store.Do(ctx, func() error {
+		// same guarantee as the unique_spot_position index
+		taken := map[entity.SpotID]bool{}
+		for _, s := range p.tables.spots {
+			taken[s.Position()] = true
+		}
+
+		for _, s := range data {
+			pos := s.Position()
+			if taken[pos] {
+				return x.NewWithCode(http.StatusInternalServerError, "failed to insert parking spots: duplicate position")
+			}
+			taken[pos] = true
+		}
+
+		for i := range data {
+			p.tables.nextSpotID++
+			data[i].ID = p.tables.nextSpotID
+			p.tables.spots = append(p.tables.spots, data[i])
+		}
+
+		return nil
+	})
+	if err == nil {
+		changes := make([]entity.SpotChange, 0, len(data))
+		for i := range data {
+			s := data[i]
+			changes = append(changes, spotChange(nil, &s))
+		}
+		p.publish(ctx, changes)
+	}
+
+	return data, err
+}
+
+func (p *parkingMemory) DeleteParkingSpots(ctx context.Context, data entity.DeleteParkingSpots) (int, error) {
+	var match func(s entity.ParkingSpot) bool
+
+	switch {
+	case data.LotID > 0 && data.Floor > 0:
+		match = func(s entity.ParkingSpot) bool { return s.LotID == data.LotID && s.Floor == data.Floor }
+	case len(data.IDs) > 0:
+		match = func(s entity.ParkingSpot) bool { return slices.Contains(data.IDs, s.ID) }
+	default:
+		return 0, x.NewWithCode(http.StatusBadRequest, "lot and floor, or ids are required")
+	}
+
+	var changes []entity.SpotChange
+
+	_ = p.store.Do(ctx, func() error {
+		kept := p.tables.spots[:0:0]
+		for _, s := range p.tables.spots {
+			if match(s) {
+				changes = append(changes, spotChange(&s, nil))
+				continue
+			}
+			kept = append(kept, s)
+		}
+		p.tables.spots = kept
+
+		return nil
+	})
+
+	p.publish(ctx, changes)
+
+	return len(changes), nil
+}
+
+func (p *parkingMemory) InsertVehicle(ctx context.Context, data entity.InsertVehicle) (entity.Vehicle, error) {
+	var vehicle entity.Vehicle
+
+	err := p.store.Do(ctx, func() error {
+		for _, v := range p.tables.vehicles {
+			if data.TicketID != "" && v.TicketID == data.TicketID {
+				return x.NewWithCode(http.StatusInternalServerError, "failed to insert vehicle: duplicate ticket_id")
+			}
+
+			// same guarantee as the unique_open_vehicle index
+			if v.VehicleNumber == data.VehicleNumber && v.UnparkedAt == nil {
+				return x.NewWithCode(http.StatusConflict, "vehicle is already parked")
+			}
+		}
+
+		p.tables.nextVehicleID++
+
+		vehicle = entity.Vehicle{
+			ID:            p.tables.nextVehicleID,
+			LotID:         data.LotID,
+			VehicleNumber: data.VehicleNumber,
+			VehicleType:   data.VehicleType,
+			SpotID:        data.SpotID,
+			TicketID:      data.TicketID,
+			ParkedAt:      time.Now(),
+			Status:        entity.SessionParked,
+		}
+		p.tables.vehicles = append(p.tables.vehicles, vehicle)
+
+		return nil
+	})
+
+	return vehicle, err
+}
+
+func (p *parkingMemory) UpdateParkingSpot(ctx context.Context, data entity.UpdateParkingSpot) error {
+	var match func(s entity.ParkingSpot) bool
+
+	// Build conditional match, same precedence as the SQL backend
+	if data.ID > 0 {
+		match = func(s entity.ParkingSpot) bool { return s.ID == data.ID }
+	} else if data.LotID > 0 && data.Floor > 0 && data.Row > 0 && data.Col > 0 {
+		match = func(s entity.ParkingSpot) bool {
+			return s.LotID == data.LotID && s.Floor == data.Floor && s.Row == data.Row && s.Col == data.Col
+		}
+	} else {
+		return x.NewWithCode(http.StatusBadRequest, "must provide either spot_id or (lot, floor, row, col)")
+	}
+
+	if data.Type == "" && data.Active == nil && data.Occupied == nil && data.Reserved == nil &&
+		data.EVCharger == nil && data.Accessible == nil {
+		return x.NewWithCode(http.StatusBadRequest, "no updates provided")
+	}
+
+	var changes []entity.SpotChange
+
+	_ = p.store.Do(ctx, func() error {
+		for i, s := range p.tables.spots {
+			if !match(s) {
+				continue
+			}
+			if data.Type != "" {
+				p.tables.spots[i].Type = data.Type
+			}
+			if data.Active != nil {
+				p.tables.spots[i].Active = *data.Active
+			}
+			if data.Occupied != nil {
+				p.tables.spots[i].Occupied = *data.Occupied
+			}
+			if data.Reserved != nil {
+				p.tables.spots[i].Reserved = *data.Reserved
+			}
+			if data.EVCharger != nil {
+				p.tables.spots[i].EVCharger = *data.EVCharger
+			}
+			if data.Accessible != nil {
+				p.tables.spots[i].Accessible = *data.Accessible
+			}
+
+			if updated := p.tables.spots[i]; updated != s {
+				changes = append(changes, spotChange(&s, &updated))
+			}
+		}
+
+		return nil
+	})
+
+	p.publish(ctx, changes)
+
+	return nil
+}
+
+func (p *parkingMemory) UpdateVehicle(ctx context.Context, data entity.UpdateVehicle) error {
+	if data.ID < 1 {
+		return x.NewWithCode(http.StatusBadRequest, "vehicle id is required")
+	}
+
+	if data.UnparkedAt == nil && data.Fee == nil && data.Status == "" && data.ExitRequestedAt == nil {
+		return x.NewWithCode(http.StatusBadRequest, "no updates provided")
+	}
+
+	return p.store.Do(ctx, func() error {
+		for i, v := range p.tables.vehicles {
+			if v.ID != data.ID {
+				continue
+			}
+			if data.UnparkedAt != nil {
+				unparkedAt := *data.UnparkedAt
+				p.tables.vehicles[i].UnparkedAt = &unparkedAt
+			}
+			if data.Fee != nil {
+				fee := *data.Fee
+				p.tables.vehicles[i].Fee = &fee
+			}
+			if data.Status != "" {
+				p.tables.vehicles[i].Status = data.Status
+			}
+			if data.ExitRequestedAt != nil {
+				requestedAt := *data.ExitRequestedAt
+				p.tables.vehicles[i].ExitRequestedAt = &requestedAt
+			}
+		}
+
+		return nil
+	})
+}
+
+func (p *parkingMemory) GetVehicle(ctx context.Context, data entity.SearchVehicle) (entity.Vehicle, error) {
+	var (
+		result entity.Vehicle
+		found  bool
+	)
+
+	_ = p.store.Do(ctx, func() error {
+		// newest first, like ORDER BY id DESC
+		for i := len(p.tables.vehicles) - 1; i >= 0; i-- {
+			v := p.tables.vehicles[i]
+			if data.ID > 0 && v.ID != data.ID {
+				continue
+			}
+
+			if data.LotID > 0 && v.LotID != data.LotID {
+				continue
+			}
+
+			if data.SpotID != "" && v.SpotID != data.SpotID {
+				continue
+			}
+
+			if data.VehicleNumber != "" && v.VehicleNumber != data.VehicleNumber {
+				continue
+			}
+
+			if data.TicketID != "" && v.TicketID != data.TicketID {
+				continue
+			}
+
+			result, found = v, true
+			return nil
+		}
+
+		return nil
+	})
+
+	if !found {
+		return result, x.NewWithCode(http.StatusNotFound, "vehicle not found")
+	}
+
+	return result, nil
+}
+
+func (p *parkingMemory) GetSessions(ctx context.Context, data entity.GetSessions) ([]entity.Vehicle, error) {
+	result := []entity.Vehicle{}
+
+	// same key as the SQL backend, parked_at or the duration in microseconds
+	key := func(v entity.Vehicle) int64 {
+		if data.Sort != entity.SessionSortDuration {
+			return v.ParkedAt.UnixMicro()
+		}
+		end := data.AsOf
+		if v.UnparkedAt != nil {
+			end = *v.UnparkedAt
+		}
+		return end.Sub(v.ParkedAt).Microseconds()
+	}
+
+	// before reports whether (ka, ida) comes first in the listing
+	before := func(ka int64, ida uint, kb int64, idb uint) bool {
+		if ka == kb {
+			return ida != idb && (ida < idb) != data.Desc
+		}
+		return (ka < kb) != data.Desc
+	}
+
+	_ = p.store.Do(ctx, func() error {
+		for _, v := range p.tables.vehicles {
+			if data.LotID > 0 && v.LotID != data.LotID {
+				continue
+			}
+			if data.VehicleNumber != "" && v.VehicleNumber != data.VehicleNumber {
+				continue
+			}
+			if data.VehicleType != "" && v.VehicleType != string(data.VehicleType) {
+				continue
+			}
+			if data.Floor > 0 && !strings.HasPrefix(v.SpotID, fmt.Sprintf("%d-%d-", data.LotID, data.Floor)) {
+				continue
+			}
+			if data.From != nil && v.UnparkedAt != nil && v.UnparkedAt.Before(*data.From) {
+				continue
+			}
+			if data.To != nil && !v.ParkedAt.Before(*data.To) {
+				continue
+			}
+			if data.Open != nil && *data.Open != (v.UnparkedAt == nil) {
+				continue
+			}
+			if data.MinFee != nil && (v.Fee == nil || *v.Fee < *data.MinFee) {
+				continue
+			}
+			if data.MaxFee != nil && (v.Fee == nil || *v.Fee > *data.MaxFee) {
+				continue
+			}
+			if data.After != nil && !before(data.After.Key, data.After.ID, key(v), v.ID) {
+				continue
+			}
+
+			result = append(result, v)
+		}
+		return nil
+	})
+
+	sort.Slice(result, func(i, j int) bool {
+		return before(key(result[i]), result[i].ID, key(result[j]), result[j].ID)
+	})
+
+	if data.Limit > 0 && len(result) > data.Limit {
+		result = result[:data.Limit]
+	}
+
+	return result, nil
+}
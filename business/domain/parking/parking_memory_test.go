@@ -0,0 +1,352 @@
+package parking_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zuhrulumam/go-parking-lot/business/domain/parking"
+	"github.com/zuhrulumam/go-parking-lot/business/domain/transaction"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+func newMemoryDomain() (parking.DomainItf, transaction.DomainItf) {
+	mem := memstore.New()
+
+	p := parking.InitParkingDomain(parking.Option{
+		Memory: mem,
+		Spots: []entity.ParkingSpot{
+			{LotID: 1, Floor: 1, Row: 1, Col: 1, Type: "A", Active: true},
+			{LotID: 1, Floor: 1, Row: 1, Col: 2, Type: "M", Active: true},
+			{LotID: 1, Floor: 1, Row: 1, Col: 3, Type: "A", Active: false},
+			{LotID: 1, Floor: 1, Row: 1, Col: 4, Type: "A", Active: true, Occupied: true},
+			{LotID: 2, Floor: 1, Row: 1, Col: 1, Type: "A", Active: true},
+		},
+	})
+
+	return p, transaction.Init(transaction.Option{Memory: mem})
+}
+
+func TestMemoryGetAvailableParkingSpot(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       entity.GetAvailableParkingSpot
+		expectedIDs []uint
+	}{
+		{
+			name: "active and free cars",
+			input: entity.GetAvailableParkingSpot{
+				LotID:       1,
+				VehicleType: entity.Automobile,
+				Active:      pkg.BoolPtr(true),
+				Occupied:    pkg.BoolPtr(false),
+			},
+			expectedIDs: []uint{1},
+		},
+		{
+			name: "same position in another lot",
+			input: entity.GetAvailableParkingSpot{
+				LotID: 2,
+				Floor: 1,
+				Row:   1,
+				Col:   1,
+			},
+			expectedIDs: []uint{5},
+		},
+		{
+			name:        "no filter",
+			input:       entity.GetAvailableParkingSpot{},
+			expectedIDs: []uint{1, 2, 3, 4, 5},
+		},
+		{
+			name: "no results found",
+			input: entity.GetAvailableParkingSpot{
+				VehicleType: entity.Bicycle,
+			},
+			expectedIDs: []uint{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, _ := newMemoryDomain()
+
+			result, err := d.GetAvailableParkingSpot(context.Background(), tt.input)
+			assert.NoError(t, err)
+
+			ids := []uint{}
+			for _, s := range result {
+				ids = append(ids, s.ID)
+			}
+			assert.Equal(t, tt.expectedIDs, ids)
+		})
+	}
+}
+
+func TestMemoryCountParkingSpots(t *testing.T) {
+	d, _ := newMemoryDomain()
+
+	counts, err := d.CountParkingSpots(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.SpotCount{
+		{Floor: 1, Type: "A", Total: 3, Active: 2, Occupied: 1, Free: 1},
+		{Floor: 1, Type: "M", Total: 1, Active: 1, Free: 1},
+	}, counts)
+
+	counts, err = d.CountParkingSpots(context.Background(), 3)
+	assert.NoError(t, err)
+	assert.Empty(t, counts)
+}
+
+func TestMemoryUpdateParkingSpot(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       entity.UpdateParkingSpot
+		expectError bool
+	}{
+		{
+			name:  "by ID",
+			input: entity.UpdateParkingSpot{ID: 1, Occupied: pkg.BoolPtr(true)},
+		},
+		{
+			name:  "by coordinates",
+			input: entity.UpdateParkingSpot{LotID: 1, Floor: 1, Row: 1, Col: 1, Occupied: pkg.BoolPtr(true)},
+		},
+		{
+			name:        "coordinates without lot",
+			input:       entity.UpdateParkingSpot{Floor: 1, Row: 1, Col: 1, Occupied: pkg.BoolPtr(true)},
+			expectError: true,
+		},
+		{
+			name:        "missing identifier",
+			input:       entity.UpdateParkingSpot{Occupied: pkg.BoolPtr(true)},
+			expectError: true,
+		},
+		{
+			name:        "no update values",
+			input:       entity.UpdateParkingSpot{ID: 1},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, _ := newMemoryDomain()
+
+			err := d.UpdateParkingSpot(context.Background(), tt.input)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			spots, _ := d.GetAvailableParkingSpot(context.Background(), entity.GetAvailableParkingSpot{
+				Occupied: pkg.BoolPtr(true),
+			})
+			assert.Len(t, spots, 2)
+		})
+	}
+}
+
+func TestMemoryVehicleLedger(t *testing.T) {
+	ctx := context.Background()
+	d, _ := newMemoryDomain()
+
+	_, err := d.GetVehicle(ctx, entity.SearchVehicle{VehicleNumber: "B1234XYZ"})
+	assert.Error(t, err)
+
+	first, err := d.InsertVehicle(ctx, entity.InsertVehicle{LotID: 1, VehicleNumber: "B1234XYZ", VehicleType: "A", SpotID: "1-1-1-1", TicketID: "t-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), first.ID)
+
+	_, err = d.InsertVehicle(ctx, entity.InsertVehicle{LotID: 2, VehicleNumber: "B1234XYZ", VehicleType: "A", SpotID: "2-1-1-1", TicketID: "t-2"})
+	assert.EqualValues(t, http.StatusConflict, x.ErrCode(err), "one open session per vehicle, across lots")
+
+	assert.NoError(t, d.UpdateVehicle(ctx, entity.UpdateVehicle{ID: first.ID, UnparkedAt: pkg.TimePtr(time.Now())}))
+
+	_, err = d.InsertVehicle(ctx, entity.InsertVehicle{LotID: 1, VehicleNumber: "B1234XYZ", VehicleType: "A", SpotID: "1-1-1-2", TicketID: "t-2"})
+	assert.NoError(t, err)
+
+	_, err = d.InsertVehicle(ctx, entity.InsertVehicle{LotID: 1, VehicleNumber: "B9999XYZ", VehicleType: "A", SpotID: "1-1-1-3", TicketID: "t-2"})
+	assert.Error(t, err, "ticket IDs are unique")
+
+	// latest session wins
+	v, err := d.GetVehicle(ctx, entity.SearchVehicle{VehicleNumber: "B1234XYZ"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), v.ID)
+	assert.Equal(t, "1-1-1-2", v.SpotID)
+
+	// lookup by ticket
+	v, err = d.GetVehicle(ctx, entity.SearchVehicle{TicketID: "t-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), v.ID)
+
+	// lookup by spot
+	v, err = d.GetVehicle(ctx, entity.SearchVehicle{LotID: 1, SpotID: "1-1-1-2"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), v.ID)
+
+	_, err = d.GetVehicle(ctx, entity.SearchVehicle{LotID: 1, SpotID: "1-1-1-3"})
+	assert.Error(t, err)
+
+	// sessions belong to their lot
+	_, err = d.GetVehicle(ctx, entity.SearchVehicle{LotID: 2, TicketID: "t-1"})
+	assert.Error(t, err)
+
+	v, err = d.GetVehicle(ctx, entity.SearchVehicle{LotID: 1, TicketID: "t-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), v.LotID)
+
+	v, err = d.GetVehicle(ctx, entity.SearchVehicle{VehicleNumber: "B1234XYZ"})
+	assert.NoError(t, err)
+
+	assert.Error(t, d.UpdateVehicle(ctx, entity.UpdateVehicle{UnparkedAt: pkg.TimePtr(time.Now())}))
+	assert.Error(t, d.UpdateVehicle(ctx, entity.UpdateVehicle{ID: v.ID}))
+	assert.NoError(t, d.UpdateVehicle(ctx, entity.UpdateVehicle{ID: v.ID, UnparkedAt: pkg.TimePtr(time.Now())}))
+
+	v, err = d.GetVehicle(ctx, entity.SearchVehicle{VehicleNumber: "B1234XYZ"})
+	assert.NoError(t, err)
+	assert.NotNil(t, v.UnparkedAt)
+}
+
+func TestMemoryGetSessions(t *testing.T) {
+	ctx := context.Background()
+	d, _ := newMemoryDomain()
+
+	// three closed stays of 3h, 1h and 2h, then an open one
+	var parkedAt []int64
+	for i, stay := range []time.Duration{3 * time.Hour, time.Hour, 2 * time.Hour, 0} {
+		v, err := d.InsertVehicle(ctx, entity.InsertVehicle{
+			LotID:         1,
+			VehicleNumber: "B1234XYZ",
+			VehicleType:   "A",
+			SpotID:        fmt.Sprintf("1-%d-1-1", i%2+1),
+		})
+		assert.NoError(t, err)
+		parkedAt = append(parkedAt, v.ParkedAt.UnixMicro())
+
+		if stay > 0 {
+			fee := int64(stay / time.Hour * 1000)
+			assert.NoError(t, d.UpdateVehicle(ctx, entity.UpdateVehicle{ID: v.ID, Fee: &fee, UnparkedAt: pkg.TimePtr(v.ParkedAt.Add(stay))}))
+		}
+	}
+
+	_, err := d.InsertVehicle(ctx, entity.InsertVehicle{LotID: 2, VehicleNumber: "B5678XYZ", VehicleType: "A", SpotID: "2-1-1-1"})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		input       entity.GetSessions
+		expectedIDs []uint
+	}{
+		{
+			name:        "oldest first",
+			input:       entity.GetSessions{LotID: 1},
+			expectedIDs: []uint{1, 2, 3, 4},
+		},
+		{
+			name:        "newest first, past the cursor",
+			input:       entity.GetSessions{LotID: 1, Desc: true, After: &entity.SessionCursor{Key: parkedAt[2], ID: 3}},
+			expectedIDs: []uint{2, 1},
+		},
+		{
+			name:        "floor",
+			input:       entity.GetSessions{LotID: 1, Floor: 2, Limit: 1},
+			expectedIDs: []uint{2},
+		},
+		{
+			name:        "closed with fee range",
+			input:       entity.GetSessions{LotID: 1, Open: pkg.BoolPtr(false), MinFee: pkg.Int64Ptr(2000), MaxFee: pkg.Int64Ptr(3000)},
+			expectedIDs: []uint{1, 3},
+		},
+		{
+			name:        "open",
+			input:       entity.GetSessions{LotID: 1, Open: pkg.BoolPtr(true)},
+			expectedIDs: []uint{4},
+		},
+		{
+			name:        "longest first",
+			input:       entity.GetSessions{LotID: 1, Open: pkg.BoolPtr(false), Sort: entity.SessionSortDuration, Desc: true},
+			expectedIDs: []uint{1, 3, 2},
+		},
+		{
+			name: "shortest first, past the cursor",
+			input: entity.GetSessions{LotID: 1, Open: pkg.BoolPtr(false), Sort: entity.SessionSortDuration,
+				After: &entity.SessionCursor{Key: time.Hour.Microseconds(), ID: 2}},
+			expectedIDs: []uint{3, 1},
+		},
+		{
+			name:        "other lot",
+			input:       entity.GetSessions{LotID: 2},
+			expectedIDs: []uint{5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := d.GetSessions(ctx, tt.input)
+			assert.NoError(t, err)
+
+			ids := []uint{}
+			for _, v := range result {
+				ids = append(ids, v.ID)
+			}
+			assert.Equal(t, tt.expectedIDs, ids)
+		})
+	}
+}
+
+func TestMemoryRunInTx(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("commit keeps changes", func(t *testing.T) {
+		d, tx := newMemoryDomain()
+
+		err := tx.RunInTx(ctx, func(ctx context.Context) error {
+			if err := d.UpdateParkingSpot(ctx, entity.UpdateParkingSpot{ID: 1, Occupied: pkg.BoolPtr(true)}); err != nil {
+				return err
+			}
+			_, err := d.InsertVehicle(ctx, entity.InsertVehicle{VehicleNumber: "B1234XYZ", VehicleType: "A", SpotID: "1-1-1-1"})
+			return err
+		})
+		assert.NoError(t, err)
+
+		_, err = d.GetVehicle(ctx, entity.SearchVehicle{VehicleNumber: "B1234XYZ"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("error rolls back", func(t *testing.T) {
+		d, tx := newMemoryDomain()
+
+		err := tx.RunInTx(ctx, func(ctx context.Context) error {
+			if err := d.UpdateParkingSpot(ctx, entity.UpdateParkingSpot{ID: 1, Occupied: pkg.BoolPtr(true)}); err != nil {
+				return err
+			}
+			if _, err := d.InsertVehicle(ctx, entity.InsertVehicle{VehicleNumber: "B1234XYZ", VehicleType: "A", SpotID: "1-1-1-1"}); err != nil {
+				return err
+			}
+			return errors.New("boom")
+		})
+		assert.Error(t, err)
+
+		_, err = d.GetVehicle(ctx, entity.SearchVehicle{VehicleNumber: "B1234XYZ"})
+		assert.Error(t, err)
+
+		spots, _ := d.GetAvailableParkingSpot(ctx, entity.GetAvailableParkingSpot{
+			LotID:       1,
+			VehicleType: entity.Automobile,
+			Active:      pkg.BoolPtr(true),
+			Occupied:    pkg.BoolPtr(false),
+		})
+		assert.Len(t, spots, 1)
+	})
+}
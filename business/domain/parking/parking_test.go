@@ -2,16 +2,22 @@ package parking_test
 
 import (
 	"context"
+	"database/sql/driver"
 	"errors"
+	"net/http"
 	"regexp"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"github.com/zuhrulumam/go-parking-lot/business/domain/parking"
+	"github.com/zuhrulumam/go-parking-lot/business/domain/transaction"
 	"github.com/zuhrulumam/go-parking-lot/business/entity"
 	"github.com/zuhrulumam/go-parking-lot/pkg"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
 )
 
 func TestGetAvailableParkingSpot(t *testing.T) {
@@ -49,6 +55,21 @@ func TestGetAvailableParkingSpot(t *testing.T) {
 			expectError:  false,
 			expectedData: []entity.ParkingSpot{},
 		},
+		{
+			name: "Lock skipping spots held elsewhere",
+			input: entity.GetAvailableParkingSpot{
+				LotID:      1,
+				Floor:      1,
+				Row:        1,
+				Col:        2,
+				UseLock:    true,
+				SkipLocked: true,
+			},
+			mockQuery:    `SELECT \* FROM "parking_spots" WHERE .* FOR UPDATE SKIP LOCKED`,
+			mockRows:     sqlmock.NewRows([]string{"id", "floor", "row", "col", "type", "occupied", "active"}),
+			expectError:  false,
+			expectedData: []entity.ParkingSpot{},
+		},
 	}
 
 	for _, tt := range tests {
@@ -77,25 +98,44 @@ func TestInsertVehicle(t *testing.T) {
 	tests := []struct {
 		name        string
 		input       entity.InsertVehicle
+		insertErr   error
 		expectError bool
+		expectCode  int
 	}{
 		{
 			name: "Success insert vehicle",
 			input: entity.InsertVehicle{
+				LotID:         1,
 				VehicleNumber: "B1234XYZ",
 				VehicleType:   "car",
-				SpotID:        "1-1-1",
+				SpotID:        "1-1-1-1",
+				TicketID:      "5f0c6f43-3b0a-4d55-9a43-6d3f3b1f1a11",
 			},
 			expectError: false,
 		},
 		{
 			name: "Error on insert",
 			input: entity.InsertVehicle{
+				LotID:         1,
 				VehicleNumber: "INVALID",
 				VehicleType:   "motor",
-				SpotID:        "1-1-2",
+				SpotID:        "1-1-1-2",
+			},
+			insertErr:   errors.New("insert failed"),
+			expectError: true,
+			expectCode:  http.StatusInternalServerError,
+		},
+		{
+			name: "Vehicle already parked",
+			input: entity.InsertVehicle{
+				LotID:         2,
+				VehicleNumber: "B1234XYZ",
+				VehicleType:   "A",
+				SpotID:        "2-1-1-1",
 			},
+			insertErr:   &pgconn.PgError{Code: "23505", ConstraintName: "unique_open_vehicle"},
 			expectError: true,
+			expectCode:  http.StatusConflict,
 		},
 	}
 
@@ -107,13 +147,13 @@ func TestInsertVehicle(t *testing.T) {
 			// Simulate insert behavior
 			if tt.expectError {
 				mock.ExpectBegin()
-				mock.ExpectExec(`INSERT INTO "vehicles"`).
-					WillReturnError(errors.New("insert failed"))
+				mock.ExpectQuery(`INSERT INTO "vehicles"`).
+					WillReturnError(tt.insertErr)
 				mock.ExpectRollback()
 			} else {
 				mock.ExpectBegin()
 				mock.ExpectQuery(`INSERT INTO "vehicles"`).
-					WithArgs(tt.input.VehicleNumber, tt.input.VehicleType, tt.input.SpotID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WithArgs(tt.input.LotID, tt.input.VehicleNumber, tt.input.VehicleType, tt.input.SpotID, tt.input.TicketID, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), entity.SessionParked, sqlmock.AnyArg()).
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 				mock.ExpectCommit()
 			}
@@ -122,12 +162,16 @@ func TestInsertVehicle(t *testing.T) {
 
 			tx := db.Begin()
 			ctx := context.WithValue(context.Background(), pkg.TxCtxValue, tx)
-			err := d.InsertVehicle(ctx, tt.input)
+			vehicle, err := d.InsertVehicle(ctx, tt.input)
 
 			if tt.expectError {
 				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectCode, x.ErrCode(err))
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, uint(1), vehicle.ID)
+				assert.Equal(t, tt.input.TicketID, vehicle.TicketID)
+				assert.Equal(t, tt.input.LotID, vehicle.LotID)
 			}
 		})
 	}
@@ -150,6 +194,7 @@ func TestUpdateParkingSpot(t *testing.T) {
 		{
 			name: "Success by coordinates",
 			input: entity.UpdateParkingSpot{
+				LotID:    1,
 				Floor:    1,
 				Row:      2,
 				Col:      3,
@@ -164,6 +209,16 @@ func TestUpdateParkingSpot(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name: "Coordinates without lot",
+			input: entity.UpdateParkingSpot{
+				Floor:    1,
+				Row:      2,
+				Col:      3,
+				Occupied: pkg.BoolPtr(false),
+			},
+			expectError: true,
+		},
 		{
 			name: "No update values",
 			input: entity.UpdateParkingSpot{
@@ -194,7 +249,7 @@ func TestUpdateParkingSpot(t *testing.T) {
 
 				mock.ExpectCommit()
 			} else {
-				if tt.input.ID == 0 && (tt.input.Floor == 0 || tt.input.Row == 0 || tt.input.Col == 0) {
+				if tt.input.ID == 0 && (tt.input.LotID == 0 || tt.input.Floor == 0 || tt.input.Row == 0 || tt.input.Col == 0) {
 					// No DB interaction if input is invalid
 				} else if tt.input.Occupied == nil {
 					// No DB interaction if no update values
@@ -310,6 +365,7 @@ func TestGetVehicle(t *testing.T) {
 		{
 			name: "Success",
 			input: entity.SearchVehicle{
+				LotID:         1,
 				VehicleNumber: "B123XYZ",
 			},
 			expectError: false,
@@ -317,13 +373,14 @@ func TestGetVehicle(t *testing.T) {
 				ID:            1,
 				VehicleNumber: "B123XYZ",
 				VehicleType:   "car",
-				SpotID:        "1-2-3",
+				SpotID:        "1-1-2-3",
 				ParkedAt:      now,
 			},
 		},
 		{
 			name: "DB Error",
 			input: entity.SearchVehicle{
+				LotID:         1,
 				VehicleNumber: "ERR123",
 			},
 			expectError:  true,
@@ -337,16 +394,16 @@ func TestGetVehicle(t *testing.T) {
 			db, mock, cleanup := pkg.SetupMockDB(t)
 			defer cleanup()
 
-			query := `SELECT * FROM "vehicles" WHERE vehicle_number = $1 ORDER BY id DESC,"vehicles"."id" LIMIT $2`
+			query := `SELECT * FROM "vehicles" WHERE lot_id = $1 AND vehicle_number = $2 ORDER BY id DESC,"vehicles"."id" LIMIT $3`
 			if tt.mockResponse != nil {
 				rows := sqlmock.NewRows([]string{"id", "vehicle_number", "vehicle_type", "spot_id", "parked_at"}).
 					AddRow(tt.mockResponse.ID, tt.mockResponse.VehicleNumber, tt.mockResponse.VehicleType, tt.mockResponse.SpotID, tt.mockResponse.ParkedAt)
 				mock.ExpectQuery(regexp.QuoteMeta(query)).
-					WithArgs(tt.input.VehicleNumber, 1).
+					WithArgs(tt.input.LotID, tt.input.VehicleNumber, 1).
 					WillReturnRows(rows)
 			} else {
 				mock.ExpectQuery(regexp.QuoteMeta(query)).
-					WithArgs(tt.input.VehicleNumber, 1).
+					WithArgs(tt.input.LotID, tt.input.VehicleNumber, 1).
 					WillReturnError(tt.mockError)
 			}
 
@@ -363,3 +420,191 @@ func TestGetVehicle(t *testing.T) {
 		})
 	}
 }
+
+func TestGetVehicleBySpot(t *testing.T) {
+	db, mock, cleanup := pkg.SetupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "vehicles" WHERE lot_id = $1 AND spot_id = $2 ORDER BY id DESC,"vehicles"."id" LIMIT $3`)).
+		WithArgs(1, "1-1-2-3", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "spot_id"}).AddRow(4, "1-1-2-3"))
+
+	d := parking.InitParkingDomain(parking.Option{DB: db})
+	v, err := d.GetVehicle(context.Background(), entity.SearchVehicle{LotID: 1, SpotID: "1-1-2-3"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(4), v.ID)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetVehicleForUpdate(t *testing.T) {
+	db, mock, cleanup := pkg.SetupMockDB(t)
+	defer cleanup()
+
+	// read in the transaction of ctx, locked until it ends
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "vehicles" WHERE lot_id = $1 AND ticket_id = $2 ORDER BY id DESC,"vehicles"."id" LIMIT $3 FOR UPDATE`)).
+		WithArgs(1, "TCK-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "ticket_id"}).AddRow(4, "TCK-1"))
+	mock.ExpectCommit()
+
+	d := parking.InitParkingDomain(parking.Option{DB: db})
+	tx := transaction.Init(transaction.Option{DB: db})
+
+	err := tx.RunInTx(context.Background(), func(ctx context.Context) error {
+		v, err := d.GetVehicle(ctx, entity.SearchVehicle{LotID: 1, TicketID: "TCK-1", UseLock: true})
+		assert.Equal(t, uint(4), v.ID)
+		return err
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetParkingSpotsOfFloor(t *testing.T) {
+	db, mock, cleanup := pkg.SetupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "parking_spots" WHERE lot_id = $1 AND floor = $2 ORDER BY id FOR UPDATE`)).
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "floor", "row", "col"}).AddRow(7, 2, 1, 1))
+
+	d := parking.InitParkingDomain(parking.Option{DB: db})
+	result, err := d.GetAvailableParkingSpot(context.Background(), entity.GetAvailableParkingSpot{LotID: 1, Floor: 2, UseLock: true})
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertParkingSpots(t *testing.T) {
+	db, mock, cleanup := pkg.SetupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "parking_spots"`).
+		WithArgs(1, 2, 1, 1, "A", true, false, false, true, false, 1, 2, 1, 2, "B", true, false, false, false, true).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10).AddRow(11))
+	mock.ExpectCommit()
+
+	d := parking.InitParkingDomain(parking.Option{DB: db})
+	spots, err := d.InsertParkingSpots(context.Background(), []entity.ParkingSpot{
+		{LotID: 1, Floor: 2, Row: 1, Col: 1, Type: "A", Active: true, EVCharger: true},
+		{LotID: 1, Floor: 2, Row: 1, Col: 2, Type: "B", Active: true, Accessible: true},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(10), spots[0].ID)
+	assert.Equal(t, uint(11), spots[1].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteParkingSpots(t *testing.T) {
+	db, mock, cleanup := pkg.SetupMockDB(t)
+	defer cleanup()
+
+	d := parking.InitParkingDomain(parking.Option{DB: db})
+
+	_, err := d.DeleteParkingSpots(context.Background(), entity.DeleteParkingSpots{})
+	assert.Error(t, err)
+
+	// a floor number alone would hit every lot
+	_, err = d.DeleteParkingSpots(context.Background(), entity.DeleteParkingSpots{Floor: 3})
+	assert.Error(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "parking_spots" WHERE lot_id = $1 AND floor = $2`)).
+		WithArgs(1, 3).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectCommit()
+
+	n, err := d.DeleteParkingSpots(context.Background(), entity.DeleteParkingSpots{LotID: 1, Floor: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetSessions(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	asOf := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		input entity.GetSessions
+		query string
+		args  []driver.Value
+	}{
+		{
+			name:  "newest first",
+			input: entity.GetSessions{LotID: 1, VehicleNumber: "B1234XYZ", Desc: true, Limit: 3},
+			query: `SELECT * FROM "vehicles" WHERE lot_id = $1 AND vehicle_number = $2 ORDER BY parked_at DESC, id DESC LIMIT $3`,
+			args:  []driver.Value{1, "B1234XYZ", 3},
+		},
+		{
+			name: "filtered, past the cursor",
+			input: entity.GetSessions{
+				LotID:       1,
+				VehicleType: entity.Automobile,
+				Floor:       2,
+				From:        &from,
+				Open:        pkg.BoolPtr(false),
+				MinFee:      pkg.Int64Ptr(1000),
+				After:       &entity.SessionCursor{Key: from.UnixMicro(), ID: 7},
+				Limit:       3,
+			},
+			query: `SELECT * FROM "vehicles" WHERE lot_id = $1 AND vehicle_type = $2 AND spot_id LIKE $3 AND (unparked_at IS NULL OR unparked_at >= $4) AND unparked_at IS NOT NULL AND fee >= $5 AND (parked_at, id) > ($6, $7) ORDER BY parked_at ASC, id ASC LIMIT $8`,
+			args:  []driver.Value{1, "A", "1-2-%", from, 1000, sqlmock.AnyArg(), 7, 3},
+		},
+		{
+			name: "longest first",
+			input: entity.GetSessions{
+				LotID: 1,
+				Sort:  entity.SessionSortDuration,
+				Desc:  true,
+				AsOf:  asOf,
+				After: &entity.SessionCursor{Key: 3600000000, ID: 7},
+			},
+			query: `SELECT * FROM "vehicles" WHERE lot_id = $1 AND (CAST(EXTRACT(EPOCH FROM (COALESCE(unparked_at, $2) - parked_at)) * 1000000 AS BIGINT), id) < ($3, $4) ORDER BY CAST(EXTRACT(EPOCH FROM (COALESCE(unparked_at, $5) - parked_at)) * 1000000 AS BIGINT) DESC, id DESC`,
+			args:  []driver.Value{1, asOf, 3600000000, 7, asOf},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, cleanup := pkg.SetupMockDB(t)
+			defer cleanup()
+
+			mock.ExpectQuery(regexp.QuoteMeta(tt.query)).
+				WithArgs(tt.args...).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "vehicle_number"}).AddRow(8, "B1234XYZ"))
+
+			d := parking.InitParkingDomain(parking.Option{DB: db})
+			result, err := d.GetSessions(context.Background(), tt.input)
+			assert.NoError(t, err)
+			assert.Len(t, result, 1)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCountParkingSpots(t *testing.T) {
+	db, mock, cleanup := pkg.SetupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`SELECT floor, type,\s+COUNT\(\*\) AS total,\s+COUNT\(\*\) FILTER \(WHERE active\) AS active,.+FROM "parking_spots" WHERE lot_id = \$1 GROUP BY floor, type ORDER BY floor, type`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"floor", "type", "total", "active", "occupied", "reserved", "free"}).
+			AddRow(1, "A", 10, 9, 4, 1, 4).
+			AddRow(1, "M", 5, 5, 0, 0, 5))
+
+	d := parking.InitParkingDomain(parking.Option{DB: db})
+	counts, err := d.CountParkingSpots(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.SpotCount{
+		{Floor: 1, Type: "A", Total: 10, Active: 9, Occupied: 4, Reserved: 1, Free: 4},
+		{Floor: 1, Type: "M", Total: 5, Active: 5, Free: 5},
+	}, counts)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
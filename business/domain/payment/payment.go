@@ -0,0 +1,37 @@
+package payment
+
+import (
+	"context"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+	"gorm.io/gorm"
+)
+
+//go:generate mockgen -source=business/domain/payment/payment.go -destination=mocks/domain/payment/mock_payment.go -package=mocks
+type DomainItf interface {
+	InsertPayment(ctx context.Context, data entity.Payment) (entity.Payment, error)
+	GetPayments(ctx context.Context, data entity.GetPayments) ([]entity.Payment, error)
+	UpdatePayment(ctx context.Context, data entity.UpdatePayment) error
+}
+
+type payment struct {
+	db *gorm.DB
+}
+
+type Option struct {
+	DB *gorm.DB
+
+	// Memory switches the domain to the in-memory backend.
+	Memory *memstore.Store
+}
+
+func InitPaymentDomain(opt Option) DomainItf {
+	if opt.Memory != nil {
+		return initPaymentMemory(opt)
+	}
+
+	return &payment{
+		db: opt.DB,
+	}
+}
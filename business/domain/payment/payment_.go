@@ -0,0 +1,98 @@
+package payment
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"gorm.io/gorm/clause"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+func (p *payment) InsertPayment(ctx context.Context, data entity.Payment) (entity.Payment, error) {
+	db := pkg.GetTransactionFromCtx(ctx, p.db)
+
+	data.ID = 0
+	data.CreatedAt = time.Now()
+
+	if err := db.WithContext(ctx).Create(&data).Error; err != nil {
+		return data, x.WrapWithCode(err, http.StatusInternalServerError, "failed to insert payment")
+	}
+
+	return data, nil
+}
+
+func (p *payment) GetPayments(ctx context.Context, data entity.GetPayments) ([]entity.Payment, error) {
+	var (
+		result []entity.Payment
+		db     = pkg.GetTransactionFromCtx(ctx, p.db)
+	)
+
+	db = db.WithContext(ctx).Model(&entity.Payment{})
+
+	if data.ID > 0 {
+		db = db.Where("id = ?", data.ID)
+	}
+
+	if data.LotID > 0 {
+		db = db.Where("lot_id = ?", data.LotID)
+	}
+
+	if data.VehicleID > 0 {
+		db = db.Where("vehicle_id = ?", data.VehicleID)
+	}
+
+	if data.TicketID != "" {
+		db = db.Where("ticket_id = ?", data.TicketID)
+	}
+
+	if data.RefundOf > 0 {
+		db = db.Where("refund_of = ?", data.RefundOf)
+	}
+
+	// if use lock
+	if data.UseLock {
+		db = db.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+
+	if err := db.Order("id").Find(&result).Error; err != nil {
+		return result, x.WrapWithCode(err, http.StatusInternalServerError, "failed get payments")
+	}
+
+	return result, nil
+}
+
+func (p *payment) UpdatePayment(ctx context.Context, data entity.UpdatePayment) error {
+	db := pkg.GetTransactionFromCtx(ctx, p.db)
+
+	if data.ID < 1 {
+		return x.NewWithCode(http.StatusBadRequest, "payment id is required")
+	}
+
+	updates := map[string]interface{}{}
+	if data.Status != "" {
+		updates["status"] = data.Status
+	}
+	if data.Reference != "" {
+		updates["reference"] = data.Reference
+	}
+	if data.Note != "" {
+		updates["note"] = data.Note
+	}
+
+	if len(updates) == 0 {
+		return x.NewWithCode(http.StatusBadRequest, "no updates provided")
+	}
+
+	if err := db.WithContext(ctx).
+		Model(&entity.Payment{}).
+		Where("id = ?", data.ID).
+		Updates(updates).Error; err != nil {
+		return x.WrapWithCode(err, http.StatusInternalServerError, "failed to update payment")
+	}
+
+	return nil
+}
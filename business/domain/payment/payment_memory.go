@@ -0,0 +1,112 @@
+package payment
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+type paymentMemory struct {
+	store  *memstore.Store
+	tables *paymentTables
+}
+
+type paymentTables struct {
+	payments []entity.Payment
+	nextID   uint
+}
+
+func (t *paymentTables) Snapshot() func() {
+	payments := append([]entity.Payment(nil), t.payments...)
+	nextID := t.nextID
+
+	return func() {
+		t.payments = payments
+		t.nextID = nextID
+	}
+}
+
+func initPaymentMemory(opt Option) DomainItf {
+	tables := &paymentTables{}
+	opt.Memory.Register(tables)
+
+	return &paymentMemory{
+		store:  opt.Memory,
+		tables: tables,
+	}
+}
+
+func (p *paymentMemory) InsertPayment(ctx context.Context, data entity.Payment) (entity.Payment, error) {
+	err := p.store.Do(ctx, func() error {
+		p.tables.nextID++
+		data.ID = p.tables.nextID
+		data.CreatedAt = time.Now()
+		p.tables.payments = append(p.tables.payments, data)
+		return nil
+	})
+
+	return data, err
+}
+
+func (p *paymentMemory) GetPayments(ctx context.Context, data entity.GetPayments) ([]entity.Payment, error) {
+	result := []entity.Payment{}
+
+	_ = p.store.Do(ctx, func() error {
+		for _, v := range p.tables.payments {
+			if data.ID > 0 && v.ID != data.ID {
+				continue
+			}
+			if data.LotID > 0 && v.LotID != data.LotID {
+				continue
+			}
+			if data.VehicleID > 0 && v.VehicleID != data.VehicleID {
+				continue
+			}
+			if data.TicketID != "" && v.TicketID != data.TicketID {
+				continue
+			}
+			if data.RefundOf > 0 && (v.RefundOf == nil || *v.RefundOf != data.RefundOf) {
+				continue
+			}
+
+			result = append(result, v)
+		}
+		return nil
+	})
+
+	return result, nil
+}
+
+func (p *paymentMemory) UpdatePayment(ctx context.Context, data entity.UpdatePayment) error {
+	if data.ID < 1 {
+		return x.NewWithCode(http.StatusBadRequest, "payment id is required")
+	}
+
+	if data.Status == "" && data.Reference == "" && data.Note == "" {
+		return x.NewWithCode(http.StatusBadRequest, "no updates provided")
+	}
+
+	return p.store.Do(ctx, func() error {
+		for i, v := range p.tables.payments {
+			if v.ID != data.ID {
+				continue
+			}
+			if data.Status != "" {
+				p.tables.payments[i].Status = data.Status
+			}
+			if data.Reference != "" {
+				p.tables.payments[i].Reference = data.Reference
+			}
+			if data.Note != "" {
+				p.tables.payments[i].Note = data.Note
+			}
+		}
+
+		return nil
+	})
+}
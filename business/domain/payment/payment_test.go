@@ -0,0 +1,160 @@
+package payment_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zuhrulumam/go-parking-lot/business/domain/payment"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+)
+
+func TestInsertPayment(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockError   error
+		expectError bool
+	}{
+		{
+			name: "Success",
+		},
+		{
+			name:        "DB Error",
+			mockError:   errors.New("insert failed"),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, cleanup := pkg.SetupMockDB(t)
+			defer cleanup()
+
+			mock.ExpectBegin()
+			q := mock.ExpectQuery(`INSERT INTO "payments"`).
+				WithArgs(uint(1), uint(1), "t-1", entity.PaymentCharge, entity.PaymentSucceeded, "cash", int64(2000), "ref-1", nil, "", sqlmock.AnyArg())
+			if tt.mockError != nil {
+				q.WillReturnError(tt.mockError)
+				mock.ExpectRollback()
+			} else {
+				q.WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+				mock.ExpectCommit()
+			}
+
+			d := payment.InitPaymentDomain(payment.Option{DB: db})
+			result, err := d.InsertPayment(context.Background(), entity.Payment{
+				LotID:     1,
+				VehicleID: 1,
+				TicketID:  "t-1",
+				Kind:      entity.PaymentCharge,
+				Status:    entity.PaymentSucceeded,
+				Provider:  "cash",
+				Amount:    2000,
+				Reference: "ref-1",
+			})
+
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, uint(7), result.ID)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetPayments(t *testing.T) {
+	db, mock, cleanup := pkg.SetupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`SELECT \* FROM "payments" WHERE vehicle_id = \$1 AND refund_of = \$2 ORDER BY id`).
+		WithArgs(1, 7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "kind", "amount"}).AddRow(8, entity.PaymentRefund, 500))
+
+	d := payment.InitPaymentDomain(payment.Option{DB: db})
+	result, err := d.GetPayments(context.Background(), entity.GetPayments{VehicleID: 1, RefundOf: 7})
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, int64(500), result[0].Amount)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPaymentsForUpdate(t *testing.T) {
+	db, mock, cleanup := pkg.SetupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "payments" WHERE id = $1 AND lot_id = $2 ORDER BY id FOR UPDATE`)).
+		WithArgs(7, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "kind", "amount"}).AddRow(7, entity.PaymentCharge, 2000))
+
+	d := payment.InitPaymentDomain(payment.Option{DB: db})
+	result, err := d.GetPayments(context.Background(), entity.GetPayments{ID: 7, LotID: 1, UseLock: true})
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdatePayment(t *testing.T) {
+	db, mock, cleanup := pkg.SetupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "payments" SET "reference"=$1,"status"=$2 WHERE id = $3`)).
+		WithArgs("re_1", entity.PaymentSucceeded, 8).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	d := payment.InitPaymentDomain(payment.Option{DB: db})
+	err := d.UpdatePayment(context.Background(), entity.UpdatePayment{ID: 8, Status: entity.PaymentSucceeded, Reference: "re_1"})
+	assert.NoError(t, err)
+
+	assert.Error(t, d.UpdatePayment(context.Background(), entity.UpdatePayment{ID: 8}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMemoryPayments(t *testing.T) {
+	ctx := context.Background()
+	mem := memstore.New()
+	d := payment.InitPaymentDomain(payment.Option{Memory: mem})
+
+	charge, err := d.InsertPayment(ctx, entity.Payment{VehicleID: 1, TicketID: "t-1", Kind: entity.PaymentCharge, Amount: 2000})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), charge.ID)
+
+	_, err = d.InsertPayment(ctx, entity.Payment{VehicleID: 2, TicketID: "t-2", Kind: entity.PaymentCharge, Amount: 1000})
+	assert.NoError(t, err)
+
+	// rolled back inserts don't reuse or leak IDs
+	err = mem.RunInTx(ctx, func(ctx context.Context) error {
+		if _, err := d.InsertPayment(ctx, entity.Payment{VehicleID: 1, Kind: entity.PaymentRefund, Amount: 500, RefundOf: &charge.ID}); err != nil {
+			return err
+		}
+		return errors.New("boom")
+	})
+	assert.Error(t, err)
+
+	refund, err := d.InsertPayment(ctx, entity.Payment{VehicleID: 1, Kind: entity.PaymentRefund, Amount: 500, RefundOf: &charge.ID})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), refund.ID)
+
+	result, err := d.GetPayments(ctx, entity.GetPayments{VehicleID: 1})
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+
+	result, err = d.GetPayments(ctx, entity.GetPayments{RefundOf: charge.ID})
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+
+	result, err = d.GetPayments(ctx, entity.GetPayments{TicketID: "t-2"})
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+}
@@ -0,0 +1,37 @@
+package reservation
+
+import (
+	"context"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+	"gorm.io/gorm"
+)
+
+//go:generate mockgen -source=business/domain/reservation/reservation.go -destination=mocks/domain/reservation/mock_reservation.go -package=mocks
+type DomainItf interface {
+	InsertReservation(ctx context.Context, data entity.Reservation) (entity.Reservation, error)
+	GetReservations(ctx context.Context, data entity.GetReservations) ([]entity.Reservation, error)
+	UpdateReservation(ctx context.Context, data entity.UpdateReservation) error
+}
+
+type reservation struct {
+	db *gorm.DB
+}
+
+type Option struct {
+	DB *gorm.DB
+
+	// Memory switches the domain to the in-memory backend.
+	Memory *memstore.Store
+}
+
+func InitReservationDomain(opt Option) DomainItf {
+	if opt.Memory != nil {
+		return initReservationMemory(opt)
+	}
+
+	return &reservation{
+		db: opt.DB,
+	}
+}
@@ -0,0 +1,91 @@
+package reservation
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"gorm.io/gorm/clause"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+func (r *reservation) InsertReservation(ctx context.Context, data entity.Reservation) (entity.Reservation, error) {
+	db := pkg.GetTransactionFromCtx(ctx, r.db)
+
+	data.ID = 0
+	data.CreatedAt = time.Now()
+
+	if err := db.WithContext(ctx).Create(&data).Error; err != nil {
+		return data, x.WrapWithCode(err, http.StatusInternalServerError, "failed to insert reservation")
+	}
+
+	return data, nil
+}
+
+func (r *reservation) GetReservations(ctx context.Context, data entity.GetReservations) ([]entity.Reservation, error) {
+	var (
+		result []entity.Reservation
+		db     = pkg.GetTransactionFromCtx(ctx, r.db)
+	)
+
+	db = db.WithContext(ctx).Model(&entity.Reservation{})
+
+	if data.LotID > 0 {
+		db = db.Where("lot_id = ?", data.LotID)
+	}
+
+	if data.Code != "" {
+		db = db.Where("code = ?", data.Code)
+	}
+
+	if data.Status != "" {
+		db = db.Where("status = ?", data.Status)
+	}
+
+	if data.EndsBefore != nil {
+		db = db.Where("ends_at < ?", *data.EndsBefore)
+	}
+
+	if data.UseLock {
+		db = db.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+
+	if err := db.Order("id").Find(&result).Error; err != nil {
+		return result, x.WrapWithCode(err, http.StatusInternalServerError, "failed get reservations")
+	}
+
+	return result, nil
+}
+
+func (r *reservation) UpdateReservation(ctx context.Context, data entity.UpdateReservation) error {
+	db := pkg.GetTransactionFromCtx(ctx, r.db)
+
+	if data.ID < 1 {
+		return x.NewWithCode(http.StatusBadRequest, "reservation id is required")
+	}
+
+	updates := map[string]interface{}{}
+	if data.Status != "" {
+		updates["status"] = data.Status
+	}
+	if data.VehicleID != nil {
+		updates["vehicle_id"] = data.VehicleID
+	}
+	if data.ReleasedAt != nil {
+		updates["released_at"] = data.ReleasedAt
+	}
+
+	if len(updates) == 0 {
+		return x.NewWithCode(http.StatusBadRequest, "no updates provided")
+	}
+
+	err := db.WithContext(ctx).Model(&entity.Reservation{}).Where("id = ?", data.ID).Updates(updates).Error
+	if err != nil {
+		return x.WrapWithCode(err, http.StatusInternalServerError, "failed to update reservation")
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package reservation
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+type reservationMemory struct {
+	store  *memstore.Store
+	tables *reservationTables
+}
+
+type reservationTables struct {
+	reservations []entity.Reservation
+	nextID       uint
+}
+
+func (t *reservationTables) Snapshot() func() {
+	reservations := append([]entity.Reservation(nil), t.reservations...)
+	nextID := t.nextID
+
+	return func() {
+		t.reservations = reservations
+		t.nextID = nextID
+	}
+}
+
+func initReservationMemory(opt Option) DomainItf {
+	tables := &reservationTables{}
+	opt.Memory.Register(tables)
+
+	return &reservationMemory{
+		store:  opt.Memory,
+		tables: tables,
+	}
+}
+
+func (r *reservationMemory) InsertReservation(ctx context.Context, data entity.Reservation) (entity.Reservation, error) {
+	err := r.store.Do(ctx, func() error {
+		// same guarantees as the unique indexes of the SQL backend
+		for _, v := range r.tables.reservations {
+			if v.Code == data.Code {
+				return x.NewWithCode(http.StatusInternalServerError, "failed to insert reservation: duplicate code")
+			}
+			if v.Status == entity.ReservationHeld && data.Status == entity.ReservationHeld && v.ParkingSpotID == data.ParkingSpotID {
+				return x.NewWithCode(http.StatusInternalServerError, "failed to insert reservation: spot already held")
+			}
+		}
+
+		r.tables.nextID++
+		data.ID = r.tables.nextID
+		data.CreatedAt = time.Now()
+		r.tables.reservations = append(r.tables.reservations, data)
+		return nil
+	})
+
+	return data, err
+}
+
+func (r *reservationMemory) GetReservations(ctx context.Context, data entity.GetReservations) ([]entity.Reservation, error) {
+	result := []entity.Reservation{}
+
+	_ = r.store.Do(ctx, func() error {
+		for _, v := range r.tables.reservations {
+			if data.LotID > 0 && v.LotID != data.LotID {
+				continue
+			}
+			if data.Code != "" && v.Code != data.Code {
+				continue
+			}
+			if data.Status != "" && v.Status != data.Status {
+				continue
+			}
+			if data.EndsBefore != nil && !v.EndsAt.Before(*data.EndsBefore) {
+				continue
+			}
+
+			result = append(result, v)
+		}
+		return nil
+	})
+
+	return result, nil
+}
+
+func (r *reservationMemory) UpdateReservation(ctx context.Context, data entity.UpdateReservation) error {
+	if data.ID < 1 {
+		return x.NewWithCode(http.StatusBadRequest, "reservation id is required")
+	}
+
+	if data.Status == "" && data.VehicleID == nil && data.ReleasedAt == nil {
+		return x.NewWithCode(http.StatusBadRequest, "no updates provided")
+	}
+
+	return r.store.Do(ctx, func() error {
+		for i, v := range r.tables.reservations {
+			if v.ID != data.ID {
+				continue
+			}
+			if data.Status != "" {
+				r.tables.reservations[i].Status = data.Status
+			}
+			if data.VehicleID != nil {
+				vehicleID := *data.VehicleID
+				r.tables.reservations[i].VehicleID = &vehicleID
+			}
+			if data.ReleasedAt != nil {
+				releasedAt := *data.ReleasedAt
+				r.tables.reservations[i].ReleasedAt = &releasedAt
+			}
+		}
+		return nil
+	})
+}
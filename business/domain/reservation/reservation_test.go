@@ -0,0 +1,128 @@
+package reservation_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zuhrulumam/go-parking-lot/business/domain/reservation"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+)
+
+func TestGetReservations(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       entity.GetReservations
+		mockQuery   string
+		mockArgs    []driver.Value
+		mockError   error
+		expectError bool
+	}{
+		{
+			name:      "by code",
+			input:     entity.GetReservations{Code: "ABCDEFGH"},
+			mockQuery: `SELECT \* FROM "reservations" WHERE code = \$1 ORDER BY id`,
+			mockArgs:  []driver.Value{"ABCDEFGH"},
+		},
+		{
+			name:      "lapsed holds with lock",
+			input:     entity.GetReservations{Status: entity.ReservationHeld, EndsBefore: pkg.TimePtr(time.Now()), UseLock: true},
+			mockQuery: `SELECT \* FROM "reservations" WHERE status = \$1 AND ends_at < \$2 ORDER BY id FOR UPDATE`,
+			mockArgs:  []driver.Value{entity.ReservationHeld, sqlmock.AnyArg()},
+		},
+		{
+			name:        "db error",
+			input:       entity.GetReservations{Code: "ABCDEFGH"},
+			mockQuery:   `SELECT \* FROM "reservations"`,
+			mockArgs:    []driver.Value{"ABCDEFGH"},
+			mockError:   errors.New("db error"),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, cleanup := pkg.SetupMockDB(t)
+			defer cleanup()
+
+			q := mock.ExpectQuery(tt.mockQuery).WithArgs(tt.mockArgs...)
+			if tt.mockError != nil {
+				q.WillReturnError(tt.mockError)
+			} else {
+				q.WillReturnRows(sqlmock.NewRows([]string{"id", "code", "status"}).AddRow(1, "ABCDEFGH", entity.ReservationHeld))
+			}
+
+			d := reservation.InitReservationDomain(reservation.Option{DB: db})
+			result, err := d.GetReservations(context.Background(), tt.input)
+
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, result, 1)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUpdateReservation(t *testing.T) {
+	db, mock, cleanup := pkg.SetupMockDB(t)
+	defer cleanup()
+
+	d := reservation.InitReservationDomain(reservation.Option{DB: db})
+
+	assert.Error(t, d.UpdateReservation(context.Background(), entity.UpdateReservation{Status: entity.ReservationExpired}))
+	assert.Error(t, d.UpdateReservation(context.Background(), entity.UpdateReservation{ID: 1}))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "reservations" SET .* WHERE id = \$\d`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := d.UpdateReservation(context.Background(), entity.UpdateReservation{
+		ID:         1,
+		Status:     entity.ReservationExpired,
+		ReleasedAt: pkg.TimePtr(time.Now()),
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMemoryReservations(t *testing.T) {
+	ctx := context.Background()
+	d := reservation.InitReservationDomain(reservation.Option{Memory: memstore.New()})
+
+	held := entity.Reservation{Code: "AAAA", ParkingSpotID: 1, Status: entity.ReservationHeld, EndsAt: time.Now().Add(-time.Minute)}
+
+	first, err := d.InsertReservation(ctx, held)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), first.ID)
+
+	_, err = d.InsertReservation(ctx, entity.Reservation{Code: "BBBB", ParkingSpotID: 1, Status: entity.ReservationHeld})
+	assert.Error(t, err, "one live hold per spot")
+
+	_, err = d.InsertReservation(ctx, entity.Reservation{Code: "AAAA", ParkingSpotID: 2, Status: entity.ReservationHeld})
+	assert.Error(t, err, "codes are unique")
+
+	_, err = d.InsertReservation(ctx, entity.Reservation{Code: "CCCC", ParkingSpotID: 2, Status: entity.ReservationHeld, EndsAt: time.Now().Add(time.Hour)})
+	assert.NoError(t, err)
+
+	lapsed, err := d.GetReservations(ctx, entity.GetReservations{Status: entity.ReservationHeld, EndsBefore: pkg.TimePtr(time.Now())})
+	assert.NoError(t, err)
+	assert.Len(t, lapsed, 1)
+	assert.Equal(t, "AAAA", lapsed[0].Code)
+
+	assert.NoError(t, d.UpdateReservation(ctx, entity.UpdateReservation{ID: first.ID, Status: entity.ReservationExpired}))
+
+	// the spot can be held again once released
+	_, err = d.InsertReservation(ctx, entity.Reservation{Code: "DDDD", ParkingSpotID: 1, Status: entity.ReservationHeld})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,44 @@
+package spotfeed
+
+import (
+	"context"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg/broker"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+//go:generate mockgen -source=business/domain/spotfeed/spotfeed.go -destination=mocks/domain/spotfeed/mock_spotfeed.go -package=mocks
+type DomainItf interface {
+	// Subscribe returns the spot changes of a lot, committed by any
+	// instance, until ctx is done. See broker.Broker.Subscribe.
+	Subscribe(ctx context.Context, lotID uint) (<-chan entity.SpotChange, error)
+}
+
+type Option struct {
+	DB  *gorm.DB
+	Log *zap.Logger
+
+	// Changes is the broker of this process. The in-memory backend is fed
+	// by the in-memory parking domain, the Postgres one relays the
+	// spot_changes notifications into it.
+	Changes *broker.Broker
+	Memory  bool
+}
+
+func InitSpotFeedDomain(opt Option) DomainItf {
+	if opt.Memory {
+		return &spotFeedMemory{changes: opt.Changes}
+	}
+
+	if opt.Log == nil {
+		opt.Log = zap.NewNop()
+	}
+
+	return &spotFeed{
+		db:      opt.DB,
+		log:     opt.Log,
+		changes: opt.Changes,
+	}
+}
@@ -0,0 +1,116 @@
+package spotfeed
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg/broker"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Channel is where the spot_changes trigger notifies, see migration 0012.
+const Channel = "spot_changes"
+
+// relayRetry is how long the relay waits before listening again.
+const relayRetry = time.Second
+
+// spotFeed listens on one connection per instance and fans the
+// notifications out in process, so every instance behind the load
+// balancer sees the changes committed by the others.
+type spotFeed struct {
+	db      *gorm.DB
+	log     *zap.Logger
+	changes *broker.Broker
+	once    sync.Once
+}
+
+func (s *spotFeed) Subscribe(ctx context.Context, lotID uint) (<-chan entity.SpotChange, error) {
+	s.once.Do(func() {
+		go s.listen(context.Background())
+	})
+
+	return s.changes.Subscribe(ctx, lotID), nil
+}
+
+// listen relays notifications until ctx is done, listening again after
+// failures. Notifications sent while nobody listened are gone, so every
+// subscriber is asked to resync once the relay is back.
+func (s *spotFeed) listen(ctx context.Context) {
+	for reconnect := false; ctx.Err() == nil; reconnect = true {
+		err := s.relay(ctx, func() {
+			if reconnect {
+				s.changes.Publish(entity.SpotChange{Resync: true})
+			}
+		})
+		if ctx.Err() != nil {
+			return
+		}
+
+		s.log.Error("spot changes relay stopped", zap.Error(err))
+		time.Sleep(relayRetry)
+	}
+}
+
+// relay holds a pooled connection on LISTEN and publishes what arrives.
+func (s *spotFeed) relay(ctx context.Context, listening func()) error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(dc any) error {
+		pc, ok := dc.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("spot changes need the pgx driver, got %T", dc)
+		}
+
+		if _, err := pc.Conn().Exec(ctx, "LISTEN "+Channel); err != nil {
+			return fmt.Errorf("%w: %v", driver.ErrBadConn, err)
+		}
+		listening()
+
+		for {
+			n, err := pc.Conn().WaitForNotification(ctx)
+			if err != nil {
+				// still listening, never hand it back to the pool
+				return fmt.Errorf("%w: %v", driver.ErrBadConn, err)
+			}
+
+			c, err := DecodeSpotChange(n.Payload)
+			if err != nil {
+				s.log.Error("invalid spot change", zap.String("payload", n.Payload), zap.Error(err))
+				continue
+			}
+
+			s.changes.Publish(c)
+		}
+	})
+}
+
+// DecodeSpotChange reads the payload of a spot_changes notification.
+func DecodeSpotChange(payload string) (entity.SpotChange, error) {
+	var c entity.SpotChange
+
+	if err := json.Unmarshal([]byte(payload), &c); err != nil {
+		return c, err
+	}
+
+	if c.LotID == 0 || (c.Old == nil && c.New == nil) {
+		return c, fmt.Errorf("spot change without lot or spot")
+	}
+
+	return c, nil
+}
@@ -0,0 +1,18 @@
+package spotfeed
+
+import (
+	"context"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg/broker"
+)
+
+// spotFeedMemory only reaches the subscribers of this process, which is all
+// there is with the in-memory store.
+type spotFeedMemory struct {
+	changes *broker.Broker
+}
+
+func (s *spotFeedMemory) Subscribe(ctx context.Context, lotID uint) (<-chan entity.SpotChange, error) {
+	return s.changes.Subscribe(ctx, lotID), nil
+}
@@ -0,0 +1,55 @@
+package spotfeed_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zuhrulumam/go-parking-lot/business/domain/spotfeed"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+)
+
+func TestDecodeSpotChange(t *testing.T) {
+	tests := []struct {
+		name        string
+		payload     string
+		expected    entity.SpotChange
+		expectedErr bool
+	}{
+		{
+			name:    "update",
+			payload: `{"lot_id":1,"old":{"id":3,"lot_id":1,"floor":2,"row":1,"col":3,"type":"A","active":true,"occupied":false,"reserved":false,"ev_charger":false,"accessible":false},"new":{"id":3,"lot_id":1,"floor":2,"row":1,"col":3,"type":"A","active":true,"occupied":true,"reserved":false,"ev_charger":false,"accessible":false}}`,
+			expected: entity.SpotChange{
+				LotID: 1,
+				Old:   &entity.ParkingSpot{ID: 3, LotID: 1, Floor: 2, Row: 1, Col: 3, Type: "A", Active: true},
+				New:   &entity.ParkingSpot{ID: 3, LotID: 1, Floor: 2, Row: 1, Col: 3, Type: "A", Active: true, Occupied: true},
+			},
+		},
+		{
+			name:     "delete",
+			payload:  `{"lot_id":2,"old":{"id":7,"lot_id":2,"floor":1,"row":1,"col":1,"type":"M"},"new":null}`,
+			expected: entity.SpotChange{LotID: 2, Old: &entity.ParkingSpot{ID: 7, LotID: 2, Floor: 1, Row: 1, Col: 1, Type: "M"}},
+		},
+		{
+			name:        "no spot",
+			payload:     `{"lot_id":1,"old":null,"new":null}`,
+			expectedErr: true,
+		},
+		{
+			name:        "not json",
+			payload:     `spot 3`,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := spotfeed.DecodeSpotChange(tt.payload)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, c)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package tariff
+
+import (
+	"context"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+	"gorm.io/gorm"
+)
+
+//go:generate mockgen -source=business/domain/tariff/tariff.go -destination=mocks/domain/tariff/mock_tariff.go -package=mocks
+type DomainItf interface {
+	GetTariff(ctx context.Context, data entity.GetTariff) (entity.Tariff, error)
+	GetTariffs(ctx context.Context, data entity.GetTariffs) ([]entity.Tariff, error)
+	UpsertTariff(ctx context.Context, data entity.Tariff) (entity.Tariff, error)
+}
+
+type tariff struct {
+	db *gorm.DB
+}
+
+type Option struct {
+	DB *gorm.DB
+
+	// Memory switches the domain to the in-memory backend. Tariffs seeds it
+	// and is ignored otherwise.
+	Memory  *memstore.Store
+	Tariffs []entity.Tariff
+}
+
+func InitTariffDomain(opt Option) DomainItf {
+	if opt.Memory != nil {
+		return initTariffMemory(opt)
+	}
+
+	return &tariff{
+		db: opt.DB,
+	}
+}
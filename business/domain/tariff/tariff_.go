@@ -0,0 +1,67 @@
+package tariff
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+func (t *tariff) GetTariff(ctx context.Context, data entity.GetTariff) (entity.Tariff, error) {
+	var (
+		result entity.Tariff
+		db     = pkg.GetTransactionFromCtx(ctx, t.db)
+	)
+
+	err := db.WithContext(ctx).
+		Where("lot_id = ? AND vehicle_type = ?", data.LotID, data.VehicleType).
+		First(&result).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return result, x.WrapWithCode(err, http.StatusNotFound, "tariff not found")
+		}
+		return result, x.WrapWithCode(err, http.StatusInternalServerError, "failed get tariff")
+	}
+
+	return result, nil
+}
+
+func (t *tariff) GetTariffs(ctx context.Context, data entity.GetTariffs) ([]entity.Tariff, error) {
+	var (
+		result []entity.Tariff
+		db     = pkg.GetTransactionFromCtx(ctx, t.db)
+	)
+
+	err := db.WithContext(ctx).
+		Where("lot_id = ?", data.LotID).
+		Order("vehicle_type").
+		Find(&result).Error
+	if err != nil {
+		return result, x.WrapWithCode(err, http.StatusInternalServerError, "failed get tariffs")
+	}
+
+	return result, nil
+}
+
+func (t *tariff) UpsertTariff(ctx context.Context, data entity.Tariff) (entity.Tariff, error) {
+	db := pkg.GetTransactionFromCtx(ctx, t.db)
+
+	data.UpdatedAt = time.Now()
+
+	err := db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "lot_id"}, {Name: "vehicle_type"}},
+		UpdateAll: true,
+	}).Create(&data).Error
+	if err != nil {
+		return data, x.WrapWithCode(err, http.StatusInternalServerError, "failed to save tariff")
+	}
+
+	return data, nil
+}
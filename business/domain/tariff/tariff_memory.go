@@ -0,0 +1,101 @@
+package tariff
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+type tariffMemory struct {
+	store  *memstore.Store
+	tables *tariffTables
+}
+
+type tariffTables struct {
+	tariffs map[tariffKey]entity.Tariff
+}
+
+// tariffKey is the primary key of the tariffs table.
+type tariffKey struct {
+	lotID       uint
+	vehicleType string
+}
+
+func (t *tariffTables) Snapshot() func() {
+	tariffs := make(map[tariffKey]entity.Tariff, len(t.tariffs))
+	for k, v := range t.tariffs {
+		tariffs[k] = v
+	}
+
+	return func() {
+		t.tariffs = tariffs
+	}
+}
+
+func initTariffMemory(opt Option) DomainItf {
+	tables := &tariffTables{tariffs: map[tariffKey]entity.Tariff{}}
+	for _, t := range opt.Tariffs {
+		tables.tariffs[tariffKey{t.LotID, t.VehicleType}] = t
+	}
+
+	opt.Memory.Register(tables)
+
+	return &tariffMemory{
+		store:  opt.Memory,
+		tables: tables,
+	}
+}
+
+func (t *tariffMemory) GetTariff(ctx context.Context, data entity.GetTariff) (entity.Tariff, error) {
+	var (
+		result entity.Tariff
+		found  bool
+	)
+
+	_ = t.store.Do(ctx, func() error {
+		result, found = t.tables.tariffs[tariffKey{data.LotID, data.VehicleType}]
+		return nil
+	})
+
+	if !found {
+		return result, x.NewWithCode(http.StatusNotFound, "tariff not found")
+	}
+
+	return result, nil
+}
+
+func (t *tariffMemory) GetTariffs(ctx context.Context, data entity.GetTariffs) ([]entity.Tariff, error) {
+	result := []entity.Tariff{}
+
+	_ = t.store.Do(ctx, func() error {
+		for k, v := range t.tables.tariffs {
+			if k.lotID == data.LotID {
+				result = append(result, v)
+			}
+		}
+		return nil
+	})
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].VehicleType < result[j].VehicleType
+	})
+
+	return result, nil
+}
+
+func (t *tariffMemory) UpsertTariff(ctx context.Context, data entity.Tariff) (entity.Tariff, error) {
+	data.UpdatedAt = time.Now()
+
+	err := t.store.Do(ctx, func() error {
+		t.tables.tariffs[tariffKey{data.LotID, data.VehicleType}] = data
+		return nil
+	})
+
+	return data, err
+}
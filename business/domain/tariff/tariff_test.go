@@ -0,0 +1,114 @@
+package tariff_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zuhrulumam/go-parking-lot/business/domain/tariff"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+	"gorm.io/gorm"
+)
+
+func TestGetTariff(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockRows    *sqlmock.Rows
+		mockError   error
+		expectError bool
+	}{
+		{
+			name: "Success",
+			mockRows: sqlmock.NewRows([]string{"vehicle_type", "first_hour_price", "hourly_price"}).
+				AddRow("A", 5000, 3000),
+		},
+		{
+			name:        "Not found",
+			mockError:   gorm.ErrRecordNotFound,
+			expectError: true,
+		},
+		{
+			name:        "DB Error",
+			mockError:   errors.New("db error"),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, cleanup := pkg.SetupMockDB(t)
+			defer cleanup()
+
+			q := mock.ExpectQuery(`SELECT \* FROM "tariffs" WHERE lot_id = \$1 AND vehicle_type = \$2`).WithArgs(1, "A", 1)
+			if tt.mockError != nil {
+				q.WillReturnError(tt.mockError)
+			} else {
+				q.WillReturnRows(tt.mockRows)
+			}
+
+			d := tariff.InitTariffDomain(tariff.Option{DB: db})
+			result, err := d.GetTariff(context.Background(), entity.GetTariff{LotID: 1, VehicleType: "A"})
+
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(5000), result.FirstHourPrice)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUpsertTariff(t *testing.T) {
+	db, mock, cleanup := pkg.SetupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "tariffs" .* ON CONFLICT \("lot_id","vehicle_type"\) DO UPDATE SET`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	d := tariff.InitTariffDomain(tariff.Option{DB: db})
+	result, err := d.UpsertTariff(context.Background(), entity.Tariff{LotID: 1, VehicleType: "A", FirstHourPrice: 6000})
+
+	assert.NoError(t, err)
+	assert.False(t, result.UpdatedAt.IsZero())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMemoryTariffs(t *testing.T) {
+	ctx := context.Background()
+	d := tariff.InitTariffDomain(tariff.Option{
+		Memory:  memstore.New(),
+		Tariffs: []entity.Tariff{{LotID: 1, VehicleType: "M", FirstHourPrice: 2000}},
+	})
+
+	_, err := d.GetTariff(ctx, entity.GetTariff{LotID: 1, VehicleType: "A"})
+	assert.Error(t, err)
+
+	_, err = d.UpsertTariff(ctx, entity.Tariff{LotID: 1, VehicleType: "A", FirstHourPrice: 5000})
+	assert.NoError(t, err)
+
+	tf, err := d.GetTariff(ctx, entity.GetTariff{LotID: 1, VehicleType: "A"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5000), tf.FirstHourPrice)
+
+	// tariffs are per lot
+	_, err = d.GetTariff(ctx, entity.GetTariff{LotID: 2, VehicleType: "A"})
+	assert.Error(t, err)
+
+	all, err := d.GetTariffs(ctx, entity.GetTariffs{LotID: 1})
+	assert.NoError(t, err)
+	assert.Len(t, all, 2)
+	assert.Equal(t, "A", all[0].VehicleType)
+
+	all, err = d.GetTariffs(ctx, entity.GetTariffs{LotID: 2})
+	assert.NoError(t, err)
+	assert.Empty(t, all)
+}
@@ -2,30 +2,123 @@ package transaction
 
 import (
 	"context"
+	"net/http"
+	"sync"
+	"time"
 
 	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+	"github.com/zuhrulumam/go-parking-lot/pkg/metrics"
+	"github.com/zuhrulumam/go-parking-lot/pkg/tracing"
 	"gorm.io/gorm"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
 )
 
+//go:generate mockgen -source=business/domain/transaction/transaction.go -destination=mocks/domain/transaction/mock_transaction.go -package=mocks
 type DomainItf interface {
 	RunInTx(ctx context.Context, fn func(ctx context.Context) error) error
+
+	// Drain refuses the transactions opened from now on, with a 503, and
+	// waits for the running ones to end or for ctx to be done. Transactions
+	// joined by the running ones go on.
+	Drain(ctx context.Context) error
 }
 
 type Option struct {
 	DB *gorm.DB
+
+	// Memory switches RunInTx to the in-memory store shared with the other
+	// in-memory domains.
+	Memory *memstore.Store
+
+	// Metrics times the database transactions and counts their rollbacks,
+	// metrics.Noop when nil.
+	Metrics metrics.Metrics
 }
 
+// runningKey marks the context of a transaction counted as running.
+type runningKey struct{}
+
 type transaction struct {
-	db *gorm.DB
+	db      *gorm.DB
+	memory  *memstore.Store
+	metrics metrics.Metrics
+
+	mu       sync.RWMutex
+	draining bool
+	running  sync.WaitGroup
 }
 
 func Init(opt Option) DomainItf {
 	return &transaction{
-		db: opt.DB,
+		db:      opt.DB,
+		memory:  opt.Memory,
+		metrics: metrics.OrNoop(opt.Metrics),
 	}
 }
 
 func (t *transaction) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	// a transaction joined is counted already
+	if ctx.Value(runningKey{}) != nil {
+		return t.run(ctx, fn)
+	}
+
+	if err := t.open(); err != nil {
+		return err
+	}
+	defer t.running.Done()
+
+	return t.run(context.WithValue(ctx, runningKey{}, true), fn)
+}
+
+func (t *transaction) Drain(ctx context.Context) error {
+	t.mu.Lock()
+	t.draining = true
+	t.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		t.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return x.WrapWithCode(ctx.Err(), http.StatusServiceUnavailable, "transactions still running")
+	}
+}
+
+// open counts a transaction as running, unless draining.
+func (t *transaction) open() error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.draining {
+		return x.NewWithCode(http.StatusServiceUnavailable, "shutting down, no new transactions")
+	}
+
+	t.running.Add(1)
+
+	return nil
+}
+
+func (t *transaction) run(ctx context.Context, fn func(ctx context.Context) error) error {
+	if t.memory != nil {
+		return t.memory.RunInTx(ctx, fn)
+	}
+
+	// join the caller's transaction instead of opening a second one
+	if _, ok := ctx.Value(pkg.TxCtxValue).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	// the statements of fn are children of the transaction span
+	ctx, span := tracing.Start(ctx, "db.transaction")
+
+	start := time.Now()
 	tx := t.db.Begin()
 
 	// Create new context with tx
@@ -34,8 +127,14 @@ func (t *transaction) RunInTx(ctx context.Context, fn func(ctx context.Context)
 	err := fn(ctxWithTx)
 	if err != nil {
 		_ = tx.Rollback()
+		t.metrics.Tx(time.Since(start), true)
+		tracing.End(span, err)
 		return err
 	}
 
-	return tx.Commit().Error
+	err = tx.Commit().Error
+	t.metrics.Tx(time.Since(start), err != nil)
+	tracing.End(span, err)
+
+	return err
 }
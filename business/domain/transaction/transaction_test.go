@@ -0,0 +1,86 @@
+package transaction_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zuhrulumam/go-parking-lot/business/domain/transaction"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+func TestDrain(t *testing.T) {
+	ctx := context.Background()
+
+	db, mock, cleanup := pkg.SetupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	tx := transaction.Init(transaction.Option{DB: db})
+
+	var (
+		started = make(chan struct{})
+		release = make(chan struct{})
+		result  = make(chan error)
+	)
+	go func() {
+		result <- tx.RunInTx(ctx, func(ctx context.Context) error {
+			close(started)
+			<-release
+
+			// joined while draining, it is part of the running one
+			return tx.RunInTx(ctx, func(ctx context.Context) error { return nil })
+		})
+	}()
+	<-started
+
+	drained := make(chan error)
+	go func() { drained <- tx.Drain(ctx) }()
+
+	// new transactions are refused once draining, the ones racing it find no
+	// BEGIN expected
+	assert.Eventually(t, func() bool {
+		err := tx.RunInTx(ctx, func(ctx context.Context) error { return nil })
+		return x.ErrCode(err) == http.StatusServiceUnavailable
+	}, time.Second, time.Millisecond)
+
+	select {
+	case <-drained:
+		t.Error("drained with a transaction running")
+	default:
+	}
+
+	close(release)
+	assert.NoError(t, <-result)
+	assert.NoError(t, <-drained)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDrainTimeout(t *testing.T) {
+	tx := transaction.Init(transaction.Option{Memory: memstore.New()})
+
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+
+	go func() {
+		_ = tx.RunInTx(context.Background(), func(ctx context.Context) error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := tx.Drain(ctx)
+	assert.EqualValues(t, http.StatusServiceUnavailable, x.ErrCode(err))
+}
@@ -0,0 +1,38 @@
+package waitlist
+
+import (
+	"context"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+	"gorm.io/gorm"
+)
+
+//go:generate mockgen -source=business/domain/waitlist/waitlist.go -destination=mocks/domain/waitlist/mock_waitlist.go -package=mocks
+type DomainItf interface {
+	InsertEntry(ctx context.Context, data entity.WaitlistEntry) (entity.WaitlistEntry, error)
+	GetEntries(ctx context.Context, data entity.GetWaitlist) ([]entity.WaitlistEntry, error)
+	CountEntries(ctx context.Context, data entity.GetWaitlist) (int, error)
+	UpdateEntry(ctx context.Context, data entity.UpdateWaitlist) error
+}
+
+type waitlist struct {
+	db *gorm.DB
+}
+
+type Option struct {
+	DB *gorm.DB
+
+	// Memory switches the domain to the in-memory backend.
+	Memory *memstore.Store
+}
+
+func InitWaitlistDomain(opt Option) DomainItf {
+	if opt.Memory != nil {
+		return initWaitlistMemory(opt)
+	}
+
+	return &waitlist{
+		db: opt.DB,
+	}
+}
@@ -0,0 +1,136 @@
+package waitlist
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+func (w *waitlist) InsertEntry(ctx context.Context, data entity.WaitlistEntry) (entity.WaitlistEntry, error) {
+	db := pkg.GetTransactionFromCtx(ctx, w.db)
+
+	data.ID = 0
+	data.CreatedAt = time.Now()
+
+	if err := db.WithContext(ctx).Create(&data).Error; err != nil {
+		return data, x.WrapWithCode(err, http.StatusInternalServerError, "failed to insert waitlist entry")
+	}
+
+	return data, nil
+}
+
+func (w *waitlist) GetEntries(ctx context.Context, data entity.GetWaitlist) ([]entity.WaitlistEntry, error) {
+	var result []entity.WaitlistEntry
+
+	db := w.filter(pkg.GetTransactionFromCtx(ctx, w.db).WithContext(ctx), data)
+
+	if data.Limit > 0 {
+		db = db.Limit(data.Limit)
+	}
+
+	if data.UseLock {
+		locking := clause.Locking{Strength: "UPDATE"}
+		if data.SkipLocked {
+			locking.Options = "SKIP LOCKED"
+		}
+		db = db.Clauses(locking)
+	}
+
+	if err := db.Order("id").Find(&result).Error; err != nil {
+		return result, x.WrapWithCode(err, http.StatusInternalServerError, "failed get waitlist entries")
+	}
+
+	return result, nil
+}
+
+func (w *waitlist) CountEntries(ctx context.Context, data entity.GetWaitlist) (int, error) {
+	var count int64
+
+	db := w.filter(pkg.GetTransactionFromCtx(ctx, w.db).WithContext(ctx), data)
+
+	if err := db.Count(&count).Error; err != nil {
+		return 0, x.WrapWithCode(err, http.StatusInternalServerError, "failed count waitlist entries")
+	}
+
+	return int(count), nil
+}
+
+func (w *waitlist) UpdateEntry(ctx context.Context, data entity.UpdateWaitlist) error {
+	db := pkg.GetTransactionFromCtx(ctx, w.db)
+
+	if data.ID < 1 {
+		return x.NewWithCode(http.StatusBadRequest, "waitlist entry id is required")
+	}
+
+	updates := map[string]interface{}{}
+	if data.Status != "" {
+		updates["status"] = data.Status
+	}
+	if data.ParkingSpotID != nil {
+		updates["parking_spot_id"] = data.ParkingSpotID
+	}
+	if data.SpotID != "" {
+		updates["spot_id"] = data.SpotID
+	}
+	if data.OfferedAt != nil {
+		updates["offered_at"] = data.OfferedAt
+	}
+	if data.OfferExpiresAt != nil {
+		updates["offer_expires_at"] = data.OfferExpiresAt
+	}
+	if data.ClosedAt != nil {
+		updates["closed_at"] = data.ClosedAt
+	}
+
+	if len(updates) == 0 {
+		return x.NewWithCode(http.StatusBadRequest, "no updates provided")
+	}
+
+	err := db.WithContext(ctx).Model(&entity.WaitlistEntry{}).Where("id = ?", data.ID).Updates(updates).Error
+	if err != nil {
+		return x.WrapWithCode(err, http.StatusInternalServerError, "failed to update waitlist entry")
+	}
+
+	return nil
+}
+
+func (w *waitlist) filter(db *gorm.DB, data entity.GetWaitlist) *gorm.DB {
+	db = db.Model(&entity.WaitlistEntry{})
+
+	if data.LotID > 0 {
+		db = db.Where("lot_id = ?", data.LotID)
+	}
+
+	if data.Code != "" {
+		db = db.Where("code = ?", data.Code)
+	}
+
+	if data.VehicleType != "" {
+		db = db.Where("vehicle_type = ?", data.VehicleType)
+	}
+
+	if data.VehicleNumber != "" {
+		db = db.Where("vehicle_number = ?", data.VehicleNumber)
+	}
+
+	if len(data.Statuses) > 0 {
+		db = db.Where("status IN ?", data.Statuses)
+	}
+
+	if data.BeforeID > 0 {
+		db = db.Where("id < ?", data.BeforeID)
+	}
+
+	if data.OfferExpiredBefore != nil {
+		db = db.Where("offer_expires_at < ?", *data.OfferExpiredBefore)
+	}
+
+	return db
+}
@@ -0,0 +1,172 @@
+package waitlist
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+type waitlistMemory struct {
+	store  *memstore.Store
+	tables *waitlistTables
+}
+
+type waitlistTables struct {
+	entries []entity.WaitlistEntry
+	nextID  uint
+}
+
+func (t *waitlistTables) Snapshot() func() {
+	entries := append([]entity.WaitlistEntry(nil), t.entries...)
+	nextID := t.nextID
+
+	return func() {
+		t.entries = entries
+		t.nextID = nextID
+	}
+}
+
+func initWaitlistMemory(opt Option) DomainItf {
+	tables := &waitlistTables{}
+	opt.Memory.Register(tables)
+
+	return &waitlistMemory{
+		store:  opt.Memory,
+		tables: tables,
+	}
+}
+
+func (w *waitlistMemory) InsertEntry(ctx context.Context, data entity.WaitlistEntry) (entity.WaitlistEntry, error) {
+	err := w.store.Do(ctx, func() error {
+		// same guarantees as the unique indexes of the SQL backend
+		for _, v := range w.tables.entries {
+			if v.Code == data.Code {
+				return x.NewWithCode(http.StatusInternalServerError, "failed to insert waitlist entry: duplicate code")
+			}
+			if v.VehicleNumber == data.VehicleNumber && isOpen(v.Status) && isOpen(data.Status) {
+				return x.NewWithCode(http.StatusInternalServerError, "failed to insert waitlist entry: vehicle already waiting")
+			}
+		}
+
+		w.tables.nextID++
+		data.ID = w.tables.nextID
+		data.CreatedAt = time.Now()
+		w.tables.entries = append(w.tables.entries, data)
+		return nil
+	})
+
+	return data, err
+}
+
+func (w *waitlistMemory) GetEntries(ctx context.Context, data entity.GetWaitlist) ([]entity.WaitlistEntry, error) {
+	result := []entity.WaitlistEntry{}
+
+	_ = w.store.Do(ctx, func() error {
+		for _, v := range w.tables.entries {
+			if data.Limit > 0 && len(result) == data.Limit {
+				break
+			}
+			if match(v, data) {
+				result = append(result, v)
+			}
+		}
+		return nil
+	})
+
+	return result, nil
+}
+
+func (w *waitlistMemory) CountEntries(ctx context.Context, data entity.GetWaitlist) (int, error) {
+	var count int
+
+	_ = w.store.Do(ctx, func() error {
+		for _, v := range w.tables.entries {
+			if match(v, data) {
+				count++
+			}
+		}
+		return nil
+	})
+
+	return count, nil
+}
+
+func (w *waitlistMemory) UpdateEntry(ctx context.Context, data entity.UpdateWaitlist) error {
+	if data.ID < 1 {
+		return x.NewWithCode(http.StatusBadRequest, "waitlist entry id is required")
+	}
+
+	if data.Status == "" && data.ParkingSpotID == nil && data.SpotID == "" &&
+		data.OfferedAt == nil && data.OfferExpiresAt == nil && data.ClosedAt == nil {
+		return x.NewWithCode(http.StatusBadRequest, "no updates provided")
+	}
+
+	return w.store.Do(ctx, func() error {
+		for i, v := range w.tables.entries {
+			if v.ID != data.ID {
+				continue
+			}
+
+			e := &w.tables.entries[i]
+			if data.Status != "" {
+				e.Status = data.Status
+			}
+			if data.ParkingSpotID != nil {
+				spotID := *data.ParkingSpotID
+				e.ParkingSpotID = &spotID
+			}
+			if data.SpotID != "" {
+				e.SpotID = data.SpotID
+			}
+			if data.OfferedAt != nil {
+				offeredAt := *data.OfferedAt
+				e.OfferedAt = &offeredAt
+			}
+			if data.OfferExpiresAt != nil {
+				expiresAt := *data.OfferExpiresAt
+				e.OfferExpiresAt = &expiresAt
+			}
+			if data.ClosedAt != nil {
+				closedAt := *data.ClosedAt
+				e.ClosedAt = &closedAt
+			}
+		}
+		return nil
+	})
+}
+
+func match(v entity.WaitlistEntry, data entity.GetWaitlist) bool {
+	if data.LotID > 0 && v.LotID != data.LotID {
+		return false
+	}
+	if data.Code != "" && v.Code != data.Code {
+		return false
+	}
+	if data.VehicleType != "" && v.VehicleType != data.VehicleType {
+		return false
+	}
+	if data.VehicleNumber != "" && v.VehicleNumber != data.VehicleNumber {
+		return false
+	}
+	if len(data.Statuses) > 0 && !slices.Contains(data.Statuses, v.Status) {
+		return false
+	}
+	if data.BeforeID > 0 && v.ID >= data.BeforeID {
+		return false
+	}
+	if data.OfferExpiredBefore != nil && (v.OfferExpiresAt == nil || !v.OfferExpiresAt.Before(*data.OfferExpiredBefore)) {
+		return false
+	}
+
+	return true
+}
+
+func isOpen(status string) bool {
+	return status == entity.WaitlistWaiting || status == entity.WaitlistOffered
+}
@@ -0,0 +1,133 @@
+package waitlist_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zuhrulumam/go-parking-lot/business/domain/waitlist"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+)
+
+func TestGetEntries(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       entity.GetWaitlist
+		mockQuery   string
+		mockArgs    []driver.Value
+		mockError   error
+		expectError bool
+	}{
+		{
+			name:      "by code",
+			input:     entity.GetWaitlist{Code: "abc"},
+			mockQuery: `SELECT \* FROM "waitlist_entries" WHERE code = \$1 ORDER BY id`,
+			mockArgs:  []driver.Value{"abc"},
+		},
+		{
+			name:      "head of the queue skipping locked rows",
+			input:     entity.GetWaitlist{VehicleType: "A", Statuses: []string{entity.WaitlistWaiting}, Limit: 1, UseLock: true, SkipLocked: true},
+			mockQuery: `SELECT \* FROM "waitlist_entries" WHERE vehicle_type = \$1 AND status IN \(\$2\) ORDER BY id LIMIT \$3 FOR UPDATE SKIP LOCKED`,
+			mockArgs:  []driver.Value{"A", entity.WaitlistWaiting, 1},
+		},
+		{
+			name:      "lapsed offers with lock",
+			input:     entity.GetWaitlist{Statuses: []string{entity.WaitlistOffered}, OfferExpiredBefore: pkg.TimePtr(time.Now()), UseLock: true},
+			mockQuery: `SELECT \* FROM "waitlist_entries" WHERE status IN \(\$1\) AND offer_expires_at < \$2 ORDER BY id FOR UPDATE`,
+			mockArgs:  []driver.Value{entity.WaitlistOffered, sqlmock.AnyArg()},
+		},
+		{
+			name:        "db error",
+			input:       entity.GetWaitlist{Code: "abc"},
+			mockQuery:   `SELECT \* FROM "waitlist_entries"`,
+			mockArgs:    []driver.Value{"abc"},
+			mockError:   errors.New("db error"),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, cleanup := pkg.SetupMockDB(t)
+			defer cleanup()
+
+			q := mock.ExpectQuery(tt.mockQuery).WithArgs(tt.mockArgs...)
+			if tt.mockError != nil {
+				q.WillReturnError(tt.mockError)
+			} else {
+				q.WillReturnRows(sqlmock.NewRows([]string{"id", "code", "status"}).AddRow(1, "abc", entity.WaitlistWaiting))
+			}
+
+			d := waitlist.InitWaitlistDomain(waitlist.Option{DB: db})
+			result, err := d.GetEntries(context.Background(), tt.input)
+
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, result, 1)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCountEntries(t *testing.T) {
+	db, mock, cleanup := pkg.SetupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "waitlist_entries" WHERE vehicle_type = \$1 AND status IN \(\$2\) AND id < \$3`).
+		WithArgs("A", entity.WaitlistWaiting, 7).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	d := waitlist.InitWaitlistDomain(waitlist.Option{DB: db})
+	n, err := d.CountEntries(context.Background(), entity.GetWaitlist{
+		VehicleType: "A",
+		Statuses:    []string{entity.WaitlistWaiting},
+		BeforeID:    7,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMemoryWaitlist(t *testing.T) {
+	ctx := context.Background()
+	d := waitlist.InitWaitlistDomain(waitlist.Option{Memory: memstore.New()})
+
+	first, err := d.InsertEntry(ctx, entity.WaitlistEntry{Code: "a", VehicleType: "A", VehicleNumber: "B1", Status: entity.WaitlistWaiting})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), first.ID)
+
+	_, err = d.InsertEntry(ctx, entity.WaitlistEntry{Code: "b", VehicleType: "A", VehicleNumber: "B1", Status: entity.WaitlistWaiting})
+	assert.Error(t, err, "one open entry per vehicle")
+
+	_, err = d.InsertEntry(ctx, entity.WaitlistEntry{Code: "a", VehicleType: "A", VehicleNumber: "B2", Status: entity.WaitlistWaiting})
+	assert.Error(t, err, "codes are unique")
+
+	_, err = d.InsertEntry(ctx, entity.WaitlistEntry{Code: "c", VehicleType: "A", VehicleNumber: "B2", Status: entity.WaitlistWaiting})
+	assert.NoError(t, err)
+
+	head, err := d.GetEntries(ctx, entity.GetWaitlist{VehicleType: "A", Statuses: []string{entity.WaitlistWaiting}, Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, head, 1)
+	assert.Equal(t, "a", head[0].Code)
+
+	ahead, err := d.CountEntries(ctx, entity.GetWaitlist{VehicleType: "A", Statuses: []string{entity.WaitlistWaiting}, BeforeID: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, ahead)
+
+	assert.NoError(t, d.UpdateEntry(ctx, entity.UpdateWaitlist{ID: first.ID, Status: entity.WaitlistLeft}))
+
+	// the vehicle can queue again once its entry is closed
+	_, err = d.InsertEntry(ctx, entity.WaitlistEntry{Code: "d", VehicleType: "A", VehicleNumber: "B1", Status: entity.WaitlistWaiting})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,38 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+	"gorm.io/gorm"
+)
+
+//go:generate mockgen -source=business/domain/webhook/webhook.go -destination=mocks/domain/webhook/mock_webhook.go -package=mocks
+type DomainItf interface {
+	InsertWebhook(ctx context.Context, data entity.Webhook) (entity.Webhook, error)
+	GetWebhooks(ctx context.Context, data entity.GetWebhooks) ([]entity.Webhook, error)
+	UpdateWebhook(ctx context.Context, data entity.SaveWebhook) error
+	DeleteWebhook(ctx context.Context, id uint) error
+}
+
+type webhook struct {
+	db *gorm.DB
+}
+
+type Option struct {
+	DB *gorm.DB
+
+	// Memory switches the domain to the in-memory backend.
+	Memory *memstore.Store
+}
+
+func InitWebhookDomain(opt Option) DomainItf {
+	if opt.Memory != nil {
+		return initWebhookMemory(opt)
+	}
+
+	return &webhook{
+		db: opt.DB,
+	}
+}
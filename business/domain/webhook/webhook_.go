@@ -0,0 +1,111 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+func (w *webhook) InsertWebhook(ctx context.Context, data entity.Webhook) (entity.Webhook, error) {
+	db := pkg.GetTransactionFromCtx(ctx, w.db)
+
+	data.ID = 0
+	data.CreatedAt = time.Now()
+	data.UpdatedAt = data.CreatedAt
+
+	if data.EventTypes == nil {
+		data.EventTypes = []string{}
+	}
+
+	if err := db.WithContext(ctx).Create(&data).Error; err != nil {
+		return data, x.WrapWithCode(err, http.StatusInternalServerError, "failed to insert webhook")
+	}
+
+	return data, nil
+}
+
+func (w *webhook) GetWebhooks(ctx context.Context, data entity.GetWebhooks) ([]entity.Webhook, error) {
+	var (
+		result []entity.Webhook
+		db     = pkg.GetTransactionFromCtx(ctx, w.db)
+	)
+
+	db = db.WithContext(ctx).Model(&entity.Webhook{})
+
+	if data.ID > 0 {
+		db = db.Where("id = ?", data.ID)
+	}
+
+	if data.Active != nil {
+		db = db.Where("active = ?", *data.Active)
+	}
+
+	if err := db.Order("id").Find(&result).Error; err != nil {
+		return result, x.WrapWithCode(err, http.StatusInternalServerError, "failed get webhooks")
+	}
+
+	return result, nil
+}
+
+func (w *webhook) UpdateWebhook(ctx context.Context, data entity.SaveWebhook) error {
+	db := pkg.GetTransactionFromCtx(ctx, w.db)
+
+	if data.ID < 1 {
+		return x.NewWithCode(http.StatusBadRequest, "webhook id is required")
+	}
+
+	updates := map[string]interface{}{}
+	if data.LotID != nil {
+		updates["lot_id"] = *data.LotID
+	}
+	if data.URL != nil {
+		updates["url"] = *data.URL
+	}
+	if data.EventTypes != nil {
+		raw, _ := json.Marshal(*data.EventTypes)
+		updates["event_types"] = string(raw)
+	}
+	if data.Secret != nil {
+		updates["secret"] = *data.Secret
+	}
+	if data.Active != nil {
+		updates["active"] = *data.Active
+	}
+
+	if len(updates) == 0 {
+		return x.NewWithCode(http.StatusBadRequest, "no updates provided")
+	}
+	updates["updated_at"] = time.Now()
+
+	res := db.WithContext(ctx).Model(&entity.Webhook{}).Where("id = ?", data.ID).Updates(updates)
+	if res.Error != nil {
+		return x.WrapWithCode(res.Error, http.StatusInternalServerError, "failed to update webhook")
+	}
+
+	if res.RowsAffected == 0 {
+		return x.NewWithCode(http.StatusNotFound, "webhook not found")
+	}
+
+	return nil
+}
+
+func (w *webhook) DeleteWebhook(ctx context.Context, id uint) error {
+	db := pkg.GetTransactionFromCtx(ctx, w.db)
+
+	res := db.WithContext(ctx).Where("id = ?", id).Delete(&entity.Webhook{})
+	if res.Error != nil {
+		return x.WrapWithCode(res.Error, http.StatusInternalServerError, "failed to delete webhook")
+	}
+
+	if res.RowsAffected == 0 {
+		return x.NewWithCode(http.StatusNotFound, "webhook not found")
+	}
+
+	return nil
+}
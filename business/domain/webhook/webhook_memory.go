@@ -0,0 +1,131 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+type webhookMemory struct {
+	store  *memstore.Store
+	tables *webhookTables
+}
+
+type webhookTables struct {
+	webhooks []entity.Webhook
+	nextID   uint
+}
+
+func (t *webhookTables) Snapshot() func() {
+	webhooks := append([]entity.Webhook(nil), t.webhooks...)
+	nextID := t.nextID
+
+	return func() {
+		t.webhooks = webhooks
+		t.nextID = nextID
+	}
+}
+
+func initWebhookMemory(opt Option) DomainItf {
+	tables := &webhookTables{}
+	opt.Memory.Register(tables)
+
+	return &webhookMemory{
+		store:  opt.Memory,
+		tables: tables,
+	}
+}
+
+func (w *webhookMemory) InsertWebhook(ctx context.Context, data entity.Webhook) (entity.Webhook, error) {
+	err := w.store.Do(ctx, func() error {
+		w.tables.nextID++
+		data.ID = w.tables.nextID
+		data.CreatedAt = time.Now()
+		data.UpdatedAt = data.CreatedAt
+		data.EventTypes = append([]string{}, data.EventTypes...)
+		w.tables.webhooks = append(w.tables.webhooks, data)
+		return nil
+	})
+
+	return data, err
+}
+
+func (w *webhookMemory) GetWebhooks(ctx context.Context, data entity.GetWebhooks) ([]entity.Webhook, error) {
+	result := []entity.Webhook{}
+
+	_ = w.store.Do(ctx, func() error {
+		for _, v := range w.tables.webhooks {
+			if data.ID > 0 && v.ID != data.ID {
+				continue
+			}
+			if data.Active != nil && v.Active != *data.Active {
+				continue
+			}
+
+			v.EventTypes = slices.Clone(v.EventTypes)
+			result = append(result, v)
+		}
+		return nil
+	})
+
+	return result, nil
+}
+
+func (w *webhookMemory) UpdateWebhook(ctx context.Context, data entity.SaveWebhook) error {
+	if data.ID < 1 {
+		return x.NewWithCode(http.StatusBadRequest, "webhook id is required")
+	}
+
+	if data.LotID == nil && data.URL == nil && data.EventTypes == nil && data.Secret == nil && data.Active == nil {
+		return x.NewWithCode(http.StatusBadRequest, "no updates provided")
+	}
+
+	return w.store.Do(ctx, func() error {
+		for i, v := range w.tables.webhooks {
+			if v.ID != data.ID {
+				continue
+			}
+
+			h := &w.tables.webhooks[i]
+			if data.LotID != nil {
+				h.LotID = *data.LotID
+			}
+			if data.URL != nil {
+				h.URL = *data.URL
+			}
+			if data.EventTypes != nil {
+				h.EventTypes = append([]string{}, *data.EventTypes...)
+			}
+			if data.Secret != nil {
+				h.Secret = *data.Secret
+			}
+			if data.Active != nil {
+				h.Active = *data.Active
+			}
+			h.UpdatedAt = time.Now()
+
+			return nil
+		}
+
+		return x.NewWithCode(http.StatusNotFound, "webhook not found")
+	})
+}
+
+func (w *webhookMemory) DeleteWebhook(ctx context.Context, id uint) error {
+	return w.store.Do(ctx, func() error {
+		for i, v := range w.tables.webhooks {
+			if v.ID == id {
+				w.tables.webhooks = slices.Delete(slices.Clone(w.tables.webhooks), i, i+1)
+				return nil
+			}
+		}
+
+		return x.NewWithCode(http.StatusNotFound, "webhook not found")
+	})
+}
@@ -0,0 +1,84 @@
+package webhook_test
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zuhrulumam/go-parking-lot/business/domain/webhook"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+func TestGetWebhooks(t *testing.T) {
+	db, mock, cleanup := pkg.SetupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "webhooks" WHERE active = $1 ORDER BY id`)).
+		WithArgs(true).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url", "event_types", "active"}).AddRow(1, "http://billing.local/events", `["vehicle.parked"]`, true))
+
+	d := webhook.InitWebhookDomain(webhook.Option{DB: db})
+	hooks, err := d.GetWebhooks(context.Background(), entity.GetWebhooks{Active: pkg.BoolPtr(true)})
+
+	assert.NoError(t, err)
+	assert.Len(t, hooks, 1)
+	assert.Equal(t, []string{entity.EventVehicleParked}, hooks[0].EventTypes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateWebhookNotFound(t *testing.T) {
+	db, mock, cleanup := pkg.SetupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "webhooks" SET "active"=$1,"updated_at"=$2 WHERE id = $3`)).
+		WithArgs(false, sqlmock.AnyArg(), 9).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	d := webhook.InitWebhookDomain(webhook.Option{DB: db})
+	err := d.UpdateWebhook(context.Background(), entity.SaveWebhook{ID: 9, Active: pkg.BoolPtr(false)})
+
+	assert.EqualValues(t, http.StatusNotFound, x.ErrCode(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMemoryWebhook(t *testing.T) {
+	ctx := context.Background()
+	d := webhook.InitWebhookDomain(webhook.Option{Memory: memstore.New()})
+
+	types := []string{entity.EventVehicleParked}
+	hook, err := d.InsertWebhook(ctx, entity.Webhook{URL: "http://billing.local/events", EventTypes: types, Active: true})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), hook.ID)
+
+	_, err = d.InsertWebhook(ctx, entity.Webhook{URL: "http://audit.local/events"})
+	assert.NoError(t, err)
+
+	// the caller's slices are not shared with the table
+	types[0] = entity.EventSpotDeactivated
+
+	active, err := d.GetWebhooks(ctx, entity.GetWebhooks{Active: pkg.BoolPtr(true)})
+	assert.NoError(t, err)
+	assert.Len(t, active, 1)
+	assert.Equal(t, []string{entity.EventVehicleParked}, active[0].EventTypes)
+
+	assert.NoError(t, d.UpdateWebhook(ctx, entity.SaveWebhook{ID: 2, Active: pkg.BoolPtr(true)}))
+	assert.EqualValues(t, http.StatusBadRequest, x.ErrCode(d.UpdateWebhook(ctx, entity.SaveWebhook{ID: 2})))
+	assert.EqualValues(t, http.StatusNotFound, x.ErrCode(d.UpdateWebhook(ctx, entity.SaveWebhook{ID: 9, Active: pkg.BoolPtr(true)})))
+
+	assert.NoError(t, d.DeleteWebhook(ctx, 1))
+	assert.EqualValues(t, http.StatusNotFound, x.ErrCode(d.DeleteWebhook(ctx, 1)))
+
+	all, err := d.GetWebhooks(ctx, entity.GetWebhooks{})
+	assert.NoError(t, err)
+	assert.Len(t, all, 1)
+	assert.True(t, all[0].Active)
+}
@@ -0,0 +1,71 @@
+package entity
+
+import "time"
+
+const (
+	AuditSpotsCreated = "spots.create"
+	AuditSpotUpdated  = "spot.update"
+	AuditFloorAdded   = "floor.add"
+	AuditFloorRemoved = "floor.remove"
+	AuditLayoutApply  = "layout.apply"
+	AuditLotCreated   = "lot.create"
+	AuditLotUpdated   = "lot.update"
+	// AuditSessionClosed is a session closed by the sessions command.
+	AuditSessionClosed = "session.close"
+)
+
+// AuditLog records one change made through the admin API. Before and After
+// hold the JSON of the affected spots or lot.
+type AuditLog struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	LotID      uint      `json:"lot_id"`
+	Actor      string    `json:"actor"`
+	Action     string    `json:"action"`
+	Resource   string    `json:"resource"`
+	ResourceID string    `json:"resource_id"`
+	Before     string    `json:"before,omitempty"`
+	After      string    `json:"after,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type GetAuditLogs struct {
+	LotID      uint
+	Action     string
+	Resource   string
+	ResourceID string
+	Limit      int
+}
+
+type CreateSpots struct {
+	LotID uint
+	Actor string
+	Spots []ParkingSpot
+}
+
+type AddFloor struct {
+	LotID uint
+	Actor string
+	Floor int
+	Rows  int
+	Cols  int
+	Type  string
+}
+
+type RemoveFloor struct {
+	LotID  uint
+	Actor  string
+	Floor  int
+	Reason string
+}
+
+type UpdateSpot struct {
+	LotID      uint
+	Actor      string
+	SpotID     string
+	Type       string
+	Active     *bool
+	EVCharger  *bool
+	Accessible *bool
+	Reason     string
+}
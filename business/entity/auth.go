@@ -0,0 +1,134 @@
+package entity
+
+import (
+	"slices"
+	"time"
+)
+
+// Roles of the API callers, from the least to the most they may do.
+const (
+	// RoleReadonly only reads: lots, occupancy, sessions, ledgers.
+	RoleReadonly = "readonly"
+	// RoleGate parks and unparks, and takes the payment at the exit.
+	RoleGate = "gate"
+	// RoleAttendant also overrides exits, refunds and handles reservations
+	// and the waitlist.
+	RoleAttendant = "attendant"
+	// RoleAdmin also manages lots, layouts, tariffs and webhooks.
+	RoleAdmin = "admin"
+)
+
+var Roles = []string{RoleReadonly, RoleGate, RoleAttendant, RoleAdmin}
+
+// ValidRole reports whether role is one of Roles.
+func ValidRole(role string) bool {
+	return slices.Contains(Roles, role)
+}
+
+// How a Principal authenticated.
+const (
+	AuthAPIKey     = "api_key"
+	AuthToken      = "token"
+	AuthAdminToken = "admin_token"
+	AuthDisabled   = "disabled"
+)
+
+// Principal is the caller of a request: the API key or user it
+// authenticated as, and what it may do.
+type Principal struct {
+	// Subject is the key or user name, recorded as the actor of changes.
+	Subject string `json:"subject"`
+	Role    string `json:"role"`
+	Method  string `json:"method"`
+
+	// LotID confines an API key to one lot, 0 is every lot.
+	LotID uint `json:"lot_id"`
+}
+
+// Can reports whether the principal has one of roles.
+func (p Principal) Can(roles ...string) bool {
+	return slices.Contains(roles, p.Role)
+}
+
+// Reaches reports whether the principal may act on the lot.
+func (p Principal) Reaches(lotID uint) bool {
+	return p.LotID == 0 || p.LotID == lotID
+}
+
+// APIKey lets a gate device or service call the API. Only the SHA-256 of
+// the key is stored, Prefix is its first characters to tell keys apart.
+type APIKey struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Hash       string     `json:"-"`
+	Role       string     `json:"role"`
+	LotID      uint       `json:"lot_id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
+
+type GetAPIKeys struct {
+	ID   uint
+	Hash string
+
+	// Revoked filters revoked keys in or out, nil is both.
+	Revoked *bool
+}
+
+type CreateAPIKey struct {
+	Name  string
+	Role  string
+	LotID uint
+}
+
+type UpdateAPIKey struct {
+	ID         uint
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// User is a member of staff signing in with a password for a token.
+type User struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	Username     string    `json:"username"`
+	PasswordHash string    `json:"-"`
+	Role         string    `json:"role"`
+	Active       bool      `json:"active"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type GetUsers struct {
+	ID       uint
+	Username string
+}
+
+// SaveUser creates a user, or changes the fields set of user ID.
+type SaveUser struct {
+	ID       uint
+	Username string
+	Password *string
+	Role     *string
+	Active   *bool
+}
+
+// UpdateUser is the change of SaveUser as stored.
+type UpdateUser struct {
+	ID           uint
+	PasswordHash *string
+	Role         *string
+	Active       *bool
+}
+
+type Login struct {
+	Username string
+	Password string
+}
+
+// Token is a signed JWT of a user.
+type Token struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,62 @@
+package entity
+
+// Layout describes the whole lot, it is read from and written to the
+// layout files of `seed --layout` and `export-layout`.
+type Layout struct {
+	Floors []LayoutFloor `yaml:"floors" json:"floors"`
+}
+
+// LayoutFloor is a rows x cols grid of spots of Type, changed cell by cell
+// by Spots in order.
+type LayoutFloor struct {
+	Floor int              `yaml:"floor" json:"floor"`
+	Rows  int              `yaml:"rows" json:"rows"`
+	Cols  int              `yaml:"cols" json:"cols"`
+	Type  string           `yaml:"type" json:"type"`
+	Spots []LayoutOverride `yaml:"spots,omitempty" json:"spots,omitempty"`
+}
+
+// LayoutOverride changes the cells of Rows x Cols, each a number ("3") or
+// an inclusive range ("1-5"). Empty means every row or col of the floor.
+// Skip leaves the cells without a spot.
+type LayoutOverride struct {
+	Rows       string `yaml:"rows,omitempty" json:"rows,omitempty"`
+	Cols       string `yaml:"cols,omitempty" json:"cols,omitempty"`
+	Type       string `yaml:"type,omitempty" json:"type,omitempty"`
+	Active     *bool  `yaml:"active,omitempty" json:"active,omitempty"`
+	EVCharger  *bool  `yaml:"ev_charger,omitempty" json:"ev_charger,omitempty"`
+	Accessible *bool  `yaml:"accessible,omitempty" json:"accessible,omitempty"`
+	Skip       bool   `yaml:"skip,omitempty" json:"skip,omitempty"`
+}
+
+type ApplyLayout struct {
+	LotID  uint
+	Actor  string
+	Layout Layout
+
+	// Prune deletes the spots missing from the layout, they are only
+	// reported as unmanaged otherwise.
+	Prune bool
+
+	// DryRun computes the diff without changing anything.
+	DryRun bool
+}
+
+type LayoutChange struct {
+	SpotID string      `json:"spot_id"`
+	Before ParkingSpot `json:"before"`
+	After  ParkingSpot `json:"after"`
+}
+
+// LayoutDiff is what applying a layout changes, by spot position.
+type LayoutDiff struct {
+	Create    []ParkingSpot  `json:"create,omitempty"`
+	Update    []LayoutChange `json:"update,omitempty"`
+	Delete    []ParkingSpot  `json:"delete,omitempty"`
+	Unmanaged []ParkingSpot  `json:"unmanaged,omitempty"`
+}
+
+// Empty reports whether the lot already matches the layout.
+func (d LayoutDiff) Empty() bool {
+	return len(d.Create) == 0 && len(d.Update) == 0 && len(d.Delete) == 0
+}
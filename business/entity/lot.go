@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+// Lot is one garage. Spots, sessions, tariffs and every other record belong
+// to exactly one lot.
+type Lot struct {
+	ID   uint   `gorm:"primaryKey" json:"id"`
+	Name string `json:"name"`
+
+	// Allocation names the strategy picking spots, empty uses the server
+	// default.
+	Allocation string `json:"allocation"`
+
+	// OpensAt and ClosesAt are "HH:MM" in Timezone, vehicles only enter in
+	// between. Both empty means always open, ClosesAt before OpensAt spans
+	// midnight.
+	OpensAt  string `json:"opens_at"`
+	ClosesAt string `json:"closes_at"`
+
+	// Timezone is the IANA zone of opening hours and night and weekend
+	// tariffs, empty uses the server's.
+	Timezone string `json:"timezone"`
+
+	// Fallback lets vehicles take larger spots when none of their own type
+	// is free, Reserve ("A:10,M:5") keeps the last free spots of a type
+	// for its own vehicles.
+	Fallback bool   `json:"fallback"`
+	Reserve  string `json:"reserve"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type GetLot struct {
+	ID uint `json:"id"`
+}
+
+// SaveLot creates a lot, or replaces the settings of Lot.ID.
+type SaveLot struct {
+	Actor string
+	Lot   Lot
+}
@@ -0,0 +1,209 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Domain event types.
+const (
+	EventVehicleParked      = "vehicle.parked"
+	EventVehicleUnparked    = "vehicle.unparked"
+	EventSpotDeactivated    = "spot.deactivated"
+	EventReservationExpired = "reservation.expired"
+)
+
+// EventTypes lists every domain event type.
+var EventTypes = []string{
+	EventVehicleParked,
+	EventVehicleUnparked,
+	EventSpotDeactivated,
+	EventReservationExpired,
+}
+
+// Delivery outcomes.
+const (
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// OutboxEvent is a domain event, written in the transaction that caused it
+// and sent to every sink by the relay afterwards. Data is the JSON of what
+// the event is about: the ticket, the closed session, the spot or the
+// reservation.
+type OutboxEvent struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	LotID     uint      `json:"lot_id"`
+	Type      string    `json:"type"`
+	Data      RawJSON   `gorm:"type:jsonb" json:"data"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// relay state, the event is retried until every sink took it or the
+	// relay gives up on it
+	Attempts      int        `json:"-"`
+	NextAttemptAt time.Time  `json:"-"`
+	DispatchedAt  *time.Time `json:"-"`
+	FailedAt      *time.Time `json:"-"`
+	LastError     string     `json:"-"`
+}
+
+// RawJSON is a JSON document kept as it is, stored in a jsonb column.
+type RawJSON json.RawMessage
+
+func (j RawJSON) MarshalJSON() ([]byte, error) {
+	if len(j) == 0 {
+		return []byte("null"), nil
+	}
+	return j, nil
+}
+
+func (j *RawJSON) UnmarshalJSON(data []byte) error {
+	*j = append((*j)[:0], data...)
+	return nil
+}
+
+func (j RawJSON) Value() (driver.Value, error) {
+	if len(j) == 0 {
+		return nil, nil
+	}
+	return string(j), nil
+}
+
+func (j *RawJSON) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*j = nil
+	case []byte:
+		*j = append(RawJSON(nil), v...)
+	case string:
+		*j = RawJSON(v)
+	default:
+		return fmt.Errorf("cannot scan %T into RawJSON", src)
+	}
+	return nil
+}
+
+// NewOutboxEvent returns an event of the given type about data.
+func NewOutboxEvent(lotID uint, eventType string, data interface{}) OutboxEvent {
+	raw, _ := json.Marshal(data)
+
+	return OutboxEvent{
+		LotID: lotID,
+		Type:  eventType,
+		Data:  raw,
+	}
+}
+
+type GetOutboxEvents struct {
+	IDs []uint
+
+	// Pending leaves out the events every sink took and those given up on.
+	Pending bool
+
+	// DueBy leaves out the events waiting for a retry after it.
+	DueBy   *time.Time
+	Limit   int
+	UseLock bool
+
+	// SkipLocked makes UseLock pass over events another relay holds.
+	SkipLocked bool
+}
+
+type UpdateOutboxEvent struct {
+	ID            uint
+	Attempts      *int
+	NextAttemptAt *time.Time
+	DispatchedAt  *time.Time
+	FailedAt      *time.Time
+	LastError     *string
+}
+
+// EventDelivery records one attempt to hand an event to a sink.
+type EventDelivery struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	EventID   uint      `json:"event_id"`
+	Sink      string    `json:"sink"`
+	Attempt   int       `json:"attempt"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type GetEventDeliveries struct {
+	IDs      []uint
+	EventIDs []uint
+	Sink     string
+	Status   string
+	Limit    int
+}
+
+// Webhook is an endpoint subscribed to the domain events, of one lot or of
+// every lot when LotID is 0, and of the listed types or all of them. Every
+// delivery is signed with Secret.
+type Webhook struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	LotID      uint      `json:"lot_id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `gorm:"serializer:json" json:"event_types"`
+	Secret     string    `json:"-"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Sink is the name the deliveries of the webhook are logged under.
+func (w Webhook) Sink() string {
+	return fmt.Sprintf("webhook:%d", w.ID)
+}
+
+// Wants reports whether the webhook subscribed to e. Events older than the
+// subscription are never sent to it.
+func (w Webhook) Wants(e OutboxEvent) bool {
+	if w.LotID != 0 && w.LotID != e.LotID {
+		return false
+	}
+
+	if w.CreatedAt.After(e.CreatedAt) {
+		return false
+	}
+
+	if len(w.EventTypes) == 0 {
+		return true
+	}
+
+	for _, t := range w.EventTypes {
+		if t == e.Type {
+			return true
+		}
+	}
+
+	return false
+}
+
+type GetWebhooks struct {
+	ID     uint
+	Active *bool
+}
+
+// SaveWebhook creates a webhook, or changes the fields set of webhook ID.
+type SaveWebhook struct {
+	ID         uint
+	LotID      *uint
+	URL        *string
+	EventTypes *[]string
+	Secret     *string
+	Active     *bool
+}
+
+type GetWebhookDeliveries struct {
+	WebhookID uint
+	Status    string
+	Limit     int
+}
+
+type ReplayDelivery struct {
+	WebhookID  uint
+	DeliveryID uint
+}
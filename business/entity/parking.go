@@ -1,6 +1,9 @@
 package entity
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 type VehicleType string
 
@@ -17,72 +20,277 @@ type Spot struct {
 	VehicleNo string
 }
 
+// GetAvailableParkingSpot filters spots, every zero field matches any spot.
 type GetAvailableParkingSpot struct {
+	ID          uint        `json:"id"`
+	LotID       uint        `json:"lot_id"`
 	VehicleType VehicleType `json:"vehicle_type"`
+	Floor       int         `json:"floor"`
+	Row         int         `json:"row"`
+	Col         int         `json:"col"`
 	Active      *bool       `json:"active"`
 	Occupied    *bool       `json:"occupied"`
+	Reserved    *bool       `json:"reserved"`
 	UseLock     bool        `json:"use_lock"`
+
+	// SkipLocked makes UseLock pass over spots another transaction holds,
+	// so concurrent parks move on to another spot instead of waiting.
+	SkipLocked bool `json:"skip_locked"`
+}
+
+// SpotCount tallies the spots of a lot sharing a floor and type. Free spots
+// are active, neither occupied nor held.
+type SpotCount struct {
+	Floor    int    `json:"floor,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Total    int    `json:"total"`
+	Active   int    `json:"active"`
+	Occupied int    `json:"occupied"`
+	Reserved int    `json:"reserved"`
+	Free     int    `json:"free"`
+}
+
+// Add sums the counts of o into c.
+func (c *SpotCount) Add(o SpotCount) {
+	c.Total += o.Total
+	c.Active += o.Active
+	c.Occupied += o.Occupied
+	c.Reserved += o.Reserved
+	c.Free += o.Free
+}
+
+// Occupancy is how full a lot is, overall and by floor and spot type.
+type Occupancy struct {
+	LotID uint `json:"lot_id"`
+	SpotCount
+	Floors []SpotCount `json:"floors"`
+	Types  []SpotCount `json:"types"`
+}
+
+// CountSpot is what a single spot adds to the counts of its floor and type.
+func CountSpot(s ParkingSpot) SpotCount {
+	c := SpotCount{Floor: s.Floor, Type: s.Type, Total: 1}
+	if s.Active {
+		c.Active = 1
+	}
+	if s.Occupied {
+		c.Occupied = 1
+	}
+	if s.Reserved {
+		c.Reserved = 1
+	}
+	if s.Active && !s.Occupied && !s.Reserved {
+		c.Free = 1
+	}
+	return c
+}
+
+// SpotChange is a spot of a lot changing state. Old is nil for a new spot
+// and New for a removed one. Resync tells that changes may have been lost,
+// for the lot or every lot when LotID is 0.
+type SpotChange struct {
+	LotID  uint         `json:"lot_id"`
+	Old    *ParkingSpot `json:"old,omitempty"`
+	New    *ParkingSpot `json:"new,omitempty"`
+	Resync bool         `json:"resync,omitempty"`
 }
 
+// Occupancy stream events.
+const (
+	OccupancySnapshot = "snapshot"
+	OccupancyChange   = "change"
+)
+
+// OccupancyEvent is a message of the occupancy stream: a snapshot that
+// replaces whatever the client counted so far, or the change of one spot
+// with Delta, what it adds to the counts of the lot, its floor and type.
+type OccupancyEvent struct {
+	Event    string      `json:"event"`
+	Snapshot *Occupancy  `json:"snapshot,omitempty"`
+	Change   *SpotChange `json:"change,omitempty"`
+	Delta    []SpotCount `json:"delta,omitempty"`
+}
+
+// SpotTypeUnusable marks a spot no vehicle type fits, e.g. a pillar.
+const SpotTypeUnusable = "X"
+
 type ParkingSpot struct {
-	ID       uint `gorm:"primaryKey"`
-	Floor    int
-	Row      int
-	Col      int
-	Type     string `gorm:"size:1"` // 'B', 'M', 'A', 'X'
-	Active   bool
-	Occupied bool
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	LotID      uint   `json:"lot_id"`
+	Floor      int    `json:"floor"`
+	Row        int    `json:"row"`
+	Col        int    `json:"col"`
+	Type       string `gorm:"size:1" json:"type"` // 'B', 'M', 'A', 'X'
+	Active     bool   `json:"active"`
+	Occupied   bool   `json:"occupied"`
+	Reserved   bool   `json:"reserved"`
+	EVCharger  bool   `gorm:"column:ev_charger" json:"ev_charger"`
+	Accessible bool   `json:"accessible"`
+}
+
+// Position is where the spot is, its SpotID.
+func (s ParkingSpot) Position() SpotID {
+	return SpotID{Lot: s.LotID, Floor: s.Floor, Row: s.Row, Col: s.Col}
 }
 
 type Vehicle struct {
-	ID            uint       `gorm:"primaryKey" json:"id"`
-	VehicleNumber string     `json:"vehicle_number"`
-	VehicleType   string     `gorm:"size:1" json:"vehicle_type"` // 'B', 'M', 'A'
-	SpotID        string     `json:"spot_id"`
-	ParkedAt      time.Time  `json:"parked_at"`
-	UnparkedAt    *time.Time `json:"unparked_at"`
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	LotID           uint       `json:"lot_id"`
+	VehicleNumber   string     `json:"vehicle_number"`
+	VehicleType     string     `gorm:"size:1" json:"vehicle_type"` // 'B', 'M', 'A'
+	SpotID          string     `json:"spot_id"`
+	TicketID        string     `json:"ticket_id"`
+	ParkedAt        time.Time  `json:"parked_at"`
+	UnparkedAt      *time.Time `json:"unparked_at"`
+	Fee             *int64     `json:"fee"`
+	Status          string     `json:"status"`
+	ExitRequestedAt *time.Time `json:"exit_requested_at"`
 }
 
-type Park struct {
+type Ticket struct {
+	TicketID      string      `json:"ticket_id"`
+	LotID         uint        `json:"lot_id"`
+	SpotID        string      `json:"spot_id"`
+	Floor         int         `json:"floor"`
+	Row           int         `json:"row"`
+	Col           int         `json:"col"`
 	VehicleType   VehicleType `json:"vehicle_type"`
+	SpotType      string      `json:"spot_type"`
 	VehicleNumber string      `json:"vehicle_number"`
+	ParkedAt      time.Time   `json:"parked_at"`
+}
+
+type Park struct {
+	LotID           uint        `json:"lot_id"`
+	VehicleType     VehicleType `json:"vehicle_type"`
+	VehicleNumber   string      `json:"vehicle_number"`
+	ReservationCode string      `json:"reservation_code"`
+	WaitlistCode    string      `json:"waitlist_code"`
 }
 
 type UnPark struct {
+	LotID         uint   `json:"lot_id"`
 	SpotID        string `json:"spot_id"`
 	VehicleNumber string `json:"vehicle_number"`
+	TicketID      string `json:"ticket_id"`
 }
 
 type GetAvailablePark struct {
+	LotID       uint        `json:"lot_id"`
 	VehicleType VehicleType `json:"vehicle_type"`
 }
 
 type SearchVehicle struct {
+	ID            uint   `json:"id"`
+	LotID         uint   `json:"lot_id"`
+	SpotID        string `json:"spot_id"`
 	VehicleNumber string `json:"vehicle_number"`
+	TicketID      string `json:"ticket_id"`
+
+	// UseLock locks the session row until the transaction of ctx ends, so
+	// concurrent exits of one ticket take turns.
+	UseLock bool `json:"use_lock"`
 }
 
 type UpdateParkingSpot struct {
-	ID       uint `json:"id"`
-	Floor    int
-	Row      int
-	Col      int
-	Occupied *bool `json:"occupied"`
+	ID         uint `json:"id"`
+	LotID      uint `json:"lot_id"`
+	Floor      int
+	Row        int
+	Col        int
+	Type       string `json:"type"`
+	Active     *bool  `json:"active"`
+	Occupied   *bool  `json:"occupied"`
+	Reserved   *bool  `json:"reserved"`
+	EVCharger  *bool  `json:"ev_charger"`
+	Accessible *bool  `json:"accessible"`
+}
+
+// DeleteParkingSpots removes every spot of a floor of the lot, or the
+// spots of IDs.
+type DeleteParkingSpots struct {
+	LotID uint   `json:"lot_id"`
+	Floor int    `json:"floor"`
+	IDs   []uint `json:"ids"`
 }
 
 type InsertVehicle struct {
+	LotID         uint
 	VehicleNumber string
 	VehicleType   string
 	SpotID        string
+	TicketID      string
 }
 
 type UpdateVehicle struct {
-	ID            uint
-	VehicleNumber string
-	UnparkedAt    *time.Time
+	ID              uint
+	VehicleNumber   string
+	UnparkedAt      *time.Time
+	Fee             *int64
+	Status          string
+	ExitRequestedAt *time.Time
 }
 
+// SpotID is the position of a spot, written lot-floor-row-col.
 type SpotID struct {
+	Lot   uint
 	Floor int
 	Row   int
 	Col   int
 }
+
+func (s SpotID) String() string {
+	return fmt.Sprintf("%d-%d-%d-%d", s.Lot, s.Floor, s.Row, s.Col)
+}
+
+// Session sort keys, both break ties on the session id.
+const (
+	SessionSortParkedAt = "parked_at"
+	SessionSortDuration = "duration"
+)
+
+// GetSessions filters the parking sessions of a lot, every zero field
+// matches any session.
+type GetSessions struct {
+	LotID         uint
+	VehicleNumber string
+	VehicleType   VehicleType
+	Floor         int
+
+	// From and To keep the sessions in the lot at some point in between.
+	From *time.Time
+	To   *time.Time
+
+	Open   *bool
+	MinFee *int64
+	MaxFee *int64
+
+	Sort string
+	Desc bool
+
+	// AsOf is where open sessions end when sorting by duration, so that
+	// every page of a listing ranks them the same.
+	AsOf time.Time
+
+	// After resumes the listing past the session it points at.
+	After *SessionCursor
+	Limit int
+
+	// Cursor is the opaque form of After handed to clients.
+	Cursor string
+}
+
+// SessionCursor is the position of a session in a listing: its sort key,
+// unix microseconds of parked_at or of the duration, and its id.
+type SessionCursor struct {
+	Key  int64     `json:"k"`
+	ID   uint      `json:"id"`
+	Sort string    `json:"s"`
+	Desc bool      `json:"d"`
+	AsOf time.Time `json:"at"`
+}
+
+type SessionPage struct {
+	Sessions   []Vehicle `json:"sessions"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
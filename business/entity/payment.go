@@ -0,0 +1,95 @@
+package entity
+
+import "time"
+
+const (
+	SessionParked          = "parked"
+	SessionAwaitingPayment = "awaiting_payment"
+	SessionExited          = "exited"
+)
+
+const (
+	PaymentCharge   = "charge"
+	PaymentRefund   = "refund"
+	PaymentOverride = "override"
+
+	PaymentSucceeded = "succeeded"
+	PaymentFailed    = "failed"
+	// PaymentPending is a refund sent to the provider and not settled yet,
+	// it already counts against what is left to refund.
+	PaymentPending = "pending"
+)
+
+// Payment is one ledger line of a parking session. Charges and refunds move
+// money; an override records an operator letting a vehicle out without
+// full payment and never counts as paid.
+type Payment struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	LotID     uint      `json:"lot_id"`
+	VehicleID uint      `json:"vehicle_id"`
+	TicketID  string    `json:"ticket_id"`
+	Kind      string    `json:"kind"`
+	Status    string    `json:"status"`
+	Provider  string    `json:"provider"`
+	Amount    int64     `json:"amount"`
+	Reference string    `json:"reference"`
+	RefundOf  *uint     `json:"refund_of,omitempty"`
+	Note      string    `json:"note,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type GetPayments struct {
+	ID        uint   `json:"id"`
+	LotID     uint   `json:"lot_id"`
+	VehicleID uint   `json:"vehicle_id"`
+	TicketID  string `json:"ticket_id"`
+	RefundOf  uint   `json:"refund_of"`
+
+	// UseLock locks the payments found until the transaction of ctx ends.
+	UseLock bool `json:"use_lock"`
+}
+
+// UpdatePayment settles a pending ledger line.
+type UpdatePayment struct {
+	ID        uint
+	Status    string
+	Reference string
+	Note      string
+}
+
+type Pay struct {
+	LotID    uint   `json:"lot_id"`
+	TicketID string `json:"ticket_id"`
+	Amount   int64  `json:"amount"`
+	Provider string `json:"provider"`
+	Source   string `json:"source"`
+}
+
+type Refund struct {
+	LotID     uint   `json:"lot_id"`
+	PaymentID uint   `json:"payment_id"`
+	Amount    int64  `json:"amount"`
+	Reason    string `json:"reason"`
+}
+
+type AuthorizeExit struct {
+	LotID    uint   `json:"lot_id"`
+	TicketID string `json:"ticket_id"`
+	Override bool   `json:"override"`
+	Operator string `json:"operator"`
+	Reason   string `json:"reason"`
+}
+
+type Ledger struct {
+	TicketID   string    `json:"ticket_id"`
+	Fee        int64     `json:"fee"`
+	AmountPaid int64     `json:"amount_paid"`
+	AmountDue  int64     `json:"amount_due"`
+	Status     string    `json:"status"`
+	Payments   []Payment `json:"payments"`
+}
+
+type PaymentResult struct {
+	Payment Payment `json:"payment"`
+	Receipt Receipt `json:"receipt"`
+}
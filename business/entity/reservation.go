@@ -0,0 +1,52 @@
+package entity
+
+import "time"
+
+const (
+	ReservationHeld      = "held"
+	ReservationCheckedIn = "checked_in"
+	ReservationExpired   = "expired"
+	ReservationCancelled = "cancelled"
+)
+
+// Reservation holds a spot until a vehicle checks in with Code or EndsAt
+// passes, whichever comes first.
+type Reservation struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	LotID         uint       `json:"lot_id"`
+	Code          string     `json:"code"`
+	ParkingSpotID uint       `json:"-"`
+	SpotID        string     `json:"spot_id"`
+	VehicleType   string     `gorm:"size:1" json:"vehicle_type"`
+	VehicleNumber string     `json:"vehicle_number,omitempty"`
+	StartsAt      time.Time  `json:"starts_at"`
+	EndsAt        time.Time  `json:"ends_at"`
+	Status        string     `json:"status"`
+	VehicleID     *uint      `json:"vehicle_id,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	ReleasedAt    *time.Time `json:"released_at,omitempty"`
+}
+
+type Reserve struct {
+	LotID         uint        `json:"lot_id"`
+	SpotID        string      `json:"spot_id"`
+	VehicleType   VehicleType `json:"vehicle_type"`
+	VehicleNumber string      `json:"vehicle_number"`
+	StartsAt      time.Time   `json:"starts_at"`
+	EndsAt        time.Time   `json:"ends_at"`
+}
+
+type GetReservations struct {
+	LotID      uint       `json:"lot_id"`
+	Code       string     `json:"code"`
+	Status     string     `json:"status"`
+	EndsBefore *time.Time `json:"ends_before"`
+	UseLock    bool       `json:"use_lock"`
+}
+
+type UpdateReservation struct {
+	ID         uint
+	Status     string
+	VehicleID  *uint
+	ReleasedAt *time.Time
+}
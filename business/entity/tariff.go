@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+// Tariff prices one VehicleType of a lot. Amounts are in the smallest currency unit.
+// Zero Night/Weekend prices fall back to HourlyPrice and a zero DailyCap
+// means no cap.
+type Tariff struct {
+	LotID              uint      `gorm:"primaryKey" json:"lot_id"`
+	VehicleType        string    `gorm:"primaryKey;size:1" json:"vehicle_type"`
+	FirstHourPrice     int64     `json:"first_hour_price"`
+	HourlyPrice        int64     `json:"hourly_price"`
+	DailyCap           int64     `json:"daily_cap"`
+	GracePeriodMinutes int       `json:"grace_period_minutes"`
+	NightHourlyPrice   int64     `json:"night_hourly_price"`
+	NightStartHour     int       `json:"night_start_hour"`
+	NightEndHour       int       `json:"night_end_hour"`
+	WeekendHourlyPrice int64     `json:"weekend_hourly_price"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+type GetTariff struct {
+	LotID       uint   `json:"lot_id"`
+	VehicleType string `json:"vehicle_type"`
+}
+
+type GetTariffs struct {
+	LotID uint `json:"lot_id"`
+}
+
+type Receipt struct {
+	TicketID      string      `json:"ticket_id"`
+	LotID         uint        `json:"lot_id"`
+	VehicleNumber string      `json:"vehicle_number"`
+	VehicleType   VehicleType `json:"vehicle_type"`
+	SpotID        string      `json:"spot_id"`
+	ParkedAt      time.Time   `json:"parked_at"`
+	UnparkedAt    *time.Time  `json:"unparked_at"`
+	Fee           int64       `json:"fee"`
+	AmountPaid    int64       `json:"amount_paid"`
+	AmountDue     int64       `json:"amount_due"`
+	Status        string      `json:"status"`
+}
@@ -0,0 +1,58 @@
+package entity
+
+import "time"
+
+const (
+	WaitlistWaiting = "waiting"
+	WaitlistOffered = "offered"
+	WaitlistClaimed = "claimed"
+	WaitlistLeft    = "left"
+	WaitlistLapsed  = "lapsed"
+)
+
+// WaitlistEntry is a vehicle queued for a spot of its type. Once a spot
+// frees up it is held for the head of the queue until OfferExpiresAt.
+type WaitlistEntry struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	LotID          uint       `json:"lot_id"`
+	Code           string     `json:"code"`
+	VehicleType    string     `gorm:"size:1" json:"vehicle_type"`
+	VehicleNumber  string     `json:"vehicle_number"`
+	Status         string     `json:"status"`
+	ParkingSpotID  *uint      `json:"-"`
+	SpotID         string     `json:"spot_id,omitempty"`
+	OfferedAt      *time.Time `json:"offered_at,omitempty"`
+	OfferExpiresAt *time.Time `json:"offer_expires_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ClosedAt       *time.Time `json:"closed_at,omitempty"`
+
+	// Position is 1 for the head of the queue, 0 once the entry stops
+	// waiting.
+	Position int `gorm:"-" json:"position"`
+}
+
+type GetWaitlist struct {
+	LotID              uint       `json:"lot_id"`
+	Code               string     `json:"code"`
+	VehicleType        string     `json:"vehicle_type"`
+	VehicleNumber      string     `json:"vehicle_number"`
+	Statuses           []string   `json:"statuses"`
+	BeforeID           uint       `json:"before_id"`
+	OfferExpiredBefore *time.Time `json:"offer_expired_before"`
+	Limit              int        `json:"limit"`
+	UseLock            bool       `json:"use_lock"`
+
+	// SkipLocked makes UseLock pass over rows another transaction holds,
+	// so concurrent offers move on to the next vehicle in the queue.
+	SkipLocked bool `json:"skip_locked"`
+}
+
+type UpdateWaitlist struct {
+	ID             uint
+	Status         string
+	ParkingSpotID  *uint
+	SpotID         string
+	OfferedAt      *time.Time
+	OfferExpiresAt *time.Time
+	ClosedAt       *time.Time
+}
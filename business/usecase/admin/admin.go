@@ -0,0 +1,63 @@
+package admin
+
+import (
+	"context"
+
+	auditDom "github.com/zuhrulumam/go-parking-lot/business/domain/audit"
+	lotDom "github.com/zuhrulumam/go-parking-lot/business/domain/lot"
+	outboxDom "github.com/zuhrulumam/go-parking-lot/business/domain/outbox"
+	parkingDom "github.com/zuhrulumam/go-parking-lot/business/domain/parking"
+	transactionDom "github.com/zuhrulumam/go-parking-lot/business/domain/transaction"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	parkingUc "github.com/zuhrulumam/go-parking-lot/business/usecase/parking"
+)
+
+// UsecaseItf manages the lots and their spot layout. Every change is
+// recorded in the audit trail within the same transaction.
+type UsecaseItf interface {
+	GetSpots(ctx context.Context, data entity.GetAvailableParkingSpot) ([]entity.ParkingSpot, error)
+	CreateSpots(ctx context.Context, data entity.CreateSpots) ([]entity.ParkingSpot, error)
+	UpdateSpot(ctx context.Context, data entity.UpdateSpot) (entity.ParkingSpot, error)
+	AddFloor(ctx context.Context, data entity.AddFloor) ([]entity.ParkingSpot, error)
+	RemoveFloor(ctx context.Context, data entity.RemoveFloor) (int, error)
+	GetAuditLogs(ctx context.Context, data entity.GetAuditLogs) ([]entity.AuditLog, error)
+	ApplyLayout(ctx context.Context, data entity.ApplyLayout) (entity.LayoutDiff, error)
+	ExportLayout(ctx context.Context, lotID uint) (entity.Layout, error)
+	GetLots(ctx context.Context) ([]entity.Lot, error)
+	GetLot(ctx context.Context, data entity.GetLot) (entity.Lot, error)
+	CreateLot(ctx context.Context, data entity.SaveLot) (entity.Lot, error)
+	UpdateLot(ctx context.Context, data entity.SaveLot) (entity.Lot, error)
+}
+
+type Option struct {
+	ParkingDom     parkingDom.DomainItf
+	LotDom         lotDom.DomainItf
+	AuditDom       auditDom.DomainItf
+	TransactionDom transactionDom.DomainItf
+
+	// OutboxDom receives SpotDeactivated, no events are written without it.
+	OutboxDom outboxDom.DomainItf
+
+	// Waitlist is told about every spot that becomes usable.
+	Waitlist parkingUc.SpotOfferer
+}
+
+type admin struct {
+	ParkingDom     parkingDom.DomainItf
+	LotDom         lotDom.DomainItf
+	AuditDom       auditDom.DomainItf
+	TransactionDom transactionDom.DomainItf
+	OutboxDom      outboxDom.DomainItf
+	Waitlist       parkingUc.SpotOfferer
+}
+
+func InitAdminUsecase(opt Option) UsecaseItf {
+	return &admin{
+		ParkingDom:     opt.ParkingDom,
+		LotDom:         opt.LotDom,
+		AuditDom:       opt.AuditDom,
+		TransactionDom: opt.TransactionDom,
+		OutboxDom:      opt.OutboxDom,
+		Waitlist:       opt.Waitlist,
+	}
+}
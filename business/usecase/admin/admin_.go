@@ -0,0 +1,473 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+const (
+	// maxSpotsPerRequest bounds bulk creation and new floors.
+	maxSpotsPerRequest = 1000
+
+	defaultAuditLimit = 50
+	maxAuditLimit     = 500
+
+	auditResourceSpot  = "spot"
+	auditResourceFloor = "floor"
+)
+
+// GetSpots lists spots with every flag, unlike Parking.AvailableSpot.
+func (a *admin) GetSpots(ctx context.Context, data entity.GetAvailableParkingSpot) ([]entity.ParkingSpot, error) {
+	if err := a.checkLot(ctx, data.LotID); err != nil {
+		return nil, err
+	}
+
+	data.UseLock = false
+
+	return a.ParkingDom.GetAvailableParkingSpot(ctx, data)
+}
+
+// CreateSpots adds spots at free positions, on existing floors or new ones.
+func (a *admin) CreateSpots(ctx context.Context, data entity.CreateSpots) ([]entity.ParkingSpot, error) {
+
+	if len(data.Spots) == 0 {
+		return nil, x.NewWithCode(http.StatusBadRequest, "spots are required")
+	}
+
+	if len(data.Spots) > maxSpotsPerRequest {
+		return nil, x.NewWithCode(http.StatusBadRequest, fmt.Sprintf("at most %d spots per request", maxSpotsPerRequest))
+	}
+
+	seen := map[entity.SpotID]bool{}
+	for _, s := range data.Spots {
+		if s.Floor < 1 || s.Row < 1 || s.Col < 1 {
+			return nil, x.NewWithCode(http.StatusBadRequest, "floor, row and col must be positive")
+		}
+
+		if !validSpotType(s.Type) {
+			return nil, x.NewWithCode(http.StatusBadRequest, fmt.Sprintf("unknown spot type %q", s.Type))
+		}
+
+		s.LotID = data.LotID
+		if seen[s.Position()] {
+			return nil, x.NewWithCode(http.StatusBadRequest, fmt.Sprintf("spot %s is listed twice", s.Position()))
+		}
+		seen[s.Position()] = true
+	}
+
+	if err := a.checkLot(ctx, data.LotID); err != nil {
+		return nil, err
+	}
+
+	var created []entity.ParkingSpot
+
+	err := a.TransactionDom.RunInTx(ctx, func(newCtx context.Context) error {
+		var err error
+		created, err = a.insertSpots(newCtx, data.LotID, data.Actor, entity.AuditSpotsCreated, data.Spots)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// UpdateSpot changes the type or features of a spot, or takes it in and out
+// of maintenance. Spots in use or held keep their type and stay active.
+func (a *admin) UpdateSpot(ctx context.Context, data entity.UpdateSpot) (entity.ParkingSpot, error) {
+
+	var result entity.ParkingSpot
+
+	sp, err := pkg.ParseSpotID(data.SpotID)
+	if err != nil || sp.Floor < 1 || sp.Row < 1 || sp.Col < 1 {
+		return result, x.NewWithCode(http.StatusBadRequest, "invalid spot_id")
+	}
+
+	if data.Type == "" && data.Active == nil && data.EVCharger == nil && data.Accessible == nil {
+		return result, x.NewWithCode(http.StatusBadRequest, "no updates provided")
+	}
+
+	if data.Type != "" && !validSpotType(data.Type) {
+		return result, x.NewWithCode(http.StatusBadRequest, fmt.Sprintf("unknown spot type %q", data.Type))
+	}
+
+	if err := a.checkLot(ctx, data.LotID); err != nil {
+		return result, err
+	}
+
+	if sp.Lot != data.LotID {
+		return result, x.NewWithCode(http.StatusBadRequest, "spot is in another lot")
+	}
+
+	err = a.TransactionDom.RunInTx(ctx, func(newCtx context.Context) error {
+
+		spots, err := a.ParkingDom.GetAvailableParkingSpot(newCtx, entity.GetAvailableParkingSpot{
+			LotID:   sp.Lot,
+			Floor:   sp.Floor,
+			Row:     sp.Row,
+			Col:     sp.Col,
+			UseLock: true,
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(spots) < 1 {
+			return x.NewWithCode(http.StatusNotFound, "spot not found")
+		}
+
+		before := spots[0]
+		after := before
+
+		if data.Type != "" {
+			after.Type = data.Type
+		}
+		if data.Active != nil {
+			after.Active = *data.Active
+		}
+		if data.EVCharger != nil {
+			after.EVCharger = *data.EVCharger
+		}
+		if data.Accessible != nil {
+			after.Accessible = *data.Accessible
+		}
+
+		// nothing parks on an unusable spot
+		if after.Type == entity.SpotTypeUnusable {
+			if data.Active != nil && *data.Active {
+				return x.NewWithCode(http.StatusUnprocessableEntity, "a spot of type X can't be active")
+			}
+			after.Active = false
+		}
+
+		changed := after.Type != before.Type || !after.Active
+		switch {
+		case before.Occupied && changed:
+			return x.NewWithCode(http.StatusConflict, "spot is occupied")
+		case before.Reserved && changed:
+			return x.NewWithCode(http.StatusConflict, "spot is held by a reservation or waitlist offer")
+		}
+
+		result = after
+		if after == before {
+			return nil
+		}
+
+		err = a.ParkingDom.UpdateParkingSpot(newCtx, entity.UpdateParkingSpot{
+			ID:         before.ID,
+			Type:       after.Type,
+			Active:     pkg.BoolPtr(after.Active),
+			EVCharger:  pkg.BoolPtr(after.EVCharger),
+			Accessible: pkg.BoolPtr(after.Accessible),
+		})
+		if err != nil {
+			return err
+		}
+
+		err = a.audit(newCtx, entity.AuditLog{
+			LotID:      data.LotID,
+			Actor:      data.Actor,
+			Action:     entity.AuditSpotUpdated,
+			Resource:   auditResourceSpot,
+			ResourceID: before.Position().String(),
+			Before:     toJSON(before),
+			After:      toJSON(after),
+			Reason:     data.Reason,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := a.deactivated(newCtx, before, after); err != nil {
+			return err
+		}
+
+		// back from maintenance, or now fits another queue
+		return a.offer(newCtx, after)
+	})
+	if err != nil {
+		return entity.ParkingSpot{}, err
+	}
+
+	return result, nil
+}
+
+// AddFloor creates a rows x cols floor with spots of one type.
+func (a *admin) AddFloor(ctx context.Context, data entity.AddFloor) ([]entity.ParkingSpot, error) {
+
+	if data.Floor < 1 || data.Rows < 1 || data.Cols < 1 {
+		return nil, x.NewWithCode(http.StatusBadRequest, "floor, rows and cols must be positive")
+	}
+
+	if data.Rows*data.Cols > maxSpotsPerRequest {
+		return nil, x.NewWithCode(http.StatusBadRequest, fmt.Sprintf("at most %d spots per floor", maxSpotsPerRequest))
+	}
+
+	if !validSpotType(data.Type) || data.Type == entity.SpotTypeUnusable {
+		return nil, x.NewWithCode(http.StatusBadRequest, fmt.Sprintf("unknown vehicle type %q", data.Type))
+	}
+
+	spots := make([]entity.ParkingSpot, 0, data.Rows*data.Cols)
+	for r := 1; r <= data.Rows; r++ {
+		for c := 1; c <= data.Cols; c++ {
+			spots = append(spots, entity.ParkingSpot{
+				LotID:  data.LotID,
+				Floor:  data.Floor,
+				Row:    r,
+				Col:    c,
+				Type:   data.Type,
+				Active: true,
+			})
+		}
+	}
+
+	if err := a.checkLot(ctx, data.LotID); err != nil {
+		return nil, err
+	}
+
+	var created []entity.ParkingSpot
+
+	err := a.TransactionDom.RunInTx(ctx, func(newCtx context.Context) error {
+
+		existing, err := a.ParkingDom.GetAvailableParkingSpot(newCtx, entity.GetAvailableParkingSpot{
+			LotID:   data.LotID,
+			Floor:   data.Floor,
+			UseLock: true,
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(existing) > 0 {
+			return x.NewWithCode(http.StatusConflict, fmt.Sprintf("floor %d already exists", data.Floor))
+		}
+
+		created, err = a.insertSpots(newCtx, data.LotID, data.Actor, entity.AuditFloorAdded, spots)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// RemoveFloor deletes every spot of a floor, refused while any of them is
+// occupied or held. Past sessions keep their spot_id.
+func (a *admin) RemoveFloor(ctx context.Context, data entity.RemoveFloor) (int, error) {
+
+	if data.Floor < 1 {
+		return 0, x.NewWithCode(http.StatusBadRequest, "floor must be positive")
+	}
+
+	if err := a.checkLot(ctx, data.LotID); err != nil {
+		return 0, err
+	}
+
+	var removed int
+
+	err := a.TransactionDom.RunInTx(ctx, func(newCtx context.Context) error {
+
+		spots, err := a.ParkingDom.GetAvailableParkingSpot(newCtx, entity.GetAvailableParkingSpot{
+			LotID:   data.LotID,
+			Floor:   data.Floor,
+			UseLock: true,
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(spots) < 1 {
+			return x.NewWithCode(http.StatusNotFound, fmt.Sprintf("floor %d not found", data.Floor))
+		}
+
+		for _, s := range spots {
+			if err := checkSpotFree(s); err != nil {
+				return err
+			}
+		}
+
+		removed, err = a.ParkingDom.DeleteParkingSpots(newCtx, entity.DeleteParkingSpots{
+			LotID: data.LotID,
+			Floor: data.Floor,
+		})
+		if err != nil {
+			return err
+		}
+
+		return a.audit(newCtx, entity.AuditLog{
+			LotID:      data.LotID,
+			Actor:      data.Actor,
+			Action:     entity.AuditFloorRemoved,
+			Resource:   auditResourceFloor,
+			ResourceID: strconv.Itoa(data.Floor),
+			Before:     toJSON(spots),
+			Reason:     data.Reason,
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return removed, nil
+}
+
+// GetAuditLogs returns the newest audit entries of a lot first.
+func (a *admin) GetAuditLogs(ctx context.Context, data entity.GetAuditLogs) ([]entity.AuditLog, error) {
+	if err := a.checkLot(ctx, data.LotID); err != nil {
+		return nil, err
+	}
+
+	if data.Limit < 1 {
+		data.Limit = defaultAuditLimit
+	}
+
+	if data.Limit > maxAuditLimit {
+		data.Limit = maxAuditLimit
+	}
+
+	return a.AuditDom.GetAuditLogs(ctx, data)
+}
+
+// insertSpots inserts spots at free positions of a lot, logs one audit entry
+// per floor touched and offers the usable ones to the waitlist.
+func (a *admin) insertSpots(ctx context.Context, lotID uint, actor, action string, spots []entity.ParkingSpot) ([]entity.ParkingSpot, error) {
+
+	floors := map[int][]entity.ParkingSpot{}
+	for _, s := range spots {
+		floors[s.Floor] = append(floors[s.Floor], s)
+	}
+
+	for floor := range floors {
+		existing, err := a.ParkingDom.GetAvailableParkingSpot(ctx, entity.GetAvailableParkingSpot{
+			LotID:   lotID,
+			Floor:   floor,
+			UseLock: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		taken := map[entity.SpotID]bool{}
+		for _, s := range existing {
+			taken[s.Position()] = true
+		}
+
+		for _, s := range floors[floor] {
+			s.LotID = lotID
+			if taken[s.Position()] {
+				return nil, x.NewWithCode(http.StatusConflict, fmt.Sprintf("spot %s already exists", s.Position()))
+			}
+		}
+	}
+
+	rows := make([]entity.ParkingSpot, len(spots))
+	for i, s := range spots {
+		rows[i] = entity.ParkingSpot{
+			LotID:      lotID,
+			Floor:      s.Floor,
+			Row:        s.Row,
+			Col:        s.Col,
+			Type:       s.Type,
+			Active:     s.Active && s.Type != entity.SpotTypeUnusable,
+			EVCharger:  s.EVCharger,
+			Accessible: s.Accessible,
+		}
+	}
+
+	created, err := a.ParkingDom.InsertParkingSpots(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	byFloor := map[int][]entity.ParkingSpot{}
+	for _, s := range created {
+		byFloor[s.Floor] = append(byFloor[s.Floor], s)
+	}
+
+	order := make([]int, 0, len(byFloor))
+	for floor := range byFloor {
+		order = append(order, floor)
+	}
+	sort.Ints(order)
+
+	for _, floor := range order {
+		err := a.audit(ctx, entity.AuditLog{
+			LotID:      lotID,
+			Actor:      actor,
+			Action:     action,
+			Resource:   auditResourceFloor,
+			ResourceID: strconv.Itoa(floor),
+			After:      toJSON(byFloor[floor]),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, s := range created {
+		if err := a.offer(ctx, s); err != nil {
+			return nil, err
+		}
+	}
+
+	return created, nil
+}
+
+func (a *admin) audit(ctx context.Context, data entity.AuditLog) error {
+	_, err := a.AuditDom.InsertAuditLog(ctx, data)
+	return err
+}
+
+// deactivated writes SpotDeactivated when a change takes a spot out of use.
+func (a *admin) deactivated(ctx context.Context, before, after entity.ParkingSpot) error {
+	if a.OutboxDom == nil || !before.Active || after.Active {
+		return nil
+	}
+
+	_, err := a.OutboxDom.InsertEvent(ctx, entity.NewOutboxEvent(after.LotID, entity.EventSpotDeactivated, after))
+	return err
+}
+
+// checkLot answers 404 for a lot that doesn't exist.
+func (a *admin) checkLot(ctx context.Context, lotID uint) error {
+	_, err := a.LotDom.GetLot(ctx, entity.GetLot{ID: lotID})
+	return err
+}
+
+// offer hands a usable spot to the waitlist of its type.
+func (a *admin) offer(ctx context.Context, spot entity.ParkingSpot) error {
+	if a.Waitlist == nil || !spot.Active || spot.Occupied || spot.Reserved {
+		return nil
+	}
+
+	return a.Waitlist.OfferSpot(ctx, spot.Position())
+}
+
+func validSpotType(t string) bool {
+	switch entity.VehicleType(t) {
+	case entity.Bicycle, entity.Motorcycle, entity.Automobile, entity.SpotTypeUnusable:
+		return true
+	}
+
+	return false
+}
+
+func toJSON(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+
+	return string(b)
+}
@@ -0,0 +1,226 @@
+package admin_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	auditDom "github.com/zuhrulumam/go-parking-lot/business/domain/audit"
+	lotDom "github.com/zuhrulumam/go-parking-lot/business/domain/lot"
+	parkingDom "github.com/zuhrulumam/go-parking-lot/business/domain/parking"
+	transactionDom "github.com/zuhrulumam/go-parking-lot/business/domain/transaction"
+	waitlistDom "github.com/zuhrulumam/go-parking-lot/business/domain/waitlist"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	uc "github.com/zuhrulumam/go-parking-lot/business/usecase/admin"
+	parkingUc "github.com/zuhrulumam/go-parking-lot/business/usecase/parking"
+	waitlistUc "github.com/zuhrulumam/go-parking-lot/business/usecase/waitlist"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+type lot struct {
+	parking  parkingUc.UsecaseItf
+	waitlist waitlistUc.UsecaseItf
+	admin    uc.UsecaseItf
+}
+
+// newLot is lot 1 with one floor holding a car spot and a motorcycle spot,
+// and an empty lot 2.
+func newLot() lot {
+	mem := memstore.New()
+
+	pDom := parkingDom.InitParkingDomain(parkingDom.Option{
+		Memory: mem,
+		Spots: []entity.ParkingSpot{
+			{LotID: 1, Floor: 1, Row: 1, Col: 1, Type: "A", Active: true},
+			{LotID: 1, Floor: 1, Row: 1, Col: 2, Type: "M", Active: true},
+		},
+	})
+	lDom := lotDom.InitLotDomain(lotDom.Option{
+		Memory: mem,
+		Lots:   []entity.Lot{{ID: 1, Name: "Main"}, {ID: 2, Name: "Annex"}},
+	})
+	wDom := waitlistDom.InitWaitlistDomain(waitlistDom.Option{Memory: mem})
+	txDom := transactionDom.Init(transactionDom.Option{Memory: mem})
+
+	waitlist := waitlistUc.InitWaitlistUsecase(waitlistUc.Option{
+		ParkingDom:     pDom,
+		LotDom:         lDom,
+		WaitlistDom:    wDom,
+		TransactionDom: txDom,
+	})
+
+	return lot{
+		parking: parkingUc.InitParkingUsecase(parkingUc.Option{
+			ParkingDom:     pDom,
+			LotDom:         lDom,
+			WaitlistDom:    wDom,
+			TransactionDom: txDom,
+			Waitlist:       waitlist,
+		}),
+		waitlist: waitlist,
+		admin: uc.InitAdminUsecase(uc.Option{
+			ParkingDom:     pDom,
+			LotDom:         lDom,
+			AuditDom:       auditDom.InitAuditDomain(auditDom.Option{Memory: mem}),
+			TransactionDom: txDom,
+			Waitlist:       waitlist,
+		}),
+	}
+}
+
+func TestCreateSpots(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        []entity.ParkingSpot
+		expectedCode int
+	}{
+		{
+			name: "new positions",
+			input: []entity.ParkingSpot{
+				{Floor: 1, Row: 2, Col: 1, Type: "B", Active: true},
+				{Floor: 2, Row: 1, Col: 1, Type: "X", Active: true},
+			},
+		},
+		{
+			name:         "existing position",
+			input:        []entity.ParkingSpot{{Floor: 1, Row: 1, Col: 1, Type: "B"}},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name: "position listed twice",
+			input: []entity.ParkingSpot{
+				{Floor: 3, Row: 1, Col: 1, Type: "B"},
+				{Floor: 3, Row: 1, Col: 1, Type: "A"},
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "unknown type",
+			input:        []entity.ParkingSpot{{Floor: 3, Row: 1, Col: 1, Type: "Z"}},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "no spots",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			l := newLot()
+
+			created, err := l.admin.CreateSpots(ctx, entity.CreateSpots{LotID: 1, Actor: "ops", Spots: tt.input})
+			if tt.expectedCode != 0 {
+				assert.EqualValues(t, tt.expectedCode, x.ErrCode(err))
+
+				logs, err := l.admin.GetAuditLogs(ctx, entity.GetAuditLogs{LotID: 1})
+				assert.NoError(t, err)
+				assert.Empty(t, logs)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, created, len(tt.input))
+			assert.False(t, created[1].Active, "X spots are never active")
+
+			logs, err := l.admin.GetAuditLogs(ctx, entity.GetAuditLogs{LotID: 1, Action: entity.AuditSpotsCreated})
+			assert.NoError(t, err)
+			assert.Len(t, logs, 2, "one entry per floor")
+			assert.Equal(t, "ops", logs[0].Actor)
+		})
+	}
+}
+
+func TestUpdateSpot(t *testing.T) {
+	ctx := context.Background()
+	l := newLot()
+
+	ticket, err := l.parking.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B1234XYZ", VehicleType: entity.Automobile})
+	assert.NoError(t, err)
+
+	_, err = l.admin.UpdateSpot(ctx, entity.UpdateSpot{LotID: 1, SpotID: ticket.SpotID, Active: pkg.BoolPtr(false)})
+	assert.EqualValues(t, http.StatusConflict, x.ErrCode(err), "occupied spots stay active")
+
+	_, err = l.admin.UpdateSpot(ctx, entity.UpdateSpot{LotID: 1, SpotID: ticket.SpotID, Type: "M"})
+	assert.EqualValues(t, http.StatusConflict, x.ErrCode(err), "occupied spots keep their type")
+
+	_, err = l.admin.UpdateSpot(ctx, entity.UpdateSpot{LotID: 1, SpotID: "1-9-9-9", Type: "M"})
+	assert.EqualValues(t, http.StatusNotFound, x.ErrCode(err))
+
+	_, err = l.admin.UpdateSpot(ctx, entity.UpdateSpot{LotID: 1, SpotID: "1-1-1-2", Type: "X", Active: pkg.BoolPtr(true)})
+	assert.EqualValues(t, http.StatusUnprocessableEntity, x.ErrCode(err))
+
+	spot, err := l.admin.UpdateSpot(ctx, entity.UpdateSpot{LotID: 1, Actor: "ops", SpotID: "1-1-1-2", Active: pkg.BoolPtr(false), Reason: "repaint"})
+	assert.NoError(t, err)
+	assert.False(t, spot.Active)
+
+	_, err = l.parking.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B0001XYZ", VehicleType: entity.Motorcycle})
+	assert.Error(t, err, "spots under maintenance are not used")
+
+	entry, err := l.waitlist.Join(ctx, entity.Park{LotID: 1, VehicleNumber: "B0002XYZ", VehicleType: entity.Automobile})
+	assert.NoError(t, err)
+	assert.Equal(t, entity.WaitlistWaiting, entry.Status)
+
+	// a motorcycle spot turned car spot goes to the car waiting
+	spot, err = l.admin.UpdateSpot(ctx, entity.UpdateSpot{LotID: 1, Actor: "ops", SpotID: "1-1-1-2", Type: "A", Active: pkg.BoolPtr(true)})
+	assert.NoError(t, err)
+	assert.Equal(t, "A", spot.Type)
+	assert.True(t, spot.Active)
+
+	entry, err = l.waitlist.GetEntry(ctx, entity.GetWaitlist{LotID: 1, Code: entry.Code})
+	assert.NoError(t, err)
+	assert.Equal(t, entity.WaitlistOffered, entry.Status)
+	assert.Equal(t, "1-1-1-2", entry.SpotID)
+
+	logs, err := l.admin.GetAuditLogs(ctx, entity.GetAuditLogs{LotID: 1, Resource: "spot", ResourceID: "1-1-1-2"})
+	assert.NoError(t, err)
+	assert.Len(t, logs, 2)
+	assert.Equal(t, entity.AuditSpotUpdated, logs[0].Action)
+	assert.Contains(t, logs[0].After, `"type":"A"`)
+	assert.Equal(t, "repaint", logs[1].Reason)
+}
+
+func TestFloors(t *testing.T) {
+	ctx := context.Background()
+	l := newLot()
+
+	_, err := l.admin.AddFloor(ctx, entity.AddFloor{LotID: 1, Floor: 1, Rows: 2, Cols: 2, Type: "A"})
+	assert.EqualValues(t, http.StatusConflict, x.ErrCode(err))
+
+	_, err = l.admin.AddFloor(ctx, entity.AddFloor{LotID: 1, Floor: 2, Rows: 100, Cols: 100, Type: "A"})
+	assert.EqualValues(t, http.StatusBadRequest, x.ErrCode(err))
+
+	spots, err := l.admin.AddFloor(ctx, entity.AddFloor{LotID: 1, Actor: "ops", Floor: 2, Rows: 2, Cols: 3, Type: "B"})
+	assert.NoError(t, err)
+	assert.Len(t, spots, 6)
+
+	ticket, err := l.parking.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B1234XYZ", VehicleType: entity.Bicycle})
+	assert.NoError(t, err)
+	assert.Equal(t, "1-2-1-1", ticket.SpotID)
+
+	_, err = l.admin.RemoveFloor(ctx, entity.RemoveFloor{LotID: 1, Floor: 2})
+	assert.EqualValues(t, http.StatusConflict, x.ErrCode(err), "a vehicle is parked there")
+
+	_, err = l.admin.RemoveFloor(ctx, entity.RemoveFloor{LotID: 1, Floor: 3})
+	assert.EqualValues(t, http.StatusNotFound, x.ErrCode(err))
+
+	removed, err := l.admin.RemoveFloor(ctx, entity.RemoveFloor{LotID: 1, Actor: "ops", Floor: 1, Reason: "demolished"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, removed)
+
+	left, err := l.admin.GetSpots(ctx, entity.GetAvailableParkingSpot{LotID: 1})
+	assert.NoError(t, err)
+	assert.Len(t, left, 6)
+
+	logs, err := l.admin.GetAuditLogs(ctx, entity.GetAuditLogs{LotID: 1, Resource: "floor", Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, entity.AuditFloorRemoved, logs[0].Action)
+	assert.Equal(t, "1", logs[0].ResourceID)
+	assert.Equal(t, "demolished", logs[0].Reason)
+}
@@ -0,0 +1,507 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+// maxLayoutSpots bounds the spots a layout file may describe.
+const maxLayoutSpots = 100000
+
+// ApplyLayout makes a lot match a layout: missing spots are created and
+// spots that differ are updated, in one transaction. Applying the same
+// layout twice changes nothing the second time.
+func (a *admin) ApplyLayout(ctx context.Context, data entity.ApplyLayout) (entity.LayoutDiff, error) {
+
+	var diff entity.LayoutDiff
+
+	desired, err := expandLayout(data.Layout)
+	if err != nil {
+		return diff, err
+	}
+
+	for i := range desired {
+		desired[i].LotID = data.LotID
+	}
+
+	if err := a.checkLot(ctx, data.LotID); err != nil {
+		return diff, err
+	}
+
+	err = a.TransactionDom.RunInTx(ctx, func(newCtx context.Context) error {
+
+		existing, err := a.ParkingDom.GetAvailableParkingSpot(newCtx, entity.GetAvailableParkingSpot{
+			LotID:   data.LotID,
+			UseLock: true,
+		})
+		if err != nil {
+			return err
+		}
+
+		diff = diffLayout(existing, desired, data.Prune)
+
+		// same rules as the admin API
+		for _, c := range diff.Update {
+			deactivated := c.Before.Active && !c.After.Active
+			if c.Before.Type == c.After.Type && !deactivated {
+				continue
+			}
+			if err := checkSpotFree(c.Before); err != nil {
+				return err
+			}
+		}
+		for _, s := range diff.Delete {
+			if err := checkSpotFree(s); err != nil {
+				return err
+			}
+		}
+
+		if data.DryRun || diff.Empty() {
+			return nil
+		}
+
+		created, err := a.ParkingDom.InsertParkingSpots(newCtx, diff.Create)
+		if err != nil {
+			return err
+		}
+		diff.Create = created
+
+		for _, c := range diff.Update {
+			err := a.ParkingDom.UpdateParkingSpot(newCtx, entity.UpdateParkingSpot{
+				ID:         c.Before.ID,
+				Type:       c.After.Type,
+				Active:     pkg.BoolPtr(c.After.Active),
+				EVCharger:  pkg.BoolPtr(c.After.EVCharger),
+				Accessible: pkg.BoolPtr(c.After.Accessible),
+			})
+			if err != nil {
+				return err
+			}
+
+			if err := a.deactivated(newCtx, c.Before, c.After); err != nil {
+				return err
+			}
+		}
+
+		if len(diff.Delete) > 0 {
+			ids := make([]uint, 0, len(diff.Delete))
+			for _, s := range diff.Delete {
+				ids = append(ids, s.ID)
+			}
+
+			_, err := a.ParkingDom.DeleteParkingSpots(newCtx, entity.DeleteParkingSpots{IDs: ids})
+			if err != nil {
+				return err
+			}
+		}
+
+		err = a.audit(newCtx, entity.AuditLog{
+			LotID:    data.LotID,
+			Actor:    data.Actor,
+			Action:   entity.AuditLayoutApply,
+			Resource: "layout",
+			After:    toJSON(diff),
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, s := range diff.Create {
+			if err := a.offer(newCtx, s); err != nil {
+				return err
+			}
+		}
+		for _, c := range diff.Update {
+			if err := a.offer(newCtx, c.After); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.LayoutDiff{}, err
+	}
+
+	return diff, nil
+}
+
+// ExportLayout describes a lot as a layout, each floor with its most common
+// spot type and overrides for the rest.
+func (a *admin) ExportLayout(ctx context.Context, lotID uint) (entity.Layout, error) {
+
+	if err := a.checkLot(ctx, lotID); err != nil {
+		return entity.Layout{}, err
+	}
+
+	spots, err := a.ParkingDom.GetAvailableParkingSpot(ctx, entity.GetAvailableParkingSpot{
+		LotID: lotID,
+	})
+	if err != nil {
+		return entity.Layout{}, err
+	}
+
+	floors := map[int][]entity.ParkingSpot{}
+	for _, s := range spots {
+		floors[s.Floor] = append(floors[s.Floor], s)
+	}
+
+	numbers := make([]int, 0, len(floors))
+	for f := range floors {
+		numbers = append(numbers, f)
+	}
+	sort.Ints(numbers)
+
+	layout := entity.Layout{Floors: []entity.LayoutFloor{}}
+	for _, f := range numbers {
+		layout.Floors = append(layout.Floors, exportFloor(f, floors[f]))
+	}
+
+	return layout, nil
+}
+
+// expandLayout validates a layout and returns its spots sorted by position.
+func expandLayout(l entity.Layout) ([]entity.ParkingSpot, error) {
+
+	var (
+		problems []string
+		spots    []entity.ParkingSpot
+		total    int
+		seen     = map[int]bool{}
+	)
+
+	if len(l.Floors) == 0 {
+		return nil, x.NewWithCode(http.StatusBadRequest, "invalid layout: no floors")
+	}
+
+	for i, f := range l.Floors {
+		path := fmt.Sprintf("floors[%d]", i)
+
+		switch {
+		case f.Floor < 1:
+			problems = append(problems, path+".floor: must be positive")
+		case seen[f.Floor]:
+			problems = append(problems, fmt.Sprintf("%s.floor: floor %d is listed twice", path, f.Floor))
+		}
+		seen[f.Floor] = true
+
+		if f.Rows < 1 || f.Cols < 1 {
+			problems = append(problems, path+": rows and cols must be positive")
+			continue
+		}
+
+		if !validSpotType(f.Type) {
+			problems = append(problems, fmt.Sprintf("%s.type: unknown spot type %q", path, f.Type))
+		}
+
+		total += f.Rows * f.Cols
+		if total > maxLayoutSpots {
+			return nil, x.NewWithCode(http.StatusBadRequest, fmt.Sprintf("invalid layout: more than %d spots", maxLayoutSpots))
+		}
+
+		grid := make([][]*entity.ParkingSpot, f.Rows+1)
+		for r := 1; r <= f.Rows; r++ {
+			grid[r] = make([]*entity.ParkingSpot, f.Cols+1)
+			for c := 1; c <= f.Cols; c++ {
+				grid[r][c] = &entity.ParkingSpot{Floor: f.Floor, Row: r, Col: c, Type: f.Type, Active: true}
+			}
+		}
+
+		for j, o := range f.Spots {
+			opath := fmt.Sprintf("%s.spots[%d]", path, j)
+
+			r1, r2, err := parseRange(o.Rows, f.Rows)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s.rows: %v", opath, err))
+			}
+
+			c1, c2, cerr := parseRange(o.Cols, f.Cols)
+			if cerr != nil {
+				problems = append(problems, fmt.Sprintf("%s.cols: %v", opath, cerr))
+			}
+
+			if o.Type != "" && !validSpotType(o.Type) {
+				problems = append(problems, fmt.Sprintf("%s.type: unknown spot type %q", opath, o.Type))
+			}
+
+			if err != nil || cerr != nil {
+				continue
+			}
+
+			for r := r1; r <= r2; r++ {
+				for c := c1; c <= c2; c++ {
+					if o.Skip {
+						grid[r][c] = nil
+						continue
+					}
+
+					if grid[r][c] == nil {
+						grid[r][c] = &entity.ParkingSpot{Floor: f.Floor, Row: r, Col: c, Type: f.Type, Active: true}
+					}
+
+					s := grid[r][c]
+					if o.Type != "" {
+						s.Type = o.Type
+					}
+					if o.Active != nil {
+						s.Active = *o.Active
+					}
+					if o.EVCharger != nil {
+						s.EVCharger = *o.EVCharger
+					}
+					if o.Accessible != nil {
+						s.Accessible = *o.Accessible
+					}
+				}
+			}
+		}
+
+		for r := 1; r <= f.Rows; r++ {
+			for c := 1; c <= f.Cols; c++ {
+				if s := grid[r][c]; s != nil {
+					s.Active = s.Active && s.Type != entity.SpotTypeUnusable
+					spots = append(spots, *s)
+				}
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, x.NewWithCode(http.StatusBadRequest, "invalid layout: "+strings.Join(problems, "; "))
+	}
+
+	sort.Slice(spots, func(i, j int) bool { return lessPosition(spots[i], spots[j]) })
+
+	return spots, nil
+}
+
+// diffLayout compares the lot with the spots of a layout by position.
+func diffLayout(existing, desired []entity.ParkingSpot, prune bool) entity.LayoutDiff {
+
+	var diff entity.LayoutDiff
+
+	current := map[entity.SpotID]entity.ParkingSpot{}
+	for _, s := range existing {
+		current[position(s)] = s
+	}
+
+	for _, want := range desired {
+		have, ok := current[position(want)]
+		if !ok {
+			diff.Create = append(diff.Create, want)
+			continue
+		}
+		delete(current, position(want))
+
+		after := have
+		after.Type = want.Type
+		after.Active = want.Active
+		after.EVCharger = want.EVCharger
+		after.Accessible = want.Accessible
+
+		if after != have {
+			diff.Update = append(diff.Update, entity.LayoutChange{
+				SpotID: have.Position().String(),
+				Before: have,
+				After:  after,
+			})
+		}
+	}
+
+	var rest []entity.ParkingSpot
+	for _, s := range current {
+		rest = append(rest, s)
+	}
+	sort.Slice(rest, func(i, j int) bool { return lessPosition(rest[i], rest[j]) })
+
+	if prune {
+		diff.Delete = rest
+	} else {
+		diff.Unmanaged = rest
+	}
+
+	return diff
+}
+
+// spotLook is what a layout says about a cell.
+type spotLook struct {
+	present    bool
+	typ        string
+	active     bool
+	evCharger  bool
+	accessible bool
+}
+
+type exportRun struct {
+	c1, c2 int
+	look   spotLook
+}
+
+func exportFloor(floor int, spots []entity.ParkingSpot) entity.LayoutFloor {
+
+	var (
+		rows, cols int
+		counts     = map[string]int{}
+		cells      = map[entity.SpotID]entity.ParkingSpot{}
+	)
+
+	for _, s := range spots {
+		rows = max(rows, s.Row)
+		cols = max(cols, s.Col)
+		counts[s.Type]++
+		cells[position(s)] = s
+	}
+
+	base := ""
+	for t, n := range counts {
+		if base == "" || n > counts[base] || (n == counts[base] && t < base) {
+			base = t
+		}
+	}
+
+	out := entity.LayoutFloor{Floor: floor, Rows: rows, Cols: cols, Type: base}
+	baseLook := spotLook{present: true, typ: base, active: base != entity.SpotTypeUnusable}
+
+	// overrides still growing over rows, by run
+	open := map[exportRun]int{}
+
+	for r := 1; r <= rows; r++ {
+		var runs []exportRun
+
+		for c := 1; c <= cols; c++ {
+			look := spotLook{}
+			if s, ok := cells[entity.SpotID{Floor: floor, Row: r, Col: c}]; ok {
+				look = spotLook{present: true, typ: s.Type, active: s.Active, evCharger: s.EVCharger, accessible: s.Accessible}
+			}
+
+			if n := len(runs); n > 0 && runs[n-1].look == look && runs[n-1].c2 == c-1 {
+				runs[n-1].c2 = c
+				continue
+			}
+			runs = append(runs, exportRun{c1: c, c2: c, look: look})
+		}
+
+		next := map[exportRun]int{}
+		for _, run := range runs {
+			if run.look == baseLook {
+				continue
+			}
+
+			if i, ok := open[run]; ok {
+				r1, _, _ := parseRange(out.Spots[i].Rows, rows)
+				out.Spots[i].Rows = formatRange(r1, r)
+				next[run] = i
+				continue
+			}
+
+			out.Spots = append(out.Spots, override(run, r, base))
+			next[run] = len(out.Spots) - 1
+		}
+		open = next
+	}
+
+	return out
+}
+
+func override(run exportRun, row int, base string) entity.LayoutOverride {
+	o := entity.LayoutOverride{
+		Rows: formatRange(row, row),
+		Cols: formatRange(run.c1, run.c2),
+	}
+
+	if !run.look.present {
+		o.Skip = true
+		return o
+	}
+
+	if run.look.typ != base {
+		o.Type = run.look.typ
+	}
+
+	// X spots are never active, nothing to say
+	if run.look.typ != entity.SpotTypeUnusable && !run.look.active {
+		o.Active = pkg.BoolPtr(false)
+	}
+
+	if run.look.evCharger {
+		o.EVCharger = pkg.BoolPtr(true)
+	}
+	if run.look.accessible {
+		o.Accessible = pkg.BoolPtr(true)
+	}
+
+	return o
+}
+
+// parseRange reads "3" or "1-5" within 1..limit, empty is the whole span.
+func parseRange(v string, limit int) (int, int, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 1, limit, nil
+	}
+
+	from, to, isRange := strings.Cut(v, "-")
+
+	lo, err := strconv.Atoi(strings.TrimSpace(from))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid range %q", v)
+	}
+
+	hi := lo
+	if isRange {
+		hi, err = strconv.Atoi(strings.TrimSpace(to))
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid range %q", v)
+		}
+	}
+
+	if lo < 1 || hi < lo || hi > limit {
+		return 0, 0, fmt.Errorf("%q is outside 1-%d", v, limit)
+	}
+
+	return lo, hi, nil
+}
+
+func formatRange(lo, hi int) string {
+	if lo == hi {
+		return strconv.Itoa(lo)
+	}
+
+	return fmt.Sprintf("%d-%d", lo, hi)
+}
+
+func checkSpotFree(s entity.ParkingSpot) error {
+	switch {
+	case s.Occupied:
+		return x.NewWithCode(http.StatusConflict, fmt.Sprintf("spot %s is occupied", s.Position()))
+	case s.Reserved:
+		return x.NewWithCode(http.StatusConflict, fmt.Sprintf("spot %s is held by a reservation or waitlist offer", s.Position()))
+	}
+
+	return nil
+}
+
+// position is where a spot is within its lot.
+func position(s entity.ParkingSpot) entity.SpotID {
+	return entity.SpotID{Floor: s.Floor, Row: s.Row, Col: s.Col}
+}
+
+func lessPosition(a, b entity.ParkingSpot) bool {
+	if a.Floor != b.Floor {
+		return a.Floor < b.Floor
+	}
+	if a.Row != b.Row {
+		return a.Row < b.Row
+	}
+
+	return a.Col < b.Col
+}
@@ -0,0 +1,182 @@
+package admin_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+// building is floor 1 of the test lot grown to 2x3, plus a second floor.
+func building() entity.Layout {
+	return entity.Layout{Floors: []entity.LayoutFloor{
+		{
+			Floor: 1, Rows: 2, Cols: 3, Type: "A",
+			Spots: []entity.LayoutOverride{
+				{Rows: "1", Cols: "2", Type: "M"},
+				{Rows: "2", Cols: "1-2", EVCharger: pkg.BoolPtr(true)},
+				{Rows: "2", Cols: "3", Type: "X"},
+			},
+		},
+		{
+			Floor: 2, Rows: 2, Cols: 2, Type: "B",
+			Spots: []entity.LayoutOverride{
+				{Rows: "1", Cols: "1", Accessible: pkg.BoolPtr(true), Active: pkg.BoolPtr(false)},
+				{Rows: "2", Cols: "2", Skip: true},
+			},
+		},
+	}}
+}
+
+func TestApplyLayout(t *testing.T) {
+	ctx := context.Background()
+	l := newLot()
+
+	plan, err := l.admin.ApplyLayout(ctx, entity.ApplyLayout{LotID: 1, Layout: building(), DryRun: true})
+	assert.NoError(t, err)
+	assert.Len(t, plan.Create, 7)
+	assert.Empty(t, plan.Update, "1-1-1-1 and 1-1-1-2 already match")
+
+	spots, err := l.admin.GetSpots(ctx, entity.GetAvailableParkingSpot{LotID: 1})
+	assert.NoError(t, err)
+	assert.Len(t, spots, 2, "a dry run changes nothing")
+
+	diff, err := l.admin.ApplyLayout(ctx, entity.ApplyLayout{LotID: 1, Actor: "seed", Layout: building()})
+	assert.NoError(t, err)
+	assert.Len(t, diff.Create, 7)
+
+	spots, err = l.admin.GetSpots(ctx, entity.GetAvailableParkingSpot{LotID: 1, Floor: 1, Row: 2})
+	assert.NoError(t, err)
+	assert.Len(t, spots, 3)
+	assert.True(t, spots[0].EVCharger)
+	assert.True(t, spots[1].EVCharger)
+	assert.Equal(t, "X", spots[2].Type)
+	assert.False(t, spots[2].Active, "X spots are never active")
+
+	spots, err = l.admin.GetSpots(ctx, entity.GetAvailableParkingSpot{LotID: 1, Floor: 2})
+	assert.NoError(t, err)
+	assert.Len(t, spots, 3, "skipped cells have no spot")
+	assert.True(t, spots[0].Accessible)
+	assert.False(t, spots[0].Active)
+
+	again, err := l.admin.ApplyLayout(ctx, entity.ApplyLayout{LotID: 1, Actor: "seed", Layout: building()})
+	assert.NoError(t, err)
+	assert.True(t, again.Empty(), "applying twice is a no-op")
+
+	logs, err := l.admin.GetAuditLogs(ctx, entity.GetAuditLogs{LotID: 1, Action: entity.AuditLayoutApply})
+	assert.NoError(t, err)
+	assert.Len(t, logs, 1)
+
+	spots, err = l.admin.GetSpots(ctx, entity.GetAvailableParkingSpot{LotID: 2})
+	assert.NoError(t, err)
+	assert.Empty(t, spots, "other lots are untouched")
+
+	_, err = l.admin.ApplyLayout(ctx, entity.ApplyLayout{LotID: 9, Layout: building()})
+	assert.EqualValues(t, http.StatusNotFound, x.ErrCode(err))
+}
+
+func TestApplyLayoutChanges(t *testing.T) {
+	ctx := context.Background()
+	l := newLot()
+
+	_, err := l.admin.ApplyLayout(ctx, entity.ApplyLayout{LotID: 1, Layout: building()})
+	assert.NoError(t, err)
+
+	ticket, err := l.parking.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B1234XYZ", VehicleType: entity.Motorcycle})
+	assert.NoError(t, err)
+	assert.Equal(t, "1-1-1-2", ticket.SpotID)
+
+	// turn the motorcycle spot into a car spot and drop floor 2
+	layout := building()
+	layout.Floors[0].Spots = layout.Floors[0].Spots[1:]
+	layout.Floors = layout.Floors[:1]
+
+	_, err = l.admin.ApplyLayout(ctx, entity.ApplyLayout{LotID: 1, Layout: layout})
+	assert.EqualValues(t, http.StatusConflict, x.ErrCode(err), "the spot is occupied")
+
+	layout.Floors[0].Spots = append(layout.Floors[0].Spots, entity.LayoutOverride{Rows: "1", Cols: "2", Type: "M", Accessible: pkg.BoolPtr(true)})
+
+	diff, err := l.admin.ApplyLayout(ctx, entity.ApplyLayout{LotID: 1, Layout: layout})
+	assert.NoError(t, err, "features change on occupied spots")
+	assert.Len(t, diff.Update, 1)
+	assert.Len(t, diff.Unmanaged, 3)
+	assert.Empty(t, diff.Delete)
+
+	diff, err = l.admin.ApplyLayout(ctx, entity.ApplyLayout{LotID: 1, Layout: layout, Prune: true})
+	assert.NoError(t, err)
+	assert.Len(t, diff.Delete, 3)
+
+	spots, err := l.admin.GetSpots(ctx, entity.GetAvailableParkingSpot{LotID: 1})
+	assert.NoError(t, err)
+	assert.Len(t, spots, 6)
+}
+
+func TestApplyLayoutValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout entity.Layout
+	}{
+		{
+			name: "no floors",
+		},
+		{
+			name:   "floor listed twice",
+			layout: entity.Layout{Floors: []entity.LayoutFloor{{Floor: 1, Rows: 1, Cols: 1, Type: "A"}, {Floor: 1, Rows: 1, Cols: 1, Type: "A"}}},
+		},
+		{
+			name:   "unknown type",
+			layout: entity.Layout{Floors: []entity.LayoutFloor{{Floor: 1, Rows: 1, Cols: 1, Type: "Z"}}},
+		},
+		{
+			name: "range outside the floor",
+			layout: entity.Layout{Floors: []entity.LayoutFloor{{Floor: 1, Rows: 2, Cols: 2, Type: "A",
+				Spots: []entity.LayoutOverride{{Rows: "1-3", Type: "M"}}}}},
+		},
+		{
+			name: "malformed range",
+			layout: entity.Layout{Floors: []entity.LayoutFloor{{Floor: 1, Rows: 2, Cols: 2, Type: "A",
+				Spots: []entity.LayoutOverride{{Cols: "two", Type: "M"}}}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newLot().admin.ApplyLayout(context.Background(), entity.ApplyLayout{LotID: 1, Layout: tt.layout})
+			assert.EqualValues(t, http.StatusBadRequest, x.ErrCode(err))
+		})
+	}
+}
+
+func TestExportLayout(t *testing.T) {
+	ctx := context.Background()
+	l := newLot()
+
+	_, err := l.admin.ApplyLayout(ctx, entity.ApplyLayout{LotID: 1, Layout: building()})
+	assert.NoError(t, err)
+
+	exported, err := l.admin.ExportLayout(ctx, 1)
+	assert.NoError(t, err)
+	assert.Len(t, exported.Floors, 2)
+	assert.Equal(t, "A", exported.Floors[0].Type)
+	assert.Equal(t, "B", exported.Floors[1].Type)
+
+	// the export describes the same lot
+	fresh := newLot()
+	_, err = fresh.admin.ApplyLayout(ctx, entity.ApplyLayout{LotID: 1, Layout: exported, Prune: true})
+	assert.NoError(t, err)
+
+	again, err := l.admin.ApplyLayout(ctx, entity.ApplyLayout{LotID: 1, Layout: exported, Prune: true})
+	assert.NoError(t, err)
+	assert.True(t, again.Empty())
+
+	want, err := l.admin.GetSpots(ctx, entity.GetAvailableParkingSpot{LotID: 1})
+	assert.NoError(t, err)
+	got, err := fresh.admin.GetSpots(ctx, entity.GetAvailableParkingSpot{LotID: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,110 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	parkingUc "github.com/zuhrulumam/go-parking-lot/business/usecase/parking"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+const auditResourceLot = "lot"
+
+func (a *admin) GetLots(ctx context.Context) ([]entity.Lot, error) {
+	return a.LotDom.GetLots(ctx)
+}
+
+func (a *admin) GetLot(ctx context.Context, data entity.GetLot) (entity.Lot, error) {
+	return a.LotDom.GetLot(ctx, data)
+}
+
+// CreateLot adds an empty lot, spots come from the layout routes.
+func (a *admin) CreateLot(ctx context.Context, data entity.SaveLot) (entity.Lot, error) {
+
+	var result entity.Lot
+
+	if err := validateLot(&data.Lot); err != nil {
+		return result, err
+	}
+
+	err := a.TransactionDom.RunInTx(ctx, func(newCtx context.Context) error {
+		var err error
+
+		result, err = a.LotDom.InsertLot(newCtx, data.Lot)
+		if err != nil {
+			return err
+		}
+
+		return a.audit(newCtx, entity.AuditLog{
+			LotID:      result.ID,
+			Actor:      data.Actor,
+			Action:     entity.AuditLotCreated,
+			Resource:   auditResourceLot,
+			ResourceID: strconv.FormatUint(uint64(result.ID), 10),
+			After:      toJSON(result),
+		})
+	})
+	if err != nil {
+		return entity.Lot{}, err
+	}
+
+	return result, nil
+}
+
+// UpdateLot replaces the name and settings of a lot. Sessions already
+// priced keep their fee.
+func (a *admin) UpdateLot(ctx context.Context, data entity.SaveLot) (entity.Lot, error) {
+
+	var result entity.Lot
+
+	if err := validateLot(&data.Lot); err != nil {
+		return result, err
+	}
+
+	err := a.TransactionDom.RunInTx(ctx, func(newCtx context.Context) error {
+
+		before, err := a.LotDom.GetLot(newCtx, entity.GetLot{ID: data.Lot.ID})
+		if err != nil {
+			return err
+		}
+
+		result, err = a.LotDom.UpdateLot(newCtx, data.Lot)
+		if err != nil {
+			return err
+		}
+		result.CreatedAt = before.CreatedAt
+
+		return a.audit(newCtx, entity.AuditLog{
+			LotID:      result.ID,
+			Actor:      data.Actor,
+			Action:     entity.AuditLotUpdated,
+			Resource:   auditResourceLot,
+			ResourceID: strconv.FormatUint(uint64(result.ID), 10),
+			Before:     toJSON(before),
+			After:      toJSON(result),
+		})
+	})
+	if err != nil {
+		return entity.Lot{}, err
+	}
+
+	return result, nil
+}
+
+// validateLot trims the lot and checks its settings resolve to a policy.
+func validateLot(lot *entity.Lot) error {
+	lot.Name = strings.TrimSpace(lot.Name)
+	if lot.Name == "" {
+		return x.NewWithCode(http.StatusBadRequest, "name is required")
+	}
+
+	if _, err := parkingUc.NewLotPolicy(*lot, parkingUc.LotPolicy{}); err != nil {
+		return x.WrapWithCode(err, http.StatusBadRequest, "invalid lot settings")
+	}
+
+	return nil
+}
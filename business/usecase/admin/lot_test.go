@@ -0,0 +1,128 @@
+package admin_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+func TestCreateLot(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        entity.Lot
+		expectedCode int
+	}{
+		{
+			name:  "name only",
+			input: entity.Lot{Name: " Harbour "},
+		},
+		{
+			name: "every setting",
+			input: entity.Lot{
+				Name:       "Harbour",
+				Allocation: "spread",
+				OpensAt:    "22:00",
+				ClosesAt:   "06:00",
+				Timezone:   "Asia/Jakarta",
+				Fallback:   true,
+				Reserve:    "A:2",
+			},
+		},
+		{
+			name:         "no name",
+			input:        entity.Lot{Name: "  "},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "unknown allocation",
+			input:        entity.Lot{Name: "Harbour", Allocation: "closest"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "opening without closing",
+			input:        entity.Lot{Name: "Harbour", OpensAt: "08:00"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "malformed hours",
+			input:        entity.Lot{Name: "Harbour", OpensAt: "8am", ClosesAt: "20:00"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "unknown timezone",
+			input:        entity.Lot{Name: "Harbour", Timezone: "Mars/Olympus"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "malformed reserve",
+			input:        entity.Lot{Name: "Harbour", Reserve: "A=2"},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			l := newLot()
+
+			created, err := l.admin.CreateLot(ctx, entity.SaveLot{Actor: "ops", Lot: tt.input})
+			if tt.expectedCode != 0 {
+				assert.EqualValues(t, tt.expectedCode, x.ErrCode(err))
+
+				lots, err := l.admin.GetLots(ctx)
+				assert.NoError(t, err)
+				assert.Len(t, lots, 2)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, uint(3), created.ID)
+			assert.Equal(t, "Harbour", created.Name)
+
+			got, err := l.admin.GetLot(ctx, entity.GetLot{ID: created.ID})
+			assert.NoError(t, err)
+			assert.Equal(t, created.Reserve, got.Reserve)
+
+			logs, err := l.admin.GetAuditLogs(ctx, entity.GetAuditLogs{LotID: created.ID, Resource: "lot"})
+			assert.NoError(t, err)
+			assert.Len(t, logs, 1)
+			assert.Equal(t, entity.AuditLotCreated, logs[0].Action)
+			assert.Equal(t, "ops", logs[0].Actor)
+		})
+	}
+}
+
+func TestUpdateLot(t *testing.T) {
+	ctx := context.Background()
+	l := newLot()
+
+	_, err := l.admin.UpdateLot(ctx, entity.SaveLot{Lot: entity.Lot{ID: 9, Name: "Nowhere"}})
+	assert.EqualValues(t, http.StatusNotFound, x.ErrCode(err))
+
+	_, err = l.admin.UpdateLot(ctx, entity.SaveLot{Lot: entity.Lot{ID: 2, Name: "Annex", OpensAt: "08:00", ClosesAt: "08:00"}})
+	assert.EqualValues(t, http.StatusBadRequest, x.ErrCode(err))
+
+	updated, err := l.admin.UpdateLot(ctx, entity.SaveLot{Actor: "ops", Lot: entity.Lot{ID: 2, Name: "Annex", Fallback: true}})
+	assert.NoError(t, err)
+	assert.True(t, updated.Fallback)
+
+	got, err := l.admin.GetLot(ctx, entity.GetLot{ID: 2})
+	assert.NoError(t, err)
+	assert.True(t, got.Fallback)
+
+	logs, err := l.admin.GetAuditLogs(ctx, entity.GetAuditLogs{LotID: 2, Resource: "lot", ResourceID: "2"})
+	assert.NoError(t, err)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, entity.AuditLotUpdated, logs[0].Action)
+	assert.Contains(t, logs[0].Before, `"fallback":false`)
+	assert.Contains(t, logs[0].After, `"fallback":true`)
+
+	logs, err = l.admin.GetAuditLogs(ctx, entity.GetAuditLogs{LotID: 1})
+	assert.NoError(t, err)
+	assert.Empty(t, logs, "audit entries belong to their lot")
+}
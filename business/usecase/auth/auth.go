@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	authDom "github.com/zuhrulumam/go-parking-lot/business/domain/auth"
+	lotDom "github.com/zuhrulumam/go-parking-lot/business/domain/lot"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+)
+
+// UsecaseItf tells who is calling the API: gate devices and services by
+// their API key, staff by the token they logged in for. It also manages
+// both.
+type UsecaseItf interface {
+	// Authenticate resolves an API key or token to its principal.
+	Authenticate(ctx context.Context, credential string) (entity.Principal, error)
+
+	// CreateAPIKey returns the key with its plain text, the only time it
+	// is shown.
+	CreateAPIKey(ctx context.Context, data entity.CreateAPIKey) (entity.APIKey, string, error)
+	GetAPIKeys(ctx context.Context) ([]entity.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id uint) error
+
+	CreateUser(ctx context.Context, data entity.SaveUser) (entity.User, error)
+	GetUsers(ctx context.Context) ([]entity.User, error)
+	UpdateUser(ctx context.Context, data entity.SaveUser) (entity.User, error)
+
+	// Login checks the password of a user and issues a token.
+	Login(ctx context.Context, data entity.Login) (entity.Token, error)
+	// IssueToken issues a token of an active user without the password.
+	IssueToken(ctx context.Context, username string) (entity.Token, error)
+}
+
+type Option struct {
+	AuthDom authDom.DomainItf
+	LotDom  lotDom.DomainItf
+
+	// Signer signs and verifies the tokens, they are refused while nil.
+	Signer *Signer
+
+	// TokenTTL is how long an issued token lasts, 12h when zero.
+	TokenTTL time.Duration
+}
+
+type auth struct {
+	AuthDom  authDom.DomainItf
+	LotDom   lotDom.DomainItf
+	Signer   *Signer
+	TokenTTL time.Duration
+}
+
+func InitAuthUsecase(opt Option) UsecaseItf {
+	if opt.TokenTTL <= 0 {
+		opt.TokenTTL = 12 * time.Hour
+	}
+
+	return &auth{
+		AuthDom:  opt.AuthDom,
+		LotDom:   opt.LotDom,
+		Signer:   opt.Signer,
+		TokenTTL: opt.TokenTTL,
+	}
+}
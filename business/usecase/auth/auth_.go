@@ -0,0 +1,333 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"golang.org/x/crypto/bcrypt"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+const (
+	// KeyPrefix starts every API key, telling them from tokens.
+	KeyPrefix = "pk_"
+
+	// keySize is the number of random bytes of a key.
+	keySize = 32
+
+	// prefixSize is how much of a key is kept in the clear.
+	prefixSize = len(KeyPrefix) + 8
+
+	// touchInterval is how stale last_used_at may get, so a busy gate
+	// doesn't write on every request.
+	touchInterval = time.Minute
+
+	minPasswordSize = 8
+)
+
+// dummyHash is compared against when the user doesn't exist, so a login
+// takes as long either way.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("not a password"), bcrypt.DefaultCost)
+
+func (a *auth) Authenticate(ctx context.Context, credential string) (entity.Principal, error) {
+
+	if credential == "" {
+		return entity.Principal{}, x.NewWithCode(http.StatusUnauthorized, "missing credentials")
+	}
+
+	if strings.HasPrefix(credential, KeyPrefix) {
+		return a.authenticateKey(ctx, credential)
+	}
+
+	if a.Signer == nil {
+		return entity.Principal{}, x.NewWithCode(http.StatusUnauthorized, "tokens are not accepted")
+	}
+
+	username, err := a.Signer.verify(credential)
+	if err != nil {
+		return entity.Principal{}, x.WrapWithCode(err, http.StatusUnauthorized, "invalid token")
+	}
+
+	// a disabled user or changed role applies to the tokens already out
+	user, err := a.activeUser(ctx, username)
+	if err != nil {
+		return entity.Principal{}, err
+	}
+
+	return entity.Principal{
+		Subject: user.Username,
+		Role:    user.Role,
+		Method:  entity.AuthToken,
+	}, nil
+}
+
+func (a *auth) authenticateKey(ctx context.Context, key string) (entity.Principal, error) {
+
+	keys, err := a.AuthDom.GetAPIKeys(ctx, entity.GetAPIKeys{
+		Hash:    hashKey(key),
+		Revoked: pkg.BoolPtr(false),
+	})
+	if err != nil {
+		return entity.Principal{}, err
+	}
+
+	if len(keys) == 0 {
+		return entity.Principal{}, x.NewWithCode(http.StatusUnauthorized, "invalid api key")
+	}
+
+	k := keys[0]
+
+	now := time.Now()
+	if k.LastUsedAt == nil || now.Sub(*k.LastUsedAt) > touchInterval {
+		// only bookkeeping, the gate gets in either way
+		_ = a.AuthDom.UpdateAPIKey(ctx, entity.UpdateAPIKey{ID: k.ID, LastUsedAt: &now})
+	}
+
+	return entity.Principal{
+		Subject: k.Name,
+		Role:    k.Role,
+		Method:  entity.AuthAPIKey,
+		LotID:   k.LotID,
+	}, nil
+}
+
+func (a *auth) CreateAPIKey(ctx context.Context, data entity.CreateAPIKey) (entity.APIKey, string, error) {
+
+	name := strings.TrimSpace(data.Name)
+	if name == "" {
+		return entity.APIKey{}, "", x.NewWithCode(http.StatusBadRequest, "name is required")
+	}
+
+	if !entity.ValidRole(data.Role) {
+		return entity.APIKey{}, "", x.NewWithCode(http.StatusBadRequest, "invalid role")
+	}
+
+	if data.LotID > 0 {
+		if _, err := a.LotDom.GetLot(ctx, entity.GetLot{ID: data.LotID}); err != nil {
+			return entity.APIKey{}, "", err
+		}
+	}
+
+	raw := make([]byte, keySize)
+	if _, err := rand.Read(raw); err != nil {
+		return entity.APIKey{}, "", x.WrapWithCode(err, http.StatusInternalServerError, "failed to generate api key")
+	}
+	key := KeyPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	created, err := a.AuthDom.InsertAPIKey(ctx, entity.APIKey{
+		Name:   name,
+		Prefix: key[:prefixSize],
+		Hash:   hashKey(key),
+		Role:   data.Role,
+		LotID:  data.LotID,
+	})
+	if err != nil {
+		return created, "", err
+	}
+
+	return created, key, nil
+}
+
+func (a *auth) GetAPIKeys(ctx context.Context) ([]entity.APIKey, error) {
+	return a.AuthDom.GetAPIKeys(ctx, entity.GetAPIKeys{})
+}
+
+func (a *auth) RevokeAPIKey(ctx context.Context, id uint) error {
+
+	if id < 1 {
+		return x.NewWithCode(http.StatusNotFound, "api key not found")
+	}
+
+	keys, err := a.AuthDom.GetAPIKeys(ctx, entity.GetAPIKeys{ID: id})
+	if err != nil {
+		return err
+	}
+
+	if len(keys) == 0 {
+		return x.NewWithCode(http.StatusNotFound, "api key not found")
+	}
+
+	if keys[0].RevokedAt != nil {
+		return x.NewWithCode(http.StatusConflict, "api key is already revoked")
+	}
+
+	return a.AuthDom.UpdateAPIKey(ctx, entity.UpdateAPIKey{ID: id, RevokedAt: pkg.TimePtr(time.Now())})
+}
+
+func (a *auth) CreateUser(ctx context.Context, data entity.SaveUser) (entity.User, error) {
+
+	username := strings.TrimSpace(data.Username)
+	if username == "" {
+		return entity.User{}, x.NewWithCode(http.StatusBadRequest, "username is required")
+	}
+
+	if data.Password == nil || data.Role == nil {
+		return entity.User{}, x.NewWithCode(http.StatusBadRequest, "password and role are required")
+	}
+
+	if !entity.ValidRole(*data.Role) {
+		return entity.User{}, x.NewWithCode(http.StatusBadRequest, "invalid role")
+	}
+
+	hash, err := hashPassword(*data.Password)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	return a.AuthDom.InsertUser(ctx, entity.User{
+		Username:     username,
+		PasswordHash: hash,
+		Role:         *data.Role,
+		Active:       data.Active == nil || *data.Active,
+	})
+}
+
+func (a *auth) GetUsers(ctx context.Context) ([]entity.User, error) {
+	return a.AuthDom.GetUsers(ctx, entity.GetUsers{})
+}
+
+// UpdateUser changes the password, role or active flag of the user ID, or
+// of Username when ID is 0.
+func (a *auth) UpdateUser(ctx context.Context, data entity.SaveUser) (entity.User, error) {
+
+	user, err := a.getUser(ctx, entity.GetUsers{ID: data.ID, Username: strings.TrimSpace(data.Username)})
+	if err != nil {
+		return user, err
+	}
+
+	update := entity.UpdateUser{
+		ID:     user.ID,
+		Role:   data.Role,
+		Active: data.Active,
+	}
+
+	if data.Role != nil && !entity.ValidRole(*data.Role) {
+		return user, x.NewWithCode(http.StatusBadRequest, "invalid role")
+	}
+
+	if data.Password != nil {
+		hash, err := hashPassword(*data.Password)
+		if err != nil {
+			return user, err
+		}
+		update.PasswordHash = &hash
+	}
+
+	if err := a.AuthDom.UpdateUser(ctx, update); err != nil {
+		return user, err
+	}
+
+	return a.getUser(ctx, entity.GetUsers{ID: user.ID})
+}
+
+func (a *auth) Login(ctx context.Context, data entity.Login) (entity.Token, error) {
+
+	users, err := a.AuthDom.GetUsers(ctx, entity.GetUsers{Username: strings.TrimSpace(data.Username)})
+	if err != nil {
+		return entity.Token{}, err
+	}
+
+	hash := dummyHash
+	if len(users) > 0 {
+		hash = []byte(users[0].PasswordHash)
+	}
+
+	if err := bcrypt.CompareHashAndPassword(hash, []byte(data.Password)); err != nil || len(users) == 0 || !users[0].Active {
+		return entity.Token{}, x.NewWithCode(http.StatusUnauthorized, "invalid username or password")
+	}
+
+	return a.issue(users[0])
+}
+
+func (a *auth) IssueToken(ctx context.Context, username string) (entity.Token, error) {
+
+	user, err := a.activeUser(ctx, username)
+	if err != nil {
+		return entity.Token{}, err
+	}
+
+	return a.issue(user)
+}
+
+func (a *auth) issue(user entity.User) (entity.Token, error) {
+
+	if !a.Signer.CanSign() {
+		return entity.Token{}, x.NewWithCode(http.StatusInternalServerError, "no key to sign tokens with")
+	}
+
+	now := time.Now()
+	token, err := a.Signer.sign(user.Username, user.Role, now, a.TokenTTL)
+	if err != nil {
+		return entity.Token{}, x.WrapWithCode(err, http.StatusInternalServerError, "failed to sign token")
+	}
+
+	return entity.Token{
+		Token:     token,
+		ExpiresAt: now.Add(a.TokenTTL),
+	}, nil
+}
+
+func (a *auth) getUser(ctx context.Context, data entity.GetUsers) (entity.User, error) {
+
+	if data.ID < 1 && data.Username == "" {
+		return entity.User{}, x.NewWithCode(http.StatusNotFound, "user not found")
+	}
+
+	users, err := a.AuthDom.GetUsers(ctx, data)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	if len(users) == 0 {
+		return entity.User{}, x.NewWithCode(http.StatusNotFound, "user not found")
+	}
+
+	return users[0], nil
+}
+
+// activeUser is the user a token is issued to or names, refused once
+// disabled or deleted.
+func (a *auth) activeUser(ctx context.Context, username string) (entity.User, error) {
+
+	user, err := a.getUser(ctx, entity.GetUsers{Username: username})
+	if err != nil {
+		if x.ErrCode(err) == http.StatusNotFound {
+			return user, x.WrapWithCode(err, http.StatusUnauthorized, "unknown user")
+		}
+		return user, err
+	}
+
+	if !user.Active {
+		return user, x.NewWithCode(http.StatusUnauthorized, "user is disabled")
+	}
+
+	return user, nil
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func hashPassword(password string) (string, error) {
+
+	if len(password) < minPasswordSize {
+		return "", x.NewWithCode(http.StatusBadRequest, "password must be at least 8 characters")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", x.WrapWithCode(err, http.StatusBadRequest, "invalid password")
+	}
+
+	return string(hash), nil
+}
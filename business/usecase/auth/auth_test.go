@@ -0,0 +1,176 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	authDom "github.com/zuhrulumam/go-parking-lot/business/domain/auth"
+	lotDom "github.com/zuhrulumam/go-parking-lot/business/domain/lot"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	uc "github.com/zuhrulumam/go-parking-lot/business/usecase/auth"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+func newAuth(t *testing.T, signer *uc.Signer) uc.UsecaseItf {
+	mem := memstore.New()
+
+	return uc.InitAuthUsecase(uc.Option{
+		AuthDom: authDom.InitAuthDomain(authDom.Option{Memory: mem}),
+		LotDom:  lotDom.InitLotDomain(lotDom.Option{Memory: mem, Lots: []entity.Lot{{ID: 1, Name: "Main"}}}),
+		Signer:  signer,
+	})
+}
+
+func keyFile(t *testing.T, content []byte) string {
+	path := filepath.Join(t.TempDir(), "jwt.key")
+	assert.NoError(t, os.WriteFile(path, content, 0o600))
+	return path
+}
+
+func hsSigner(t *testing.T) *uc.Signer {
+	s, err := uc.NewSigner(uc.AlgHS256, keyFile(t, []byte(strings.Repeat("s", 32)+"\n")))
+	assert.NoError(t, err)
+	return s
+}
+
+func TestAPIKey(t *testing.T) {
+	ctx := context.Background()
+	a := newAuth(t, nil)
+
+	_, _, err := a.CreateAPIKey(ctx, entity.CreateAPIKey{Name: "gate-1", Role: "root"})
+	assert.EqualValues(t, http.StatusBadRequest, x.ErrCode(err))
+
+	_, _, err = a.CreateAPIKey(ctx, entity.CreateAPIKey{Name: "gate-1", Role: entity.RoleGate, LotID: 9})
+	assert.EqualValues(t, http.StatusNotFound, x.ErrCode(err))
+
+	key, plain, err := a.CreateAPIKey(ctx, entity.CreateAPIKey{Name: "gate-1", Role: entity.RoleGate, LotID: 1})
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(plain, key.Prefix))
+
+	p, err := a.Authenticate(ctx, plain)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.Principal{Subject: "gate-1", Role: entity.RoleGate, Method: entity.AuthAPIKey, LotID: 1}, p)
+
+	keys, err := a.GetAPIKeys(ctx)
+	assert.NoError(t, err)
+	assert.NotNil(t, keys[0].LastUsedAt)
+
+	_, err = a.Authenticate(ctx, plain+"x")
+	assert.EqualValues(t, http.StatusUnauthorized, x.ErrCode(err))
+
+	assert.NoError(t, a.RevokeAPIKey(ctx, key.ID))
+	assert.EqualValues(t, http.StatusConflict, x.ErrCode(a.RevokeAPIKey(ctx, key.ID)))
+
+	_, err = a.Authenticate(ctx, plain)
+	assert.EqualValues(t, http.StatusUnauthorized, x.ErrCode(err))
+}
+
+func TestLogin(t *testing.T) {
+	ctx := context.Background()
+	a := newAuth(t, hsSigner(t))
+
+	_, err := a.CreateUser(ctx, entity.SaveUser{Username: "ana", Password: pkg.StringPtr("short"), Role: pkg.StringPtr(entity.RoleAttendant)})
+	assert.EqualValues(t, http.StatusBadRequest, x.ErrCode(err))
+
+	_, err = a.CreateUser(ctx, entity.SaveUser{Username: "ana", Password: pkg.StringPtr("correct horse"), Role: pkg.StringPtr(entity.RoleAttendant)})
+	assert.NoError(t, err)
+
+	_, err = a.Login(ctx, entity.Login{Username: "ana", Password: "wrong horse"})
+	assert.EqualValues(t, http.StatusUnauthorized, x.ErrCode(err))
+
+	_, err = a.Login(ctx, entity.Login{Username: "bob", Password: "correct horse"})
+	assert.EqualValues(t, http.StatusUnauthorized, x.ErrCode(err))
+
+	token, err := a.Login(ctx, entity.Login{Username: "ana", Password: "correct horse"})
+	assert.NoError(t, err)
+
+	p, err := a.Authenticate(ctx, token.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.Principal{Subject: "ana", Role: entity.RoleAttendant, Method: entity.AuthToken}, p)
+
+	// the tokens already out follow the user
+	_, err = a.UpdateUser(ctx, entity.SaveUser{Username: "ana", Role: pkg.StringPtr(entity.RoleReadonly)})
+	assert.NoError(t, err)
+
+	p, err = a.Authenticate(ctx, token.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.RoleReadonly, p.Role)
+
+	_, err = a.UpdateUser(ctx, entity.SaveUser{Username: "ana", Active: pkg.BoolPtr(false)})
+	assert.NoError(t, err)
+
+	_, err = a.Authenticate(ctx, token.Token)
+	assert.EqualValues(t, http.StatusUnauthorized, x.ErrCode(err))
+
+	_, err = a.Login(ctx, entity.Login{Username: "ana", Password: "correct horse"})
+	assert.EqualValues(t, http.StatusUnauthorized, x.ErrCode(err))
+}
+
+func TestRS256(t *testing.T) {
+	ctx := context.Background()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	private, err := uc.NewSigner(uc.AlgRS256, keyFile(t, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})))
+	assert.NoError(t, err)
+
+	pub, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+
+	public, err := uc.NewSigner(uc.AlgRS256, keyFile(t, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub})))
+	assert.NoError(t, err)
+	assert.False(t, public.CanSign())
+
+	// both servers share the users
+	mem := memstore.New()
+	dom := authDom.InitAuthDomain(authDom.Option{Memory: mem})
+	issuer := uc.InitAuthUsecase(uc.Option{AuthDom: dom, Signer: private})
+	verifier := uc.InitAuthUsecase(uc.Option{AuthDom: dom, Signer: public})
+
+	_, err = issuer.CreateUser(ctx, entity.SaveUser{Username: "root", Password: pkg.StringPtr("correct horse"), Role: pkg.StringPtr(entity.RoleAdmin)})
+	assert.NoError(t, err)
+
+	_, err = verifier.IssueToken(ctx, "root")
+	assert.EqualValues(t, http.StatusInternalServerError, x.ErrCode(err))
+
+	token, err := issuer.IssueToken(ctx, "root")
+	assert.NoError(t, err)
+
+	p, err := verifier.Authenticate(ctx, token.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.RoleAdmin, p.Role)
+
+	// a token of another algorithm is refused
+	hs, err := uc.InitAuthUsecase(uc.Option{AuthDom: dom, Signer: hsSigner(t)}).IssueToken(ctx, "root")
+	assert.NoError(t, err)
+
+	_, err = verifier.Authenticate(ctx, hs.Token)
+	assert.EqualValues(t, http.StatusUnauthorized, x.ErrCode(err))
+}
+
+func TestNewSigner(t *testing.T) {
+	_, err := uc.NewSigner(uc.AlgHS256, keyFile(t, []byte("too short")))
+	assert.Error(t, err)
+
+	_, err = uc.NewSigner(uc.AlgRS256, keyFile(t, []byte(strings.Repeat("s", 32))))
+	assert.Error(t, err)
+
+	_, err = uc.NewSigner("ES256", keyFile(t, []byte(strings.Repeat("s", 32))))
+	assert.Error(t, err)
+
+	_, err = uc.NewSigner(uc.AlgHS256, filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
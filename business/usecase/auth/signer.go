@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+
+	// tokenIssuer is the iss of the tokens, others are refused.
+	tokenIssuer = "go-parking-lot"
+
+	// minSecretSize is the shortest HS256 secret accepted, as long as
+	// the hash.
+	minSecretSize = 32
+)
+
+// Signer signs and verifies the tokens of the users with a key read from
+// a local file: the shared secret for HS256, a PEM key for RS256. A
+// public key only verifies, for servers that accept the tokens another
+// one issues.
+type Signer struct {
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// claims are the token claims, the role is informative: the user's
+// current one applies.
+type claims struct {
+	jwt.RegisteredClaims
+	Role string `json:"role"`
+}
+
+// NewSigner reads the key of alg, HS256 when empty, from keyFile.
+func NewSigner(alg, keyFile string) (*Signer, error) {
+	raw, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jwt key: %w", err)
+	}
+
+	switch strings.ToUpper(alg) {
+	case "", AlgHS256:
+		secret := []byte(strings.TrimSpace(string(raw)))
+		if len(secret) < minSecretSize {
+			return nil, fmt.Errorf("jwt secret must be at least %d bytes", minSecretSize)
+		}
+
+		return &Signer{method: jwt.SigningMethodHS256, signKey: secret, verifyKey: secret}, nil
+	case AlgRS256:
+		if key, err := jwt.ParseRSAPrivateKeyFromPEM(raw); err == nil {
+			return &Signer{method: jwt.SigningMethodRS256, signKey: key, verifyKey: &key.PublicKey}, nil
+		}
+
+		key, err := jwt.ParseRSAPublicKeyFromPEM(raw)
+		if err != nil {
+			return nil, fmt.Errorf("jwt key is neither an RSA private nor public key: %w", err)
+		}
+
+		return &Signer{method: jwt.SigningMethodRS256, verifyKey: key}, nil
+	default:
+		return nil, fmt.Errorf("unknown jwt algorithm %q", alg)
+	}
+}
+
+// CanSign reports whether the signer has the key to issue tokens.
+func (s *Signer) CanSign() bool {
+	return s != nil && s.signKey != nil
+}
+
+func (s *Signer) sign(subject, role string, now time.Time, ttl time.Duration) (string, error) {
+	return jwt.NewWithClaims(s.method, claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   subject,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+		Role: role,
+	}).SignedString(s.signKey)
+}
+
+// verify returns the subject of a valid token.
+func (s *Signer) verify(token string) (string, error) {
+	var c claims
+
+	_, err := jwt.ParseWithClaims(token, &c, func(*jwt.Token) (interface{}, error) {
+		return s.verifyKey, nil
+	},
+		jwt.WithValidMethods([]string{s.method.Alg()}),
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	if c.Subject == "" {
+		return "", fmt.Errorf("token has no subject")
+	}
+
+	return c.Subject, nil
+}
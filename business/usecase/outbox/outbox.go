@@ -0,0 +1,96 @@
+package outbox
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	outboxDom "github.com/zuhrulumam/go-parking-lot/business/domain/outbox"
+	transactionDom "github.com/zuhrulumam/go-parking-lot/business/domain/transaction"
+	webhookDom "github.com/zuhrulumam/go-parking-lot/business/domain/webhook"
+)
+
+// UsecaseItf sends the domain events written to the outbox on to the
+// sinks and the webhooks subscribed to them. Delivery is at least once: an
+// event is sent again to the sinks that failed it, with a growing backoff,
+// until every sink took it or MaxAttempts ran out.
+type UsecaseItf interface {
+	Relay(ctx context.Context) (int, error)
+}
+
+type Option struct {
+	OutboxDom      outboxDom.DomainItf
+	TransactionDom transactionDom.DomainItf
+
+	// WebhookDom holds the webhook subscriptions, events only go to the
+	// static Sinks without it.
+	WebhookDom webhookDom.DomainItf
+
+	// HTTPClient posts to the subscribed webhooks, http.DefaultClient when
+	// nil.
+	HTTPClient *http.Client
+
+	// Sinks defaults to a LogSink without a logger.
+	Sinks []Sink
+
+	// BatchSize is how many events one Relay sends at most, defaults to 100.
+	BatchSize int
+
+	// Backoff is the wait before the first retry, doubled after every
+	// failed attempt up to MaxBackoff. They default to 5 seconds and 10
+	// minutes.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	// MaxAttempts is how many times an event is tried before the relay
+	// gives up on it, defaults to 15.
+	MaxAttempts int
+}
+
+type outbox struct {
+	OutboxDom      outboxDom.DomainItf
+	TransactionDom transactionDom.DomainItf
+	WebhookDom     webhookDom.DomainItf
+	HTTPClient     *http.Client
+	Sinks          []Sink
+	BatchSize      int
+	Backoff        time.Duration
+	MaxBackoff     time.Duration
+	MaxAttempts    int
+}
+
+func InitOutboxUsecase(opt Option) UsecaseItf {
+	o := &outbox{
+		OutboxDom:      opt.OutboxDom,
+		TransactionDom: opt.TransactionDom,
+		WebhookDom:     opt.WebhookDom,
+		HTTPClient:     opt.HTTPClient,
+		Sinks:          opt.Sinks,
+		BatchSize:      opt.BatchSize,
+		Backoff:        opt.Backoff,
+		MaxBackoff:     opt.MaxBackoff,
+		MaxAttempts:    opt.MaxAttempts,
+	}
+
+	if len(o.Sinks) == 0 {
+		o.Sinks = []Sink{NewLogSink(nil)}
+	}
+
+	if o.BatchSize < 1 {
+		o.BatchSize = 100
+	}
+
+	if o.Backoff <= 0 {
+		o.Backoff = 5 * time.Second
+	}
+
+	if o.MaxBackoff < o.Backoff {
+		o.MaxBackoff = max(10*time.Minute, o.Backoff)
+	}
+
+	if o.MaxAttempts < 1 {
+		o.MaxAttempts = 15
+	}
+
+	return o
+}
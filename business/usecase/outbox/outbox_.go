@@ -0,0 +1,191 @@
+package outbox
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+)
+
+const (
+	// lease keeps a claimed batch away from the other relays. A relay that
+	// dies holding it lets the events go again once it runs out.
+	lease = 5 * time.Minute
+
+	// sendTimeout bounds one attempt to hand an event to a sink.
+	sendTimeout = 10 * time.Second
+)
+
+// Relay sends a batch of due events to the sinks that don't have them yet
+// and returns how many events every sink now has.
+//
+// The webhooks subscribed to an event are sinks of it too, as they are at
+// the time of sending: a webhook deactivated in between gets nothing more.
+func (o *outbox) Relay(ctx context.Context) (int, error) {
+
+	var (
+		events []entity.OutboxEvent
+		now    = time.Now()
+	)
+
+	// claim the batch, sending happens outside the transaction
+	err := o.TransactionDom.RunInTx(ctx, func(newCtx context.Context) error {
+		var err error
+
+		events, err = o.OutboxDom.GetEvents(newCtx, entity.GetOutboxEvents{
+			Pending:    true,
+			DueBy:      &now,
+			Limit:      o.BatchSize,
+			UseLock:    true,
+			SkipLocked: true,
+		})
+		if err != nil {
+			return err
+		}
+
+		until := now.Add(lease)
+		for _, e := range events {
+			err := o.OutboxDom.UpdateEvent(newCtx, entity.UpdateOutboxEvent{
+				ID:            e.ID,
+				NextAttemptAt: &until,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil || len(events) == 0 {
+		return 0, err
+	}
+
+	ids := make([]uint, 0, len(events))
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+
+	// sinks that took an event on an earlier attempt don't get it again
+	deliveries, err := o.OutboxDom.GetDeliveries(ctx, entity.GetEventDeliveries{
+		EventIDs: ids,
+		Status:   entity.DeliverySucceeded,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	webhooks, err := o.webhooks(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	done := map[uint]map[string]bool{}
+	for _, d := range deliveries {
+		if done[d.EventID] == nil {
+			done[d.EventID] = map[string]bool{}
+		}
+		done[d.EventID][d.Sink] = true
+	}
+
+	var dispatched int
+	for _, e := range events {
+		sinks := o.Sinks
+		for _, w := range webhooks {
+			if w.Wants(e) {
+				sinks = append(sinks[:len(sinks):len(sinks)], NewSubscriptionSink(w, o.HTTPClient))
+			}
+		}
+
+		ok, err := o.dispatch(ctx, e, sinks, done[e.ID])
+		if err != nil {
+			return dispatched, err
+		}
+		if ok {
+			dispatched++
+		}
+	}
+
+	return dispatched, nil
+}
+
+// webhooks returns the active webhook subscriptions.
+func (o *outbox) webhooks(ctx context.Context) ([]entity.Webhook, error) {
+	if o.WebhookDom == nil {
+		return nil, nil
+	}
+
+	return o.WebhookDom.GetWebhooks(ctx, entity.GetWebhooks{Active: pkg.BoolPtr(true)})
+}
+
+// dispatch sends an event to the sinks not in done, records every attempt
+// in the delivery log and schedules a retry when any of them failed, or
+// gives up on the event after MaxAttempts.
+func (o *outbox) dispatch(ctx context.Context, e entity.OutboxEvent, sinks []Sink, done map[string]bool) (bool, error) {
+
+	attempt := e.Attempts + 1
+
+	var failed []string
+	for _, s := range sinks {
+		if done[s.Name()] {
+			continue
+		}
+
+		sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+		err := s.Send(sendCtx, e, attempt)
+		cancel()
+
+		d := entity.EventDelivery{
+			EventID: e.ID,
+			Sink:    s.Name(),
+			Attempt: attempt,
+			Status:  entity.DeliverySucceeded,
+		}
+		if err != nil {
+			d.Status = entity.DeliveryFailed
+			d.Error = err.Error()
+			failed = append(failed, s.Name()+": "+err.Error())
+		}
+
+		if _, err := o.OutboxDom.InsertDelivery(ctx, d); err != nil {
+			return false, err
+		}
+	}
+
+	var (
+		now     = time.Now()
+		lastErr = strings.Join(failed, "; ")
+		update  = entity.UpdateOutboxEvent{
+			ID:        e.ID,
+			Attempts:  &attempt,
+			LastError: &lastErr,
+		}
+	)
+
+	switch {
+	case len(failed) == 0:
+		update.DispatchedAt = &now
+	case attempt >= o.MaxAttempts:
+		update.FailedAt = &now
+	default:
+		next := now.Add(o.backoff(attempt))
+		update.NextAttemptAt = &next
+	}
+
+	if err := o.OutboxDom.UpdateEvent(ctx, update); err != nil {
+		return false, err
+	}
+
+	return len(failed) == 0, nil
+}
+
+// backoff is the wait after the given failed attempt.
+func (o *outbox) backoff(attempt int) time.Duration {
+	d := o.Backoff
+	for i := 1; i < attempt && d < o.MaxBackoff; i++ {
+		d *= 2
+	}
+
+	return min(d, o.MaxBackoff)
+}
@@ -0,0 +1,273 @@
+package outbox_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	auditDom "github.com/zuhrulumam/go-parking-lot/business/domain/audit"
+	lotDom "github.com/zuhrulumam/go-parking-lot/business/domain/lot"
+	outboxDom "github.com/zuhrulumam/go-parking-lot/business/domain/outbox"
+	parkingDom "github.com/zuhrulumam/go-parking-lot/business/domain/parking"
+	reservationDom "github.com/zuhrulumam/go-parking-lot/business/domain/reservation"
+	tariffDom "github.com/zuhrulumam/go-parking-lot/business/domain/tariff"
+	transactionDom "github.com/zuhrulumam/go-parking-lot/business/domain/transaction"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	adminUc "github.com/zuhrulumam/go-parking-lot/business/usecase/admin"
+	uc "github.com/zuhrulumam/go-parking-lot/business/usecase/outbox"
+	parkingUc "github.com/zuhrulumam/go-parking-lot/business/usecase/parking"
+	reservationUc "github.com/zuhrulumam/go-parking-lot/business/usecase/reservation"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+)
+
+func TestDomainEvents(t *testing.T) {
+	ctx := context.Background()
+	mem := memstore.New()
+
+	pDom := parkingDom.InitParkingDomain(parkingDom.Option{
+		Memory: mem,
+		Spots: []entity.ParkingSpot{
+			{LotID: 1, Floor: 1, Row: 1, Col: 1, Type: "M", Active: true},
+			{LotID: 1, Floor: 1, Row: 1, Col: 2, Type: "M", Active: true},
+			{LotID: 1, Floor: 1, Row: 1, Col: 3, Type: "A", Active: true},
+		},
+	})
+	lDom := lotDom.InitLotDomain(lotDom.Option{Memory: mem, Lots: []entity.Lot{{ID: 1, Name: "Main"}}})
+	rDom := reservationDom.InitReservationDomain(reservationDom.Option{Memory: mem})
+	oDom := outboxDom.InitOutboxDomain(outboxDom.Option{Memory: mem})
+	txDom := transactionDom.Init(transactionDom.Option{Memory: mem})
+
+	parking := parkingUc.InitParkingUsecase(parkingUc.Option{
+		ParkingDom:     pDom,
+		LotDom:         lDom,
+		ReservationDom: rDom,
+		TransactionDom: txDom,
+		OutboxDom:      oDom,
+		TariffDom: tariffDom.InitTariffDomain(tariffDom.Option{
+			Memory:  mem,
+			Tariffs: []entity.Tariff{{LotID: 1, VehicleType: "M", FirstHourPrice: 2000, GracePeriodMinutes: 10}},
+		}),
+	})
+	reservation := reservationUc.InitReservationUsecase(reservationUc.Option{
+		ParkingDom:     pDom,
+		LotDom:         lDom,
+		ReservationDom: rDom,
+		TransactionDom: txDom,
+		OutboxDom:      oDom,
+	})
+	admin := adminUc.InitAdminUsecase(adminUc.Option{
+		ParkingDom:     pDom,
+		LotDom:         lDom,
+		AuditDom:       auditDom.InitAuditDomain(auditDom.Option{Memory: mem}),
+		TransactionDom: txDom,
+		OutboxDom:      oDom,
+	})
+
+	sink := uc.NewMemorySink("memory")
+	relay := uc.InitOutboxUsecase(uc.Option{OutboxDom: oDom, TransactionDom: txDom, Sinks: []uc.Sink{sink}})
+
+	ticket, err := parking.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B1234XYZ", VehicleType: entity.Motorcycle})
+	assert.NoError(t, err)
+
+	// refused, nothing to tell
+	_, err = parking.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B1234XYZ", VehicleType: entity.Motorcycle})
+	assert.Error(t, err)
+
+	_, err = parking.Unpark(ctx, entity.UnPark{LotID: 1, TicketID: ticket.TicketID})
+	assert.NoError(t, err)
+
+	_, err = admin.UpdateSpot(ctx, entity.UpdateSpot{LotID: 1, SpotID: "1-1-1-2", Active: pkg.BoolPtr(false)})
+	assert.NoError(t, err)
+
+	_, err = reservation.Reserve(ctx, entity.Reserve{LotID: 1, SpotID: "1-1-1-3", EndsAt: time.Now().Add(10 * time.Millisecond)})
+	assert.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+
+	_, err = reservation.ExpireReservations(ctx)
+	assert.NoError(t, err)
+
+	assert.Empty(t, sink.Events(), "nothing is sent before the relay runs")
+
+	n, err := relay.Relay(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, n)
+
+	var types []string
+	for _, e := range sink.Events() {
+		types = append(types, e.Type)
+		assert.Equal(t, uint(1), e.LotID)
+	}
+	assert.Equal(t, []string{entity.EventVehicleParked, entity.EventVehicleUnparked, entity.EventSpotDeactivated, entity.EventReservationExpired}, types)
+
+	var parked entity.Ticket
+	assert.NoError(t, json.Unmarshal(sink.Events()[0].Data, &parked))
+	assert.Equal(t, ticket.TicketID, parked.TicketID)
+
+	n, err = relay.Relay(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n, "sent once")
+}
+
+func TestRelayRetries(t *testing.T) {
+	ctx := context.Background()
+	mem := memstore.New()
+	oDom := outboxDom.InitOutboxDomain(outboxDom.Option{Memory: mem})
+
+	good, flaky := uc.NewMemorySink("good"), uc.NewMemorySink("flaky")
+	relay := uc.InitOutboxUsecase(uc.Option{
+		OutboxDom:      oDom,
+		TransactionDom: transactionDom.Init(transactionDom.Option{Memory: mem}),
+		Sinks:          []uc.Sink{good, flaky},
+		Backoff:        20 * time.Millisecond,
+	})
+
+	event, err := oDom.InsertEvent(ctx, entity.NewOutboxEvent(1, entity.EventVehicleParked, entity.Ticket{TicketID: "t1"}))
+	assert.NoError(t, err)
+
+	flaky.Fail(errors.New("unavailable"))
+
+	n, err := relay.Relay(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	// not due yet
+	flaky.Fail(nil)
+	n, err = relay.Relay(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	time.Sleep(30 * time.Millisecond)
+
+	n, err = relay.Relay(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	assert.Len(t, good.Events(), 1, "sinks that took it are not sent it again")
+	assert.Len(t, flaky.Events(), 1)
+
+	deliveries, err := oDom.GetDeliveries(ctx, entity.GetEventDeliveries{EventIDs: []uint{event.ID}})
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 3)
+	assert.Equal(t, entity.EventDelivery{ID: 3, EventID: event.ID, Sink: "flaky", Attempt: 2, Status: entity.DeliverySucceeded, CreatedAt: deliveries[0].CreatedAt}, deliveries[0])
+	assert.Equal(t, "unavailable", deliveries[1].Error)
+
+	events, err := oDom.GetEvents(ctx, entity.GetOutboxEvents{IDs: []uint{event.ID}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, events[0].Attempts)
+	assert.NotNil(t, events[0].DispatchedAt)
+	assert.Empty(t, events[0].LastError)
+}
+
+func TestRelayGivesUp(t *testing.T) {
+	ctx := context.Background()
+	mem := memstore.New()
+	oDom := outboxDom.InitOutboxDomain(outboxDom.Option{Memory: mem})
+
+	down := uc.NewMemorySink("down")
+	down.Fail(errors.New("unavailable"))
+
+	relay := uc.InitOutboxUsecase(uc.Option{
+		OutboxDom:      oDom,
+		TransactionDom: transactionDom.Init(transactionDom.Option{Memory: mem}),
+		Sinks:          []uc.Sink{down},
+		Backoff:        time.Millisecond,
+		MaxAttempts:    2,
+	})
+
+	event, err := oDom.InsertEvent(ctx, entity.NewOutboxEvent(1, entity.EventVehicleParked, entity.Ticket{TicketID: "t1"}))
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err := relay.Relay(ctx)
+		assert.NoError(t, err)
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	deliveries, err := oDom.GetDeliveries(ctx, entity.GetEventDeliveries{EventIDs: []uint{event.ID}})
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 2, "not tried after the last attempt")
+
+	events, err := oDom.GetEvents(ctx, entity.GetOutboxEvents{IDs: []uint{event.ID}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, events[0].Attempts)
+	assert.NotNil(t, events[0].FailedAt)
+	assert.Nil(t, events[0].DispatchedAt)
+	assert.Equal(t, "down: unavailable", events[0].LastError)
+}
+
+func TestWebhookSink(t *testing.T) {
+	var (
+		status = http.StatusNoContent
+		got    entity.OutboxEvent
+		body   []byte
+		header http.Header
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &got)
+		header = r.Header
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	sink := uc.NewWebhookSink(srv.URL, "", srv.Client())
+	event := entity.NewOutboxEvent(1, entity.EventVehicleParked, entity.Ticket{TicketID: "t1"})
+	event.ID = 7
+
+	assert.NoError(t, sink.Send(context.Background(), event, 1))
+	assert.Equal(t, "7", header.Get("X-Event-ID"))
+	assert.Equal(t, entity.EventVehicleParked, header.Get("X-Event-Type"))
+	assert.Equal(t, "1", header.Get("X-Delivery-Attempt"))
+	assert.Empty(t, header.Get("X-Signature"), "nothing to sign with")
+	assert.Equal(t, uint(7), got.ID)
+	assert.JSONEq(t, string(event.Data), string(got.Data))
+
+	status = http.StatusServiceUnavailable
+	assert.EqualError(t, sink.Send(context.Background(), event, 2), "webhook answered 503")
+
+	// the receiver checks the signature with the shared secret
+	status = http.StatusOK
+	sink = uc.NewWebhookSink(srv.URL, "s3cret", srv.Client())
+	assert.NoError(t, sink.Send(context.Background(), event, 3))
+	assert.Equal(t, "3", header.Get("X-Delivery-Attempt"))
+
+	sig := header.Get("X-Signature")
+	var ts int64
+	_, err := fmt.Sscanf(sig, "t=%d,", &ts)
+	assert.NoError(t, err)
+	assert.Equal(t, uc.Sign("s3cret", time.Unix(ts, 0), body), sig)
+	assert.NotEqual(t, uc.Sign("other", time.Unix(ts, 0), body), sig)
+
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte(fmt.Sprintf("%d.%s", ts, body)))
+	assert.Equal(t, fmt.Sprintf("t=%d,v1=%s", ts, hex.EncodeToString(mac.Sum(nil))), sig)
+}
+
+func TestNewSinks(t *testing.T) {
+	sinks, err := uc.NewSinks("", uc.SinkOption{})
+	assert.NoError(t, err)
+	assert.Len(t, sinks, 1)
+	assert.Equal(t, uc.SinkLog, sinks[0].Name())
+
+	sinks, err = uc.NewSinks("log, webhook", uc.SinkOption{WebhookURL: "http://billing.local/events"})
+	assert.NoError(t, err)
+	assert.Len(t, sinks, 2)
+
+	_, err = uc.NewSinks("webhook", uc.SinkOption{})
+	assert.Error(t, err, "webhook needs a url")
+
+	_, err = uc.NewSinks("kafka", uc.SinkOption{})
+	assert.Error(t, err)
+}
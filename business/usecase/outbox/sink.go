@@ -0,0 +1,218 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"go.uber.org/zap"
+)
+
+const (
+	SinkLog     = "log"
+	SinkWebhook = "webhook"
+)
+
+// Sink is where the relay sends events. Send may see an event more than
+// once, receivers tell them apart by id; attempt counts from 1.
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, e entity.OutboxEvent, attempt int) error
+}
+
+type SinkOption struct {
+	Log *zap.Logger
+
+	// WebhookURL is where SinkWebhook posts the events, signed with
+	// WebhookSecret when set.
+	WebhookURL    string
+	WebhookSecret string
+}
+
+// NewSinks returns the sinks named in a comma separated list. An empty
+// list selects SinkLog.
+func NewSinks(names string, opt SinkOption) ([]Sink, error) {
+	if strings.TrimSpace(names) == "" {
+		names = SinkLog
+	}
+
+	var sinks []Sink
+	for _, name := range strings.Split(names, ",") {
+		switch strings.TrimSpace(name) {
+		case SinkLog:
+			sinks = append(sinks, NewLogSink(opt.Log))
+		case SinkWebhook:
+			if opt.WebhookURL == "" {
+				return nil, fmt.Errorf("the %s sink needs a url", SinkWebhook)
+			}
+			sinks = append(sinks, NewWebhookSink(opt.WebhookURL, opt.WebhookSecret, nil))
+		default:
+			return nil, fmt.Errorf("unknown event sink %q", name)
+		}
+	}
+
+	return sinks, nil
+}
+
+// LogSink writes every event to the log, it never fails.
+type LogSink struct {
+	log *zap.Logger
+}
+
+func NewLogSink(log *zap.Logger) *LogSink {
+	if log == nil {
+		log = zap.NewNop()
+	}
+
+	return &LogSink{log: log}
+}
+
+func (s *LogSink) Name() string { return SinkLog }
+
+func (s *LogSink) Send(ctx context.Context, e entity.OutboxEvent, attempt int) error {
+	s.log.Info("domain event",
+		zap.Uint("id", e.ID),
+		zap.Int("attempt", attempt),
+		zap.Uint("lot_id", e.LotID),
+		zap.String("type", e.Type),
+		zap.ByteString("data", e.Data),
+	)
+
+	return nil
+}
+
+// WebhookSink posts every event as JSON to one URL. Any answer but a 2xx
+// is a failed attempt.
+//
+// Requests carry the event id and type and the attempt number in the
+// X-Event-ID, X-Event-Type and X-Delivery-Attempt headers. With a secret
+// they are signed in X-Signature, see Sign.
+type WebhookSink struct {
+	name   string
+	url    string
+	secret string
+	client *http.Client
+}
+
+// NewWebhookSink posts through client, http.DefaultClient when nil.
+func NewWebhookSink(url, secret string, client *http.Client) *WebhookSink {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &WebhookSink{name: SinkWebhook, url: url, secret: secret, client: client}
+}
+
+// NewSubscriptionSink posts the events to a subscribed webhook, logging the
+// deliveries under its own name.
+func NewSubscriptionSink(w entity.Webhook, client *http.Client) *WebhookSink {
+	s := NewWebhookSink(w.URL, w.Secret, client)
+	s.name = w.Sink()
+
+	return s
+}
+
+func (s *WebhookSink) Name() string { return s.name }
+
+func (s *WebhookSink) Send(ctx context.Context, e entity.OutboxEvent, attempt int) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", strconv.FormatUint(uint64(e.ID), 10))
+	req.Header.Set("X-Event-Type", e.Type)
+	req.Header.Set("X-Delivery-Attempt", strconv.Itoa(attempt))
+	if s.secret != "" {
+		req.Header.Set("X-Signature", Sign(s.secret, time.Now(), body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// let the connection be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Sign returns the X-Signature of a webhook body sent at t:
+// "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<unix seconds>.<body>">". The
+// receiver recomputes it with the shared secret and rejects old timestamps
+// to stop replays.
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// MemorySink keeps the events it was sent, for tests.
+type MemorySink struct {
+	name   string
+	mu     sync.Mutex
+	events []entity.OutboxEvent
+	err    error
+}
+
+func NewMemorySink(name string) *MemorySink {
+	return &MemorySink{name: name}
+}
+
+func (s *MemorySink) Name() string { return s.name }
+
+func (s *MemorySink) Send(ctx context.Context, e entity.OutboxEvent, attempt int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+
+	s.events = append(s.events, e)
+
+	return nil
+}
+
+// Fail makes Send return err until it is called again with nil.
+func (s *MemorySink) Fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err = err
+}
+
+// Events returns what was sent so far, oldest first.
+func (s *MemorySink) Events() []entity.OutboxEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]entity.OutboxEvent(nil), s.events...)
+}
@@ -0,0 +1,204 @@
+package parking
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+)
+
+const (
+	AllocationNearest   = "nearest"
+	AllocationFillFloor = "fill-floor"
+	AllocationSpread    = "spread"
+	AllocationRandom    = "random"
+)
+
+// AllocationStrategy decides which of the free candidate spots a vehicle
+// gets. Pick is only called with at least one candidate.
+type AllocationStrategy interface {
+	Pick(candidates []entity.ParkingSpot) entity.ParkingSpot
+}
+
+// LoadAwareStrategy is a strategy ranking floors by how full they are, Park
+// hands it the load of every floor of the lot along with the candidates.
+type LoadAwareStrategy interface {
+	AllocationStrategy
+	PickByLoad(candidates []entity.ParkingSpot, load FloorLoad) entity.ParkingSpot
+}
+
+// FloorLoad is the spot counts of each floor of a lot, all types summed.
+type FloorLoad map[int]entity.SpotCount
+
+// NewFloorLoad sums the counts of a lot per floor.
+func NewFloorLoad(counts []entity.SpotCount) FloorLoad {
+	load := FloorLoad{}
+	for _, c := range counts {
+		f := load[c.Floor]
+		f.Floor = c.Floor
+		f.Total += c.Total
+		f.Active += c.Active
+		f.Occupied += c.Occupied
+		f.Reserved += c.Reserved
+		f.Free += c.Free
+		load[c.Floor] = f
+	}
+
+	return load
+}
+
+// fuller reports whether floor a has a larger share of its active spots
+// taken than floor b.
+func (l FloorLoad) fuller(a, b int) bool {
+	fa, fb := l[a], l[b]
+	if fa.Active == 0 || fb.Active == 0 {
+		return fa.Active > 0 && fa.Free < fa.Active
+	}
+
+	// (active-free)/active compared without dividing
+	return (fa.Active-fa.Free)*fb.Active > (fb.Active-fb.Free)*fa.Active
+}
+
+// NewAllocationStrategy returns the strategy registered under name. An empty
+// name selects AllocationNearest.
+func NewAllocationStrategy(name string) (AllocationStrategy, error) {
+	switch name {
+	case "", AllocationNearest:
+		return NearestStrategy{}, nil
+	case AllocationFillFloor:
+		return FillFloorStrategy{}, nil
+	case AllocationSpread:
+		return SpreadStrategy{}, nil
+	case AllocationRandom:
+		return NewRandomStrategy(rand.New(rand.NewSource(time.Now().UnixNano()))), nil
+	default:
+		return nil, fmt.Errorf("unknown allocation strategy %q", name)
+	}
+}
+
+// NearestStrategy takes the spot closest to the entrance, that is the
+// lowest floor, then row, then column.
+type NearestStrategy struct{}
+
+func (NearestStrategy) Pick(candidates []entity.ParkingSpot) entity.ParkingSpot {
+	best := candidates[0]
+	for _, s := range candidates[1:] {
+		if lessPosition(s, best) {
+			best = s
+		}
+	}
+
+	return best
+}
+
+// FillFloorStrategy keeps filling the floor that is already the fullest,
+// i.e. has the largest share of its active spots taken, so whole floors
+// can be closed off when the lot is quiet. Without the load, Pick is
+// NearestStrategy.
+type FillFloorStrategy struct{}
+
+func (s FillFloorStrategy) Pick(candidates []entity.ParkingSpot) entity.ParkingSpot {
+	return s.PickByLoad(candidates, nil)
+}
+
+func (FillFloorStrategy) PickByLoad(candidates []entity.ParkingSpot, load FloorLoad) entity.ParkingSpot {
+	floors := candidateFloors(candidates)
+
+	best := floors[0]
+	for _, f := range floors[1:] {
+		if load.fuller(f, best) {
+			best = f
+		}
+	}
+
+	return NearestStrategy{}.Pick(onFloor(candidates, best))
+}
+
+// SpreadStrategy sends the vehicle to the floor with the most free
+// candidates, spreading load evenly across floors.
+type SpreadStrategy struct{}
+
+func (SpreadStrategy) Pick(candidates []entity.ParkingSpot) entity.ParkingSpot {
+	floor := pickFloor(candidates, func(free, bestFree int) bool { return free > bestFree })
+	return NearestStrategy{}.Pick(onFloor(candidates, floor))
+}
+
+// RandomStrategy picks a uniformly random candidate. Candidates are ordered
+// by position first, so a seeded source gives repeatable picks.
+type RandomStrategy struct {
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+func NewRandomStrategy(rnd *rand.Rand) *RandomStrategy {
+	return &RandomStrategy{rnd: rnd}
+}
+
+func (r *RandomStrategy) Pick(candidates []entity.ParkingSpot) entity.ParkingSpot {
+	sorted := append([]entity.ParkingSpot(nil), candidates...)
+	sort.Slice(sorted, func(i, j int) bool { return lessPosition(sorted[i], sorted[j]) })
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return sorted[r.rnd.Intn(len(sorted))]
+}
+
+func lessPosition(a, b entity.ParkingSpot) bool {
+	if a.Floor != b.Floor {
+		return a.Floor < b.Floor
+	}
+	if a.Row != b.Row {
+		return a.Row < b.Row
+	}
+	return a.Col < b.Col
+}
+
+// pickFloor returns the floor whose free count wins against every other per
+// better. Ties go to the lower floor.
+func pickFloor(candidates []entity.ParkingSpot, better func(free, bestFree int) bool) int {
+	free := map[int]int{}
+	for _, s := range candidates {
+		free[s.Floor]++
+	}
+
+	floors := candidateFloors(candidates)
+
+	best := floors[0]
+	for _, f := range floors[1:] {
+		if better(free[f], free[best]) {
+			best = f
+		}
+	}
+
+	return best
+}
+
+// candidateFloors returns the floors having candidates, lowest first.
+func candidateFloors(candidates []entity.ParkingSpot) []int {
+	seen := map[int]bool{}
+	floors := []int{}
+	for _, s := range candidates {
+		if !seen[s.Floor] {
+			seen[s.Floor] = true
+			floors = append(floors, s.Floor)
+		}
+	}
+	sort.Ints(floors)
+
+	return floors
+}
+
+func onFloor(candidates []entity.ParkingSpot, floor int) []entity.ParkingSpot {
+	var result []entity.ParkingSpot
+	for _, s := range candidates {
+		if s.Floor == floor {
+			result = append(result, s)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,156 @@
+package parking_test
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	lotDom "github.com/zuhrulumam/go-parking-lot/business/domain/lot"
+	parkingDom "github.com/zuhrulumam/go-parking-lot/business/domain/parking"
+	transactionDom "github.com/zuhrulumam/go-parking-lot/business/domain/transaction"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	uc "github.com/zuhrulumam/go-parking-lot/business/usecase/parking"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+)
+
+// allocationLot is two floors of 2x2 car spots, listed in reverse so the
+// strategies can't rely on insertion order. 1-2-1-1 starts occupied.
+func allocationLot() []entity.ParkingSpot {
+	var spots []entity.ParkingSpot
+	for f := 2; f >= 1; f-- {
+		for r := 2; r >= 1; r-- {
+			for c := 2; c >= 1; c-- {
+				spots = append(spots, entity.ParkingSpot{
+					LotID:    1,
+					Floor:    f,
+					Row:      r,
+					Col:      c,
+					Type:     string(entity.Automobile),
+					Active:   true,
+					Occupied: f == 2 && r == 1 && c == 1,
+				})
+			}
+		}
+	}
+
+	return spots
+}
+
+func TestNewAllocationStrategy(t *testing.T) {
+	for _, name := range []string{"", uc.AllocationNearest, uc.AllocationFillFloor, uc.AllocationSpread, uc.AllocationRandom} {
+		s, err := uc.NewAllocationStrategy(name)
+		assert.NoError(t, err)
+		assert.NotNil(t, s)
+	}
+
+	_, err := uc.NewAllocationStrategy("closest")
+	assert.Error(t, err)
+}
+
+func TestAllocationStrategies(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy uc.AllocationStrategy
+		expected []string
+	}{
+		{
+			name:     "nearest to entrance",
+			strategy: uc.NearestStrategy{},
+			expected: []string{"1-1-1-1", "1-1-1-2", "1-1-2-1", "1-1-2-2", "1-2-1-2"},
+		},
+		{
+			name:     "fill floor first",
+			strategy: uc.FillFloorStrategy{},
+			expected: []string{"1-2-1-2", "1-2-2-1", "1-2-2-2", "1-1-1-1", "1-1-1-2"},
+		},
+		{
+			name:     "spread evenly across floors",
+			strategy: uc.SpreadStrategy{},
+			expected: []string{"1-1-1-1", "1-1-1-2", "1-2-1-2", "1-1-2-1", "1-2-2-1"},
+		},
+		{
+			name:     "random with fixed seed",
+			strategy: uc.NewRandomStrategy(rand.New(rand.NewSource(42))),
+			expected: randomPicks(42, 5),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mem := memstore.New()
+			dom := parkingDom.InitParkingDomain(parkingDom.Option{
+				Memory: mem,
+				Spots:  allocationLot(),
+			})
+
+			usecase := uc.InitParkingUsecase(uc.Option{
+				ParkingDom:     dom,
+				LotDom:         lotDom.InitLotDomain(lotDom.Option{Memory: mem, Lots: []entity.Lot{{ID: 1}}}),
+				TransactionDom: transactionDom.Init(transactionDom.Option{Memory: mem}),
+				Allocation:     tt.strategy,
+			})
+
+			var got []string
+			for i := range tt.expected {
+				number := fmt.Sprintf("B%04dXYZ", i)
+				ticket, err := usecase.Park(ctx, entity.Park{LotID: 1, VehicleNumber: number, VehicleType: entity.Automobile})
+				assert.NoError(t, err)
+				got = append(got, ticket.SpotID)
+			}
+
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestFillFloorUnequalFloors(t *testing.T) {
+	ctx := context.Background()
+	mem := memstore.New()
+
+	// floor 1 is 2 spots, all free; floor 2 is 10 spots, 7 taken. Floor 2
+	// has more free spots left but is by far the fuller one.
+	spots := []entity.ParkingSpot{
+		{LotID: 1, Floor: 1, Row: 1, Col: 1, Type: string(entity.Automobile), Active: true},
+		{LotID: 1, Floor: 1, Row: 1, Col: 2, Type: string(entity.Automobile), Active: true},
+	}
+	for c := 1; c <= 10; c++ {
+		spots = append(spots, entity.ParkingSpot{
+			LotID: 1, Floor: 2, Row: 1, Col: c, Type: string(entity.Automobile), Active: true, Occupied: c <= 7,
+		})
+	}
+
+	usecase := uc.InitParkingUsecase(uc.Option{
+		ParkingDom:     parkingDom.InitParkingDomain(parkingDom.Option{Memory: mem, Spots: spots}),
+		LotDom:         lotDom.InitLotDomain(lotDom.Option{Memory: mem, Lots: []entity.Lot{{ID: 1}}}),
+		TransactionDom: transactionDom.Init(transactionDom.Option{Memory: mem}),
+		Allocation:     uc.FillFloorStrategy{},
+	})
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		ticket, err := usecase.Park(ctx, entity.Park{LotID: 1, VehicleNumber: fmt.Sprintf("B%04dXYZ", i), VehicleType: entity.Automobile})
+		assert.NoError(t, err)
+		got = append(got, ticket.SpotID)
+	}
+
+	assert.Equal(t, []string{"1-2-1-8", "1-2-1-9", "1-2-1-10", "1-1-1-1"}, got)
+}
+
+// randomPicks replays what RandomStrategy does on allocationLot with the
+// given seed, walking the free spots in position order.
+func randomPicks(seed int64, n int) []string {
+	rnd := rand.New(rand.NewSource(seed))
+	free := []string{"1-1-1-1", "1-1-1-2", "1-1-2-1", "1-1-2-2", "1-2-1-2", "1-2-2-1", "1-2-2-2"}
+
+	var result []string
+	for i := 0; i < n; i++ {
+		idx := rnd.Intn(len(free))
+		result = append(result, free[idx])
+		free = append(free[:idx], free[idx+1:]...)
+	}
+
+	return result
+}
@@ -0,0 +1,227 @@
+package parking
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	parkingDom "github.com/zuhrulumam/go-parking-lot/business/domain/parking"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+)
+
+// vehicleTypes are the vehicle types, smallest first.
+var vehicleTypes = []entity.VehicleType{entity.Bicycle, entity.Motorcycle, entity.Automobile}
+
+// Compatibility is the policy on which spot types a vehicle may use, Fits
+// is shared by every lot while Fallback and Reserve come from the lot. The
+// zero value only allows spots of the vehicle's own type.
+type Compatibility struct {
+	// Fits lists per vehicle type the spot types it fits in, its own type
+	// first and then the larger ones in order of preference.
+	Fits map[entity.VehicleType][]string
+
+	// Fallback lets a vehicle take a larger spot from Fits when no spot of
+	// its own type is free.
+	Fallback bool
+
+	// Reserve is how many free spots of a type are kept for vehicles of that
+	// type, fallback never takes the last ones.
+	Reserve map[string]int
+}
+
+// DefaultFits lets bicycles use motorcycle and car spots and motorcycles use
+// car spots.
+func DefaultFits() map[entity.VehicleType][]string {
+	return map[entity.VehicleType][]string{
+		entity.Bicycle:    {"B", "M", "A"},
+		entity.Motorcycle: {"M", "A"},
+		entity.Automobile: {"A"},
+	}
+}
+
+// NewCompatibility parses fits from its env form like "B:B,M,A;M:M,A", empty
+// selects DefaultFits. Fallback stays off until ForLot.
+func NewCompatibility(fits string) (Compatibility, error) {
+	c := Compatibility{
+		Fits: DefaultFits(),
+	}
+
+	if fits != "" {
+		c.Fits = map[entity.VehicleType][]string{}
+
+		for _, rule := range strings.Split(fits, ";") {
+			vt, list, ok := strings.Cut(strings.TrimSpace(rule), ":")
+			if !ok || !knownType(vt) {
+				return c, fmt.Errorf("invalid compatibility rule %q", rule)
+			}
+
+			types := []string{vt}
+			for _, t := range strings.Split(list, ",") {
+				t = strings.TrimSpace(t)
+				if !knownType(t) {
+					return c, fmt.Errorf("invalid compatibility rule %q: unknown spot type %q", rule, t)
+				}
+				if !slices.Contains(types, t) {
+					types = append(types, t)
+				}
+			}
+
+			c.Fits[entity.VehicleType(vt)] = types
+		}
+	}
+
+	return c, nil
+}
+
+// ParseReserve parses a reserve like "A:10,M:5", empty reserves nothing.
+func ParseReserve(reserve string) (map[string]int, error) {
+	result := map[string]int{}
+	if reserve == "" {
+		return result, nil
+	}
+
+	for _, rule := range strings.Split(reserve, ",") {
+		t, n, ok := strings.Cut(strings.TrimSpace(rule), ":")
+		if !ok || !knownType(t) {
+			return nil, fmt.Errorf("invalid reserve rule %q", rule)
+		}
+
+		count, err := strconv.Atoi(n)
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf("invalid reserve rule %q: count must be a non negative number", rule)
+		}
+
+		result[t] = count
+	}
+
+	return result, nil
+}
+
+// ForLot applies the fallback and reserve settings of a lot.
+func (c Compatibility) ForLot(lot entity.Lot) (Compatibility, error) {
+	reserve, err := ParseReserve(lot.Reserve)
+	if err != nil {
+		return c, err
+	}
+
+	if c.Fits == nil {
+		c.Fits = DefaultFits()
+	}
+	c.Fallback = lot.Fallback
+	c.Reserve = reserve
+
+	return c, nil
+}
+
+// SpotTypes returns the spot types a vehicle type may use, best fit first.
+func (c Compatibility) SpotTypes(vt entity.VehicleType) []string {
+	fits, ok := c.Fits[vt]
+	if !c.Fallback || !ok {
+		return []string{string(vt)}
+	}
+
+	return fits
+}
+
+// VehicleTypes returns the vehicle types that may use a spot type, its own
+// type first and then those for which it is the closest fallback.
+func (c Compatibility) VehicleTypes(spotType string) []entity.VehicleType {
+	result := []entity.VehicleType{entity.VehicleType(spotType)}
+	if !c.Fallback {
+		return result
+	}
+
+	rank := map[entity.VehicleType]int{}
+	for _, vt := range vehicleTypes {
+		if i := slices.Index(c.Fits[vt], spotType); i > 0 && string(vt) != spotType {
+			rank[vt] = i
+			result = append(result, vt)
+		}
+	}
+
+	sort.SliceStable(result[1:], func(i, j int) bool {
+		return rank[result[1+i]] < rank[result[1+j]]
+	})
+
+	return result
+}
+
+// Fit reports whether a vehicle type may use a spot type.
+func (c Compatibility) Fit(vt entity.VehicleType, spotType string) bool {
+	return slices.Contains(c.SpotTypes(vt), spotType)
+}
+
+// CanFallback reports whether a smaller vehicle may take one of the free
+// spots of a type without eating into its reserve.
+func (c Compatibility) CanFallback(spotType string, free int) bool {
+	return c.Fallback && free > c.Reserve[spotType]
+}
+
+// FreeSpots returns the free spots of a lot a vehicle type may take right
+// now: the spots of its own type, or when none is free the spots of the
+// first larger type with more free than its reserve. Nothing is locked,
+// take one of them with ClaimSpot.
+func (c Compatibility) FreeSpots(ctx context.Context, dom parkingDom.DomainItf, lotID uint, vt entity.VehicleType) ([]entity.ParkingSpot, error) {
+	for i, t := range c.SpotTypes(vt) {
+		spots, err := dom.GetAvailableParkingSpot(ctx, entity.GetAvailableParkingSpot{
+			LotID:       lotID,
+			VehicleType: entity.VehicleType(t),
+			Active:      pkg.BoolPtr(true),
+			Occupied:    pkg.BoolPtr(false),
+			Reserved:    pkg.BoolPtr(false),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(spots) > 0 && (i == 0 || c.CanFallback(t, len(spots))) {
+			return spots, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// ClaimSpot locks the candidate pick chooses, passing over the candidates
+// another transaction holds or has taken since they were read, and reports
+// whether one was left.
+func ClaimSpot(ctx context.Context, dom parkingDom.DomainItf, candidates []entity.ParkingSpot, pick func([]entity.ParkingSpot) entity.ParkingSpot) (entity.ParkingSpot, bool, error) {
+	candidates = slices.Clone(candidates)
+
+	for len(candidates) > 0 {
+		s := pick(candidates)
+
+		spots, err := dom.GetAvailableParkingSpot(ctx, entity.GetAvailableParkingSpot{
+			LotID:      s.LotID,
+			Floor:      s.Floor,
+			Row:        s.Row,
+			Col:        s.Col,
+			Active:     pkg.BoolPtr(true),
+			Occupied:   pkg.BoolPtr(false),
+			Reserved:   pkg.BoolPtr(false),
+			UseLock:    true,
+			SkipLocked: true,
+		})
+		if err != nil {
+			return entity.ParkingSpot{}, false, err
+		}
+
+		if len(spots) > 0 {
+			return spots[0], true, nil
+		}
+
+		candidates = slices.DeleteFunc(candidates, func(c entity.ParkingSpot) bool {
+			return c.LotID == s.LotID && c.Floor == s.Floor && c.Row == s.Row && c.Col == s.Col
+		})
+	}
+
+	return entity.ParkingSpot{}, false, nil
+}
+
+func knownType(t string) bool {
+	return slices.Contains(vehicleTypes, entity.VehicleType(t))
+}
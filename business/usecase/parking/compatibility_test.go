@@ -0,0 +1,163 @@
+package parking_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	lotDom "github.com/zuhrulumam/go-parking-lot/business/domain/lot"
+	parkingDom "github.com/zuhrulumam/go-parking-lot/business/domain/parking"
+	transactionDom "github.com/zuhrulumam/go-parking-lot/business/domain/transaction"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	uc "github.com/zuhrulumam/go-parking-lot/business/usecase/parking"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+func TestNewCompatibility(t *testing.T) {
+	c, err := uc.NewCompatibility("")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"B"}, c.SpotTypes(entity.Bicycle), "no fallback until a lot allows it")
+
+	c, err = c.ForLot(entity.Lot{Fallback: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"B", "M", "A"}, c.SpotTypes(entity.Bicycle))
+	assert.Equal(t, []entity.VehicleType{"A", "M", "B"}, c.VehicleTypes("A"))
+	assert.True(t, c.Fit(entity.Motorcycle, "A"))
+	assert.False(t, c.Fit(entity.Automobile, "M"))
+
+	c, err = uc.NewCompatibility("M:A; B:A,M")
+	assert.NoError(t, err)
+	c, err = c.ForLot(entity.Lot{Fallback: true, Reserve: "A:10"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"M", "A"}, c.SpotTypes(entity.Motorcycle), "own type comes first")
+	assert.Equal(t, []string{"B", "A", "M"}, c.SpotTypes(entity.Bicycle))
+	assert.Equal(t, []string{"A"}, c.SpotTypes(entity.Automobile))
+	assert.Equal(t, []entity.VehicleType{"A", "B", "M"}, c.VehicleTypes("A"), "closest fallback first")
+	assert.False(t, c.CanFallback("A", 10))
+	assert.True(t, c.CanFallback("A", 11))
+
+	c, err = c.ForLot(entity.Lot{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"B"}, c.SpotTypes(entity.Bicycle), "no fallback without the policy")
+	assert.Equal(t, []entity.VehicleType{"A"}, c.VehicleTypes("A"))
+
+	c, err = uc.Compatibility{}.ForLot(entity.Lot{Fallback: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"M", "A"}, c.SpotTypes(entity.Motorcycle), "fits default when unset")
+
+	for _, fits := range []string{"M", "M:X", "Q:A"} {
+		_, err = uc.NewCompatibility(fits)
+		assert.Error(t, err, fits)
+	}
+
+	for _, reserve := range []string{"A", "A:-1", "X:2"} {
+		_, err = uc.ParseReserve(reserve)
+		assert.Error(t, err, reserve)
+
+		_, err = uc.Compatibility{}.ForLot(entity.Lot{Fallback: true, Reserve: reserve})
+		assert.Error(t, err, reserve)
+	}
+}
+
+func TestParkFallback(t *testing.T) {
+	ctx := context.Background()
+
+	newLot := func(l entity.Lot) uc.UsecaseItf {
+		mem := memstore.New()
+		l.ID = 1
+		return uc.InitParkingUsecase(uc.Option{
+			ParkingDom: parkingDom.InitParkingDomain(parkingDom.Option{
+				Memory: mem,
+				Spots: []entity.ParkingSpot{
+					{LotID: 1, Floor: 1, Row: 1, Col: 1, Type: "A", Active: true},
+					{LotID: 1, Floor: 1, Row: 1, Col: 2, Type: "A", Active: true},
+					{LotID: 1, Floor: 1, Row: 1, Col: 3, Type: "M", Active: true},
+				},
+			}),
+			LotDom:         lotDom.InitLotDomain(lotDom.Option{Memory: mem, Lots: []entity.Lot{l}}),
+			TransactionDom: transactionDom.Init(transactionDom.Option{Memory: mem}),
+		})
+	}
+
+	motorcycle := func(number string) entity.Park {
+		return entity.Park{LotID: 1, VehicleNumber: number, VehicleType: entity.Motorcycle}
+	}
+
+	lot := newLot(entity.Lot{Fallback: true, Reserve: "A:1"})
+
+	// exact match first, even though car spots are nearer
+	ticket, err := lot.Park(ctx, motorcycle("B0001XYZ"))
+	assert.NoError(t, err)
+	assert.Equal(t, "1-1-1-3", ticket.SpotID)
+	assert.Equal(t, "M", ticket.SpotType)
+
+	spots, err := lot.AvailableSpot(ctx, entity.GetAvailablePark{LotID: 1, VehicleType: entity.Motorcycle})
+	assert.NoError(t, err)
+	assert.Len(t, spots, 2)
+
+	ticket, err = lot.Park(ctx, motorcycle("B0002XYZ"))
+	assert.NoError(t, err)
+	assert.Equal(t, "A", ticket.SpotType)
+	assert.Equal(t, entity.Motorcycle, ticket.VehicleType)
+
+	// the last car spot is kept for cars
+	_, err = lot.Park(ctx, motorcycle("B0003XYZ"))
+	assert.EqualValues(t, http.StatusConflict, x.ErrCode(err))
+	assert.Equal(t, uc.ErrNoAvailableParking, x.RootCause(err))
+
+	spots, err = lot.AvailableSpot(ctx, entity.GetAvailablePark{LotID: 1, VehicleType: entity.Motorcycle})
+	assert.NoError(t, err)
+	assert.Empty(t, spots)
+
+	_, err = lot.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B0004XYZ", VehicleType: entity.Automobile})
+	assert.NoError(t, err)
+
+	// without fallback a motorcycle only gets its own spot
+	lot = newLot(entity.Lot{})
+
+	_, err = lot.Park(ctx, motorcycle("B0001XYZ"))
+	assert.NoError(t, err)
+
+	_, err = lot.Park(ctx, motorcycle("B0002XYZ"))
+	assert.EqualValues(t, http.StatusConflict, x.ErrCode(err))
+}
+
+func TestClaimSpot(t *testing.T) {
+	ctx := context.Background()
+
+	dom := parkingDom.InitParkingDomain(parkingDom.Option{
+		Memory: memstore.New(),
+		Spots: []entity.ParkingSpot{
+			{LotID: 1, Floor: 1, Row: 1, Col: 1, Type: "A", Active: true},
+			{LotID: 1, Floor: 1, Row: 1, Col: 2, Type: "A", Active: true},
+		},
+	})
+
+	c, err := uc.NewCompatibility("")
+	assert.NoError(t, err)
+
+	spots, err := c.FreeSpots(ctx, dom, 1, entity.Automobile)
+	assert.NoError(t, err)
+	assert.Len(t, spots, 2)
+
+	// the nearest is taken after the candidates were read
+	err = dom.UpdateParkingSpot(ctx, entity.UpdateParkingSpot{LotID: 1, Floor: 1, Row: 1, Col: 1, Occupied: pkg.BoolPtr(true)})
+	assert.NoError(t, err)
+
+	spot, ok, err := uc.ClaimSpot(ctx, dom, spots, uc.NearestStrategy{}.Pick)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 2, spot.Col)
+	assert.Len(t, spots, 2, "the candidates are left alone")
+
+	err = dom.UpdateParkingSpot(ctx, entity.UpdateParkingSpot{LotID: 1, Floor: 1, Row: 1, Col: 2, Occupied: pkg.BoolPtr(true)})
+	assert.NoError(t, err)
+
+	_, ok, err = uc.ClaimSpot(ctx, dom, spots, uc.NearestStrategy{}.Pick)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,120 @@
+package parking
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	lotDom "github.com/zuhrulumam/go-parking-lot/business/domain/lot"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+// LotPolicy is how a lot hands out spots, its settings resolved against the
+// server defaults.
+type LotPolicy struct {
+	Allocation    AllocationStrategy
+	Compatibility Compatibility
+	Location      *time.Location
+
+	// opens and closes are minutes after midnight, equal when always open.
+	opens, closes int
+}
+
+// NewLotPolicy checks the settings of a lot and fills the ones it leaves
+// empty from defaults.
+func NewLotPolicy(lot entity.Lot, defaults LotPolicy) (LotPolicy, error) {
+	p := defaults
+
+	if lot.Allocation != "" {
+		a, err := NewAllocationStrategy(lot.Allocation)
+		if err != nil {
+			return p, err
+		}
+		p.Allocation = a
+	}
+
+	if lot.Timezone != "" {
+		loc, err := time.LoadLocation(lot.Timezone)
+		if err != nil {
+			return p, fmt.Errorf("unknown timezone %q", lot.Timezone)
+		}
+		p.Location = loc
+	}
+
+	c, err := defaults.Compatibility.ForLot(lot)
+	if err != nil {
+		return p, err
+	}
+	p.Compatibility = c
+
+	switch {
+	case lot.OpensAt == "" && lot.ClosesAt == "":
+		p.opens, p.closes = 0, 0
+	case lot.OpensAt == "" || lot.ClosesAt == "":
+		return p, fmt.Errorf("opens_at and closes_at go together")
+	default:
+		if p.opens, err = minuteOfDay(lot.OpensAt); err != nil {
+			return p, err
+		}
+		if p.closes, err = minuteOfDay(lot.ClosesAt); err != nil {
+			return p, err
+		}
+		if p.opens == p.closes {
+			return p, fmt.Errorf("opens_at and closes_at must differ")
+		}
+	}
+
+	if p.Allocation == nil {
+		p.Allocation = NearestStrategy{}
+	}
+
+	if p.Location == nil {
+		p.Location = time.Local
+	}
+
+	return p, nil
+}
+
+// LoadLotPolicy returns a lot and its policy, 404 when there is no such lot.
+func LoadLotPolicy(ctx context.Context, dom lotDom.DomainItf, lotID uint, defaults LotPolicy) (entity.Lot, LotPolicy, error) {
+	lot, err := dom.GetLot(ctx, entity.GetLot{ID: lotID})
+	if err != nil {
+		return lot, LotPolicy{}, err
+	}
+
+	p, err := NewLotPolicy(lot, defaults)
+	if err != nil {
+		return lot, p, x.WrapWithCode(err, http.StatusInternalServerError, "invalid lot settings")
+	}
+
+	return lot, p, nil
+}
+
+// IsOpen reports whether vehicles may enter the lot at t.
+func (p LotPolicy) IsOpen(t time.Time) bool {
+	if p.opens == p.closes {
+		return true
+	}
+
+	t = t.In(p.Location)
+	m := t.Hour()*60 + t.Minute()
+
+	if p.opens < p.closes {
+		return m >= p.opens && m < p.closes
+	}
+
+	// open across midnight
+	return m >= p.opens || m < p.closes
+}
+
+func minuteOfDay(v string) (int, error) {
+	t, err := time.Parse("15:04", v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, want HH:MM", v)
+	}
+
+	return t.Hour()*60 + t.Minute(), nil
+}
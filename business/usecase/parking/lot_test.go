@@ -0,0 +1,128 @@
+package parking_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	lotDom "github.com/zuhrulumam/go-parking-lot/business/domain/lot"
+	parkingDom "github.com/zuhrulumam/go-parking-lot/business/domain/parking"
+	transactionDom "github.com/zuhrulumam/go-parking-lot/business/domain/transaction"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	uc "github.com/zuhrulumam/go-parking-lot/business/usecase/parking"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+func TestNewLotPolicy(t *testing.T) {
+	p, err := uc.NewLotPolicy(entity.Lot{}, uc.LotPolicy{Allocation: uc.SpreadStrategy{}})
+	assert.NoError(t, err)
+	assert.Equal(t, uc.SpreadStrategy{}, p.Allocation, "empty settings keep the defaults")
+	assert.Equal(t, time.Local, p.Location)
+	assert.False(t, p.Compatibility.Fallback)
+
+	p, err = uc.NewLotPolicy(entity.Lot{Allocation: uc.AllocationFillFloor, Timezone: "Asia/Jakarta", Fallback: true}, uc.LotPolicy{})
+	assert.NoError(t, err)
+	assert.Equal(t, uc.FillFloorStrategy{}, p.Allocation)
+	assert.Equal(t, "Asia/Jakarta", p.Location.String())
+	assert.True(t, p.Compatibility.Fallback)
+
+	for _, lot := range []entity.Lot{
+		{Allocation: "closest"},
+		{Timezone: "Mars/Olympus"},
+		{Reserve: "A"},
+		{OpensAt: "08:00"},
+		{ClosesAt: "08:00"},
+		{OpensAt: "08:00", ClosesAt: "08:00"},
+		{OpensAt: "8am", ClosesAt: "20:00"},
+		{OpensAt: "08:00", ClosesAt: "24:00"},
+	} {
+		_, err = uc.NewLotPolicy(lot, uc.LotPolicy{})
+		assert.Error(t, err, lot)
+	}
+}
+
+func TestLotPolicyIsOpen(t *testing.T) {
+	at := func(clock string) time.Time {
+		tm, _ := time.ParseInLocation("2006-01-02 15:04", "2026-03-02 "+clock, time.UTC)
+		return tm
+	}
+
+	tests := []struct {
+		name   string
+		lot    entity.Lot
+		open   []string
+		closed []string
+	}{
+		{
+			name: "always open",
+			lot:  entity.Lot{},
+			open: []string{"00:00", "12:00", "23:59"},
+		},
+		{
+			name:   "daytime",
+			lot:    entity.Lot{OpensAt: "08:00", ClosesAt: "20:00"},
+			open:   []string{"08:00", "19:59"},
+			closed: []string{"07:59", "20:00", "23:00"},
+		},
+		{
+			name:   "across midnight",
+			lot:    entity.Lot{OpensAt: "22:00", ClosesAt: "06:00"},
+			open:   []string{"22:00", "00:00", "05:59"},
+			closed: []string{"06:00", "12:00", "21:59"},
+		},
+		{
+			name:   "in the lot's timezone",
+			lot:    entity.Lot{OpensAt: "08:00", ClosesAt: "20:00", Timezone: "Asia/Jakarta"},
+			open:   []string{"01:00", "12:59"},
+			closed: []string{"00:59", "13:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := uc.NewLotPolicy(tt.lot, uc.LotPolicy{Location: time.UTC})
+			assert.NoError(t, err)
+
+			for _, c := range tt.open {
+				assert.True(t, p.IsOpen(at(c)), c)
+			}
+			for _, c := range tt.closed {
+				assert.False(t, p.IsOpen(at(c)), c)
+			}
+		})
+	}
+}
+
+func TestParkClosedLot(t *testing.T) {
+	ctx := context.Background()
+	mem := memstore.New()
+
+	// open for an hour starting an hour from now
+	now := time.Now().UTC()
+	lot := entity.Lot{
+		ID:       1,
+		OpensAt:  now.Add(time.Hour).Format("15:04"),
+		ClosesAt: now.Add(2 * time.Hour).Format("15:04"),
+		Timezone: "UTC",
+	}
+
+	usecase := uc.InitParkingUsecase(uc.Option{
+		ParkingDom: parkingDom.InitParkingDomain(parkingDom.Option{
+			Memory: mem,
+			Spots:  []entity.ParkingSpot{{LotID: 1, Floor: 1, Row: 1, Col: 1, Type: "A", Active: true}},
+		}),
+		LotDom:         lotDom.InitLotDomain(lotDom.Option{Memory: mem, Lots: []entity.Lot{lot}}),
+		TransactionDom: transactionDom.Init(transactionDom.Option{Memory: mem}),
+	})
+
+	_, err := usecase.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B1234XYZ", VehicleType: entity.Automobile})
+	assert.EqualValues(t, http.StatusConflict, x.ErrCode(err))
+
+	spots, err := usecase.AvailableSpot(ctx, entity.GetAvailablePark{LotID: 1, VehicleType: entity.Automobile})
+	assert.NoError(t, err)
+	assert.Len(t, spots, 1, "the spot is still free")
+}
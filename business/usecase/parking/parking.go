@@ -2,34 +2,140 @@ package parking
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	lotDom "github.com/zuhrulumam/go-parking-lot/business/domain/lot"
+	outboxDom "github.com/zuhrulumam/go-parking-lot/business/domain/outbox"
 	parkingDom "github.com/zuhrulumam/go-parking-lot/business/domain/parking"
+	paymentDom "github.com/zuhrulumam/go-parking-lot/business/domain/payment"
+	reservationDom "github.com/zuhrulumam/go-parking-lot/business/domain/reservation"
+	spotFeedDom "github.com/zuhrulumam/go-parking-lot/business/domain/spotfeed"
+	tariffDom "github.com/zuhrulumam/go-parking-lot/business/domain/tariff"
 	transactionDom "github.com/zuhrulumam/go-parking-lot/business/domain/transaction"
+	waitlistDom "github.com/zuhrulumam/go-parking-lot/business/domain/waitlist"
 	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg/metrics"
 )
 
+// ErrNoAvailableParking is the root cause of Park failing because every
+// spot of the vehicle type is taken or held.
+var ErrNoAvailableParking = errors.New("no available parking")
+
+// SpotOfferer hands a spot that just became free to whoever is waiting for
+// it, instead of leaving it to the next walk-in.
+type SpotOfferer interface {
+	OfferSpot(ctx context.Context, spot entity.SpotID) error
+}
+
 type UsecaseItf interface {
-	Park(ctx context.Context, data entity.Park) error
-	Unpark(ctx context.Context, data entity.UnPark) error
+	Park(ctx context.Context, data entity.Park) (entity.Ticket, error)
+	Unpark(ctx context.Context, data entity.UnPark) (entity.Receipt, error)
+	AuthorizeExit(ctx context.Context, data entity.AuthorizeExit) (entity.Receipt, error)
+	Quote(ctx context.Context, data entity.UnPark) (entity.Receipt, error)
 	AvailableSpot(ctx context.Context, data entity.GetAvailablePark) ([]entity.ParkingSpot, error)
+	Occupancy(ctx context.Context, lotID uint) (entity.Occupancy, error)
+	WatchOccupancy(ctx context.Context, lotID uint) (<-chan entity.OccupancyEvent, error)
 	SearchVehicle(ctx context.Context, data entity.SearchVehicle) (entity.Vehicle, error)
+	GetSessions(ctx context.Context, data entity.GetSessions) (entity.SessionPage, error)
 }
 
 type Option struct {
 	ParkingDom     parkingDom.DomainItf
+	LotDom         lotDom.DomainItf
 	TransactionDom transactionDom.DomainItf
+	TariffDom      tariffDom.DomainItf
+	PaymentDom     paymentDom.DomainItf
+	ReservationDom reservationDom.DomainItf
+	WaitlistDom    waitlistDom.DomainItf
+	SpotFeedDom    spotFeedDom.DomainItf
+
+	// OutboxDom receives VehicleParked and VehicleUnparked, no events are
+	// written without it.
+	OutboxDom outboxDom.DomainItf
+
+	// Waitlist is told about every spot freed by an exit.
+	Waitlist SpotOfferer
+
+	// Allocation picks the spot for Park in lots without their own,
+	// defaults to NearestStrategy.
+	Allocation AllocationStrategy
+
+	// Compatibility decides which spot types a vehicle may park in, each
+	// lot turns fallback on and sets its reserve.
+	Compatibility Compatibility
+
+	// Location is where opening hours and night and weekend tariffs are
+	// evaluated in lots without a timezone, defaults to time.Local.
+	Location *time.Location
+
+	// Resync is how often WatchOccupancy sends a fresh snapshot, defaults
+	// to 30 seconds.
+	Resync time.Duration
+
+	// Metrics counts the parks and unparks, metrics.Noop when nil.
+	Metrics metrics.Metrics
 }
 
 type parking struct {
 	ParkingDom     parkingDom.DomainItf
+	LotDom         lotDom.DomainItf
 	TransactionDom transactionDom.DomainItf
+	TariffDom      tariffDom.DomainItf
+	PaymentDom     paymentDom.DomainItf
+	ReservationDom reservationDom.DomainItf
+	WaitlistDom    waitlistDom.DomainItf
+	SpotFeedDom    spotFeedDom.DomainItf
+	OutboxDom      outboxDom.DomainItf
+	Waitlist       SpotOfferer
+	Allocation     AllocationStrategy
+	Compatibility  Compatibility
+	Location       *time.Location
+	Resync         time.Duration
+	Metrics        metrics.Metrics
 }
 
 func InitParkingUsecase(opt Option) UsecaseItf {
 	p := &parking{
 		ParkingDom:     opt.ParkingDom,
+		LotDom:         opt.LotDom,
 		TransactionDom: opt.TransactionDom,
+		TariffDom:      opt.TariffDom,
+		PaymentDom:     opt.PaymentDom,
+		ReservationDom: opt.ReservationDom,
+		WaitlistDom:    opt.WaitlistDom,
+		SpotFeedDom:    opt.SpotFeedDom,
+		OutboxDom:      opt.OutboxDom,
+		Waitlist:       opt.Waitlist,
+		Allocation:     opt.Allocation,
+		Compatibility:  opt.Compatibility,
+		Location:       opt.Location,
+		Resync:         opt.Resync,
+		Metrics:        metrics.OrNoop(opt.Metrics),
+	}
+
+	if p.Allocation == nil {
+		p.Allocation = NearestStrategy{}
+	}
+
+	if p.Location == nil {
+		p.Location = time.Local
+	}
+
+	if p.Resync <= 0 {
+		p.Resync = 30 * time.Second
 	}
 
 	return p
 }
+
+// policy loads the lot and how it hands out spots.
+func (p *parking) policy(ctx context.Context, lotID uint) (LotPolicy, error) {
+	_, policy, err := LoadLotPolicy(ctx, p.LotDom, lotID, LotPolicy{
+		Allocation:    p.Allocation,
+		Compatibility: p.Compatibility,
+		Location:      p.Location,
+	})
+
+	return policy, err
+}
@@ -2,74 +2,390 @@ package parking
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	tariffUc "github.com/zuhrulumam/go-parking-lot/business/usecase/tariff"
 	"github.com/zuhrulumam/go-parking-lot/pkg"
 	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+	"github.com/zuhrulumam/go-parking-lot/pkg/metrics"
+	"github.com/zuhrulumam/go-parking-lot/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func (p *parking) Park(ctx context.Context, data entity.Park) error {
+func (p *parking) Park(ctx context.Context, data entity.Park) (entity.Ticket, error) {
+	ctx, span := tracing.Start(ctx, "parking.Park", trace.WithAttributes(
+		attribute.Int64("parking.lot_id", int64(data.LotID)),
+		attribute.String("parking.vehicle_type", string(data.VehicleType)),
+	))
 
-	err := p.TransactionDom.RunInTx(ctx, func(newCtx context.Context) error {
+	ticket, err := p.park(ctx, data)
 
-		// check parking_spot by vehicle type, active, and not occupied
-		pSpots, err := p.ParkingDom.GetAvailableParkingSpot(newCtx, entity.GetAvailableParkingSpot{
-			VehicleType: data.VehicleType,
-			Active:      pkg.BoolPtr(true),
-			Occupied:    pkg.BoolPtr(false),
-			UseLock:     true,
-		})
-		if err != nil {
+	result := outcome(err)
+	p.Metrics.Park(string(data.VehicleType), result)
+
+	span.SetAttributes(attribute.String("parking.outcome", result))
+	tracing.End(span, err)
+
+	return ticket, err
+}
+
+func (p *parking) park(ctx context.Context, data entity.Park) (entity.Ticket, error) {
+
+	var ticket entity.Ticket
+
+	policy, err := p.policy(ctx, data.LotID)
+	if err != nil {
+		return ticket, err
+	}
+
+	if !policy.IsOpen(time.Now()) {
+		return ticket, x.NewWithCode(http.StatusConflict, "lot is closed")
+	}
+
+	err = p.TransactionDom.RunInTx(ctx, func(newCtx context.Context) error {
+
+		var (
+			spot  entity.ParkingSpot
+			res   *entity.Reservation
+			entry *entity.WaitlistEntry
+			err   error
+		)
+
+		if err := p.checkNotParked(newCtx, data.VehicleNumber); err != nil {
 			return err
 		}
 
-		if len(pSpots) < 1 {
-			return errors.New("no available parking")
+		switch {
+		case data.ReservationCode != "":
+			// check in on the held spot
+			spot, res, err = p.reservedSpot(newCtx, data)
+		case data.WaitlistCode != "":
+			// claim the spot offered from the waitlist
+			spot, entry, err = p.offeredSpot(newCtx, data)
+		default:
+			// check parking_spot of a type the vehicle fits, active, not occupied and not held
+			spot, err = p.freeSpot(newCtx, policy, data)
+		}
+		if err != nil {
+			return err
 		}
 
-		spot := pSpots[0]
-		spotID := fmt.Sprintf("%d-%d-%d", spot.Floor, spot.Row, spot.Col)
+		spotID := spot.Position().String()
 
 		// update parking_spot occupied = true as floor row col
-		err = p.ParkingDom.UpdateParkingSpot(newCtx, entity.UpdateParkingSpot{
+		update := entity.UpdateParkingSpot{
 			ID:       spot.ID,
 			Occupied: pkg.BoolPtr(true),
-		})
+		}
+		if res != nil || entry != nil {
+			update.Reserved = pkg.BoolPtr(false)
+		}
+
+		err = p.ParkingDom.UpdateParkingSpot(newCtx, update)
 		if err != nil {
 			return err
 		}
 
 		// insert vehicle
-		err = p.ParkingDom.InsertVehicle(newCtx, entity.InsertVehicle{
+		vec, err := p.ParkingDom.InsertVehicle(newCtx, entity.InsertVehicle{
+			LotID:         data.LotID,
 			VehicleNumber: data.VehicleNumber,
 			VehicleType:   string(data.VehicleType),
 			SpotID:        spotID,
+			TicketID:      uuid.New().String(),
 		})
 		if err != nil {
 			return err
 		}
 
-		return nil
+		if res != nil {
+			err = p.ReservationDom.UpdateReservation(newCtx, entity.UpdateReservation{
+				ID:         res.ID,
+				Status:     entity.ReservationCheckedIn,
+				VehicleID:  &vec.ID,
+				ReleasedAt: pkg.TimePtr(vec.ParkedAt),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if entry != nil {
+			err = p.WaitlistDom.UpdateEntry(newCtx, entity.UpdateWaitlist{
+				ID:       entry.ID,
+				Status:   entity.WaitlistClaimed,
+				ClosedAt: pkg.TimePtr(vec.ParkedAt),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		ticket = entity.Ticket{
+			TicketID:      vec.TicketID,
+			LotID:         data.LotID,
+			SpotID:        spotID,
+			Floor:         spot.Floor,
+			Row:           spot.Row,
+			Col:           spot.Col,
+			VehicleType:   data.VehicleType,
+			SpotType:      spot.Type,
+			VehicleNumber: vec.VehicleNumber,
+			ParkedAt:      vec.ParkedAt,
+		}
+
+		return p.emit(newCtx, data.LotID, entity.EventVehicleParked, ticket)
 	})
 	if err != nil {
+		return entity.Ticket{}, err
+	}
+
+	return ticket, nil
+}
+
+// outcomeAwaitingPayment is an unpark quoting a fee still to be paid.
+const outcomeAwaitingPayment = "awaiting_payment"
+
+// outcome is the metrics label of how a park or unpark ended, a few
+// reasons so the series stay bounded.
+func outcome(err error) string {
+	if err == nil {
+		return metrics.OutcomeOK
+	}
+
+	if x.RootCause(err) == ErrNoAvailableParking {
+		return "full"
+	}
+
+	switch x.ErrCode(err) {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return "invalid"
+	case http.StatusNotFound:
+		return "not_found"
+	case http.StatusConflict:
+		return "conflict"
+	default:
+		return "error"
+	}
+}
+
+// checkNotParked refuses a vehicle with an open session in any lot. Gates
+// racing past this check are stopped by the unique_open_vehicle index when
+// the session is inserted.
+func (p *parking) checkNotParked(ctx context.Context, vehicleNumber string) error {
+	vec, err := p.ParkingDom.GetVehicle(ctx, entity.SearchVehicle{VehicleNumber: vehicleNumber})
+	if err != nil {
+		if x.ErrCode(err) == http.StatusNotFound {
+			return nil
+		}
 		return err
 	}
 
+	if vec.UnparkedAt == nil {
+		return x.NewWithCode(http.StatusConflict, "vehicle is already parked")
+	}
+
 	return nil
 }
 
-func (p *parking) Unpark(ctx context.Context, data entity.UnPark) error {
+// freeSpot picks a spot of the vehicle's own type, falling back to a larger
+// one when the lot policy allows it.
+func (p *parking) freeSpot(ctx context.Context, policy LotPolicy, data entity.Park) (entity.ParkingSpot, error) {
+	pSpots, err := policy.Compatibility.FreeSpots(ctx, p.ParkingDom, data.LotID, data.VehicleType)
+	if err != nil {
+		return entity.ParkingSpot{}, err
+	}
 
-	return p.TransactionDom.RunInTx(ctx, func(newCtx context.Context) error {
+	pick := policy.Allocation.Pick
+	if s, ok := policy.Allocation.(LoadAwareStrategy); ok && len(pSpots) > 0 {
+		counts, err := p.ParkingDom.CountParkingSpots(ctx, data.LotID)
+		if err != nil {
+			return entity.ParkingSpot{}, err
+		}
 
-		// get vehicle by spotID, vehicle number, and UnparkedAt null
-		vec, err := p.ParkingDom.GetVehicle(newCtx, entity.SearchVehicle{
-			VehicleNumber: data.VehicleNumber,
-		})
+		load := NewFloorLoad(counts)
+		pick = func(candidates []entity.ParkingSpot) entity.ParkingSpot {
+			return s.PickByLoad(candidates, load)
+		}
+	}
+
+	spot, ok, err := ClaimSpot(ctx, p.ParkingDom, pSpots, pick)
+	if err != nil {
+		return entity.ParkingSpot{}, err
+	}
+
+	if !ok {
+		return entity.ParkingSpot{}, x.WrapWithCode(ErrNoAvailableParking, http.StatusConflict, "no spot for vehicle type")
+	}
+
+	return spot, nil
+}
+
+// reservedSpot checks the reservation behind data.ReservationCode can be
+// used now by this vehicle and returns its spot.
+func (p *parking) reservedSpot(ctx context.Context, data entity.Park) (entity.ParkingSpot, *entity.Reservation, error) {
+	if p.ReservationDom == nil {
+		return entity.ParkingSpot{}, nil, x.NewWithCode(http.StatusBadRequest, "reservations are not enabled")
+	}
+
+	reservations, err := p.ReservationDom.GetReservations(ctx, entity.GetReservations{
+		LotID:   data.LotID,
+		Code:    data.ReservationCode,
+		UseLock: true,
+	})
+	if err != nil {
+		return entity.ParkingSpot{}, nil, err
+	}
+
+	if len(reservations) < 1 {
+		return entity.ParkingSpot{}, nil, x.NewWithCode(http.StatusNotFound, "reservation not found")
+	}
+
+	res := reservations[0]
+	now := time.Now()
+
+	switch {
+	case res.Status != entity.ReservationHeld:
+		return entity.ParkingSpot{}, nil, x.NewWithCode(http.StatusConflict, fmt.Sprintf("reservation is %s", res.Status))
+	case now.Before(res.StartsAt):
+		return entity.ParkingSpot{}, nil, x.NewWithCode(http.StatusConflict, "reservation window has not started")
+	case !now.Before(res.EndsAt):
+		return entity.ParkingSpot{}, nil, x.NewWithCode(http.StatusConflict, "reservation expired")
+	case res.VehicleType != string(data.VehicleType):
+		return entity.ParkingSpot{}, nil, x.NewWithCode(http.StatusUnprocessableEntity, "reservation is for another vehicle type")
+	case res.VehicleNumber != "" && res.VehicleNumber != data.VehicleNumber:
+		return entity.ParkingSpot{}, nil, x.NewWithCode(http.StatusUnprocessableEntity, "reservation is for another vehicle")
+	}
+
+	sp, err := pkg.ParseSpotID(res.SpotID)
+	if err != nil {
+		return entity.ParkingSpot{}, nil, err
+	}
+
+	spots, err := p.ParkingDom.GetAvailableParkingSpot(ctx, entity.GetAvailableParkingSpot{
+		LotID:    sp.Lot,
+		Floor:    sp.Floor,
+		Row:      sp.Row,
+		Col:      sp.Col,
+		Occupied: pkg.BoolPtr(false),
+		Reserved: pkg.BoolPtr(true),
+		UseLock:  true,
+	})
+	if err != nil {
+		return entity.ParkingSpot{}, nil, err
+	}
+
+	if len(spots) < 1 {
+		return entity.ParkingSpot{}, nil, x.NewWithCode(http.StatusConflict, "reserved spot is no longer held")
+	}
+
+	return spots[0], &res, nil
+}
+
+// offeredSpot checks the waitlist entry behind data.WaitlistCode holds a
+// live offer for this vehicle and returns the offered spot.
+func (p *parking) offeredSpot(ctx context.Context, data entity.Park) (entity.ParkingSpot, *entity.WaitlistEntry, error) {
+	if p.WaitlistDom == nil {
+		return entity.ParkingSpot{}, nil, x.NewWithCode(http.StatusBadRequest, "waitlist is not enabled")
+	}
+
+	entries, err := p.WaitlistDom.GetEntries(ctx, entity.GetWaitlist{
+		LotID:   data.LotID,
+		Code:    data.WaitlistCode,
+		UseLock: true,
+	})
+	if err != nil {
+		return entity.ParkingSpot{}, nil, err
+	}
+
+	if len(entries) < 1 {
+		return entity.ParkingSpot{}, nil, x.NewWithCode(http.StatusNotFound, "waitlist entry not found")
+	}
+
+	entry := entries[0]
+
+	switch {
+	case entry.Status != entity.WaitlistOffered:
+		return entity.ParkingSpot{}, nil, x.NewWithCode(http.StatusConflict, fmt.Sprintf("waitlist entry is %s", entry.Status))
+	case entry.OfferExpiresAt != nil && !time.Now().Before(*entry.OfferExpiresAt):
+		return entity.ParkingSpot{}, nil, x.NewWithCode(http.StatusConflict, "offer expired")
+	case entry.VehicleType != string(data.VehicleType) || entry.VehicleNumber != data.VehicleNumber:
+		return entity.ParkingSpot{}, nil, x.NewWithCode(http.StatusUnprocessableEntity, "offer is for another vehicle")
+	}
+
+	sp, err := pkg.ParseSpotID(entry.SpotID)
+	if err != nil {
+		return entity.ParkingSpot{}, nil, err
+	}
+
+	spots, err := p.ParkingDom.GetAvailableParkingSpot(ctx, entity.GetAvailableParkingSpot{
+		LotID:    sp.Lot,
+		Floor:    sp.Floor,
+		Row:      sp.Row,
+		Col:      sp.Col,
+		Occupied: pkg.BoolPtr(false),
+		Reserved: pkg.BoolPtr(true),
+		UseLock:  true,
+	})
+	if err != nil {
+		return entity.ParkingSpot{}, nil, err
+	}
+
+	if len(spots) < 1 {
+		return entity.ParkingSpot{}, nil, x.NewWithCode(http.StatusConflict, "offered spot is no longer held")
+	}
+
+	return spots[0], &entry, nil
+}
+
+// Unpark requests the exit of a session. The fee is quoted once and kept on
+// the session; the spot is only released when the quote is settled, either
+// right away for a free stay or later through AuthorizeExit.
+func (p *parking) Unpark(ctx context.Context, data entity.UnPark) (entity.Receipt, error) {
+	ctx, span := tracing.Start(ctx, "parking.Unpark", trace.WithAttributes(
+		attribute.Int64("parking.lot_id", int64(data.LotID)),
+	))
+
+	receipt, err := p.unpark(ctx, data)
+
+	// the vehicle type is only known once the session is found
+	vehicleType, result := string(receipt.VehicleType), outcome(err)
+	if vehicleType == "" {
+		vehicleType = "unknown"
+	}
+	if err == nil && receipt.UnparkedAt == nil {
+		result = outcomeAwaitingPayment
+	}
+	p.Metrics.Unpark(vehicleType, result)
+
+	span.SetAttributes(
+		attribute.String("parking.vehicle_type", vehicleType),
+		attribute.String("parking.outcome", result),
+	)
+	tracing.End(span, err)
+
+	return receipt, err
+}
+
+func (p *parking) unpark(ctx context.Context, data entity.UnPark) (entity.Receipt, error) {
+
+	var receipt entity.Receipt
+
+	// exits stay open outside opening hours
+	policy, err := p.policy(ctx, data.LotID)
+	if err != nil {
+		return receipt, err
+	}
+
+	err = p.TransactionDom.RunInTx(ctx, func(newCtx context.Context) error {
+
+		vec, err := p.exitingSession(newCtx, data)
 		if err != nil {
 			return err
 		}
@@ -78,43 +394,413 @@ func (p *parking) Unpark(ctx context.Context, data entity.UnPark) error {
 			return x.NewWithCode(http.StatusBadRequest, "already unparked")
 		}
 
+		paid, err := p.amountPaid(newCtx, vec.ID)
+		if err != nil {
+			return err
+		}
+
+		// exit already requested, hand back the same quote
+		if vec.Status == entity.SessionAwaitingPayment && vec.Fee != nil {
+			receipt = toReceipt(vec, paid)
+			return nil
+		}
+
+		// price the stay
+		tf, err := p.TariffDom.GetTariff(newCtx, entity.GetTariff{
+			LotID:       vec.LotID,
+			VehicleType: vec.VehicleType,
+		})
+		if err != nil {
+			return x.WrapWithCode(err, http.StatusInternalServerError, "no tariff configured for vehicle type")
+		}
+
+		now := time.Now()
+		fee := tariffUc.CalculateFee(tf, vec.ParkedAt, now, policy.Location)
+
 		// update vehicle
 		err = p.ParkingDom.UpdateVehicle(newCtx, entity.UpdateVehicle{
-			ID:         vec.ID,
-			UnparkedAt: pkg.TimePtr(time.Now()),
+			ID:              vec.ID,
+			Fee:             &fee,
+			Status:          entity.SessionAwaitingPayment,
+			ExitRequestedAt: pkg.TimePtr(now),
 		})
 		if err != nil {
 			return err
 		}
 
-		sp, err := pkg.ParseSpotID(vec.SpotID)
+		vec.Fee = &fee
+		vec.Status = entity.SessionAwaitingPayment
+		vec.ExitRequestedAt = pkg.TimePtr(now)
+
+		if paid >= fee {
+			vec, err = p.closeSession(newCtx, vec, now)
+			if err != nil {
+				return err
+			}
+		}
+
+		receipt = toReceipt(vec, paid)
+
+		return nil
+	})
+	if err != nil {
+		return entity.Receipt{}, err
+	}
+
+	return receipt, nil
+}
+
+// exitingSession finds the session an unpark is about, by ticket, spot or
+// plate in that order. Every other identifier given must point at the same
+// session.
+func (p *parking) exitingSession(ctx context.Context, data entity.UnPark) (entity.Vehicle, error) {
+
+	if data.TicketID == "" && data.SpotID == "" && data.VehicleNumber == "" {
+		return entity.Vehicle{}, x.NewWithCode(http.StatusBadRequest, "ticket_id, spot_id or vehicle_number is required")
+	}
+
+	if data.SpotID != "" {
+		sp, err := pkg.ParseSpotID(data.SpotID)
+		if err != nil || sp.Lot != data.LotID {
+			return entity.Vehicle{}, x.NewWithCode(http.StatusBadRequest, "invalid spot_id")
+		}
+	}
+
+	// concurrent exits of the session wait for this one
+	search := entity.SearchVehicle{LotID: data.LotID, UseLock: true}
+	switch {
+	case data.TicketID != "":
+		search.TicketID = data.TicketID
+	case data.SpotID != "":
+		search.SpotID = data.SpotID
+	default:
+		search.VehicleNumber = data.VehicleNumber
+	}
+
+	vec, err := p.ParkingDom.GetVehicle(ctx, search)
+	if search.SpotID != "" {
+		// the newest session of a spot is the open one, if any
+		if x.ErrCode(err) == http.StatusNotFound || (err == nil && vec.UnparkedAt != nil) {
+			return entity.Vehicle{}, x.NewWithCode(http.StatusConflict, "no vehicle is parked at the spot")
+		}
+	}
+	if err != nil {
+		return entity.Vehicle{}, err
+	}
+
+	if data.VehicleNumber != "" && vec.VehicleNumber != data.VehicleNumber {
+		return entity.Vehicle{}, x.NewWithCode(http.StatusUnprocessableEntity, "vehicle_number does not match the session")
+	}
+
+	if data.SpotID != "" && vec.SpotID != data.SpotID {
+		return entity.Vehicle{}, x.NewWithCode(http.StatusUnprocessableEntity, "vehicle is not parked at the spot")
+	}
+
+	return vec, nil
+}
+
+// Quote returns the receipt of a session awaiting payment, with what is
+// paid so far, without requesting the exit again nor counting it as one.
+func (p *parking) Quote(ctx context.Context, data entity.UnPark) (entity.Receipt, error) {
+
+	var receipt entity.Receipt
+
+	err := p.TransactionDom.RunInTx(ctx, func(newCtx context.Context) error {
+
+		vec, err := p.exitingSession(newCtx, data)
+		if err != nil {
+			return err
+		}
+
+		if vec.UnparkedAt != nil {
+			return x.NewWithCode(http.StatusBadRequest, "already unparked")
+		}
+
+		if vec.Status != entity.SessionAwaitingPayment || vec.Fee == nil {
+			return x.NewWithCode(http.StatusConflict, "exit has not been requested")
+		}
+
+		paid, err := p.amountPaid(newCtx, vec.ID)
 		if err != nil {
 			return err
 		}
 
-		// update parking_spot to occupied = false
-		err = p.ParkingDom.UpdateParkingSpot(newCtx, entity.UpdateParkingSpot{
-			Floor:    sp.Floor,
-			Row:      sp.Row,
-			Col:      sp.Col,
-			Occupied: pkg.BoolPtr(false),
+		receipt = toReceipt(vec, paid)
+
+		return nil
+	})
+	if err != nil {
+		return entity.Receipt{}, err
+	}
+
+	return receipt, nil
+}
+
+// AuthorizeExit closes a session awaiting payment. The quote must be fully
+// paid unless an operator overrides it, in which case the outstanding amount
+// is written to the ledger as an override line.
+func (p *parking) AuthorizeExit(ctx context.Context, data entity.AuthorizeExit) (entity.Receipt, error) {
+	ctx, span := tracing.Start(ctx, "parking.AuthorizeExit", trace.WithAttributes(
+		attribute.Int64("parking.lot_id", int64(data.LotID)),
+	))
+
+	receipt, err := p.authorizeExit(ctx, data)
+
+	// counted as the unpark it ends, refused while still unpaid
+	vehicleType, result := string(receipt.VehicleType), outcome(err)
+	if vehicleType == "" {
+		vehicleType = "unknown"
+	}
+	if x.ErrCode(err) == http.StatusPaymentRequired {
+		result = outcomeAwaitingPayment
+	}
+	p.Metrics.Unpark(vehicleType, result)
+
+	span.SetAttributes(
+		attribute.String("parking.vehicle_type", vehicleType),
+		attribute.String("parking.outcome", result),
+	)
+	tracing.End(span, err)
+
+	return receipt, err
+}
+
+func (p *parking) authorizeExit(ctx context.Context, data entity.AuthorizeExit) (entity.Receipt, error) {
+
+	var receipt entity.Receipt
+
+	err := p.TransactionDom.RunInTx(ctx, func(newCtx context.Context) error {
+
+		vec, err := p.ParkingDom.GetVehicle(newCtx, entity.SearchVehicle{
+			LotID:    data.LotID,
+			TicketID: data.TicketID,
+			UseLock:  true,
 		})
 		if err != nil {
 			return err
 		}
 
+		if vec.UnparkedAt != nil {
+			return x.NewWithCode(http.StatusBadRequest, "already unparked")
+		}
+
+		if vec.Status != entity.SessionAwaitingPayment || vec.Fee == nil {
+			return x.NewWithCode(http.StatusConflict, "exit has not been requested")
+		}
+
+		paid, err := p.amountPaid(newCtx, vec.ID)
+		if err != nil {
+			return err
+		}
+
+		if due := *vec.Fee - paid; due > 0 {
+			if !data.Override {
+				return x.NewWithCode(http.StatusPaymentRequired, fmt.Sprintf("payment outstanding: %d", due))
+			}
+
+			if data.Operator == "" || data.Reason == "" {
+				return x.NewWithCode(http.StatusBadRequest, "override requires operator and reason")
+			}
+
+			_, err = p.PaymentDom.InsertPayment(newCtx, entity.Payment{
+				LotID:     vec.LotID,
+				VehicleID: vec.ID,
+				TicketID:  vec.TicketID,
+				Kind:      entity.PaymentOverride,
+				Status:    entity.PaymentSucceeded,
+				Provider:  "operator",
+				Amount:    due,
+				Reference: data.Operator,
+				Note:      data.Reason,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		vec, err = p.closeSession(newCtx, vec, time.Now())
+		if err != nil {
+			return err
+		}
+
+		receipt = toReceipt(vec, paid)
+
 		return nil
 	})
+	if err != nil {
+		return entity.Receipt{}, err
+	}
+
+	return receipt, nil
+}
+
+// closeSession marks the vehicle as exited and frees its spot.
+func (p *parking) closeSession(ctx context.Context, vec entity.Vehicle, now time.Time) (entity.Vehicle, error) {
+
+	// update vehicle
+	err := p.ParkingDom.UpdateVehicle(ctx, entity.UpdateVehicle{
+		ID:         vec.ID,
+		UnparkedAt: pkg.TimePtr(now),
+		Status:     entity.SessionExited,
+	})
+	if err != nil {
+		return vec, err
+	}
+
+	sp, err := pkg.ParseSpotID(vec.SpotID)
+	if err != nil {
+		return vec, err
+	}
+
+	// update parking_spot to occupied = false
+	err = p.ParkingDom.UpdateParkingSpot(ctx, entity.UpdateParkingSpot{
+		LotID:    sp.Lot,
+		Floor:    sp.Floor,
+		Row:      sp.Row,
+		Col:      sp.Col,
+		Occupied: pkg.BoolPtr(false),
+	})
+	if err != nil {
+		return vec, err
+	}
+
+	if p.Waitlist != nil {
+		if err := p.Waitlist.OfferSpot(ctx, *sp); err != nil {
+			return vec, err
+		}
+	}
+
+	vec.UnparkedAt = pkg.TimePtr(now)
+	vec.Status = entity.SessionExited
+
+	return vec, p.emit(ctx, vec.LotID, entity.EventVehicleUnparked, vec)
+}
+
+// emit writes a domain event to the outbox, in the transaction of ctx.
+func (p *parking) emit(ctx context.Context, lotID uint, eventType string, data interface{}) error {
+	if p.OutboxDom == nil {
+		return nil
+	}
+
+	_, err := p.OutboxDom.InsertEvent(ctx, entity.NewOutboxEvent(lotID, eventType, data))
+	return err
 }
 
+func (p *parking) amountPaid(ctx context.Context, vehicleID uint) (int64, error) {
+	if p.PaymentDom == nil {
+		return 0, nil
+	}
+
+	payments, err := p.PaymentDom.GetPayments(ctx, entity.GetPayments{
+		VehicleID: vehicleID,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return AmountPaid(payments), nil
+}
+
+// AmountPaid nets the succeeded charges against the succeeded refunds of a
+// ledger. Overrides and failed attempts don't count.
+func AmountPaid(payments []entity.Payment) int64 {
+	var paid int64
+	for _, v := range payments {
+		if v.Status != entity.PaymentSucceeded {
+			continue
+		}
+
+		switch v.Kind {
+		case entity.PaymentCharge:
+			paid += v.Amount
+		case entity.PaymentRefund:
+			paid -= v.Amount
+		}
+	}
+
+	return paid
+}
+
+func toReceipt(vec entity.Vehicle, paid int64) entity.Receipt {
+	var fee int64
+	if vec.Fee != nil {
+		fee = *vec.Fee
+	}
+
+	due := fee - paid
+	if due < 0 || vec.Status == entity.SessionExited {
+		due = 0
+	}
+
+	return entity.Receipt{
+		TicketID:      vec.TicketID,
+		LotID:         vec.LotID,
+		VehicleNumber: vec.VehicleNumber,
+		VehicleType:   entity.VehicleType(vec.VehicleType),
+		SpotID:        vec.SpotID,
+		ParkedAt:      vec.ParkedAt,
+		UnparkedAt:    vec.UnparkedAt,
+		Fee:           fee,
+		AmountPaid:    paid,
+		AmountDue:     due,
+		Status:        vec.Status,
+	}
+}
+
+// AvailableSpot lists the spots of the lot Park would pick from for the
+// vehicle type, larger ones only when none of its own type is free.
 func (p *parking) AvailableSpot(ctx context.Context, data entity.GetAvailablePark) ([]entity.ParkingSpot, error) {
-	// check parking_spot by vehicle type, active, and not occupied
-	return p.ParkingDom.GetAvailableParkingSpot(ctx, entity.GetAvailableParkingSpot{
-		VehicleType: data.VehicleType,
-		Active:      pkg.BoolPtr(true),
-		Occupied:    pkg.BoolPtr(false),
+	policy, err := p.policy(ctx, data.LotID)
+	if err != nil {
+		return nil, err
+	}
+
+	return policy.Compatibility.FreeSpots(ctx, p.ParkingDom, data.LotID, data.VehicleType)
+}
+
+// Occupancy counts the spots of a lot by state, in total and per floor and
+// spot type.
+func (p *parking) Occupancy(ctx context.Context, lotID uint) (entity.Occupancy, error) {
+
+	result := entity.Occupancy{
+		LotID:  lotID,
+		Floors: []entity.SpotCount{},
+		Types:  []entity.SpotCount{},
+	}
+
+	if _, err := p.policy(ctx, lotID); err != nil {
+		return result, err
+	}
+
+	counts, err := p.ParkingDom.CountParkingSpots(ctx, lotID)
+	if err != nil {
+		return result, err
+	}
+
+	// counts come by floor then type
+	types := map[string]int{}
+	for _, c := range counts {
+		result.SpotCount.Add(c)
+
+		if n := len(result.Floors); n == 0 || result.Floors[n-1].Floor != c.Floor {
+			result.Floors = append(result.Floors, entity.SpotCount{Floor: c.Floor})
+		}
+		result.Floors[len(result.Floors)-1].Add(c)
+
+		i, ok := types[c.Type]
+		if !ok {
+			i = len(result.Types)
+			types[c.Type] = i
+			result.Types = append(result.Types, entity.SpotCount{Type: c.Type})
+		}
+		result.Types[i].Add(c)
+	}
+
+	sort.Slice(result.Types, func(i, j int) bool {
+		return result.Types[i].Type < result.Types[j].Type
 	})
 
+	return result, nil
 }
 
 func (p *parking) SearchVehicle(ctx context.Context, data entity.SearchVehicle) (entity.Vehicle, error) {
@@ -3,18 +3,51 @@ package parking_test
 import (
 	"context"
 	"errors"
+	"net/http"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	lotDom "github.com/zuhrulumam/go-parking-lot/business/domain/lot"
+	parkingDom "github.com/zuhrulumam/go-parking-lot/business/domain/parking"
+	tariffDom "github.com/zuhrulumam/go-parking-lot/business/domain/tariff"
+	transactionDom "github.com/zuhrulumam/go-parking-lot/business/domain/transaction"
 	"github.com/zuhrulumam/go-parking-lot/business/entity"
 	uc "github.com/zuhrulumam/go-parking-lot/business/usecase/parking"
+	mockLot "github.com/zuhrulumam/go-parking-lot/mocks/domain/lot"
 	mockParking "github.com/zuhrulumam/go-parking-lot/mocks/domain/parking"
+	mockPayment "github.com/zuhrulumam/go-parking-lot/mocks/domain/payment"
+	mockTariff "github.com/zuhrulumam/go-parking-lot/mocks/domain/tariff"
 	mockTx "github.com/zuhrulumam/go-parking-lot/mocks/domain/transaction"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+	"github.com/zuhrulumam/go-parking-lot/pkg/metrics"
 	"go.uber.org/mock/gomock"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
 )
 
+// mockLots knows lot 1, always open with the server defaults.
+func mockLots(ctrl *gomock.Controller) *mockLot.MockDomainItf {
+	m := mockLot.NewMockDomainItf(ctrl)
+	m.EXPECT().GetLot(gomock.Any(), entity.GetLot{ID: 1}).Return(entity.Lot{ID: 1, Name: "Main"}, nil).AnyTimes()
+	return m
+}
+
 func TestPark(t *testing.T) {
+	// only the picked spot is locked
+	claimSpot := entity.GetAvailableParkingSpot{
+		LotID:      1,
+		Floor:      1,
+		Row:        1,
+		Col:        1,
+		Active:     pkg.BoolPtr(true),
+		Occupied:   pkg.BoolPtr(false),
+		Reserved:   pkg.BoolPtr(false),
+		UseLock:    true,
+		SkipLocked: true,
+	}
 
 	tests := []struct {
 		name        string
@@ -26,11 +59,19 @@ func TestPark(t *testing.T) {
 			setupMocks: func(p mockParking.MockDomainItf, t mockTx.MockDomainItf) {
 				t.EXPECT().RunInTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
 
+					p.EXPECT().GetVehicle(gomock.Any(), entity.SearchVehicle{VehicleNumber: "B1234XYZ"}).
+						Return(entity.Vehicle{}, x.NewWithCode(http.StatusNotFound, "vehicle not found"))
+
 					p.EXPECT().GetAvailableParkingSpot(gomock.Any(), gomock.Any()).
-						Return([]entity.ParkingSpot{{ID: 1, Floor: 1, Row: 1, Col: 1}}, nil)
+						Return([]entity.ParkingSpot{{ID: 1, LotID: 1, Floor: 1, Row: 1, Col: 1}}, nil)
+
+					p.EXPECT().GetAvailableParkingSpot(gomock.Any(), claimSpot).
+						Return([]entity.ParkingSpot{{ID: 1, LotID: 1, Floor: 1, Row: 1, Col: 1}}, nil)
 
 					p.EXPECT().InsertVehicle(gomock.Any(), gomock.Any()).
-						Return(nil)
+						DoAndReturn(func(_ context.Context, data entity.InsertVehicle) (entity.Vehicle, error) {
+							return entity.Vehicle{ID: 1, VehicleNumber: data.VehicleNumber, SpotID: data.SpotID, TicketID: data.TicketID}, nil
+						})
 
 					p.EXPECT().UpdateParkingSpot(gomock.Any(), gomock.Any()).
 						Return(nil)
@@ -46,6 +87,9 @@ func TestPark(t *testing.T) {
 			setupMocks: func(p mockParking.MockDomainItf, t mockTx.MockDomainItf) {
 				t.EXPECT().RunInTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
 
+					p.EXPECT().GetVehicle(gomock.Any(), entity.SearchVehicle{VehicleNumber: "B1234XYZ"}).
+						Return(entity.Vehicle{}, x.NewWithCode(http.StatusNotFound, "vehicle not found"))
+
 					p.EXPECT().GetAvailableParkingSpot(gomock.Any(), gomock.Any()).
 						Return([]entity.ParkingSpot{}, nil)
 
@@ -60,11 +104,20 @@ func TestPark(t *testing.T) {
 			setupMocks: func(p mockParking.MockDomainItf, t mockTx.MockDomainItf) {
 				t.EXPECT().RunInTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
 
+					p.EXPECT().GetVehicle(gomock.Any(), entity.SearchVehicle{VehicleNumber: "B1234XYZ"}).
+						Return(entity.Vehicle{}, x.NewWithCode(http.StatusNotFound, "vehicle not found"))
+
 					p.EXPECT().GetAvailableParkingSpot(gomock.Any(), gomock.Any()).
-						Return([]entity.ParkingSpot{{ID: 1, Floor: 1, Row: 1, Col: 1}}, nil)
+						Return([]entity.ParkingSpot{{ID: 1, LotID: 1, Floor: 1, Row: 1, Col: 1}}, nil)
+
+					p.EXPECT().GetAvailableParkingSpot(gomock.Any(), claimSpot).
+						Return([]entity.ParkingSpot{{ID: 1, LotID: 1, Floor: 1, Row: 1, Col: 1}}, nil)
+
+					p.EXPECT().UpdateParkingSpot(gomock.Any(), gomock.Any()).
+						Return(nil)
 
 					p.EXPECT().InsertVehicle(gomock.Any(), gomock.Any()).
-						Return(errors.New("insert failed"))
+						Return(entity.Vehicle{}, errors.New("insert failed"))
 
 					return fn(ctx)
 				})
@@ -77,11 +130,14 @@ func TestPark(t *testing.T) {
 			setupMocks: func(p mockParking.MockDomainItf, t mockTx.MockDomainItf) {
 				t.EXPECT().RunInTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
 
+					p.EXPECT().GetVehicle(gomock.Any(), entity.SearchVehicle{VehicleNumber: "B1234XYZ"}).
+						Return(entity.Vehicle{}, x.NewWithCode(http.StatusNotFound, "vehicle not found"))
+
 					p.EXPECT().GetAvailableParkingSpot(gomock.Any(), gomock.Any()).
-						Return([]entity.ParkingSpot{{ID: 1, Floor: 1, Row: 1, Col: 1}}, nil)
+						Return([]entity.ParkingSpot{{ID: 1, LotID: 1, Floor: 1, Row: 1, Col: 1}}, nil)
 
-					p.EXPECT().InsertVehicle(gomock.Any(), gomock.Any()).
-						Return(nil)
+					p.EXPECT().GetAvailableParkingSpot(gomock.Any(), claimSpot).
+						Return([]entity.ParkingSpot{{ID: 1, LotID: 1, Floor: 1, Row: 1, Col: 1}}, nil)
 
 					p.EXPECT().UpdateParkingSpot(gomock.Any(), gomock.Any()).
 						Return(errors.New("update failed"))
@@ -91,6 +147,19 @@ func TestPark(t *testing.T) {
 			},
 			expectedErr: true,
 		},
+		{
+			name: "vehicle already parked",
+			setupMocks: func(p mockParking.MockDomainItf, t mockTx.MockDomainItf) {
+				t.EXPECT().RunInTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+
+					p.EXPECT().GetVehicle(gomock.Any(), entity.SearchVehicle{VehicleNumber: "B1234XYZ"}).
+						Return(entity.Vehicle{ID: 1, LotID: 2, VehicleNumber: "B1234XYZ", SpotID: "2-1-1-1"}, nil)
+
+					return fn(ctx)
+				})
+			},
+			expectedErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -104,18 +173,24 @@ func TestPark(t *testing.T) {
 
 			usecase := uc.InitParkingUsecase(uc.Option{
 				ParkingDom:     mockpark,
+				LotDom:         mockLots(ctrl),
 				TransactionDom: mocktx,
 			})
 
-			err := usecase.Park(context.Background(), entity.Park{
+			ticket, err := usecase.Park(context.Background(), entity.Park{
+				LotID:         1,
 				VehicleNumber: "B1234XYZ",
 				VehicleType:   "car",
 			})
 
 			if tt.expectedErr {
 				assert.Error(t, err)
+				assert.Empty(t, ticket.TicketID)
 			} else {
 				assert.NoError(t, err)
+				assert.NotEmpty(t, ticket.TicketID)
+				assert.Equal(t, "1-1-1-1", ticket.SpotID)
+				assert.Equal(t, "B1234XYZ", ticket.VehicleNumber)
 			}
 
 		})
@@ -124,29 +199,114 @@ func TestPark(t *testing.T) {
 
 func TestUnpark(t *testing.T) {
 	tests := []struct {
-		name        string
-		setupMocks  func(p *mockParking.MockDomainItf, t *mockTx.MockDomainItf)
-		expectedErr bool
+		name           string
+		setupMocks     func(p *mockParking.MockDomainItf, t *mockTx.MockDomainItf)
+		tariffErr      error
+		payments       []entity.Payment
+		expectedErr    bool
+		expectedFee    int64
+		expectedStatus string
 	}{
 		{
-			name: "success unpark",
+			name: "exit requested, fee due",
 			setupMocks: func(p *mockParking.MockDomainItf, t *mockTx.MockDomainItf) {
 				t.EXPECT().RunInTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
 					p.EXPECT().GetVehicle(gomock.Any(), gomock.Any()).Return(entity.Vehicle{
 						ID:            1,
 						VehicleNumber: "B1234XYZ",
-						SpotID:        "1-2-3",
+						VehicleType:   "A",
+						LotID:         1,
+						SpotID:        "1-1-2-3",
+						ParkedAt:      time.Now().Add(-90 * time.Minute),
 						UnparkedAt:    nil,
 					}, nil)
 
-					p.EXPECT().UpdateVehicle(gomock.Any(), gomock.Any()).Return(nil)
+					p.EXPECT().UpdateVehicle(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, data entity.UpdateVehicle) error {
+						if data.Fee == nil || *data.Fee != 8000 {
+							return errors.New("fee not saved on the session")
+						}
+						if data.Status != entity.SessionAwaitingPayment || data.ExitRequestedAt == nil {
+							return errors.New("exit request not saved on the session")
+						}
+						return nil
+					})
+
+					return fn(ctx)
+				})
+			},
+			expectedErr:    false,
+			expectedFee:    8000,
+			expectedStatus: entity.SessionAwaitingPayment,
+		},
+		{
+			name: "prepaid stay frees the spot",
+			setupMocks: func(p *mockParking.MockDomainItf, t *mockTx.MockDomainItf) {
+				t.EXPECT().RunInTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+					p.EXPECT().GetVehicle(gomock.Any(), gomock.Any()).Return(entity.Vehicle{
+						ID:            1,
+						VehicleNumber: "B1234XYZ",
+						VehicleType:   "A",
+						LotID:         1,
+						SpotID:        "1-1-2-3",
+						ParkedAt:      time.Now().Add(-90 * time.Minute),
+					}, nil)
+
+					p.EXPECT().UpdateVehicle(gomock.Any(), gomock.Any()).Return(nil).Times(2)
 
-					p.EXPECT().UpdateParkingSpot(gomock.Any(), gomock.Any()).Return(nil)
+					p.EXPECT().UpdateParkingSpot(gomock.Any(), entity.UpdateParkingSpot{
+						LotID: 1, Floor: 1, Row: 2, Col: 3, Occupied: pkg.BoolPtr(false),
+					}).Return(nil)
 
 					return fn(ctx)
 				})
 			},
-			expectedErr: false,
+			payments: []entity.Payment{
+				{Kind: entity.PaymentCharge, Status: entity.PaymentSucceeded, Amount: 8000},
+			},
+			expectedErr:    false,
+			expectedFee:    8000,
+			expectedStatus: entity.SessionExited,
+		},
+		{
+			name: "exit already requested returns the quote",
+			setupMocks: func(p *mockParking.MockDomainItf, t *mockTx.MockDomainItf) {
+				t.EXPECT().RunInTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+					p.EXPECT().GetVehicle(gomock.Any(), gomock.Any()).Return(entity.Vehicle{
+						ID:            1,
+						VehicleNumber: "B1234XYZ",
+						VehicleType:   "A",
+						LotID:         1,
+						SpotID:        "1-1-2-3",
+						ParkedAt:      time.Now().Add(-5 * time.Hour),
+						Fee:           pkg.Int64Ptr(5000),
+						Status:        entity.SessionAwaitingPayment,
+					}, nil)
+
+					return fn(ctx)
+				})
+			},
+			expectedErr:    false,
+			expectedFee:    5000,
+			expectedStatus: entity.SessionAwaitingPayment,
+		},
+		{
+			name: "no tariff for vehicle type",
+			setupMocks: func(p *mockParking.MockDomainItf, t *mockTx.MockDomainItf) {
+				t.EXPECT().RunInTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+					p.EXPECT().GetVehicle(gomock.Any(), gomock.Any()).Return(entity.Vehicle{
+						ID:            1,
+						VehicleNumber: "B1234XYZ",
+						VehicleType:   "A",
+						LotID:         1,
+						SpotID:        "1-1-2-3",
+						ParkedAt:      time.Now(),
+						UnparkedAt:    nil,
+					}, nil)
+					return fn(ctx)
+				})
+			},
+			tariffErr:   errors.New("tariff not found"),
+			expectedErr: true,
 		},
 		{
 			name: "already unparked",
@@ -156,7 +316,8 @@ func TestUnpark(t *testing.T) {
 					p.EXPECT().GetVehicle(gomock.Any(), gomock.Any()).Return(entity.Vehicle{
 						ID:            1,
 						VehicleNumber: "B1234XYZ",
-						SpotID:        "1-2-3",
+						LotID:         1,
+						SpotID:        "1-1-2-3",
 						UnparkedAt:    &now,
 					}, nil)
 					return fn(ctx)
@@ -181,7 +342,10 @@ func TestUnpark(t *testing.T) {
 					p.EXPECT().GetVehicle(gomock.Any(), gomock.Any()).Return(entity.Vehicle{
 						ID:            1,
 						VehicleNumber: "B1234XYZ",
-						SpotID:        "1-2-3",
+						VehicleType:   "A",
+						LotID:         1,
+						SpotID:        "1-1-2-3",
+						ParkedAt:      time.Now(),
 						UnparkedAt:    nil,
 					}, nil)
 
@@ -199,17 +363,23 @@ func TestUnpark(t *testing.T) {
 					p.EXPECT().GetVehicle(gomock.Any(), gomock.Any()).Return(entity.Vehicle{
 						ID:            1,
 						VehicleNumber: "B1234XYZ",
-						SpotID:        "1-2-3",
+						VehicleType:   "A",
+						LotID:         1,
+						SpotID:        "1-1-2-3",
+						ParkedAt:      time.Now(),
 						UnparkedAt:    nil,
 					}, nil)
 
-					p.EXPECT().UpdateVehicle(gomock.Any(), gomock.Any()).Return(nil)
+					p.EXPECT().UpdateVehicle(gomock.Any(), gomock.Any()).Return(nil).Times(2)
 
 					p.EXPECT().UpdateParkingSpot(gomock.Any(), gomock.Any()).Return(errors.New("update spot failed"))
 
 					return fn(ctx)
 				})
 			},
+			payments: []entity.Payment{
+				{Kind: entity.PaymentCharge, Status: entity.PaymentSucceeded, Amount: 5000},
+			},
 			expectedErr: true,
 		},
 	}
@@ -221,15 +391,27 @@ func TestUnpark(t *testing.T) {
 
 			mockTx := mockTx.NewMockDomainItf(ctrl)
 			mockPark := mockParking.NewMockDomainItf(ctrl)
+			mockTariff := mockTariff.NewMockDomainItf(ctrl)
+			mockPayment := mockPayment.NewMockDomainItf(ctrl)
 
 			tt.setupMocks(mockPark, mockTx)
 
+			mockPayment.EXPECT().GetPayments(gomock.Any(), gomock.Any()).Return(tt.payments, nil).AnyTimes()
+
+			mockTariff.EXPECT().GetTariff(gomock.Any(), entity.GetTariff{LotID: 1, VehicleType: "A"}).
+				Return(entity.Tariff{VehicleType: "A", FirstHourPrice: 5000, HourlyPrice: 3000}, tt.tariffErr).
+				AnyTimes()
+
 			usecase := uc.InitParkingUsecase(uc.Option{
 				ParkingDom:     mockPark,
+				LotDom:         mockLots(ctrl),
 				TransactionDom: mockTx,
+				TariffDom:      mockTariff,
+				PaymentDom:     mockPayment,
 			})
 
-			err := usecase.Unpark(context.Background(), entity.UnPark{
+			receipt, err := usecase.Unpark(context.Background(), entity.UnPark{
+				LotID:         1,
 				VehicleNumber: "B1234XYZ",
 			})
 
@@ -237,6 +419,8 @@ func TestUnpark(t *testing.T) {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedFee, receipt.Fee)
+				assert.Equal(t, tt.expectedStatus, receipt.Status)
 			}
 		})
 	}
@@ -250,6 +434,7 @@ func TestAvailableSpot(t *testing.T) {
 
 	usecase := uc.InitParkingUsecase(uc.Option{
 		ParkingDom:     mockPark,
+		LotDom:         mockLots(ctrl),
 		TransactionDom: nil,
 	})
 
@@ -263,9 +448,10 @@ func TestAvailableSpot(t *testing.T) {
 		{
 			name: "success",
 			mockReturn: []entity.ParkingSpot{
-				{ID: 1, Floor: 1, Row: 1, Col: 1},
+				{ID: 1, LotID: 1, Floor: 1, Row: 1, Col: 1},
 			},
 			input: entity.GetAvailablePark{
+				LotID:       1,
 				VehicleType: "M",
 			},
 			mockError:   nil,
@@ -275,6 +461,7 @@ func TestAvailableSpot(t *testing.T) {
 			name:       "error from domain",
 			mockReturn: nil,
 			input: entity.GetAvailablePark{
+				LotID:       1,
 				VehicleType: "M",
 			},
 			mockError:   errors.New("db error"),
@@ -355,3 +542,347 @@ func TestSearchVehicle(t *testing.T) {
 		})
 	}
 }
+
+func TestParkUnparkInMemory(t *testing.T) {
+	ctx := context.Background()
+	mem := memstore.New()
+
+	usecase := uc.InitParkingUsecase(uc.Option{
+		ParkingDom: parkingDom.InitParkingDomain(parkingDom.Option{
+			Memory: mem,
+			Spots: []entity.ParkingSpot{
+				{LotID: 1, Floor: 1, Row: 1, Col: 1, Type: "M", Active: true},
+			},
+		}),
+		LotDom: lotDom.InitLotDomain(lotDom.Option{
+			Memory: mem,
+			Lots:   []entity.Lot{{ID: 1, Name: "Main"}, {ID: 2, Name: "Annex"}},
+		}),
+		TariffDom: tariffDom.InitTariffDomain(tariffDom.Option{
+			Memory:  mem,
+			Tariffs: []entity.Tariff{{LotID: 1, VehicleType: "M", FirstHourPrice: 2000, GracePeriodMinutes: 10}},
+		}),
+		TransactionDom: transactionDom.Init(transactionDom.Option{Memory: mem}),
+	})
+
+	ticket, err := usecase.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B1234XYZ", VehicleType: entity.Motorcycle})
+	assert.NoError(t, err)
+
+	// the only spot is taken, nothing must leak from the failed attempt
+	_, err = usecase.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B5678XYZ", VehicleType: entity.Motorcycle})
+	assert.Error(t, err)
+
+	v, err := usecase.SearchVehicle(ctx, entity.SearchVehicle{LotID: 1, TicketID: ticket.TicketID})
+	assert.NoError(t, err)
+	assert.Equal(t, "B1234XYZ", v.VehicleNumber)
+
+	spots, err := usecase.AvailableSpot(ctx, entity.GetAvailablePark{LotID: 1, VehicleType: entity.Motorcycle})
+	assert.NoError(t, err)
+	assert.Empty(t, spots)
+
+	receipt, err := usecase.Unpark(ctx, entity.UnPark{LotID: 1, TicketID: ticket.TicketID})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), receipt.Fee, "within grace period")
+
+	_, err = usecase.Unpark(ctx, entity.UnPark{LotID: 1, VehicleNumber: "B1234XYZ"})
+	assert.Error(t, err)
+
+	spots, err = usecase.AvailableSpot(ctx, entity.GetAvailablePark{LotID: 1, VehicleType: entity.Motorcycle})
+	assert.NoError(t, err)
+	assert.Len(t, spots, 1)
+
+	// lots don't share spots or sessions
+	_, err = usecase.Park(ctx, entity.Park{LotID: 2, VehicleNumber: "B5678XYZ", VehicleType: entity.Motorcycle})
+	assert.Error(t, err)
+
+	_, err = usecase.SearchVehicle(ctx, entity.SearchVehicle{LotID: 2, TicketID: ticket.TicketID})
+	assert.Error(t, err)
+
+	_, err = usecase.Park(ctx, entity.Park{LotID: 9, VehicleNumber: "B5678XYZ", VehicleType: entity.Motorcycle})
+	assert.EqualValues(t, http.StatusNotFound, x.ErrCode(err))
+}
+
+// recordMetrics keeps the park and unpark outcomes.
+type recordMetrics struct {
+	metrics.Noop
+	parks   []string
+	unparks []string
+}
+
+func (m *recordMetrics) Park(vehicleType, outcome string) {
+	m.parks = append(m.parks, vehicleType+" "+outcome)
+}
+
+func (m *recordMetrics) Unpark(vehicleType, outcome string) {
+	m.unparks = append(m.unparks, vehicleType+" "+outcome)
+}
+
+func TestParkUnparkMetrics(t *testing.T) {
+	ctx := context.Background()
+	mem := memstore.New()
+	m := &recordMetrics{}
+
+	usecase := uc.InitParkingUsecase(uc.Option{
+		ParkingDom: parkingDom.InitParkingDomain(parkingDom.Option{
+			Memory: mem,
+			Spots: []entity.ParkingSpot{
+				{LotID: 1, Floor: 1, Row: 1, Col: 1, Type: "M", Active: true},
+			},
+		}),
+		LotDom: lotDom.InitLotDomain(lotDom.Option{
+			Memory: mem,
+			Lots:   []entity.Lot{{ID: 1, Name: "Main"}},
+		}),
+		TariffDom: tariffDom.InitTariffDomain(tariffDom.Option{
+			Memory:  mem,
+			Tariffs: []entity.Tariff{{LotID: 1, VehicleType: "M", FirstHourPrice: 2000, GracePeriodMinutes: 10}},
+		}),
+		TransactionDom: transactionDom.Init(transactionDom.Option{Memory: mem}),
+		Metrics:        m,
+	})
+
+	ticket, err := usecase.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B1234XYZ", VehicleType: entity.Motorcycle})
+	assert.NoError(t, err)
+
+	_, err = usecase.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B5678XYZ", VehicleType: entity.Motorcycle})
+	assert.Error(t, err)
+
+	_, err = usecase.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B1234XYZ", VehicleType: entity.Motorcycle})
+	assert.Error(t, err)
+
+	_, err = usecase.Unpark(ctx, entity.UnPark{LotID: 1, TicketID: ticket.TicketID})
+	assert.NoError(t, err)
+
+	_, err = usecase.Unpark(ctx, entity.UnPark{LotID: 1, TicketID: ticket.TicketID})
+	assert.Error(t, err)
+
+	_, err = usecase.Unpark(ctx, entity.UnPark{LotID: 1, TicketID: "no-such-ticket"})
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{"M ok", "M full", "M conflict"}, m.parks)
+	assert.Equal(t, []string{"M ok", "unknown invalid", "unknown not_found"}, m.unparks)
+}
+
+func TestParkSamePlateConcurrently(t *testing.T) {
+	ctx := context.Background()
+	mem := memstore.New()
+
+	var spots []entity.ParkingSpot
+	for lotID := uint(1); lotID <= 2; lotID++ {
+		for c := 1; c <= 10; c++ {
+			spots = append(spots, entity.ParkingSpot{LotID: lotID, Floor: 1, Row: 1, Col: c, Type: "A", Active: true})
+		}
+	}
+
+	usecase := uc.InitParkingUsecase(uc.Option{
+		ParkingDom: parkingDom.InitParkingDomain(parkingDom.Option{Memory: mem, Spots: spots}),
+		LotDom: lotDom.InitLotDomain(lotDom.Option{
+			Memory: mem,
+			Lots:   []entity.Lot{{ID: 1, Name: "Main"}, {ID: 2, Name: "Annex"}},
+		}),
+		TransactionDom: transactionDom.Init(transactionDom.Option{Memory: mem}),
+	})
+
+	// every gate of both lots sees the same plate at once
+	const gates = 20
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		parked    int
+		conflicts int
+	)
+	for i := 0; i < gates; i++ {
+		wg.Add(1)
+		go func(lotID uint) {
+			defer wg.Done()
+
+			_, err := usecase.Park(ctx, entity.Park{LotID: lotID, VehicleNumber: "B1234XYZ", VehicleType: entity.Automobile})
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				parked++
+			case x.ErrCode(err) == http.StatusConflict:
+				conflicts++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(uint(1 + i%2))
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, parked)
+	assert.Equal(t, gates-1, conflicts)
+
+	// the refused attempts took no spot
+	free := 0
+	for lotID := uint(1); lotID <= 2; lotID++ {
+		s, err := usecase.AvailableSpot(ctx, entity.GetAvailablePark{LotID: lotID, VehicleType: entity.Automobile})
+		assert.NoError(t, err)
+		free += len(s)
+	}
+	assert.Equal(t, len(spots)-1, free)
+}
+
+func TestUnparkBy(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        func(first entity.Ticket) entity.UnPark
+		expectedCode int
+	}{
+		{
+			name:  "spot",
+			input: func(entity.Ticket) entity.UnPark { return entity.UnPark{SpotID: "1-1-1-1"} },
+		},
+		{
+			name:  "spot and plate",
+			input: func(entity.Ticket) entity.UnPark { return entity.UnPark{SpotID: "1-1-1-1", VehicleNumber: "B1234XYZ"} },
+		},
+		{
+			name: "ticket, spot and plate",
+			input: func(first entity.Ticket) entity.UnPark {
+				return entity.UnPark{TicketID: first.TicketID, SpotID: "1-1-1-1", VehicleNumber: "B1234XYZ"}
+			},
+		},
+		{
+			name:         "nothing",
+			input:        func(entity.Ticket) entity.UnPark { return entity.UnPark{} },
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "spot of another lot",
+			input:        func(entity.Ticket) entity.UnPark { return entity.UnPark{SpotID: "2-1-1-1"} },
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "empty spot",
+			input:        func(entity.Ticket) entity.UnPark { return entity.UnPark{SpotID: "1-1-1-3"} },
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:         "plate parked elsewhere",
+			input:        func(entity.Ticket) entity.UnPark { return entity.UnPark{SpotID: "1-1-1-2", VehicleNumber: "B1234XYZ"} },
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "ticket of another plate",
+			input: func(first entity.Ticket) entity.UnPark {
+				return entity.UnPark{TicketID: first.TicketID, VehicleNumber: "B5678XYZ"}
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "ticket of another spot",
+			input: func(first entity.Ticket) entity.UnPark {
+				return entity.UnPark{TicketID: first.TicketID, SpotID: "1-1-1-2"}
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mem := memstore.New()
+
+			usecase := uc.InitParkingUsecase(uc.Option{
+				ParkingDom: parkingDom.InitParkingDomain(parkingDom.Option{
+					Memory: mem,
+					Spots: []entity.ParkingSpot{
+						{LotID: 1, Floor: 1, Row: 1, Col: 1, Type: "M", Active: true},
+						{LotID: 1, Floor: 1, Row: 1, Col: 2, Type: "M", Active: true},
+						{LotID: 1, Floor: 1, Row: 1, Col: 3, Type: "A", Active: true},
+					},
+				}),
+				LotDom: lotDom.InitLotDomain(lotDom.Option{
+					Memory: mem,
+					Lots:   []entity.Lot{{ID: 1, Name: "Main"}, {ID: 2, Name: "Annex"}},
+				}),
+				TariffDom: tariffDom.InitTariffDomain(tariffDom.Option{
+					Memory:  mem,
+					Tariffs: []entity.Tariff{{LotID: 1, VehicleType: "M", FirstHourPrice: 2000, GracePeriodMinutes: 10}},
+				}),
+				TransactionDom: transactionDom.Init(transactionDom.Option{Memory: mem}),
+			})
+
+			first, err := usecase.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B1234XYZ", VehicleType: entity.Motorcycle})
+			assert.NoError(t, err)
+			assert.Equal(t, "1-1-1-1", first.SpotID)
+
+			_, err = usecase.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B5678XYZ", VehicleType: entity.Motorcycle})
+			assert.NoError(t, err)
+
+			input := tt.input(first)
+			input.LotID = 1
+
+			receipt, err := usecase.Unpark(ctx, input)
+			if tt.expectedCode != 0 {
+				assert.EqualValues(t, tt.expectedCode, x.ErrCode(err))
+
+				spots, err := usecase.AvailableSpot(ctx, entity.GetAvailablePark{LotID: 1, VehicleType: entity.Motorcycle})
+				assert.NoError(t, err)
+				assert.Empty(t, spots, "nobody left")
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, first.TicketID, receipt.TicketID)
+			assert.Equal(t, entity.SessionExited, receipt.Status)
+
+			// the spot is free again, so there is nothing left to unpark there
+			_, err = usecase.Unpark(ctx, entity.UnPark{LotID: 1, SpotID: "1-1-1-1"})
+			assert.EqualValues(t, http.StatusConflict, x.ErrCode(err))
+		})
+	}
+}
+
+func TestOccupancy(t *testing.T) {
+	ctx := context.Background()
+	mem := memstore.New()
+
+	usecase := uc.InitParkingUsecase(uc.Option{
+		ParkingDom: parkingDom.InitParkingDomain(parkingDom.Option{
+			Memory: mem,
+			Spots: []entity.ParkingSpot{
+				{LotID: 1, Floor: 1, Row: 1, Col: 1, Type: "A", Active: true},
+				{LotID: 1, Floor: 1, Row: 1, Col: 2, Type: "A", Active: true, Reserved: true},
+				{LotID: 1, Floor: 1, Row: 1, Col: 3, Type: "M", Active: true},
+				{LotID: 1, Floor: 2, Row: 1, Col: 1, Type: "A", Active: false},
+				{LotID: 1, Floor: 2, Row: 1, Col: 2, Type: "X"},
+				{LotID: 2, Floor: 1, Row: 1, Col: 1, Type: "A", Active: true},
+			},
+		}),
+		LotDom: lotDom.InitLotDomain(lotDom.Option{
+			Memory: mem,
+			Lots:   []entity.Lot{{ID: 1, Name: "Main"}, {ID: 2, Name: "Annex"}},
+		}),
+		TransactionDom: transactionDom.Init(transactionDom.Option{Memory: mem}),
+	})
+
+	_, err := usecase.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B1234XYZ", VehicleType: entity.Automobile})
+	assert.NoError(t, err)
+
+	occupancy, err := usecase.Occupancy(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.Occupancy{
+		LotID:     1,
+		SpotCount: entity.SpotCount{Total: 5, Active: 3, Occupied: 1, Reserved: 1, Free: 1},
+		Floors: []entity.SpotCount{
+			{Floor: 1, Total: 3, Active: 3, Occupied: 1, Reserved: 1, Free: 1},
+			{Floor: 2, Total: 2},
+		},
+		Types: []entity.SpotCount{
+			{Type: "A", Total: 3, Active: 2, Occupied: 1, Reserved: 1},
+			{Type: "M", Total: 1, Active: 1, Free: 1},
+			{Type: "X", Total: 1},
+		},
+	}, occupancy)
+
+	occupancy, err = usecase.Occupancy(ctx, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, occupancy.Free)
+
+	_, err = usecase.Occupancy(ctx, 9)
+	assert.EqualValues(t, http.StatusNotFound, x.ErrCode(err))
+}
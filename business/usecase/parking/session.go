@@ -0,0 +1,124 @@
+package parking
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+const (
+	defaultSessionLimit = 50
+	maxSessionLimit     = 500
+)
+
+// GetSessions lists the sessions of a lot a page at a time. NextCursor is
+// set while there are more, passing it back as Cursor returns the next page
+// with the same sort.
+func (p *parking) GetSessions(ctx context.Context, data entity.GetSessions) (entity.SessionPage, error) {
+
+	var page entity.SessionPage
+
+	if _, err := p.policy(ctx, data.LotID); err != nil {
+		return page, err
+	}
+
+	switch data.Sort {
+	case "":
+		data.Sort = entity.SessionSortParkedAt
+	case entity.SessionSortParkedAt, entity.SessionSortDuration:
+	default:
+		return page, x.NewWithCode(http.StatusBadRequest, "sort must be parked_at or duration")
+	}
+
+	if data.From != nil && data.To != nil && !data.From.Before(*data.To) {
+		return page, x.NewWithCode(http.StatusBadRequest, "from must be before to")
+	}
+
+	if data.MinFee != nil && data.MaxFee != nil && *data.MinFee > *data.MaxFee {
+		return page, x.NewWithCode(http.StatusBadRequest, "min_fee is above max_fee")
+	}
+
+	if data.Limit < 1 {
+		data.Limit = defaultSessionLimit
+	}
+
+	if data.Limit > maxSessionLimit {
+		data.Limit = maxSessionLimit
+	}
+
+	data.AsOf = time.Now().Truncate(time.Microsecond)
+	if data.Cursor != "" {
+		after, err := decodeSessionCursor(data.Cursor)
+		if err != nil {
+			return page, x.WrapWithCode(err, http.StatusBadRequest, "invalid cursor")
+		}
+
+		if after.Sort != data.Sort || after.Desc != data.Desc {
+			return page, x.NewWithCode(http.StatusBadRequest, "cursor is for another sort")
+		}
+
+		data.After, data.AsOf = &after, after.AsOf
+	}
+
+	// one more than asked tells whether there is a next page
+	limit := data.Limit
+	data.Limit++
+
+	sessions, err := p.ParkingDom.GetSessions(ctx, data)
+	if err != nil {
+		return page, err
+	}
+
+	page.Sessions = sessions
+	if len(sessions) > limit {
+		page.Sessions = sessions[:limit]
+
+		last := page.Sessions[limit-1]
+		page.NextCursor = encodeSessionCursor(entity.SessionCursor{
+			Key:  sessionKey(last, data.Sort, data.AsOf),
+			ID:   last.ID,
+			Sort: data.Sort,
+			Desc: data.Desc,
+			AsOf: data.AsOf,
+		})
+	}
+
+	return page, nil
+}
+
+// sessionKey is what a session is sorted on, in microseconds.
+func sessionKey(v entity.Vehicle, sort string, asOf time.Time) int64 {
+	if sort != entity.SessionSortDuration {
+		return v.ParkedAt.UnixMicro()
+	}
+
+	end := asOf
+	if v.UnparkedAt != nil {
+		end = *v.UnparkedAt
+	}
+
+	return end.Sub(v.ParkedAt).Microseconds()
+}
+
+func encodeSessionCursor(c entity.SessionCursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeSessionCursor(s string) (entity.SessionCursor, error) {
+	var c entity.SessionCursor
+
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, err
+	}
+
+	err = json.Unmarshal(raw, &c)
+	return c, err
+}
@@ -0,0 +1,158 @@
+package parking_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	lotDom "github.com/zuhrulumam/go-parking-lot/business/domain/lot"
+	parkingDom "github.com/zuhrulumam/go-parking-lot/business/domain/parking"
+	tariffDom "github.com/zuhrulumam/go-parking-lot/business/domain/tariff"
+	transactionDom "github.com/zuhrulumam/go-parking-lot/business/domain/transaction"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	uc "github.com/zuhrulumam/go-parking-lot/business/usecase/parking"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+// newHistory parks B1234XYZ three times, leaving the last stay open, with
+// other vehicles in between.
+func newHistory(t *testing.T) (uc.UsecaseItf, []entity.Ticket) {
+	ctx := context.Background()
+	mem := memstore.New()
+
+	usecase := uc.InitParkingUsecase(uc.Option{
+		ParkingDom: parkingDom.InitParkingDomain(parkingDom.Option{
+			Memory: mem,
+			Spots: []entity.ParkingSpot{
+				{LotID: 1, Floor: 1, Row: 1, Col: 1, Type: "M", Active: true},
+				{LotID: 1, Floor: 2, Row: 1, Col: 1, Type: "M", Active: true},
+			},
+		}),
+		LotDom: lotDom.InitLotDomain(lotDom.Option{
+			Memory: mem,
+			Lots:   []entity.Lot{{ID: 1, Name: "Main"}},
+		}),
+		TariffDom: tariffDom.InitTariffDomain(tariffDom.Option{
+			Memory:  mem,
+			Tariffs: []entity.Tariff{{LotID: 1, VehicleType: "M", FirstHourPrice: 2000, GracePeriodMinutes: 10}},
+		}),
+		TransactionDom: transactionDom.Init(transactionDom.Option{Memory: mem}),
+	})
+
+	var tickets []entity.Ticket
+	for i, plate := range []string{"B1234XYZ", "B5678XYZ", "B1234XYZ", "B1234XYZ"} {
+		ticket, err := usecase.Park(ctx, entity.Park{LotID: 1, VehicleNumber: plate, VehicleType: entity.Motorcycle})
+		assert.NoError(t, err)
+		tickets = append(tickets, ticket)
+
+		if i < 3 {
+			_, err = usecase.Unpark(ctx, entity.UnPark{LotID: 1, TicketID: ticket.TicketID})
+			assert.NoError(t, err)
+		}
+
+		// sessions are told apart by when they started, and the open stay
+		// outlasts the closed ones
+		time.Sleep(time.Millisecond)
+	}
+
+	return usecase, tickets
+}
+
+func TestGetSessions(t *testing.T) {
+	ctx := context.Background()
+	usecase, tickets := newHistory(t)
+
+	// walk the history of the vehicle a page at a time, newest first
+	var got []string
+	filter := entity.GetSessions{LotID: 1, VehicleNumber: "B1234XYZ", Desc: true, Limit: 2}
+	for pages := 0; ; pages++ {
+		assert.Less(t, pages, 2)
+
+		page, err := usecase.GetSessions(ctx, filter)
+		assert.NoError(t, err)
+		for _, s := range page.Sessions {
+			got = append(got, s.TicketID)
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+		filter.Cursor = page.NextCursor
+	}
+	assert.Equal(t, []string{tickets[3].TicketID, tickets[2].TicketID, tickets[0].TicketID}, got)
+
+	page, err := usecase.GetSessions(ctx, entity.GetSessions{LotID: 1, Open: pkg.BoolPtr(true)})
+	assert.NoError(t, err)
+	assert.Len(t, page.Sessions, 1)
+	assert.Equal(t, tickets[3].TicketID, page.Sessions[0].TicketID)
+	assert.Empty(t, page.NextCursor)
+
+	// the open stay is the longest, it is still going
+	page, err = usecase.GetSessions(ctx, entity.GetSessions{LotID: 1, Sort: entity.SessionSortDuration, Desc: true, Limit: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, tickets[3].TicketID, page.Sessions[0].TicketID)
+	assert.NotEmpty(t, page.NextCursor)
+
+	page, err = usecase.GetSessions(ctx, entity.GetSessions{LotID: 1, Sort: entity.SessionSortDuration, Desc: true, Limit: 5, Cursor: page.NextCursor})
+	assert.NoError(t, err)
+	assert.Len(t, page.Sessions, 3)
+}
+
+func TestGetSessionsInvalid(t *testing.T) {
+	ctx := context.Background()
+	usecase, _ := newHistory(t)
+
+	page, err := usecase.GetSessions(ctx, entity.GetSessions{LotID: 1, Limit: 1})
+	assert.NoError(t, err)
+
+	now := time.Now()
+
+	tests := []struct {
+		name         string
+		input        entity.GetSessions
+		expectedCode int
+	}{
+		{
+			name:         "unknown lot",
+			input:        entity.GetSessions{LotID: 9},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "unknown sort",
+			input:        entity.GetSessions{LotID: 1, Sort: "fee"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "empty time range",
+			input:        entity.GetSessions{LotID: 1, From: &now, To: &now},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "empty fee range",
+			input:        entity.GetSessions{LotID: 1, MinFee: pkg.Int64Ptr(2), MaxFee: pkg.Int64Ptr(1)},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "malformed cursor",
+			input:        entity.GetSessions{LotID: 1, Cursor: "not a cursor"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "cursor of another sort",
+			input:        entity.GetSessions{LotID: 1, Desc: true, Cursor: page.NextCursor},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := usecase.GetSessions(ctx, tt.input)
+			assert.EqualValues(t, tt.expectedCode, x.ErrCode(err))
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package parking
+
+import (
+	"context"
+	"time"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+)
+
+// WatchOccupancy streams the occupancy of a lot: a snapshot first, then
+// every spot change with what it does to the counts. A fresh snapshot
+// follows every Resync and whenever changes may have been missed, so a
+// client never drifts for long. The channel is closed once ctx is done or
+// the watcher falls too far behind.
+func (p *parking) WatchOccupancy(ctx context.Context, lotID uint) (<-chan entity.OccupancyEvent, error) {
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	// subscribe before the snapshot, nothing committed in between is lost
+	changes, err := p.SpotFeedDom.Subscribe(ctx, lotID)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	snapshot, err := p.Occupancy(ctx, lotID)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	events := make(chan entity.OccupancyEvent)
+
+	go func() {
+		defer cancel()
+		defer close(events)
+
+		resync := time.NewTicker(p.Resync)
+		defer resync.Stop()
+
+		send := func(e entity.OccupancyEvent) bool {
+			select {
+			case events <- e:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		resend := func() bool {
+			snapshot, err := p.Occupancy(ctx, lotID)
+			if err != nil {
+				return false
+			}
+			return send(entity.OccupancyEvent{Event: entity.OccupancySnapshot, Snapshot: &snapshot})
+		}
+
+		if !send(entity.OccupancyEvent{Event: entity.OccupancySnapshot, Snapshot: &snapshot}) {
+			return
+		}
+
+		for {
+			var ok bool
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-resync.C:
+				ok = resend()
+			case c, open := <-changes:
+				switch {
+				case !open:
+					return
+				case c.Resync:
+					ok = resend()
+				default:
+					ok = send(entity.OccupancyEvent{Event: entity.OccupancyChange, Change: &c, Delta: spotDelta(c)})
+				}
+			}
+
+			if !ok {
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// spotDelta is what a change adds to the counts of the floors and types it
+// touches, groups left as they were are omitted.
+func spotDelta(c entity.SpotChange) []entity.SpotCount {
+	var delta []entity.SpotCount
+
+	add := func(s *entity.ParkingSpot, sign int) {
+		if s == nil {
+			return
+		}
+
+		n := entity.CountSpot(*s)
+		n = entity.SpotCount{
+			Floor:    n.Floor,
+			Type:     n.Type,
+			Total:    sign * n.Total,
+			Active:   sign * n.Active,
+			Occupied: sign * n.Occupied,
+			Reserved: sign * n.Reserved,
+			Free:     sign * n.Free,
+		}
+
+		for i := range delta {
+			if delta[i].Floor == n.Floor && delta[i].Type == n.Type {
+				delta[i].Add(n)
+				return
+			}
+		}
+		delta = append(delta, n)
+	}
+
+	add(c.Old, -1)
+	add(c.New, 1)
+
+	kept := delta[:0]
+	for _, d := range delta {
+		if d.Total != 0 || d.Active != 0 || d.Occupied != 0 || d.Reserved != 0 || d.Free != 0 {
+			kept = append(kept, d)
+		}
+	}
+
+	return kept
+}
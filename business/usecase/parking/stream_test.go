@@ -0,0 +1,127 @@
+package parking_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	lotDom "github.com/zuhrulumam/go-parking-lot/business/domain/lot"
+	parkingDom "github.com/zuhrulumam/go-parking-lot/business/domain/parking"
+	spotFeedDom "github.com/zuhrulumam/go-parking-lot/business/domain/spotfeed"
+	transactionDom "github.com/zuhrulumam/go-parking-lot/business/domain/transaction"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	uc "github.com/zuhrulumam/go-parking-lot/business/usecase/parking"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"github.com/zuhrulumam/go-parking-lot/pkg/broker"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+func nextEvent(t *testing.T, events <-chan entity.OccupancyEvent) entity.OccupancyEvent {
+	t.Helper()
+
+	select {
+	case ev, ok := <-events:
+		assert.True(t, ok, "stream closed")
+		return ev
+	case <-time.After(time.Second):
+		t.Fatal("no occupancy event")
+		return entity.OccupancyEvent{}
+	}
+}
+
+func TestWatchOccupancy(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mem := memstore.New()
+	changes := broker.New()
+
+	parking := parkingDom.InitParkingDomain(parkingDom.Option{
+		Memory: mem,
+		Spots: []entity.ParkingSpot{
+			{LotID: 1, Floor: 1, Row: 1, Col: 1, Type: "A", Active: true},
+			{LotID: 1, Floor: 2, Row: 1, Col: 1, Type: "M", Active: true},
+		},
+		Changes: changes,
+	})
+	tx := transactionDom.Init(transactionDom.Option{Memory: mem})
+
+	usecase := uc.InitParkingUsecase(uc.Option{
+		ParkingDom:     parking,
+		LotDom:         lotDom.InitLotDomain(lotDom.Option{Memory: mem, Lots: []entity.Lot{{ID: 1, Name: "Main"}}}),
+		TransactionDom: tx,
+		SpotFeedDom:    spotFeedDom.InitSpotFeedDomain(spotFeedDom.Option{Changes: changes, Memory: true}),
+		Resync:         time.Hour,
+	})
+
+	_, err := usecase.WatchOccupancy(ctx, 9)
+	assert.EqualValues(t, http.StatusNotFound, x.ErrCode(err))
+
+	events, err := usecase.WatchOccupancy(ctx, 1)
+	assert.NoError(t, err)
+
+	ev := nextEvent(t, events)
+	assert.Equal(t, entity.OccupancySnapshot, ev.Event)
+	assert.Equal(t, 2, ev.Snapshot.Free)
+
+	// rolled back changes are never streamed
+	err = tx.RunInTx(ctx, func(ctx context.Context) error {
+		if err := parking.UpdateParkingSpot(ctx, entity.UpdateParkingSpot{ID: 2, Active: pkg.BoolPtr(false)}); err != nil {
+			return err
+		}
+		return errors.New("boom")
+	})
+	assert.Error(t, err)
+
+	_, err = usecase.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B1234XYZ", VehicleType: entity.Automobile})
+	assert.NoError(t, err)
+
+	ev = nextEvent(t, events)
+	assert.Equal(t, entity.OccupancyChange, ev.Event)
+	assert.Equal(t, "1-1-1-1", ev.Change.New.Position().String())
+	assert.Equal(t, []entity.SpotCount{{Floor: 1, Type: "A", Occupied: 1, Free: -1}}, ev.Delta)
+
+	// a relay that missed changes asks for a snapshot
+	changes.Publish(entity.SpotChange{Resync: true})
+
+	ev = nextEvent(t, events)
+	assert.Equal(t, entity.OccupancySnapshot, ev.Event)
+	assert.Equal(t, 1, ev.Snapshot.Occupied)
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestWatchOccupancyResync(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mem := memstore.New()
+	changes := broker.New()
+
+	usecase := uc.InitParkingUsecase(uc.Option{
+		ParkingDom: parkingDom.InitParkingDomain(parkingDom.Option{
+			Memory:  mem,
+			Spots:   []entity.ParkingSpot{{LotID: 1, Floor: 1, Row: 1, Col: 1, Type: "A", Active: true}},
+			Changes: changes,
+		}),
+		LotDom:         lotDom.InitLotDomain(lotDom.Option{Memory: mem, Lots: []entity.Lot{{ID: 1, Name: "Main"}}}),
+		TransactionDom: transactionDom.Init(transactionDom.Option{Memory: mem}),
+		SpotFeedDom:    spotFeedDom.InitSpotFeedDomain(spotFeedDom.Option{Changes: changes, Memory: true}),
+		Resync:         10 * time.Millisecond,
+	})
+
+	events, err := usecase.WatchOccupancy(ctx, 1)
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		ev := nextEvent(t, events)
+		assert.Equal(t, entity.OccupancySnapshot, ev.Event)
+	}
+}
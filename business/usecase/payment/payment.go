@@ -0,0 +1,58 @@
+package payment
+
+import (
+	"context"
+
+	parkingDom "github.com/zuhrulumam/go-parking-lot/business/domain/parking"
+	paymentDom "github.com/zuhrulumam/go-parking-lot/business/domain/payment"
+	transactionDom "github.com/zuhrulumam/go-parking-lot/business/domain/transaction"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	parkingUc "github.com/zuhrulumam/go-parking-lot/business/usecase/parking"
+)
+
+type UsecaseItf interface {
+	Pay(ctx context.Context, data entity.Pay) (entity.PaymentResult, error)
+	Refund(ctx context.Context, data entity.Refund) (entity.Payment, error)
+	GetLedger(ctx context.Context, data entity.GetPayments) (entity.Ledger, error)
+}
+
+type Option struct {
+	ParkingDom     parkingDom.DomainItf
+	PaymentDom     paymentDom.DomainItf
+	TransactionDom transactionDom.DomainItf
+
+	// Parking authorizes the exit once a session is fully paid.
+	Parking parkingUc.UsecaseItf
+
+	// Providers defaults to CashProvider and SimulatedCardProvider.
+	Providers []Provider
+}
+
+type payment struct {
+	ParkingDom     parkingDom.DomainItf
+	PaymentDom     paymentDom.DomainItf
+	TransactionDom transactionDom.DomainItf
+	Parking        parkingUc.UsecaseItf
+	Providers      map[string]Provider
+}
+
+func InitPaymentUsecase(opt Option) UsecaseItf {
+	p := &payment{
+		ParkingDom:     opt.ParkingDom,
+		PaymentDom:     opt.PaymentDom,
+		TransactionDom: opt.TransactionDom,
+		Parking:        opt.Parking,
+		Providers:      map[string]Provider{},
+	}
+
+	providers := opt.Providers
+	if len(providers) == 0 {
+		providers = []Provider{CashProvider{}, SimulatedCardProvider{}}
+	}
+
+	for _, v := range providers {
+		p.Providers[v.Name()] = v
+	}
+
+	return p
+}
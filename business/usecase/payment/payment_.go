@@ -0,0 +1,291 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	parkingUc "github.com/zuhrulumam/go-parking-lot/business/usecase/parking"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+// Pay charges the provider and records the payment against a session
+// awaiting payment. Partial payments are allowed; the one settling the quote
+// also authorizes the exit.
+func (p *payment) Pay(ctx context.Context, data entity.Pay) (entity.PaymentResult, error) {
+
+	var result entity.PaymentResult
+
+	if data.Amount <= 0 {
+		return result, x.NewWithCode(http.StatusBadRequest, "amount must be positive")
+	}
+
+	provider, ok := p.Providers[data.Provider]
+	if !ok {
+		return result, x.NewWithCode(http.StatusBadRequest, "unknown payment provider")
+	}
+
+	// check before charging so a wrong ticket never reaches the provider
+	vec, _, err := p.awaitingPayment(ctx, data.LotID, data.TicketID, data.Amount, false)
+	if err != nil {
+		return result, err
+	}
+
+	reference, err := provider.Charge(ctx, data.Source, data.Amount)
+	if err != nil {
+		_, _ = p.PaymentDom.InsertPayment(ctx, entity.Payment{
+			LotID:     vec.LotID,
+			VehicleID: vec.ID,
+			TicketID:  vec.TicketID,
+			Kind:      entity.PaymentCharge,
+			Status:    entity.PaymentFailed,
+			Provider:  provider.Name(),
+			Amount:    data.Amount,
+			Note:      err.Error(),
+		})
+
+		if errors.Is(err, ErrDeclined) {
+			return result, x.WrapWithCode(err, http.StatusPaymentRequired, "payment declined by provider")
+		}
+
+		return result, x.WrapWithCode(err, http.StatusInternalServerError, "payment provider failed")
+	}
+
+	err = p.TransactionDom.RunInTx(ctx, func(newCtx context.Context) error {
+
+		// the ledger may have moved while the provider was charging
+		vec, paid, err := p.awaitingPayment(newCtx, data.LotID, data.TicketID, data.Amount, true)
+		if err != nil {
+			return err
+		}
+
+		result.Payment, err = p.PaymentDom.InsertPayment(newCtx, entity.Payment{
+			LotID:     vec.LotID,
+			VehicleID: vec.ID,
+			TicketID:  vec.TicketID,
+			Kind:      entity.PaymentCharge,
+			Status:    entity.PaymentSucceeded,
+			Provider:  provider.Name(),
+			Amount:    data.Amount,
+			Reference: reference,
+		})
+		if err != nil {
+			return err
+		}
+
+		if paid+data.Amount >= *vec.Fee {
+			result.Receipt, err = p.Parking.AuthorizeExit(newCtx, entity.AuthorizeExit{
+				LotID:    vec.LotID,
+				TicketID: vec.TicketID,
+			})
+			return err
+		}
+
+		// still short, hand back the updated quote
+		result.Receipt, err = p.Parking.Quote(newCtx, entity.UnPark{
+			LotID:    vec.LotID,
+			TicketID: vec.TicketID,
+		})
+		return err
+	})
+	if err != nil {
+		// the money moved but the ledger didn't, give it back
+		if _, rerr := provider.Refund(ctx, reference, data.Amount); rerr != nil {
+			return entity.PaymentResult{}, x.WrapWithCode(rerr, http.StatusInternalServerError,
+				fmt.Sprintf("failed to refund unrecorded payment %s: %v", reference, err))
+		}
+
+		return entity.PaymentResult{}, err
+	}
+
+	return result, nil
+}
+
+// Refund returns part or all of a succeeded charge. The total refunded can
+// never exceed the original charge.
+//
+// The refund is written as a pending line before the provider is called,
+// outside the transaction, and settled after. A refund the provider made
+// is never missing from the ledger, and one still pending counts against
+// what is left so concurrent refunds can't exceed the charge.
+func (p *payment) Refund(ctx context.Context, data entity.Refund) (entity.Payment, error) {
+
+	var (
+		refund   entity.Payment
+		charge   entity.Payment
+		provider Provider
+	)
+
+	if data.Amount <= 0 {
+		return refund, x.NewWithCode(http.StatusBadRequest, "amount must be positive")
+	}
+
+	err := p.TransactionDom.RunInTx(ctx, func(newCtx context.Context) error {
+
+		// concurrent refunds of the charge take turns
+		payments, err := p.PaymentDom.GetPayments(newCtx, entity.GetPayments{
+			LotID:   data.LotID,
+			ID:      data.PaymentID,
+			UseLock: true,
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(payments) < 1 {
+			return x.NewWithCode(http.StatusNotFound, "payment not found")
+		}
+
+		charge = payments[0]
+		if charge.Kind != entity.PaymentCharge || charge.Status != entity.PaymentSucceeded {
+			return x.NewWithCode(http.StatusUnprocessableEntity, "only succeeded charges can be refunded")
+		}
+
+		refunds, err := p.PaymentDom.GetPayments(newCtx, entity.GetPayments{
+			RefundOf: charge.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		remaining := charge.Amount
+		for _, v := range refunds {
+			if v.Status == entity.PaymentSucceeded || v.Status == entity.PaymentPending {
+				remaining -= v.Amount
+			}
+		}
+
+		if data.Amount > remaining {
+			return x.NewWithCode(http.StatusUnprocessableEntity, fmt.Sprintf("refund exceeds refundable amount: %d", remaining))
+		}
+
+		var ok bool
+		provider, ok = p.Providers[charge.Provider]
+		if !ok {
+			return x.NewWithCode(http.StatusInternalServerError, "payment provider no longer configured")
+		}
+
+		refund, err = p.PaymentDom.InsertPayment(newCtx, entity.Payment{
+			LotID:     charge.LotID,
+			VehicleID: charge.VehicleID,
+			TicketID:  charge.TicketID,
+			Kind:      entity.PaymentRefund,
+			Status:    entity.PaymentPending,
+			Provider:  charge.Provider,
+			Amount:    data.Amount,
+			RefundOf:  &charge.ID,
+			Note:      data.Reason,
+		})
+		return err
+	})
+	if err != nil {
+		return entity.Payment{}, err
+	}
+
+	reference, err := provider.Refund(ctx, charge.Reference, data.Amount)
+	if err != nil {
+		if uerr := p.PaymentDom.UpdatePayment(ctx, entity.UpdatePayment{
+			ID:     refund.ID,
+			Status: entity.PaymentFailed,
+			Note:   err.Error(),
+		}); uerr != nil {
+			return entity.Payment{}, x.WrapWithCode(uerr, http.StatusInternalServerError,
+				fmt.Sprintf("failed to record failed refund %d: %v", refund.ID, err))
+		}
+
+		return entity.Payment{}, x.WrapWithCode(err, http.StatusInternalServerError, "payment provider failed")
+	}
+
+	// left pending when this fails, for an operator to settle with the
+	// reference of the provider
+	err = p.PaymentDom.UpdatePayment(ctx, entity.UpdatePayment{
+		ID:        refund.ID,
+		Status:    entity.PaymentSucceeded,
+		Reference: reference,
+	})
+	if err != nil {
+		return entity.Payment{}, x.WrapWithCode(err, http.StatusInternalServerError,
+			fmt.Sprintf("refund %s made but refund %d is still pending", reference, refund.ID))
+	}
+
+	refund.Status = entity.PaymentSucceeded
+	refund.Reference = reference
+
+	return refund, nil
+}
+
+func (p *payment) GetLedger(ctx context.Context, data entity.GetPayments) (entity.Ledger, error) {
+
+	var ledger entity.Ledger
+
+	if data.TicketID == "" {
+		return ledger, x.NewWithCode(http.StatusBadRequest, "ticket id is required")
+	}
+
+	vec, err := p.ParkingDom.GetVehicle(ctx, entity.SearchVehicle{
+		LotID:    data.LotID,
+		TicketID: data.TicketID,
+	})
+	if err != nil {
+		return ledger, err
+	}
+
+	payments, err := p.PaymentDom.GetPayments(ctx, entity.GetPayments{
+		VehicleID: vec.ID,
+	})
+	if err != nil {
+		return ledger, err
+	}
+
+	ledger = entity.Ledger{
+		TicketID:   vec.TicketID,
+		AmountPaid: parkingUc.AmountPaid(payments),
+		Status:     vec.Status,
+		Payments:   payments,
+	}
+
+	if vec.Fee != nil {
+		ledger.Fee = *vec.Fee
+	}
+
+	if vec.Status == entity.SessionAwaitingPayment && ledger.Fee > ledger.AmountPaid {
+		ledger.AmountDue = ledger.Fee - ledger.AmountPaid
+	}
+
+	return ledger, nil
+}
+
+// awaitingPayment loads the session of a ticket in a lot and checks it can
+// take a payment of amount. lock holds the session until the transaction
+// of ctx ends, so the payment settles before another exit reads it.
+func (p *payment) awaitingPayment(ctx context.Context, lotID uint, ticketID string, amount int64, lock bool) (entity.Vehicle, int64, error) {
+	vec, err := p.ParkingDom.GetVehicle(ctx, entity.SearchVehicle{
+		LotID:    lotID,
+		TicketID: ticketID,
+		UseLock:  lock,
+	})
+	if err != nil {
+		return vec, 0, err
+	}
+
+	if vec.Status != entity.SessionAwaitingPayment || vec.Fee == nil {
+		return vec, 0, x.NewWithCode(http.StatusConflict, "session is not awaiting payment")
+	}
+
+	payments, err := p.PaymentDom.GetPayments(ctx, entity.GetPayments{
+		VehicleID: vec.ID,
+	})
+	if err != nil {
+		return vec, 0, err
+	}
+
+	paid := parkingUc.AmountPaid(payments)
+	if due := *vec.Fee - paid; amount > due {
+		return vec, paid, x.NewWithCode(http.StatusUnprocessableEntity, fmt.Sprintf("amount exceeds amount due: %d", due))
+	}
+
+	return vec, paid, nil
+}
@@ -0,0 +1,298 @@
+package payment_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	lotDom "github.com/zuhrulumam/go-parking-lot/business/domain/lot"
+	parkingDom "github.com/zuhrulumam/go-parking-lot/business/domain/parking"
+	paymentDom "github.com/zuhrulumam/go-parking-lot/business/domain/payment"
+	tariffDom "github.com/zuhrulumam/go-parking-lot/business/domain/tariff"
+	transactionDom "github.com/zuhrulumam/go-parking-lot/business/domain/transaction"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	parkingUc "github.com/zuhrulumam/go-parking-lot/business/usecase/parking"
+	uc "github.com/zuhrulumam/go-parking-lot/business/usecase/payment"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+	"github.com/zuhrulumam/go-parking-lot/pkg/metrics"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+// newLot is a single car spot where every stay costs 2000.
+func newLot(providers ...uc.Provider) (parkingUc.UsecaseItf, uc.UsecaseItf) {
+	return newMeteredLot(nil, providers...)
+}
+
+// newMeteredLot is newLot reporting its parks and unparks to m.
+func newMeteredLot(m metrics.Metrics, providers ...uc.Provider) (parkingUc.UsecaseItf, uc.UsecaseItf) {
+	mem := memstore.New()
+
+	pDom := parkingDom.InitParkingDomain(parkingDom.Option{
+		Memory: mem,
+		Spots:  []entity.ParkingSpot{{LotID: 1, Floor: 1, Row: 1, Col: 1, Type: "A", Active: true}},
+	})
+	payDom := paymentDom.InitPaymentDomain(paymentDom.Option{Memory: mem})
+	txDom := transactionDom.Init(transactionDom.Option{Memory: mem})
+
+	parking := parkingUc.InitParkingUsecase(parkingUc.Option{
+		ParkingDom: pDom,
+		LotDom: lotDom.InitLotDomain(lotDom.Option{
+			Memory: mem,
+			Lots:   []entity.Lot{{ID: 1, Name: "Main"}},
+		}),
+		TariffDom: tariffDom.InitTariffDomain(tariffDom.Option{
+			Memory:  mem,
+			Tariffs: []entity.Tariff{{LotID: 1, VehicleType: "A", FirstHourPrice: 2000}},
+		}),
+		PaymentDom:     payDom,
+		TransactionDom: txDom,
+		Metrics:        m,
+	})
+
+	payment := uc.InitPaymentUsecase(uc.Option{
+		ParkingDom:     pDom,
+		PaymentDom:     payDom,
+		TransactionDom: txDom,
+		Parking:        parking,
+		Providers:      providers,
+	})
+
+	return parking, payment
+}
+
+func requestExit(t *testing.T, parking parkingUc.UsecaseItf, number string) string {
+	ctx := context.Background()
+
+	ticket, err := parking.Park(ctx, entity.Park{LotID: 1, VehicleNumber: number, VehicleType: entity.Automobile})
+	assert.NoError(t, err)
+
+	receipt, err := parking.Unpark(ctx, entity.UnPark{LotID: 1, TicketID: ticket.TicketID})
+	assert.NoError(t, err)
+	assert.Equal(t, entity.SessionAwaitingPayment, receipt.Status)
+	assert.Equal(t, int64(2000), receipt.AmountDue)
+	assert.Nil(t, receipt.UnparkedAt)
+
+	return ticket.TicketID
+}
+
+func TestPayAndRefund(t *testing.T) {
+	ctx := context.Background()
+	parking, payment := newLot()
+
+	ticket, err := parking.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B1234XYZ", VehicleType: entity.Automobile})
+	assert.NoError(t, err)
+
+	_, err = payment.Pay(ctx, entity.Pay{LotID: 1, TicketID: ticket.TicketID, Amount: 500, Provider: uc.ProviderCash})
+	assert.EqualValues(t, http.StatusConflict, x.ErrCode(err), "exit not requested yet")
+
+	_, err = parking.Unpark(ctx, entity.UnPark{LotID: 1, TicketID: ticket.TicketID})
+	assert.NoError(t, err)
+
+	// partial payment keeps the spot taken
+	result, err := payment.Pay(ctx, entity.Pay{LotID: 1, TicketID: ticket.TicketID, Amount: 500, Provider: uc.ProviderCash})
+	assert.NoError(t, err)
+	assert.Equal(t, entity.SessionAwaitingPayment, result.Receipt.Status)
+	assert.Equal(t, int64(1500), result.Receipt.AmountDue)
+
+	_, err = parking.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B5678XYZ", VehicleType: entity.Automobile})
+	assert.Error(t, err)
+
+	_, err = payment.Pay(ctx, entity.Pay{LotID: 1, TicketID: ticket.TicketID, Amount: 1500, Provider: uc.ProviderCard, Source: "4000000000000002"})
+	assert.EqualValues(t, http.StatusPaymentRequired, x.ErrCode(err))
+
+	_, err = payment.Pay(ctx, entity.Pay{LotID: 1, TicketID: ticket.TicketID, Amount: 2000, Provider: uc.ProviderCash})
+	assert.EqualValues(t, http.StatusUnprocessableEntity, x.ErrCode(err))
+
+	_, err = payment.Pay(ctx, entity.Pay{LotID: 1, TicketID: ticket.TicketID, Amount: 100, Provider: "cheque"})
+	assert.EqualValues(t, http.StatusBadRequest, x.ErrCode(err))
+
+	// settling the fee closes the session and frees the spot
+	result, err = payment.Pay(ctx, entity.Pay{LotID: 1, TicketID: ticket.TicketID, Amount: 1500, Provider: uc.ProviderCard, Source: "4242424242424242"})
+	assert.NoError(t, err)
+	assert.Equal(t, entity.SessionExited, result.Receipt.Status)
+	assert.Equal(t, int64(2000), result.Receipt.AmountPaid)
+	assert.NotNil(t, result.Receipt.UnparkedAt)
+
+	_, err = parking.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B5678XYZ", VehicleType: entity.Automobile})
+	assert.NoError(t, err)
+
+	_, err = payment.Pay(ctx, entity.Pay{LotID: 1, TicketID: ticket.TicketID, Amount: 100, Provider: uc.ProviderCash})
+	assert.Error(t, err, "session already closed")
+
+	// refunds are capped by what is left of the charge
+	refund, err := payment.Refund(ctx, entity.Refund{LotID: 1, PaymentID: result.Payment.ID, Amount: 600, Reason: "loyalty discount"})
+	assert.NoError(t, err)
+	assert.Equal(t, entity.PaymentRefund, refund.Kind)
+	assert.Equal(t, result.Payment.ID, *refund.RefundOf)
+
+	_, err = payment.Refund(ctx, entity.Refund{LotID: 1, PaymentID: result.Payment.ID, Amount: 1000})
+	assert.EqualValues(t, http.StatusUnprocessableEntity, x.ErrCode(err))
+
+	_, err = payment.Refund(ctx, entity.Refund{LotID: 1, PaymentID: refund.ID, Amount: 100})
+	assert.EqualValues(t, http.StatusUnprocessableEntity, x.ErrCode(err), "refunds can't be refunded")
+
+	_, err = payment.Refund(ctx, entity.Refund{LotID: 1, PaymentID: 999, Amount: 100})
+	assert.EqualValues(t, http.StatusNotFound, x.ErrCode(err))
+
+	_, err = payment.Refund(ctx, entity.Refund{LotID: 2, PaymentID: result.Payment.ID, Amount: 100})
+	assert.EqualValues(t, http.StatusNotFound, x.ErrCode(err), "payments belong to their lot")
+
+	ledger, err := payment.GetLedger(ctx, entity.GetPayments{LotID: 1, TicketID: ticket.TicketID})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2000), ledger.Fee)
+	assert.Equal(t, int64(1400), ledger.AmountPaid)
+	assert.Equal(t, int64(0), ledger.AmountDue)
+	assert.Equal(t, entity.SessionExited, ledger.Status)
+
+	var kinds []string
+	for _, v := range ledger.Payments {
+		kinds = append(kinds, v.Kind+"/"+v.Status)
+	}
+	assert.Equal(t, []string{"charge/succeeded", "charge/failed", "charge/succeeded", "refund/succeeded"}, kinds)
+}
+
+// bankProvider takes any charge. Its refunds fail while fail is set, and
+// run during while with the bank.
+type bankProvider struct {
+	fail   bool
+	during func()
+}
+
+func (*bankProvider) Name() string { return "bank" }
+
+func (*bankProvider) Charge(ctx context.Context, source string, amount int64) (string, error) {
+	return "bank-charge", nil
+}
+
+func (b *bankProvider) Refund(ctx context.Context, reference string, amount int64) (string, error) {
+	if b.during != nil {
+		b.during()
+	}
+
+	if b.fail {
+		return "", errors.New("bank unavailable")
+	}
+
+	return "bank-refund", nil
+}
+
+// unparkMetrics keeps the unpark outcomes.
+type unparkMetrics struct {
+	metrics.Noop
+	unparks []string
+}
+
+func (m *unparkMetrics) Unpark(vehicleType, outcome string) {
+	m.unparks = append(m.unparks, vehicleType+" "+outcome)
+}
+
+func TestPayUnparkMetrics(t *testing.T) {
+	ctx := context.Background()
+	m := &unparkMetrics{}
+	parking, payment := newMeteredLot(m)
+
+	ticket := requestExit(t, parking, "B1234XYZ")
+
+	// partial payments quote without counting another exit
+	for i := 0; i < 3; i++ {
+		_, err := payment.Pay(ctx, entity.Pay{LotID: 1, TicketID: ticket, Amount: 500, Provider: uc.ProviderCash})
+		assert.NoError(t, err)
+	}
+
+	_, err := parking.AuthorizeExit(ctx, entity.AuthorizeExit{LotID: 1, TicketID: ticket})
+	assert.EqualValues(t, http.StatusPaymentRequired, x.ErrCode(err))
+
+	result, err := payment.Pay(ctx, entity.Pay{LotID: 1, TicketID: ticket, Amount: 500, Provider: uc.ProviderCash})
+	assert.NoError(t, err)
+	assert.Equal(t, entity.SessionExited, result.Receipt.Status)
+
+	assert.Equal(t, []string{"A awaiting_payment", "unknown awaiting_payment", "A ok"}, m.unparks)
+}
+
+func TestRefundPending(t *testing.T) {
+	ctx := context.Background()
+	bank := &bankProvider{}
+	parking, payment := newLot(bank)
+
+	ticket := requestExit(t, parking, "B1234XYZ")
+	result, err := payment.Pay(ctx, entity.Pay{LotID: 1, TicketID: ticket, Amount: 2000, Provider: "bank"})
+	assert.NoError(t, err)
+
+	// a refund the bank refused is kept as failed, and refunds nothing
+	bank.fail = true
+	_, err = payment.Refund(ctx, entity.Refund{LotID: 1, PaymentID: result.Payment.ID, Amount: 500})
+	assert.EqualValues(t, http.StatusInternalServerError, x.ErrCode(err))
+
+	// one with the bank already counts against what is left
+	bank.fail = false
+	bank.during = func() {
+		bank.during = nil
+		_, err := payment.Refund(ctx, entity.Refund{LotID: 1, PaymentID: result.Payment.ID, Amount: 1600})
+		assert.EqualValues(t, http.StatusUnprocessableEntity, x.ErrCode(err))
+	}
+
+	refund, err := payment.Refund(ctx, entity.Refund{LotID: 1, PaymentID: result.Payment.ID, Amount: 500})
+	assert.NoError(t, err)
+	assert.Equal(t, entity.PaymentSucceeded, refund.Status)
+	assert.Equal(t, "bank-refund", refund.Reference)
+
+	ledger, err := payment.GetLedger(ctx, entity.GetPayments{LotID: 1, TicketID: ticket})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1500), ledger.AmountPaid)
+
+	var kinds []string
+	for _, v := range ledger.Payments {
+		kinds = append(kinds, v.Kind+"/"+v.Status)
+	}
+	assert.Equal(t, []string{"charge/succeeded", "refund/failed", "refund/succeeded"}, kinds)
+}
+
+func TestAuthorizeExitOverride(t *testing.T) {
+	ctx := context.Background()
+	parking, payment := newLot()
+
+	ticketID := requestExit(t, parking, "B1234XYZ")
+
+	_, err := payment.Pay(ctx, entity.Pay{LotID: 1, TicketID: ticketID, Amount: 500, Provider: uc.ProviderCash})
+	assert.NoError(t, err)
+
+	_, err = parking.AuthorizeExit(ctx, entity.AuthorizeExit{LotID: 1, TicketID: ticketID})
+	assert.EqualValues(t, http.StatusPaymentRequired, x.ErrCode(err))
+
+	_, err = parking.AuthorizeExit(ctx, entity.AuthorizeExit{LotID: 1, TicketID: ticketID, Override: true})
+	assert.EqualValues(t, http.StatusBadRequest, x.ErrCode(err), "override needs operator and reason")
+
+	receipt, err := parking.AuthorizeExit(ctx, entity.AuthorizeExit{LotID: 1, TicketID: ticketID, Override: true, Operator: "ops-1", Reason: "barrier jammed"})
+	assert.NoError(t, err)
+	assert.Equal(t, entity.SessionExited, receipt.Status)
+	assert.Equal(t, int64(500), receipt.AmountPaid)
+
+	ledger, err := payment.GetLedger(ctx, entity.GetPayments{LotID: 1, TicketID: ticketID})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(500), ledger.AmountPaid, "overrides are not money")
+	assert.Len(t, ledger.Payments, 2)
+	assert.Equal(t, entity.PaymentOverride, ledger.Payments[1].Kind)
+	assert.Equal(t, int64(1500), ledger.Payments[1].Amount)
+	assert.Equal(t, "barrier jammed", ledger.Payments[1].Note)
+
+	_, err = parking.AuthorizeExit(ctx, entity.AuthorizeExit{LotID: 1, TicketID: ticketID, Override: true, Operator: "ops-1", Reason: "again"})
+	assert.Error(t, err)
+}
+
+func TestSimulatedCardProvider(t *testing.T) {
+	ctx := context.Background()
+	card := uc.SimulatedCardProvider{}
+
+	ref, err := card.Charge(ctx, "4242424242424242", 1000)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, ref)
+
+	_, err = card.Charge(ctx, "4000000000000002", 1000)
+	assert.ErrorIs(t, err, uc.ErrDeclined)
+
+	_, err = card.Charge(ctx, "", 1000)
+	assert.ErrorIs(t, err, uc.ErrDeclined)
+}
@@ -0,0 +1,56 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ProviderCash = "cash"
+	ProviderCard = "card"
+)
+
+// ErrDeclined is returned by a Provider that refused the charge.
+var ErrDeclined = errors.New("payment declined")
+
+// Provider moves the money for a payment. Charge and Refund return the
+// provider's reference, which is kept on the ledger line.
+type Provider interface {
+	Name() string
+	Charge(ctx context.Context, source string, amount int64) (string, error)
+	Refund(ctx context.Context, reference string, amount int64) (string, error)
+}
+
+// CashProvider records money taken at the booth, it never declines.
+type CashProvider struct{}
+
+func (CashProvider) Name() string { return ProviderCash }
+
+func (CashProvider) Charge(ctx context.Context, source string, amount int64) (string, error) {
+	return "cash-" + uuid.New().String(), nil
+}
+
+func (CashProvider) Refund(ctx context.Context, reference string, amount int64) (string, error) {
+	return "cash-" + uuid.New().String(), nil
+}
+
+// SimulatedCardProvider stands in for a card gateway. Card numbers ending
+// in 0002 are declined, like the usual gateway test cards.
+type SimulatedCardProvider struct{}
+
+func (SimulatedCardProvider) Name() string { return ProviderCard }
+
+func (SimulatedCardProvider) Charge(ctx context.Context, source string, amount int64) (string, error) {
+	if source == "" || strings.HasSuffix(source, "0002") {
+		return "", ErrDeclined
+	}
+
+	return "card-" + uuid.New().String(), nil
+}
+
+func (SimulatedCardProvider) Refund(ctx context.Context, reference string, amount int64) (string, error) {
+	return "card-" + uuid.New().String(), nil
+}
@@ -0,0 +1,82 @@
+package reservation
+
+import (
+	"context"
+	"time"
+
+	lotDom "github.com/zuhrulumam/go-parking-lot/business/domain/lot"
+	outboxDom "github.com/zuhrulumam/go-parking-lot/business/domain/outbox"
+	parkingDom "github.com/zuhrulumam/go-parking-lot/business/domain/parking"
+	reservationDom "github.com/zuhrulumam/go-parking-lot/business/domain/reservation"
+	transactionDom "github.com/zuhrulumam/go-parking-lot/business/domain/transaction"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	parkingUc "github.com/zuhrulumam/go-parking-lot/business/usecase/parking"
+)
+
+type UsecaseItf interface {
+	Reserve(ctx context.Context, data entity.Reserve) (entity.Reservation, error)
+	GetReservation(ctx context.Context, data entity.GetReservations) (entity.Reservation, error)
+	CancelReservation(ctx context.Context, data entity.GetReservations) (entity.Reservation, error)
+	ExpireReservations(ctx context.Context) (int, error)
+}
+
+type Option struct {
+	ParkingDom     parkingDom.DomainItf
+	LotDom         lotDom.DomainItf
+	ReservationDom reservationDom.DomainItf
+	TransactionDom transactionDom.DomainItf
+
+	// OutboxDom receives ReservationExpired, no events are written without
+	// it.
+	OutboxDom outboxDom.DomainItf
+
+	// Allocation picks the spot of "any spot of type X" reservations in
+	// lots without their own, defaults to parking.NearestStrategy.
+	Allocation parkingUc.AllocationStrategy
+
+	// Compatibility decides which spot types a vehicle type may hold, each
+	// lot turns fallback on and sets its reserve.
+	Compatibility parkingUc.Compatibility
+
+	// Waitlist is told about every spot a released hold frees.
+	Waitlist parkingUc.SpotOfferer
+
+	// MaxHold caps how far from now a hold may end, defaults to 24 hours.
+	MaxHold time.Duration
+}
+
+type reservation struct {
+	ParkingDom     parkingDom.DomainItf
+	LotDom         lotDom.DomainItf
+	ReservationDom reservationDom.DomainItf
+	TransactionDom transactionDom.DomainItf
+	OutboxDom      outboxDom.DomainItf
+	Allocation     parkingUc.AllocationStrategy
+	Compatibility  parkingUc.Compatibility
+	Waitlist       parkingUc.SpotOfferer
+	MaxHold        time.Duration
+}
+
+func InitReservationUsecase(opt Option) UsecaseItf {
+	r := &reservation{
+		ParkingDom:     opt.ParkingDom,
+		LotDom:         opt.LotDom,
+		ReservationDom: opt.ReservationDom,
+		TransactionDom: opt.TransactionDom,
+		OutboxDom:      opt.OutboxDom,
+		Allocation:     opt.Allocation,
+		Compatibility:  opt.Compatibility,
+		Waitlist:       opt.Waitlist,
+		MaxHold:        opt.MaxHold,
+	}
+
+	if r.Allocation == nil {
+		r.Allocation = parkingUc.NearestStrategy{}
+	}
+
+	if r.MaxHold == 0 {
+		r.MaxHold = 24 * time.Hour
+	}
+
+	return r
+}
@@ -0,0 +1,291 @@
+package reservation
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	parkingUc "github.com/zuhrulumam/go-parking-lot/business/usecase/parking"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+// Reserve holds a specific spot, or the spot the allocation strategy picks
+// for the vehicle type, from now until data.EndsAt. The vehicle may check in
+// between data.StartsAt and data.EndsAt.
+func (r *reservation) Reserve(ctx context.Context, data entity.Reserve) (entity.Reservation, error) {
+
+	var result entity.Reservation
+
+	now := time.Now()
+	if data.StartsAt.IsZero() {
+		data.StartsAt = now
+	}
+
+	switch {
+	case data.EndsAt.IsZero():
+		return result, x.NewWithCode(http.StatusBadRequest, "ends_at is required")
+	case !data.EndsAt.After(data.StartsAt):
+		return result, x.NewWithCode(http.StatusBadRequest, "ends_at must be after starts_at")
+	case !data.EndsAt.After(now):
+		return result, x.NewWithCode(http.StatusBadRequest, "ends_at must be in the future")
+	case data.EndsAt.Sub(now) > r.MaxHold:
+		return result, x.NewWithCode(http.StatusBadRequest, fmt.Sprintf("ends_at must be within %s", r.MaxHold))
+	}
+
+	if data.SpotID == "" && data.VehicleType == "" {
+		return result, x.NewWithCode(http.StatusBadRequest, "spot_id or vehicle_type is required")
+	}
+
+	_, policy, err := parkingUc.LoadLotPolicy(ctx, r.LotDom, data.LotID, parkingUc.LotPolicy{
+		Allocation:    r.Allocation,
+		Compatibility: r.Compatibility,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	err = r.TransactionDom.RunInTx(ctx, func(newCtx context.Context) error {
+
+		spot, err := r.pickSpot(newCtx, policy, data)
+		if err != nil {
+			return err
+		}
+
+		// hold the spot so Park and other reservations skip it
+		err = r.ParkingDom.UpdateParkingSpot(newCtx, entity.UpdateParkingSpot{
+			ID:       spot.ID,
+			Reserved: pkg.BoolPtr(true),
+		})
+		if err != nil {
+			return err
+		}
+
+		code, err := newCode()
+		if err != nil {
+			return err
+		}
+
+		// the vehicle type checking in, a spot reserved on its own is for
+		// vehicles of its type
+		vehicleType := string(data.VehicleType)
+		if vehicleType == "" {
+			vehicleType = spot.Type
+		}
+
+		result, err = r.ReservationDom.InsertReservation(newCtx, entity.Reservation{
+			LotID:         data.LotID,
+			Code:          code,
+			ParkingSpotID: spot.ID,
+			SpotID:        spot.Position().String(),
+			VehicleType:   vehicleType,
+			VehicleNumber: data.VehicleNumber,
+			StartsAt:      data.StartsAt,
+			EndsAt:        data.EndsAt,
+			Status:        entity.ReservationHeld,
+		})
+		return err
+	})
+	if err != nil {
+		return entity.Reservation{}, err
+	}
+
+	return result, nil
+}
+
+func (r *reservation) GetReservation(ctx context.Context, data entity.GetReservations) (entity.Reservation, error) {
+	if data.Code == "" {
+		return entity.Reservation{}, x.NewWithCode(http.StatusBadRequest, "reservation code is required")
+	}
+
+	reservations, err := r.ReservationDom.GetReservations(ctx, entity.GetReservations{
+		LotID:   data.LotID,
+		Code:    data.Code,
+		UseLock: data.UseLock,
+	})
+	if err != nil {
+		return entity.Reservation{}, err
+	}
+
+	if len(reservations) < 1 {
+		return entity.Reservation{}, x.NewWithCode(http.StatusNotFound, "reservation not found")
+	}
+
+	return reservations[0], nil
+}
+
+func (r *reservation) CancelReservation(ctx context.Context, data entity.GetReservations) (entity.Reservation, error) {
+
+	var result entity.Reservation
+
+	err := r.TransactionDom.RunInTx(ctx, func(newCtx context.Context) error {
+
+		// as a check-in or expiry, so a reservation just used isn't released
+		res, err := r.GetReservation(newCtx, entity.GetReservations{
+			LotID:   data.LotID,
+			Code:    data.Code,
+			UseLock: true,
+		})
+		if err != nil {
+			return err
+		}
+
+		if res.Status != entity.ReservationHeld {
+			return x.NewWithCode(http.StatusConflict, fmt.Sprintf("reservation is %s", res.Status))
+		}
+
+		result, err = r.release(newCtx, res, entity.ReservationCancelled)
+		return err
+	})
+	if err != nil {
+		return entity.Reservation{}, err
+	}
+
+	return result, nil
+}
+
+// ExpireReservations releases the holds nobody checked in for before their
+// deadline and returns how many were released.
+func (r *reservation) ExpireReservations(ctx context.Context) (int, error) {
+
+	var expired int
+
+	err := r.TransactionDom.RunInTx(ctx, func(newCtx context.Context) error {
+
+		reservations, err := r.ReservationDom.GetReservations(newCtx, entity.GetReservations{
+			Status:     entity.ReservationHeld,
+			EndsBefore: pkg.TimePtr(time.Now()),
+			UseLock:    true,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, v := range reservations {
+			released, err := r.release(newCtx, v, entity.ReservationExpired)
+			if err != nil {
+				return err
+			}
+
+			if r.OutboxDom != nil {
+				_, err = r.OutboxDom.InsertEvent(newCtx, entity.NewOutboxEvent(released.LotID, entity.EventReservationExpired, released))
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		expired = len(reservations)
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return expired, nil
+}
+
+// pickSpot returns the requested spot when the vehicle type fits it, or
+// else the spot the allocation strategy picks among those the vehicle type
+// may take.
+func (r *reservation) pickSpot(ctx context.Context, policy parkingUc.LotPolicy, data entity.Reserve) (entity.ParkingSpot, error) {
+	if data.SpotID == "" {
+		spots, err := policy.Compatibility.FreeSpots(ctx, r.ParkingDom, data.LotID, data.VehicleType)
+		if err != nil {
+			return entity.ParkingSpot{}, err
+		}
+
+		spot, ok, err := parkingUc.ClaimSpot(ctx, r.ParkingDom, spots, policy.Allocation.Pick)
+		if err != nil {
+			return entity.ParkingSpot{}, err
+		}
+
+		if !ok {
+			return entity.ParkingSpot{}, x.NewWithCode(http.StatusConflict, "no available parking")
+		}
+
+		return spot, nil
+	}
+
+	sp, err := pkg.ParseSpotID(data.SpotID)
+	if err != nil {
+		return entity.ParkingSpot{}, x.WrapWithCode(err, http.StatusBadRequest, "invalid spot id")
+	}
+
+	if sp.Lot != data.LotID {
+		return entity.ParkingSpot{}, x.NewWithCode(http.StatusBadRequest, "spot is in another lot")
+	}
+
+	spots, err := r.ParkingDom.GetAvailableParkingSpot(ctx, entity.GetAvailableParkingSpot{
+		LotID:    sp.Lot,
+		Floor:    sp.Floor,
+		Row:      sp.Row,
+		Col:      sp.Col,
+		Active:   pkg.BoolPtr(true),
+		Occupied: pkg.BoolPtr(false),
+		Reserved: pkg.BoolPtr(false),
+		UseLock:  true,
+	})
+	if err != nil {
+		return entity.ParkingSpot{}, err
+	}
+
+	if len(spots) < 1 || (data.VehicleType != "" && !policy.Compatibility.Fit(data.VehicleType, spots[0].Type)) {
+		return entity.ParkingSpot{}, x.NewWithCode(http.StatusConflict, "spot is not available for this vehicle type")
+	}
+
+	return spots[0], nil
+}
+
+func (r *reservation) release(ctx context.Context, res entity.Reservation, status string) (entity.Reservation, error) {
+	now := time.Now()
+
+	err := r.ReservationDom.UpdateReservation(ctx, entity.UpdateReservation{
+		ID:         res.ID,
+		Status:     status,
+		ReleasedAt: pkg.TimePtr(now),
+	})
+	if err != nil {
+		return res, err
+	}
+
+	err = r.ParkingDom.UpdateParkingSpot(ctx, entity.UpdateParkingSpot{
+		ID:       res.ParkingSpotID,
+		Reserved: pkg.BoolPtr(false),
+	})
+	if err != nil {
+		return res, err
+	}
+
+	if r.Waitlist != nil {
+		sp, err := pkg.ParseSpotID(res.SpotID)
+		if err != nil {
+			return res, err
+		}
+
+		if err := r.Waitlist.OfferSpot(ctx, *sp); err != nil {
+			return res, err
+		}
+	}
+
+	res.Status = status
+	res.ReleasedAt = &now
+
+	return res, nil
+}
+
+// newCode returns an 8 character code, short enough to read out at the gate.
+func newCode() (string, error) {
+	b := make([]byte, 5)
+	if _, err := rand.Read(b); err != nil {
+		return "", x.WrapWithCode(err, http.StatusInternalServerError, "failed to generate reservation code")
+	}
+
+	return base32.StdEncoding.EncodeToString(b), nil
+}
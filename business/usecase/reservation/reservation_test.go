@@ -0,0 +1,278 @@
+package reservation_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	lotDom "github.com/zuhrulumam/go-parking-lot/business/domain/lot"
+	parkingDom "github.com/zuhrulumam/go-parking-lot/business/domain/parking"
+	reservationDom "github.com/zuhrulumam/go-parking-lot/business/domain/reservation"
+	transactionDom "github.com/zuhrulumam/go-parking-lot/business/domain/transaction"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	parkingUc "github.com/zuhrulumam/go-parking-lot/business/usecase/parking"
+	uc "github.com/zuhrulumam/go-parking-lot/business/usecase/reservation"
+	mockParking "github.com/zuhrulumam/go-parking-lot/mocks/domain/parking"
+	mockReservation "github.com/zuhrulumam/go-parking-lot/mocks/domain/reservation"
+	mockTx "github.com/zuhrulumam/go-parking-lot/mocks/domain/transaction"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+	"go.uber.org/mock/gomock"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+// newLot is lot 1 with one floor of two car spots and one motorcycle spot,
+// and an empty lot 2.
+func newLot() (parkingUc.UsecaseItf, uc.UsecaseItf) {
+	mem := memstore.New()
+
+	pDom := parkingDom.InitParkingDomain(parkingDom.Option{
+		Memory: mem,
+		Spots: []entity.ParkingSpot{
+			{LotID: 1, Floor: 1, Row: 1, Col: 1, Type: "A", Active: true},
+			{LotID: 1, Floor: 1, Row: 1, Col: 2, Type: "A", Active: true},
+			{LotID: 1, Floor: 1, Row: 1, Col: 3, Type: "M", Active: true},
+		},
+	})
+	lDom := lotDom.InitLotDomain(lotDom.Option{
+		Memory: mem,
+		Lots:   []entity.Lot{{ID: 1, Name: "Main"}, {ID: 2, Name: "Annex"}},
+	})
+	rDom := reservationDom.InitReservationDomain(reservationDom.Option{Memory: mem})
+	txDom := transactionDom.Init(transactionDom.Option{Memory: mem})
+
+	parking := parkingUc.InitParkingUsecase(parkingUc.Option{
+		ParkingDom:     pDom,
+		LotDom:         lDom,
+		ReservationDom: rDom,
+		TransactionDom: txDom,
+	})
+
+	reservation := uc.InitReservationUsecase(uc.Option{
+		ParkingDom:     pDom,
+		LotDom:         lDom,
+		ReservationDom: rDom,
+		TransactionDom: txDom,
+	})
+
+	return parking, reservation
+}
+
+func TestReserve(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name         string
+		input        entity.Reserve
+		expectedSpot string
+		expectedCode int
+	}{
+		{
+			name:         "any spot of a type",
+			input:        entity.Reserve{LotID: 1, VehicleType: entity.Automobile, EndsAt: now.Add(time.Hour)},
+			expectedSpot: "1-1-1-1",
+		},
+		{
+			name:         "specific spot",
+			input:        entity.Reserve{LotID: 1, SpotID: "1-1-1-2", EndsAt: now.Add(time.Hour)},
+			expectedSpot: "1-1-1-2",
+		},
+		{
+			name:         "specific spot of another type",
+			input:        entity.Reserve{LotID: 1, SpotID: "1-1-1-3", VehicleType: entity.Automobile, EndsAt: now.Add(time.Hour)},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:         "spot in another lot",
+			input:        entity.Reserve{LotID: 2, SpotID: "1-1-1-2", EndsAt: now.Add(time.Hour)},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "no spots in the lot",
+			input:        entity.Reserve{LotID: 2, VehicleType: entity.Automobile, EndsAt: now.Add(time.Hour)},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:         "unknown lot",
+			input:        entity.Reserve{LotID: 9, VehicleType: entity.Automobile, EndsAt: now.Add(time.Hour)},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "no type available",
+			input:        entity.Reserve{LotID: 1, VehicleType: entity.Bicycle, EndsAt: now.Add(time.Hour)},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:         "window in the past",
+			input:        entity.Reserve{LotID: 1, VehicleType: entity.Automobile, StartsAt: now.Add(-2 * time.Hour), EndsAt: now.Add(-time.Hour)},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "hold too long",
+			input:        entity.Reserve{LotID: 1, VehicleType: entity.Automobile, EndsAt: now.Add(48 * time.Hour)},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "neither spot nor type",
+			input:        entity.Reserve{LotID: 1, EndsAt: now.Add(time.Hour)},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, reservation := newLot()
+
+			res, err := reservation.Reserve(context.Background(), tt.input)
+			if tt.expectedCode != 0 {
+				assert.EqualValues(t, tt.expectedCode, x.ErrCode(err))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedSpot, res.SpotID)
+			assert.Equal(t, entity.ReservationHeld, res.Status)
+			assert.Len(t, res.Code, 8)
+		})
+	}
+}
+
+func TestReservationCheckIn(t *testing.T) {
+	ctx := context.Background()
+	parking, reservation := newLot()
+
+	res, err := reservation.Reserve(ctx, entity.Reserve{LotID: 1, SpotID: "1-1-1-1", VehicleNumber: "B1234XYZ", EndsAt: time.Now().Add(time.Hour)})
+	assert.NoError(t, err)
+
+	// held spots are no longer offered
+	spots, err := parking.AvailableSpot(ctx, entity.GetAvailablePark{LotID: 1, VehicleType: entity.Automobile})
+	assert.NoError(t, err)
+	assert.Len(t, spots, 1)
+
+	ticket, err := parking.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B0001XYZ", VehicleType: entity.Automobile})
+	assert.NoError(t, err)
+	assert.Equal(t, "1-1-1-2", ticket.SpotID, "walk-ins skip the held spot")
+
+	_, err = parking.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B0002XYZ", VehicleType: entity.Automobile})
+	assert.Error(t, err)
+
+	_, err = parking.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B9999XYZ", VehicleType: entity.Automobile, ReservationCode: res.Code})
+	assert.EqualValues(t, http.StatusUnprocessableEntity, x.ErrCode(err))
+
+	_, err = parking.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B1234XYZ", VehicleType: entity.Automobile, ReservationCode: "NOPE"})
+	assert.EqualValues(t, http.StatusNotFound, x.ErrCode(err))
+
+	ticket, err = parking.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B1234XYZ", VehicleType: entity.Automobile, ReservationCode: res.Code})
+	assert.NoError(t, err)
+	assert.Equal(t, "1-1-1-1", ticket.SpotID)
+
+	res, err = reservation.GetReservation(ctx, entity.GetReservations{LotID: 1, Code: res.Code})
+	assert.NoError(t, err)
+	assert.Equal(t, entity.ReservationCheckedIn, res.Status)
+	assert.NotNil(t, res.VehicleID)
+
+	_, err = parking.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B1234XYZ", VehicleType: entity.Automobile, ReservationCode: res.Code})
+	assert.EqualValues(t, http.StatusConflict, x.ErrCode(err), "codes are single use")
+
+	_, err = reservation.CancelReservation(ctx, entity.GetReservations{LotID: 1, Code: res.Code})
+	assert.EqualValues(t, http.StatusConflict, x.ErrCode(err))
+
+	_, err = reservation.GetReservation(ctx, entity.GetReservations{LotID: 2, Code: res.Code})
+	assert.EqualValues(t, http.StatusNotFound, x.ErrCode(err), "codes belong to their lot")
+}
+
+func TestReservationWindow(t *testing.T) {
+	ctx := context.Background()
+	parking, reservation := newLot()
+
+	res, err := reservation.Reserve(ctx, entity.Reserve{
+		LotID:       1,
+		VehicleType: entity.Motorcycle,
+		StartsAt:    time.Now().Add(30 * time.Minute),
+		EndsAt:      time.Now().Add(time.Hour),
+	})
+	assert.NoError(t, err)
+
+	_, err = parking.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B1234XYZ", VehicleType: entity.Motorcycle, ReservationCode: res.Code})
+	assert.EqualValues(t, http.StatusConflict, x.ErrCode(err), "too early")
+
+	_, err = parking.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B1234XYZ", VehicleType: entity.Motorcycle})
+	assert.Error(t, err, "the only motorcycle spot is held")
+
+	res, err = reservation.CancelReservation(ctx, entity.GetReservations{LotID: 1, Code: res.Code})
+	assert.NoError(t, err)
+	assert.Equal(t, entity.ReservationCancelled, res.Status)
+
+	_, err = parking.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B1234XYZ", VehicleType: entity.Motorcycle})
+	assert.NoError(t, err)
+}
+
+func TestCancelReservationLocks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	r := mockReservation.NewMockDomainItf(ctrl)
+	p := mockParking.NewMockDomainItf(ctrl)
+	tx := mockTx.NewMockDomainItf(ctrl)
+
+	tx.EXPECT().RunInTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+
+	// locked like a check-in or expiry of the same reservation
+	r.EXPECT().GetReservations(gomock.Any(), entity.GetReservations{LotID: 1, Code: "ABCD2345", UseLock: true}).
+		Return([]entity.Reservation{{ID: 3, LotID: 1, ParkingSpotID: 7, SpotID: "1-1-1-1", Status: entity.ReservationHeld}}, nil)
+	r.EXPECT().UpdateReservation(gomock.Any(), gomock.Any()).Return(nil)
+	p.EXPECT().UpdateParkingSpot(gomock.Any(), entity.UpdateParkingSpot{ID: 7, Reserved: pkg.BoolPtr(false)}).Return(nil)
+
+	reservation := uc.InitReservationUsecase(uc.Option{
+		ParkingDom:     p,
+		ReservationDom: r,
+		TransactionDom: tx,
+	})
+
+	res, err := reservation.CancelReservation(context.Background(), entity.GetReservations{LotID: 1, Code: "ABCD2345"})
+	assert.NoError(t, err)
+	assert.Equal(t, entity.ReservationCancelled, res.Status)
+}
+
+func TestExpireReservations(t *testing.T) {
+	ctx := context.Background()
+	parking, reservation := newLot()
+
+	short, err := reservation.Reserve(ctx, entity.Reserve{LotID: 1, SpotID: "1-1-1-1", EndsAt: time.Now().Add(20 * time.Millisecond)})
+	assert.NoError(t, err)
+
+	long, err := reservation.Reserve(ctx, entity.Reserve{LotID: 1, SpotID: "1-1-1-2", EndsAt: time.Now().Add(time.Hour)})
+	assert.NoError(t, err)
+
+	n, err := reservation.ExpireReservations(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	time.Sleep(30 * time.Millisecond)
+
+	// a late check-in is refused even before the sweep runs
+	_, err = parking.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B1234XYZ", VehicleType: entity.Automobile, ReservationCode: short.Code})
+	assert.EqualValues(t, http.StatusConflict, x.ErrCode(err))
+
+	n, err = reservation.ExpireReservations(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	short, err = reservation.GetReservation(ctx, entity.GetReservations{LotID: 1, Code: short.Code})
+	assert.NoError(t, err)
+	assert.Equal(t, entity.ReservationExpired, short.Status)
+	assert.NotNil(t, short.ReleasedAt)
+
+	long, err = reservation.GetReservation(ctx, entity.GetReservations{LotID: 1, Code: long.Code})
+	assert.NoError(t, err)
+	assert.Equal(t, entity.ReservationHeld, long.Status)
+
+	ticket, err := parking.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B1234XYZ", VehicleType: entity.Automobile})
+	assert.NoError(t, err)
+	assert.Equal(t, "1-1-1-1", ticket.SpotID)
+}
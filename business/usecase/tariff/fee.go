@@ -0,0 +1,72 @@
+package tariff
+
+import (
+	"time"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+)
+
+// CalculateFee prices a stay from parkedAt to unparkedAt.
+//
+// Stays within the grace period are free. Otherwise every started hour is
+// charged: the first one at FirstHourPrice, the rest at the weekend, night
+// or regular hourly price, in that order of precedence, based on when the
+// hour starts in loc. The stay is cut into 24 hour windows from parkedAt
+// and each window is capped at DailyCap.
+func CalculateFee(t entity.Tariff, parkedAt, unparkedAt time.Time, loc *time.Location) int64 {
+	stay := unparkedAt.Sub(parkedAt)
+	if stay <= time.Duration(t.GracePeriodMinutes)*time.Minute {
+		return 0
+	}
+
+	var total int64
+	for dayStart := parkedAt; dayStart.Before(unparkedAt); dayStart = dayStart.Add(24 * time.Hour) {
+		dayEnd := dayStart.Add(24 * time.Hour)
+		if dayEnd.After(unparkedAt) {
+			dayEnd = unparkedAt
+		}
+
+		var day int64
+		for hour := dayStart; hour.Before(dayEnd); hour = hour.Add(time.Hour) {
+			day += hourPrice(t, hour.In(loc), hour.Equal(parkedAt))
+		}
+
+		if t.DailyCap > 0 && day > t.DailyCap {
+			day = t.DailyCap
+		}
+
+		total += day
+	}
+
+	return total
+}
+
+func hourPrice(t entity.Tariff, start time.Time, first bool) int64 {
+	switch {
+	case first:
+		return t.FirstHourPrice
+	case t.WeekendHourlyPrice > 0 && isWeekend(start):
+		return t.WeekendHourlyPrice
+	case t.NightHourlyPrice > 0 && isNight(t, start):
+		return t.NightHourlyPrice
+	default:
+		return t.HourlyPrice
+	}
+}
+
+func isWeekend(at time.Time) bool {
+	return at.Weekday() == time.Saturday || at.Weekday() == time.Sunday
+}
+
+// isNight reports whether at falls in [NightStartHour, NightEndHour), a
+// window that may wrap around midnight. Equal hours mean no night window.
+func isNight(t entity.Tariff, at time.Time) bool {
+	h := at.Hour()
+	if t.NightStartHour == t.NightEndHour {
+		return false
+	}
+	if t.NightStartHour < t.NightEndHour {
+		return h >= t.NightStartHour && h < t.NightEndHour
+	}
+	return h >= t.NightStartHour || h < t.NightEndHour
+}
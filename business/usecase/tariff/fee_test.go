@@ -0,0 +1,77 @@
+package tariff_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/business/usecase/tariff"
+)
+
+func TestCalculateFee(t *testing.T) {
+	car := entity.Tariff{
+		VehicleType:        "A",
+		FirstHourPrice:     5000,
+		HourlyPrice:        3000,
+		DailyCap:           40000,
+		GracePeriodMinutes: 10,
+		NightHourlyPrice:   2000,
+		NightStartHour:     22,
+		NightEndHour:       6,
+		WeekendHourlyPrice: 4000,
+	}
+
+	// 2026-10-14 is a Wednesday, 2026-10-17 a Saturday
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2026, 10, day, hour, min, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name       string
+		tariff     entity.Tariff
+		parkedAt   time.Time
+		unparkedAt time.Time
+		loc        *time.Location
+		expected   int64
+	}{
+		{name: "inside grace period", tariff: car, parkedAt: at(14, 10, 0), unparkedAt: at(14, 10, 5), expected: 0},
+		{name: "exactly grace period", tariff: car, parkedAt: at(14, 10, 0), unparkedAt: at(14, 10, 10), expected: 0},
+		{name: "just over grace period", tariff: car, parkedAt: at(14, 10, 0), unparkedAt: at(14, 10, 11), expected: 5000},
+		{name: "exactly one hour", tariff: car, parkedAt: at(14, 10, 0), unparkedAt: at(14, 11, 0), expected: 5000},
+		{name: "started second hour", tariff: car, parkedAt: at(14, 10, 0), unparkedAt: at(14, 11, 1), expected: 8000},
+		{name: "three hours", tariff: car, parkedAt: at(14, 10, 0), unparkedAt: at(14, 13, 0), expected: 11000},
+		{name: "night rate", tariff: car, parkedAt: at(14, 21, 0), unparkedAt: at(15, 0, 30), expected: 11000},
+		{name: "weekend rate", tariff: car, parkedAt: at(17, 10, 0), unparkedAt: at(17, 13, 0), expected: 13000},
+		{name: "weekend wins over night", tariff: car, parkedAt: at(17, 21, 0), unparkedAt: at(17, 23, 30), expected: 13000},
+		{name: "below daily cap", tariff: car, parkedAt: at(14, 8, 0), unparkedAt: at(14, 20, 0), expected: 38000},
+		{name: "daily cap", tariff: car, parkedAt: at(14, 8, 0), unparkedAt: at(14, 22, 0), expected: 40000},
+		{name: "cap per 24h window", tariff: car, parkedAt: at(14, 8, 0), unparkedAt: at(15, 10, 0), expected: 46000},
+		{
+			name:       "night evaluated in lot timezone",
+			tariff:     car,
+			parkedAt:   at(14, 14, 0),
+			unparkedAt: at(14, 17, 30),
+			loc:        time.FixedZone("WIB", 7*60*60),
+			expected:   11000,
+		},
+		{
+			name:       "no cap configured",
+			tariff:     entity.Tariff{FirstHourPrice: 1000, HourlyPrice: 1000},
+			parkedAt:   at(14, 0, 0),
+			unparkedAt: at(15, 0, 0),
+			expected:   24000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc := tt.loc
+			if loc == nil {
+				loc = time.UTC
+			}
+
+			assert.Equal(t, tt.expected, tariff.CalculateFee(tt.tariff, tt.parkedAt, tt.unparkedAt, loc))
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package tariff
+
+import (
+	"context"
+
+	lotDom "github.com/zuhrulumam/go-parking-lot/business/domain/lot"
+	tariffDom "github.com/zuhrulumam/go-parking-lot/business/domain/tariff"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+)
+
+type UsecaseItf interface {
+	GetTariffs(ctx context.Context, data entity.GetTariffs) ([]entity.Tariff, error)
+	GetTariff(ctx context.Context, data entity.GetTariff) (entity.Tariff, error)
+	UpdateTariff(ctx context.Context, data entity.Tariff) (entity.Tariff, error)
+}
+
+type Option struct {
+	TariffDom tariffDom.DomainItf
+	LotDom    lotDom.DomainItf
+}
+
+type tariff struct {
+	TariffDom tariffDom.DomainItf
+	LotDom    lotDom.DomainItf
+}
+
+func InitTariffUsecase(opt Option) UsecaseItf {
+	return &tariff{
+		TariffDom: opt.TariffDom,
+		LotDom:    opt.LotDom,
+	}
+}
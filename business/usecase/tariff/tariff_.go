@@ -0,0 +1,53 @@
+package tariff
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+func (t *tariff) GetTariffs(ctx context.Context, data entity.GetTariffs) ([]entity.Tariff, error) {
+	if _, err := t.LotDom.GetLot(ctx, entity.GetLot{ID: data.LotID}); err != nil {
+		return nil, err
+	}
+
+	return t.TariffDom.GetTariffs(ctx, data)
+}
+
+func (t *tariff) GetTariff(ctx context.Context, data entity.GetTariff) (entity.Tariff, error) {
+	return t.TariffDom.GetTariff(ctx, data)
+}
+
+func (t *tariff) UpdateTariff(ctx context.Context, data entity.Tariff) (entity.Tariff, error) {
+	if err := validateTariff(data); err != nil {
+		return data, err
+	}
+
+	if _, err := t.LotDom.GetLot(ctx, entity.GetLot{ID: data.LotID}); err != nil {
+		return data, err
+	}
+
+	return t.TariffDom.UpsertTariff(ctx, data)
+}
+
+func validateTariff(t entity.Tariff) error {
+	switch entity.VehicleType(t.VehicleType) {
+	case entity.Bicycle, entity.Motorcycle, entity.Automobile:
+	default:
+		return x.NewWithCode(http.StatusBadRequest, "unknown vehicle type")
+	}
+
+	if t.FirstHourPrice < 0 || t.HourlyPrice < 0 || t.DailyCap < 0 ||
+		t.NightHourlyPrice < 0 || t.WeekendHourlyPrice < 0 || t.GracePeriodMinutes < 0 {
+		return x.NewWithCode(http.StatusBadRequest, "prices and grace period must not be negative")
+	}
+
+	if t.NightStartHour < 0 || t.NightStartHour > 23 || t.NightEndHour < 0 || t.NightEndHour > 23 {
+		return x.NewWithCode(http.StatusBadRequest, "night hours must be between 0 and 23")
+	}
+
+	return nil
+}
@@ -1,24 +1,138 @@
 package usecase
 
 import (
+	"time"
+
 	"github.com/zuhrulumam/go-parking-lot/business/domain"
+	"github.com/zuhrulumam/go-parking-lot/business/usecase/admin"
+	"github.com/zuhrulumam/go-parking-lot/business/usecase/auth"
+	"github.com/zuhrulumam/go-parking-lot/business/usecase/outbox"
 	"github.com/zuhrulumam/go-parking-lot/business/usecase/parking"
+	"github.com/zuhrulumam/go-parking-lot/business/usecase/payment"
+	"github.com/zuhrulumam/go-parking-lot/business/usecase/reservation"
+	"github.com/zuhrulumam/go-parking-lot/business/usecase/tariff"
+	"github.com/zuhrulumam/go-parking-lot/business/usecase/waitlist"
+	"github.com/zuhrulumam/go-parking-lot/business/usecase/webhook"
+	"github.com/zuhrulumam/go-parking-lot/pkg/metrics"
 )
 
 type Usecase struct {
-	Parking parking.UsecaseItf
+	Parking     parking.UsecaseItf
+	Tariff      tariff.UsecaseItf
+	Payment     payment.UsecaseItf
+	Reservation reservation.UsecaseItf
+	Waitlist    waitlist.UsecaseItf
+	Admin       admin.UsecaseItf
+	Outbox      outbox.UsecaseItf
+	Webhook     webhook.UsecaseItf
+	Auth        auth.UsecaseItf
 }
 
 type Option struct {
+	// Allocation, Compatibility and Location are the defaults of lots
+	// leaving them empty.
+	Allocation    parking.AllocationStrategy
+	Compatibility parking.Compatibility
+	Location      *time.Location
+
+	// ClaimTimeout is how long a spot offered from the waitlist is held.
+	ClaimTimeout time.Duration
+
+	// OccupancyResync is how often occupancy streams get a fresh snapshot.
+	OccupancyResync time.Duration
+
+	// EventSinks receive the domain events, see outbox.NewSinks.
+	EventSinks []outbox.Sink
+
+	// TokenSigner signs and verifies the user tokens, see auth.NewSigner.
+	TokenSigner *auth.Signer
+	TokenTTL    time.Duration
+
+	// Metrics counts the parks and unparks by outcome.
+	Metrics metrics.Metrics
 }
 
 func Init(dom *domain.Domain, opt Option) *Usecase {
 	u := &Usecase{
-		Parking: parking.InitParkingUsecase(parking.Option{
+		Waitlist: waitlist.InitWaitlistUsecase(waitlist.Option{
 			ParkingDom:     dom.Parking,
+			LotDom:         dom.Lot,
+			WaitlistDom:    dom.Waitlist,
 			TransactionDom: dom.Transaction,
+			Allocation:     opt.Allocation,
+			Compatibility:  opt.Compatibility,
+			ClaimTimeout:   opt.ClaimTimeout,
+		}),
+		Tariff: tariff.InitTariffUsecase(tariff.Option{
+			TariffDom: dom.Tariff,
+			LotDom:    dom.Lot,
 		}),
 	}
 
+	u.Parking = parking.InitParkingUsecase(parking.Option{
+		ParkingDom:     dom.Parking,
+		LotDom:         dom.Lot,
+		TransactionDom: dom.Transaction,
+		TariffDom:      dom.Tariff,
+		PaymentDom:     dom.Payment,
+		ReservationDom: dom.Reservation,
+		WaitlistDom:    dom.Waitlist,
+		SpotFeedDom:    dom.SpotFeed,
+		OutboxDom:      dom.Outbox,
+		Waitlist:       u.Waitlist,
+		Allocation:     opt.Allocation,
+		Compatibility:  opt.Compatibility,
+		Location:       opt.Location,
+		Resync:         opt.OccupancyResync,
+		Metrics:        opt.Metrics,
+	})
+
+	u.Reservation = reservation.InitReservationUsecase(reservation.Option{
+		ParkingDom:     dom.Parking,
+		LotDom:         dom.Lot,
+		ReservationDom: dom.Reservation,
+		TransactionDom: dom.Transaction,
+		OutboxDom:      dom.Outbox,
+		Allocation:     opt.Allocation,
+		Compatibility:  opt.Compatibility,
+		Waitlist:       u.Waitlist,
+	})
+
+	u.Payment = payment.InitPaymentUsecase(payment.Option{
+		ParkingDom:     dom.Parking,
+		PaymentDom:     dom.Payment,
+		TransactionDom: dom.Transaction,
+		Parking:        u.Parking,
+	})
+
+	u.Admin = admin.InitAdminUsecase(admin.Option{
+		ParkingDom:     dom.Parking,
+		LotDom:         dom.Lot,
+		AuditDom:       dom.Audit,
+		TransactionDom: dom.Transaction,
+		OutboxDom:      dom.Outbox,
+		Waitlist:       u.Waitlist,
+	})
+
+	u.Outbox = outbox.InitOutboxUsecase(outbox.Option{
+		OutboxDom:      dom.Outbox,
+		TransactionDom: dom.Transaction,
+		WebhookDom:     dom.Webhook,
+		Sinks:          opt.EventSinks,
+	})
+
+	u.Webhook = webhook.InitWebhookUsecase(webhook.Option{
+		WebhookDom: dom.Webhook,
+		OutboxDom:  dom.Outbox,
+		LotDom:     dom.Lot,
+	})
+
+	u.Auth = auth.InitAuthUsecase(auth.Option{
+		AuthDom:  dom.Auth,
+		LotDom:   dom.Lot,
+		Signer:   opt.TokenSigner,
+		TokenTTL: opt.TokenTTL,
+	})
+
 	return u
 }
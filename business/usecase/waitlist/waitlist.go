@@ -0,0 +1,82 @@
+package waitlist
+
+import (
+	"context"
+	"time"
+
+	lotDom "github.com/zuhrulumam/go-parking-lot/business/domain/lot"
+	parkingDom "github.com/zuhrulumam/go-parking-lot/business/domain/parking"
+	transactionDom "github.com/zuhrulumam/go-parking-lot/business/domain/transaction"
+	waitlistDom "github.com/zuhrulumam/go-parking-lot/business/domain/waitlist"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	parkingUc "github.com/zuhrulumam/go-parking-lot/business/usecase/parking"
+)
+
+type UsecaseItf interface {
+	Join(ctx context.Context, data entity.Park) (entity.WaitlistEntry, error)
+	GetEntry(ctx context.Context, data entity.GetWaitlist) (entity.WaitlistEntry, error)
+	Leave(ctx context.Context, data entity.GetWaitlist) (entity.WaitlistEntry, error)
+	OfferSpot(ctx context.Context, spot entity.SpotID) error
+	ExpireOffers(ctx context.Context) (int, error)
+}
+
+type Option struct {
+	ParkingDom     parkingDom.DomainItf
+	LotDom         lotDom.DomainItf
+	WaitlistDom    waitlistDom.DomainItf
+	TransactionDom transactionDom.DomainItf
+
+	// Allocation picks the spot offered when several are free at once in
+	// lots without their own strategy, defaults to parking.NearestStrategy.
+	Allocation parkingUc.AllocationStrategy
+
+	// Compatibility decides which queues a free spot may be offered to,
+	// each lot turns fallback on and sets its reserve.
+	Compatibility parkingUc.Compatibility
+
+	// ClaimTimeout is how long an offered spot is held, defaults to 5
+	// minutes.
+	ClaimTimeout time.Duration
+}
+
+type waitlist struct {
+	ParkingDom     parkingDom.DomainItf
+	LotDom         lotDom.DomainItf
+	WaitlistDom    waitlistDom.DomainItf
+	TransactionDom transactionDom.DomainItf
+	Allocation     parkingUc.AllocationStrategy
+	Compatibility  parkingUc.Compatibility
+	ClaimTimeout   time.Duration
+}
+
+func InitWaitlistUsecase(opt Option) UsecaseItf {
+	w := &waitlist{
+		ParkingDom:     opt.ParkingDom,
+		LotDom:         opt.LotDom,
+		WaitlistDom:    opt.WaitlistDom,
+		TransactionDom: opt.TransactionDom,
+		Allocation:     opt.Allocation,
+		Compatibility:  opt.Compatibility,
+		ClaimTimeout:   opt.ClaimTimeout,
+	}
+
+	if w.Allocation == nil {
+		w.Allocation = parkingUc.NearestStrategy{}
+	}
+
+	if w.ClaimTimeout == 0 {
+		w.ClaimTimeout = 5 * time.Minute
+	}
+
+	return w
+}
+
+// policy loads how a lot hands out spots.
+func (w *waitlist) policy(ctx context.Context, lotID uint) (parkingUc.LotPolicy, error) {
+	_, policy, err := parkingUc.LoadLotPolicy(ctx, w.LotDom, lotID, parkingUc.LotPolicy{
+		Allocation:    w.Allocation,
+		Compatibility: w.Compatibility,
+	})
+
+	return policy, err
+}
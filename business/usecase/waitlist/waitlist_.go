@@ -0,0 +1,356 @@
+package waitlist
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	parkingUc "github.com/zuhrulumam/go-parking-lot/business/usecase/parking"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+// Join queues a vehicle for the next free spot of its type in the lot. A
+// spot freed between the failed Park and Join is offered right away.
+func (w *waitlist) Join(ctx context.Context, data entity.Park) (entity.WaitlistEntry, error) {
+
+	var entry entity.WaitlistEntry
+
+	switch data.VehicleType {
+	case entity.Bicycle, entity.Motorcycle, entity.Automobile:
+	default:
+		return entry, x.NewWithCode(http.StatusBadRequest, "unknown vehicle type")
+	}
+
+	if data.VehicleNumber == "" {
+		return entry, x.NewWithCode(http.StatusBadRequest, "vehicle number is required")
+	}
+
+	policy, err := w.policy(ctx, data.LotID)
+	if err != nil {
+		return entry, err
+	}
+
+	err = w.TransactionDom.RunInTx(ctx, func(newCtx context.Context) error {
+
+		open, err := w.WaitlistDom.CountEntries(newCtx, entity.GetWaitlist{
+			VehicleNumber: data.VehicleNumber,
+			Statuses:      []string{entity.WaitlistWaiting, entity.WaitlistOffered},
+		})
+		if err != nil {
+			return err
+		}
+
+		if open > 0 {
+			return x.NewWithCode(http.StatusConflict, "vehicle is already on the waitlist")
+		}
+
+		entry, err = w.WaitlistDom.InsertEntry(newCtx, entity.WaitlistEntry{
+			LotID:         data.LotID,
+			Code:          uuid.New().String(),
+			VehicleType:   string(data.VehicleType),
+			VehicleNumber: data.VehicleNumber,
+			Status:        entity.WaitlistWaiting,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := w.fill(newCtx, policy, data.LotID, entry.VehicleType); err != nil {
+			return err
+		}
+
+		entry, err = w.GetEntry(newCtx, entity.GetWaitlist{
+			LotID: data.LotID,
+			Code:  entry.Code,
+		})
+		return err
+	})
+	if err != nil {
+		return entity.WaitlistEntry{}, err
+	}
+
+	return entry, nil
+}
+
+// GetEntry returns a waitlist entry with its current queue position.
+func (w *waitlist) GetEntry(ctx context.Context, data entity.GetWaitlist) (entity.WaitlistEntry, error) {
+	if data.Code == "" {
+		return entity.WaitlistEntry{}, x.NewWithCode(http.StatusBadRequest, "waitlist code is required")
+	}
+
+	entries, err := w.WaitlistDom.GetEntries(ctx, entity.GetWaitlist{
+		LotID:   data.LotID,
+		Code:    data.Code,
+		UseLock: data.UseLock,
+	})
+	if err != nil {
+		return entity.WaitlistEntry{}, err
+	}
+
+	if len(entries) < 1 {
+		return entity.WaitlistEntry{}, x.NewWithCode(http.StatusNotFound, "waitlist entry not found")
+	}
+
+	entry := entries[0]
+
+	if entry.Status == entity.WaitlistWaiting {
+		ahead, err := w.WaitlistDom.CountEntries(ctx, entity.GetWaitlist{
+			LotID:       entry.LotID,
+			VehicleType: entry.VehicleType,
+			Statuses:    []string{entity.WaitlistWaiting},
+			BeforeID:    entry.ID,
+		})
+		if err != nil {
+			return entry, err
+		}
+
+		entry.Position = ahead + 1
+	}
+
+	return entry, nil
+}
+
+// Leave takes a vehicle off the waitlist. A spot it was offered goes to the
+// next vehicle in the queue.
+func (w *waitlist) Leave(ctx context.Context, data entity.GetWaitlist) (entity.WaitlistEntry, error) {
+
+	var entry entity.WaitlistEntry
+
+	err := w.TransactionDom.RunInTx(ctx, func(newCtx context.Context) error {
+
+		var err error
+
+		entry, err = w.GetEntry(newCtx, entity.GetWaitlist{
+			LotID:   data.LotID,
+			Code:    data.Code,
+			UseLock: true,
+		})
+		if err != nil {
+			return err
+		}
+
+		if entry.Status != entity.WaitlistWaiting && entry.Status != entity.WaitlistOffered {
+			return x.NewWithCode(http.StatusConflict, fmt.Sprintf("waitlist entry is %s", entry.Status))
+		}
+
+		entry, err = w.close(newCtx, entry, entity.WaitlistLeft)
+		return err
+	})
+	if err != nil {
+		return entity.WaitlistEntry{}, err
+	}
+
+	return entry, nil
+}
+
+// OfferSpot holds a free spot for the head of the queue of its type in its
+// lot. It is a no-op when nobody waits or the spot can't be used.
+func (w *waitlist) OfferSpot(ctx context.Context, spot entity.SpotID) error {
+	policy, err := w.policy(ctx, spot.Lot)
+	if err != nil {
+		return err
+	}
+
+	return w.TransactionDom.RunInTx(ctx, func(newCtx context.Context) error {
+
+		spots, err := w.ParkingDom.GetAvailableParkingSpot(newCtx, entity.GetAvailableParkingSpot{
+			LotID:    spot.Lot,
+			Floor:    spot.Floor,
+			Row:      spot.Row,
+			Col:      spot.Col,
+			Active:   pkg.BoolPtr(true),
+			Occupied: pkg.BoolPtr(false),
+			Reserved: pkg.BoolPtr(false),
+			UseLock:  true,
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(spots) < 1 {
+			return nil
+		}
+
+		_, err = w.offerNext(newCtx, policy, spots[0])
+		return err
+	})
+}
+
+// ExpireOffers ends the offers nobody claimed in time, passing each spot on
+// to the next vehicle, and returns how many lapsed.
+func (w *waitlist) ExpireOffers(ctx context.Context) (int, error) {
+
+	var lapsed int
+
+	err := w.TransactionDom.RunInTx(ctx, func(newCtx context.Context) error {
+
+		entries, err := w.WaitlistDom.GetEntries(newCtx, entity.GetWaitlist{
+			Statuses:           []string{entity.WaitlistOffered},
+			OfferExpiredBefore: pkg.TimePtr(time.Now()),
+			UseLock:            true,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, v := range entries {
+			if _, err := w.close(newCtx, v, entity.WaitlistLapsed); err != nil {
+				return err
+			}
+		}
+
+		lapsed = len(entries)
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return lapsed, nil
+}
+
+// fill offers the free spots of a lot a vehicle type may take to the queues
+// until the spots or the vehicles waiting for them run out.
+func (w *waitlist) fill(ctx context.Context, policy parkingUc.LotPolicy, lotID uint, vehicleType string) error {
+	for {
+		spots, err := policy.Compatibility.FreeSpots(ctx, w.ParkingDom, lotID, entity.VehicleType(vehicleType))
+		if err != nil {
+			return err
+		}
+
+		spot, ok, err := parkingUc.ClaimSpot(ctx, w.ParkingDom, spots, policy.Allocation.Pick)
+		if err != nil || !ok {
+			return err
+		}
+
+		offered, err := w.offerNext(ctx, policy, spot)
+		if err != nil || !offered {
+			return err
+		}
+	}
+}
+
+// offerNext holds spot for the head of the queue of its type, or of the
+// first smaller type that may fall back to it, and reports whether anybody
+// was waiting.
+func (w *waitlist) offerNext(ctx context.Context, policy parkingUc.LotPolicy, spot entity.ParkingSpot) (bool, error) {
+	var (
+		head []entity.WaitlistEntry
+		err  error
+	)
+
+	for i, vt := range policy.Compatibility.VehicleTypes(spot.Type) {
+		if i == 1 {
+			ok, err := w.canFallback(ctx, policy, spot)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+
+		head, err = w.WaitlistDom.GetEntries(ctx, entity.GetWaitlist{
+			LotID:       spot.LotID,
+			VehicleType: string(vt),
+			Statuses:    []string{entity.WaitlistWaiting},
+			Limit:       1,
+			UseLock:     true,
+			SkipLocked:  true,
+		})
+		if err != nil {
+			return false, err
+		}
+
+		if len(head) > 0 {
+			break
+		}
+	}
+
+	if len(head) < 1 {
+		return false, nil
+	}
+
+	err = w.ParkingDom.UpdateParkingSpot(ctx, entity.UpdateParkingSpot{
+		ID:       spot.ID,
+		Reserved: pkg.BoolPtr(true),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+
+	err = w.WaitlistDom.UpdateEntry(ctx, entity.UpdateWaitlist{
+		ID:             head[0].ID,
+		Status:         entity.WaitlistOffered,
+		ParkingSpotID:  &spot.ID,
+		SpotID:         spot.Position().String(),
+		OfferedAt:      pkg.TimePtr(now),
+		OfferExpiresAt: pkg.TimePtr(now.Add(w.ClaimTimeout)),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// canFallback reports whether the free spots of the lot of the type of spot
+// exceed the reserve kept for vehicles of that type.
+func (w *waitlist) canFallback(ctx context.Context, policy parkingUc.LotPolicy, spot entity.ParkingSpot) (bool, error) {
+	spots, err := w.ParkingDom.GetAvailableParkingSpot(ctx, entity.GetAvailableParkingSpot{
+		LotID:       spot.LotID,
+		VehicleType: entity.VehicleType(spot.Type),
+		Active:      pkg.BoolPtr(true),
+		Occupied:    pkg.BoolPtr(false),
+		Reserved:    pkg.BoolPtr(false),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return policy.Compatibility.CanFallback(spot.Type, len(spots)), nil
+}
+
+// close ends an entry and releases the spot it was offered, if any.
+func (w *waitlist) close(ctx context.Context, entry entity.WaitlistEntry, status string) (entity.WaitlistEntry, error) {
+	now := time.Now()
+
+	err := w.WaitlistDom.UpdateEntry(ctx, entity.UpdateWaitlist{
+		ID:       entry.ID,
+		Status:   status,
+		ClosedAt: pkg.TimePtr(now),
+	})
+	if err != nil {
+		return entry, err
+	}
+
+	wasOffered := entry.Status == entity.WaitlistOffered && entry.ParkingSpotID != nil
+
+	entry.Status = status
+	entry.ClosedAt = &now
+	entry.Position = 0
+
+	if !wasOffered {
+		return entry, nil
+	}
+
+	err = w.ParkingDom.UpdateParkingSpot(ctx, entity.UpdateParkingSpot{
+		ID:       *entry.ParkingSpotID,
+		Reserved: pkg.BoolPtr(false),
+	})
+	if err != nil {
+		return entry, err
+	}
+
+	sp, err := pkg.ParseSpotID(entry.SpotID)
+	if err != nil {
+		return entry, err
+	}
+
+	return entry, w.OfferSpot(ctx, *sp)
+}
@@ -0,0 +1,243 @@
+package waitlist_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	lotDom "github.com/zuhrulumam/go-parking-lot/business/domain/lot"
+	parkingDom "github.com/zuhrulumam/go-parking-lot/business/domain/parking"
+	paymentDom "github.com/zuhrulumam/go-parking-lot/business/domain/payment"
+	tariffDom "github.com/zuhrulumam/go-parking-lot/business/domain/tariff"
+	transactionDom "github.com/zuhrulumam/go-parking-lot/business/domain/transaction"
+	waitlistDom "github.com/zuhrulumam/go-parking-lot/business/domain/waitlist"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	parkingUc "github.com/zuhrulumam/go-parking-lot/business/usecase/parking"
+	uc "github.com/zuhrulumam/go-parking-lot/business/usecase/waitlist"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+// newLot is lot 1 with a single free car spot, so every exit frees it
+// right away, and an empty lot 2.
+func newLot(claimTimeout time.Duration) (parkingUc.UsecaseItf, uc.UsecaseItf) {
+	return newLotWith(claimTimeout, entity.Lot{})
+}
+
+// newLotWith is newLot with the settings of lot 1.
+func newLotWith(claimTimeout time.Duration, lot entity.Lot) (parkingUc.UsecaseItf, uc.UsecaseItf) {
+	mem := memstore.New()
+
+	lot.ID = 1
+	pDom := parkingDom.InitParkingDomain(parkingDom.Option{
+		Memory: mem,
+		Spots:  []entity.ParkingSpot{{LotID: 1, Floor: 1, Row: 1, Col: 1, Type: "A", Active: true}},
+	})
+	lDom := lotDom.InitLotDomain(lotDom.Option{
+		Memory: mem,
+		Lots:   []entity.Lot{lot, {ID: 2, Name: "Annex"}},
+	})
+	wDom := waitlistDom.InitWaitlistDomain(waitlistDom.Option{Memory: mem})
+	txDom := transactionDom.Init(transactionDom.Option{Memory: mem})
+
+	waitlist := uc.InitWaitlistUsecase(uc.Option{
+		ParkingDom:     pDom,
+		LotDom:         lDom,
+		WaitlistDom:    wDom,
+		TransactionDom: txDom,
+		ClaimTimeout:   claimTimeout,
+	})
+
+	parking := parkingUc.InitParkingUsecase(parkingUc.Option{
+		ParkingDom: pDom,
+		LotDom:     lDom,
+		TariffDom: tariffDom.InitTariffDomain(tariffDom.Option{
+			Memory:  mem,
+			Tariffs: []entity.Tariff{{LotID: 1, VehicleType: "A"}},
+		}),
+		PaymentDom:     paymentDom.InitPaymentDomain(paymentDom.Option{Memory: mem}),
+		WaitlistDom:    wDom,
+		TransactionDom: txDom,
+		Waitlist:       waitlist,
+	})
+
+	return parking, waitlist
+}
+
+func car(number string) entity.Park {
+	return entity.Park{LotID: 1, VehicleNumber: number, VehicleType: entity.Automobile}
+}
+
+func TestWaitlistQueue(t *testing.T) {
+	ctx := context.Background()
+	parking, waitlist := newLot(time.Minute)
+
+	ticket, err := parking.Park(ctx, car("B0001XYZ"))
+	assert.NoError(t, err)
+
+	_, err = parking.Park(ctx, car("B0002XYZ"))
+	assert.EqualValues(t, http.StatusConflict, x.ErrCode(err))
+	assert.Equal(t, parkingUc.ErrNoAvailableParking, x.RootCause(err))
+
+	first, err := waitlist.Join(ctx, car("B0002XYZ"))
+	assert.NoError(t, err)
+	assert.Equal(t, entity.WaitlistWaiting, first.Status)
+	assert.Equal(t, 1, first.Position)
+
+	second, err := waitlist.Join(ctx, car("B0003XYZ"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, second.Position)
+
+	_, err = waitlist.Join(ctx, car("B0003XYZ"))
+	assert.EqualValues(t, http.StatusConflict, x.ErrCode(err), "already waiting")
+
+	elsewhere, err := waitlist.Join(ctx, entity.Park{LotID: 2, VehicleNumber: "B0005XYZ", VehicleType: entity.Automobile})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, elsewhere.Position, "each lot has its own queue")
+
+	// the freed spot goes to the head of the queue, not to a walk-in
+	_, err = parking.Unpark(ctx, entity.UnPark{LotID: 1, TicketID: ticket.TicketID})
+	assert.NoError(t, err)
+
+	first, err = waitlist.GetEntry(ctx, entity.GetWaitlist{LotID: 1, Code: first.Code})
+	assert.NoError(t, err)
+	assert.Equal(t, entity.WaitlistOffered, first.Status)
+	assert.Equal(t, "1-1-1-1", first.SpotID)
+	assert.NotNil(t, first.OfferExpiresAt)
+
+	second, err = waitlist.GetEntry(ctx, entity.GetWaitlist{LotID: 1, Code: second.Code})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, second.Position)
+
+	elsewhere, err = waitlist.GetEntry(ctx, entity.GetWaitlist{LotID: 2, Code: elsewhere.Code})
+	assert.NoError(t, err)
+	assert.Equal(t, entity.WaitlistWaiting, elsewhere.Status, "spots stay in their lot")
+
+	_, err = parking.Park(ctx, car("B0004XYZ"))
+	assert.EqualValues(t, http.StatusConflict, x.ErrCode(err))
+
+	_, err = parking.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B0003XYZ", VehicleType: entity.Automobile, WaitlistCode: first.Code})
+	assert.EqualValues(t, http.StatusUnprocessableEntity, x.ErrCode(err), "offer is for another vehicle")
+
+	_, err = parking.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B0003XYZ", VehicleType: entity.Automobile, WaitlistCode: second.Code})
+	assert.EqualValues(t, http.StatusConflict, x.ErrCode(err), "still waiting")
+
+	claimed, err := parking.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B0002XYZ", VehicleType: entity.Automobile, WaitlistCode: first.Code})
+	assert.NoError(t, err)
+	assert.Equal(t, "1-1-1-1", claimed.SpotID)
+
+	first, err = waitlist.GetEntry(ctx, entity.GetWaitlist{LotID: 1, Code: first.Code})
+	assert.NoError(t, err)
+	assert.Equal(t, entity.WaitlistClaimed, first.Status)
+
+	_, err = waitlist.Leave(ctx, entity.GetWaitlist{LotID: 1, Code: first.Code})
+	assert.EqualValues(t, http.StatusConflict, x.ErrCode(err))
+
+	second, err = waitlist.Leave(ctx, entity.GetWaitlist{LotID: 1, Code: second.Code})
+	assert.NoError(t, err)
+	assert.Equal(t, entity.WaitlistLeft, second.Status)
+
+	_, err = waitlist.GetEntry(ctx, entity.GetWaitlist{LotID: 1, Code: "nope"})
+	assert.EqualValues(t, http.StatusNotFound, x.ErrCode(err))
+}
+
+func TestWaitlistJoinWithFreeSpot(t *testing.T) {
+	ctx := context.Background()
+	_, waitlist := newLot(time.Minute)
+
+	entry, err := waitlist.Join(ctx, car("B0001XYZ"))
+	assert.NoError(t, err)
+	assert.Equal(t, entity.WaitlistOffered, entry.Status, "a free spot is offered right away")
+	assert.Equal(t, "1-1-1-1", entry.SpotID)
+
+	_, err = waitlist.Join(ctx, entity.Park{LotID: 1, VehicleNumber: "B0002XYZ", VehicleType: "X"})
+	assert.EqualValues(t, http.StatusBadRequest, x.ErrCode(err))
+}
+
+func TestExpireOffers(t *testing.T) {
+	ctx := context.Background()
+	parking, waitlist := newLot(20 * time.Millisecond)
+
+	ticket, err := parking.Park(ctx, car("B0001XYZ"))
+	assert.NoError(t, err)
+
+	first, err := waitlist.Join(ctx, car("B0002XYZ"))
+	assert.NoError(t, err)
+
+	second, err := waitlist.Join(ctx, car("B0003XYZ"))
+	assert.NoError(t, err)
+
+	_, err = parking.Unpark(ctx, entity.UnPark{LotID: 1, TicketID: ticket.TicketID})
+	assert.NoError(t, err)
+
+	n, err := waitlist.ExpireOffers(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	time.Sleep(30 * time.Millisecond)
+
+	// a late claim is refused even before the sweep runs
+	_, err = parking.Park(ctx, entity.Park{LotID: 1, VehicleNumber: "B0002XYZ", VehicleType: entity.Automobile, WaitlistCode: first.Code})
+	assert.EqualValues(t, http.StatusConflict, x.ErrCode(err))
+
+	n, err = waitlist.ExpireOffers(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	first, err = waitlist.GetEntry(ctx, entity.GetWaitlist{LotID: 1, Code: first.Code})
+	assert.NoError(t, err)
+	assert.Equal(t, entity.WaitlistLapsed, first.Status)
+
+	// the lapsed spot moves on to the next vehicle
+	second, err = waitlist.GetEntry(ctx, entity.GetWaitlist{LotID: 1, Code: second.Code})
+	assert.NoError(t, err)
+	assert.Equal(t, entity.WaitlistOffered, second.Status)
+	assert.Equal(t, "1-1-1-1", second.SpotID)
+
+	// leaving hands the spot back to the lot once nobody else waits
+	_, err = waitlist.Leave(ctx, entity.GetWaitlist{LotID: 1, Code: second.Code})
+	assert.NoError(t, err)
+
+	_, err = parking.Park(ctx, car("B0004XYZ"))
+	assert.NoError(t, err)
+}
+
+func TestWaitlistFallback(t *testing.T) {
+	ctx := context.Background()
+	parking, waitlist := newLotWith(time.Minute, entity.Lot{Fallback: true})
+
+	motorcycle := entity.Park{LotID: 1, VehicleNumber: "B0001ABC", VehicleType: entity.Motorcycle}
+
+	ticket, err := parking.Park(ctx, car("B0001XYZ"))
+	assert.NoError(t, err)
+
+	first, err := waitlist.Join(ctx, motorcycle)
+	assert.NoError(t, err)
+
+	second, err := waitlist.Join(ctx, car("B0002XYZ"))
+	assert.NoError(t, err)
+
+	// cars go first for a car spot, whoever joined earlier
+	_, err = parking.Unpark(ctx, entity.UnPark{LotID: 1, TicketID: ticket.TicketID})
+	assert.NoError(t, err)
+
+	second, err = waitlist.GetEntry(ctx, entity.GetWaitlist{LotID: 1, Code: second.Code})
+	assert.NoError(t, err)
+	assert.Equal(t, entity.WaitlistOffered, second.Status)
+
+	// with no car left waiting the spot falls back to the motorcycle
+	_, err = waitlist.Leave(ctx, entity.GetWaitlist{LotID: 1, Code: second.Code})
+	assert.NoError(t, err)
+
+	first, err = waitlist.GetEntry(ctx, entity.GetWaitlist{LotID: 1, Code: first.Code})
+	assert.NoError(t, err)
+	assert.Equal(t, entity.WaitlistOffered, first.Status)
+
+	motorcycle.WaitlistCode = first.Code
+	claimed, err := parking.Park(ctx, motorcycle)
+	assert.NoError(t, err)
+	assert.Equal(t, "A", claimed.SpotType)
+}
@@ -0,0 +1,49 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+
+	lotDom "github.com/zuhrulumam/go-parking-lot/business/domain/lot"
+	outboxDom "github.com/zuhrulumam/go-parking-lot/business/domain/outbox"
+	webhookDom "github.com/zuhrulumam/go-parking-lot/business/domain/webhook"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+)
+
+// UsecaseItf manages the webhooks subscribed to the domain events and
+// their delivery log. The outbox relay does the sending.
+type UsecaseItf interface {
+	CreateWebhook(ctx context.Context, data entity.SaveWebhook) (entity.Webhook, error)
+	GetWebhooks(ctx context.Context) ([]entity.Webhook, error)
+	GetWebhook(ctx context.Context, id uint) (entity.Webhook, error)
+	UpdateWebhook(ctx context.Context, data entity.SaveWebhook) (entity.Webhook, error)
+	DeleteWebhook(ctx context.Context, id uint) error
+	GetDeliveries(ctx context.Context, data entity.GetWebhookDeliveries) ([]entity.EventDelivery, error)
+	Replay(ctx context.Context, data entity.ReplayDelivery) (entity.EventDelivery, error)
+}
+
+type Option struct {
+	WebhookDom webhookDom.DomainItf
+	OutboxDom  outboxDom.DomainItf
+	LotDom     lotDom.DomainItf
+
+	// HTTPClient posts the replayed deliveries, http.DefaultClient when
+	// nil.
+	HTTPClient *http.Client
+}
+
+type webhook struct {
+	WebhookDom webhookDom.DomainItf
+	OutboxDom  outboxDom.DomainItf
+	LotDom     lotDom.DomainItf
+	HTTPClient *http.Client
+}
+
+func InitWebhookUsecase(opt Option) UsecaseItf {
+	return &webhook{
+		WebhookDom: opt.WebhookDom,
+		OutboxDom:  opt.OutboxDom,
+		LotDom:     opt.LotDom,
+		HTTPClient: opt.HTTPClient,
+	}
+}
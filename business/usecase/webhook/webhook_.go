@@ -0,0 +1,253 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	outboxUc "github.com/zuhrulumam/go-parking-lot/business/usecase/outbox"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+const (
+	// secretSize is the number of random bytes of a generated secret.
+	secretSize = 32
+
+	// replayTimeout bounds a replayed delivery.
+	replayTimeout = 10 * time.Second
+
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 500
+)
+
+// CreateWebhook subscribes an endpoint to the events. Without a secret one
+// is generated; the webhook returned is the only place it is shown.
+func (w *webhook) CreateWebhook(ctx context.Context, data entity.SaveWebhook) (entity.Webhook, error) {
+
+	hook := entity.Webhook{Active: true}
+
+	if data.URL == nil {
+		return hook, x.NewWithCode(http.StatusBadRequest, "url is required")
+	}
+
+	if data.Secret == nil || strings.TrimSpace(*data.Secret) == "" {
+		secret, err := newSecret()
+		if err != nil {
+			return hook, err
+		}
+		data.Secret = &secret
+	}
+
+	if err := w.apply(ctx, &hook, data); err != nil {
+		return hook, err
+	}
+
+	return w.WebhookDom.InsertWebhook(ctx, hook)
+}
+
+func (w *webhook) GetWebhooks(ctx context.Context) ([]entity.Webhook, error) {
+	return w.WebhookDom.GetWebhooks(ctx, entity.GetWebhooks{})
+}
+
+func (w *webhook) GetWebhook(ctx context.Context, id uint) (entity.Webhook, error) {
+	if id < 1 {
+		return entity.Webhook{}, x.NewWithCode(http.StatusNotFound, "webhook not found")
+	}
+
+	hooks, err := w.WebhookDom.GetWebhooks(ctx, entity.GetWebhooks{ID: id})
+	if err != nil {
+		return entity.Webhook{}, err
+	}
+
+	if len(hooks) == 0 {
+		return entity.Webhook{}, x.NewWithCode(http.StatusNotFound, "webhook not found")
+	}
+
+	return hooks[0], nil
+}
+
+// UpdateWebhook changes the fields set. Deactivating a webhook stops the
+// deliveries still pending for it.
+func (w *webhook) UpdateWebhook(ctx context.Context, data entity.SaveWebhook) (entity.Webhook, error) {
+
+	hook, err := w.GetWebhook(ctx, data.ID)
+	if err != nil {
+		return hook, err
+	}
+
+	if data.Secret != nil && strings.TrimSpace(*data.Secret) == "" {
+		return hook, x.NewWithCode(http.StatusBadRequest, "secret can't be empty")
+	}
+
+	if err := w.apply(ctx, &hook, data); err != nil {
+		return hook, err
+	}
+
+	// stored as apply cleaned them up
+	if data.URL != nil {
+		data.URL = &hook.URL
+	}
+	if data.Secret != nil {
+		data.Secret = &hook.Secret
+	}
+
+	if err := w.WebhookDom.UpdateWebhook(ctx, data); err != nil {
+		return hook, err
+	}
+
+	return w.GetWebhook(ctx, data.ID)
+}
+
+func (w *webhook) DeleteWebhook(ctx context.Context, id uint) error {
+	return w.WebhookDom.DeleteWebhook(ctx, id)
+}
+
+// GetDeliveries returns the delivery log of a webhook, newest first.
+func (w *webhook) GetDeliveries(ctx context.Context, data entity.GetWebhookDeliveries) ([]entity.EventDelivery, error) {
+
+	hook, err := w.GetWebhook(ctx, data.WebhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch data.Status {
+	case "", entity.DeliverySucceeded, entity.DeliveryFailed:
+	default:
+		return nil, x.NewWithCode(http.StatusBadRequest, "unknown delivery status")
+	}
+
+	if data.Limit < 1 {
+		data.Limit = defaultDeliveryLimit
+	}
+
+	if data.Limit > maxDeliveryLimit {
+		data.Limit = maxDeliveryLimit
+	}
+
+	return w.OutboxDom.GetDeliveries(ctx, entity.GetEventDeliveries{
+		Sink:   hook.Sink(),
+		Status: data.Status,
+		Limit:  data.Limit,
+	})
+}
+
+// Replay sends the event of a failed delivery to the webhook again, now,
+// whether or not the relay gave up on it. The new attempt is logged and
+// returned; once one succeeds the relay won't send the event again.
+func (w *webhook) Replay(ctx context.Context, data entity.ReplayDelivery) (entity.EventDelivery, error) {
+
+	var result entity.EventDelivery
+
+	hook, err := w.GetWebhook(ctx, data.WebhookID)
+	if err != nil {
+		return result, err
+	}
+
+	deliveries, err := w.OutboxDom.GetDeliveries(ctx, entity.GetEventDeliveries{IDs: []uint{data.DeliveryID}})
+	if err != nil {
+		return result, err
+	}
+
+	if len(deliveries) == 0 || deliveries[0].Sink != hook.Sink() {
+		return result, x.NewWithCode(http.StatusNotFound, "delivery not found")
+	}
+
+	failed := deliveries[0]
+	if failed.Status != entity.DeliveryFailed {
+		return result, x.NewWithCode(http.StatusConflict, "only failed deliveries can be replayed")
+	}
+
+	events, err := w.OutboxDom.GetEvents(ctx, entity.GetOutboxEvents{IDs: []uint{failed.EventID}})
+	if err != nil {
+		return result, err
+	}
+
+	if len(events) == 0 {
+		return result, x.NewWithCode(http.StatusNotFound, "event not found")
+	}
+
+	// the attempt after the latest one to this webhook
+	latest, err := w.OutboxDom.GetDeliveries(ctx, entity.GetEventDeliveries{
+		EventIDs: []uint{failed.EventID},
+		Sink:     hook.Sink(),
+		Limit:    1,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result = entity.EventDelivery{
+		EventID: failed.EventID,
+		Sink:    hook.Sink(),
+		Attempt: latest[0].Attempt + 1,
+		Status:  entity.DeliverySucceeded,
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, replayTimeout)
+	err = outboxUc.NewSubscriptionSink(hook, w.HTTPClient).Send(sendCtx, events[0], result.Attempt)
+	cancel()
+
+	if err != nil {
+		result.Status = entity.DeliveryFailed
+		result.Error = err.Error()
+	}
+
+	return w.OutboxDom.InsertDelivery(ctx, result)
+}
+
+// apply checks the fields set in data and copies them to hook.
+func (w *webhook) apply(ctx context.Context, hook *entity.Webhook, data entity.SaveWebhook) error {
+
+	if data.URL != nil {
+		u, err := url.Parse(strings.TrimSpace(*data.URL))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return x.NewWithCode(http.StatusBadRequest, "url must be an absolute http or https url")
+		}
+		hook.URL = u.String()
+	}
+
+	if data.EventTypes != nil {
+		for _, t := range *data.EventTypes {
+			if !slices.Contains(entity.EventTypes, t) {
+				return x.NewWithCode(http.StatusBadRequest, "unknown event type "+t)
+			}
+		}
+		hook.EventTypes = *data.EventTypes
+	}
+
+	if data.LotID != nil {
+		if *data.LotID > 0 {
+			if _, err := w.LotDom.GetLot(ctx, entity.GetLot{ID: *data.LotID}); err != nil {
+				return err
+			}
+		}
+		hook.LotID = *data.LotID
+	}
+
+	if data.Secret != nil {
+		hook.Secret = strings.TrimSpace(*data.Secret)
+	}
+
+	if data.Active != nil {
+		hook.Active = *data.Active
+	}
+
+	return nil
+}
+
+// newSecret returns a random hex secret.
+func newSecret() (string, error) {
+	b := make([]byte, secretSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,243 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	lotDom "github.com/zuhrulumam/go-parking-lot/business/domain/lot"
+	outboxDom "github.com/zuhrulumam/go-parking-lot/business/domain/outbox"
+	transactionDom "github.com/zuhrulumam/go-parking-lot/business/domain/transaction"
+	webhookDom "github.com/zuhrulumam/go-parking-lot/business/domain/webhook"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	outboxUc "github.com/zuhrulumam/go-parking-lot/business/usecase/outbox"
+	uc "github.com/zuhrulumam/go-parking-lot/business/usecase/webhook"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"github.com/zuhrulumam/go-parking-lot/pkg/memstore"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+// receiver is a webhook endpoint checking the signatures with secret.
+type receiver struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	secret string
+	status int
+	events []entity.OutboxEvent
+
+	// attempts are the X-Delivery-Attempt headers received
+	attempts []string
+}
+
+func newReceiver(t *testing.T) *receiver {
+	r := &receiver{status: http.StatusOK}
+
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		sig := req.Header.Get("X-Signature")
+		ts, _ := strconv.ParseInt(strings.TrimPrefix(strings.Split(sig, ",")[0], "t="), 10, 64)
+		if sig == "" || outboxUc.Sign(r.secret, time.Unix(ts, 0), body) != sig {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		r.attempts = append(r.attempts, req.Header.Get("X-Delivery-Attempt"))
+		if r.status == http.StatusOK {
+			var e entity.OutboxEvent
+			_ = json.Unmarshal(body, &e)
+			r.events = append(r.events, e)
+		}
+
+		w.WriteHeader(r.status)
+	}))
+	t.Cleanup(r.Close)
+
+	return r
+}
+
+func (r *receiver) set(secret string, status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.secret, r.status = secret, status
+}
+
+func (r *receiver) received() ([]entity.OutboxEvent, []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]entity.OutboxEvent(nil), r.events...), append([]string(nil), r.attempts...)
+}
+
+type fixture struct {
+	outbox  outboxDom.DomainItf
+	webhook uc.UsecaseItf
+	relay   outboxUc.UsecaseItf
+}
+
+func newFixture(recv *receiver) fixture {
+	mem := memstore.New()
+
+	oDom := outboxDom.InitOutboxDomain(outboxDom.Option{Memory: mem})
+	wDom := webhookDom.InitWebhookDomain(webhookDom.Option{Memory: mem})
+
+	return fixture{
+		outbox: oDom,
+		webhook: uc.InitWebhookUsecase(uc.Option{
+			WebhookDom: wDom,
+			OutboxDom:  oDom,
+			LotDom:     lotDom.InitLotDomain(lotDom.Option{Memory: mem, Lots: []entity.Lot{{ID: 1, Name: "Main"}, {ID: 2, Name: "Annex"}}}),
+			HTTPClient: recv.Client(),
+		}),
+		relay: outboxUc.InitOutboxUsecase(outboxUc.Option{
+			OutboxDom:      oDom,
+			TransactionDom: transactionDom.Init(transactionDom.Option{Memory: mem}),
+			WebhookDom:     wDom,
+			HTTPClient:     recv.Client(),
+			Sinks:          []outboxUc.Sink{outboxUc.NewMemorySink("memory")},
+			Backoff:        time.Millisecond,
+			MaxAttempts:    2,
+		}),
+	}
+}
+
+func TestWebhookSubscriptions(t *testing.T) {
+	ctx := context.Background()
+	recv := newReceiver(t)
+	f := newFixture(recv)
+
+	for name, data := range map[string]entity.SaveWebhook{
+		"no url":      {},
+		"not http":    {URL: pkg.StringPtr("ftp://billing.local/events")},
+		"relative":    {URL: pkg.StringPtr("/events")},
+		"bad type":    {URL: pkg.StringPtr(recv.URL), EventTypes: &[]string{"vehicle.towed"}},
+		"no such lot": {URL: pkg.StringPtr(recv.URL), LotID: pkg.UintPtr(9)},
+	} {
+		_, err := f.webhook.CreateWebhook(ctx, data)
+		assert.Error(t, err, name)
+	}
+
+	hook, err := f.webhook.CreateWebhook(ctx, entity.SaveWebhook{
+		URL:        pkg.StringPtr(recv.URL),
+		LotID:      pkg.UintPtr(1),
+		EventTypes: &[]string{entity.EventVehicleParked},
+	})
+	assert.NoError(t, err)
+	assert.True(t, hook.Active)
+	assert.Len(t, hook.Secret, 64, "generated")
+	recv.set(hook.Secret, http.StatusOK)
+
+	// only the events of lot 1 it subscribed to
+	for _, e := range []entity.OutboxEvent{
+		entity.NewOutboxEvent(1, entity.EventVehicleParked, entity.Ticket{TicketID: "t1"}),
+		entity.NewOutboxEvent(1, entity.EventVehicleUnparked, entity.Ticket{TicketID: "t1"}),
+		entity.NewOutboxEvent(2, entity.EventVehicleParked, entity.Ticket{TicketID: "t2"}),
+	} {
+		_, err := f.outbox.InsertEvent(ctx, e)
+		assert.NoError(t, err)
+	}
+
+	n, err := f.relay.Relay(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	events, attempts := recv.received()
+	assert.Len(t, events, 1)
+	assert.Equal(t, entity.EventVehicleParked, events[0].Type)
+	assert.Equal(t, uint(1), events[0].LotID)
+	assert.Equal(t, []string{"1"}, attempts)
+
+	deliveries, err := f.webhook.GetDeliveries(ctx, entity.GetWebhookDeliveries{WebhookID: hook.ID})
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+	assert.Equal(t, hook.Sink(), deliveries[0].Sink)
+
+	// paused webhooks are sent nothing
+	_, err = f.webhook.UpdateWebhook(ctx, entity.SaveWebhook{ID: hook.ID, Active: pkg.BoolPtr(false)})
+	assert.NoError(t, err)
+
+	_, err = f.outbox.InsertEvent(ctx, entity.NewOutboxEvent(1, entity.EventVehicleParked, entity.Ticket{TicketID: "t3"}))
+	assert.NoError(t, err)
+
+	_, err = f.relay.Relay(ctx)
+	assert.NoError(t, err)
+
+	events, _ = recv.received()
+	assert.Len(t, events, 1)
+
+	_, err = f.webhook.UpdateWebhook(ctx, entity.SaveWebhook{ID: hook.ID, URL: pkg.StringPtr("nope")})
+	assert.EqualValues(t, http.StatusBadRequest, x.ErrCode(err))
+
+	assert.NoError(t, f.webhook.DeleteWebhook(ctx, hook.ID))
+	_, err = f.webhook.GetWebhook(ctx, hook.ID)
+	assert.EqualValues(t, http.StatusNotFound, x.ErrCode(err))
+}
+
+func TestWebhookReplay(t *testing.T) {
+	ctx := context.Background()
+	recv := newReceiver(t)
+	f := newFixture(recv)
+
+	hook, err := f.webhook.CreateWebhook(ctx, entity.SaveWebhook{URL: pkg.StringPtr(recv.URL), Secret: pkg.StringPtr("s3cret")})
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cret", hook.Secret)
+	recv.set("s3cret", http.StatusServiceUnavailable)
+
+	event, err := f.outbox.InsertEvent(ctx, entity.NewOutboxEvent(1, entity.EventSpotDeactivated, entity.ParkingSpot{ID: 1}))
+	assert.NoError(t, err)
+
+	// the relay gives up after two attempts
+	for i := 0; i < 3; i++ {
+		_, err := f.relay.Relay(ctx)
+		assert.NoError(t, err)
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	_, attempts := recv.received()
+	assert.Equal(t, []string{"1", "2"}, attempts)
+
+	events, err := f.outbox.GetEvents(ctx, entity.GetOutboxEvents{IDs: []uint{event.ID}})
+	assert.NoError(t, err)
+	assert.NotNil(t, events[0].FailedAt)
+
+	failed, err := f.webhook.GetDeliveries(ctx, entity.GetWebhookDeliveries{WebhookID: hook.ID, Status: entity.DeliveryFailed})
+	assert.NoError(t, err)
+	assert.Len(t, failed, 2)
+	assert.Equal(t, "webhook answered 503", failed[0].Error)
+
+	_, err = f.webhook.GetDeliveries(ctx, entity.GetWebhookDeliveries{WebhookID: hook.ID, Status: "lost"})
+	assert.EqualValues(t, http.StatusBadRequest, x.ErrCode(err))
+
+	// the receiver is back
+	recv.set("s3cret", http.StatusOK)
+
+	replayed, err := f.webhook.Replay(ctx, entity.ReplayDelivery{WebhookID: hook.ID, DeliveryID: failed[1].ID})
+	assert.NoError(t, err)
+	assert.Equal(t, entity.DeliverySucceeded, replayed.Status)
+	assert.Equal(t, 3, replayed.Attempt)
+
+	got, attempts := recv.received()
+	assert.Equal(t, []string{"1", "2", "3"}, attempts)
+	assert.Equal(t, event.ID, got[0].ID)
+
+	_, err = f.webhook.Replay(ctx, entity.ReplayDelivery{WebhookID: hook.ID, DeliveryID: replayed.ID})
+	assert.EqualValues(t, http.StatusConflict, x.ErrCode(err), "succeeded deliveries are not replayed")
+
+	// deliveries of the other sinks are not the webhook's
+	other, err := f.outbox.GetDeliveries(ctx, entity.GetEventDeliveries{Sink: "memory"})
+	assert.NoError(t, err)
+	_, err = f.webhook.Replay(ctx, entity.ReplayDelivery{WebhookID: hook.ID, DeliveryID: other[0].ID})
+	assert.EqualValues(t, http.StatusNotFound, x.ErrCode(err))
+}
@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	authDom "github.com/zuhrulumam/go-parking-lot/business/domain/auth"
+	lotDom "github.com/zuhrulumam/go-parking-lot/business/domain/lot"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	authUc "github.com/zuhrulumam/go-parking-lot/business/usecase/auth"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+)
+
+var (
+	keyName  string
+	keyRole  string
+	keyLotID uint
+	userRole string
+)
+
+var apiKeyCommand = &cobra.Command{
+	Use:   "apikey",
+	Short: "manage the API keys of gate devices and services",
+}
+
+var apiKeyCreateCommand = &cobra.Command{
+	Use:   "create",
+	Short: "create an API key, printed this once only",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		key, plain, err := newAuth().CreateAPIKey(context.Background(), entity.CreateAPIKey{
+			Name:  keyName,
+			Role:  keyRole,
+			LotID: keyLotID,
+		})
+		if err != nil {
+			log.Fatalf("failed to create api key: %v", err)
+		}
+
+		log.Printf("created api key %d %q (%s)", key.ID, key.Name, key.Role)
+		fmt.Println(plain)
+	},
+}
+
+var apiKeyListCommand = &cobra.Command{
+	Use:   "list",
+	Short: "list the API keys",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		keys, err := newAuth().GetAPIKeys(context.Background())
+		if err != nil {
+			log.Fatalf("failed to list api keys: %v", err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tPREFIX\tROLE\tLOT\tLAST USED\tREVOKED AT")
+		for _, k := range keys {
+			lot := "all"
+			if k.LotID > 0 {
+				lot = strconv.FormatUint(uint64(k.LotID), 10)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", k.ID, k.Name, k.Prefix, k.Role, lot, formatTime(k.LastUsedAt, "never"), formatTime(k.RevokedAt, "-"))
+		}
+		_ = w.Flush()
+	},
+}
+
+var apiKeyRevokeCommand = &cobra.Command{
+	Use:   "revoke [id]",
+	Short: "revoke an API key",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		id, err := strconv.ParseUint(args[0], 10, 0)
+		if err != nil {
+			log.Fatalf("invalid id %q", args[0])
+		}
+
+		if err := newAuth().RevokeAPIKey(context.Background(), uint(id)); err != nil {
+			log.Fatalf("failed to revoke api key: %v", err)
+		}
+
+		log.Printf("revoked api key %d", id)
+	},
+}
+
+var userCommand = &cobra.Command{
+	Use:   "user",
+	Short: "manage the staff users logging in for tokens",
+}
+
+var userAddCommand = &cobra.Command{
+	Use:   "add [username]",
+	Short: "add a user, the password is read from stdin",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		password := readPassword()
+
+		user, err := newAuth().CreateUser(context.Background(), entity.SaveUser{
+			Username: args[0],
+			Password: &password,
+			Role:     &userRole,
+		})
+		if err != nil {
+			log.Fatalf("failed to add user: %v", err)
+		}
+
+		log.Printf("added user %d %q (%s)", user.ID, user.Username, user.Role)
+	},
+}
+
+var userListCommand = &cobra.Command{
+	Use:   "list",
+	Short: "list the users",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		users, err := newAuth().GetUsers(context.Background())
+		if err != nil {
+			log.Fatalf("failed to list users: %v", err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tUSERNAME\tROLE\tACTIVE\tUPDATED AT")
+		for _, u := range users {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%t\t%s\n", u.ID, u.Username, u.Role, u.Active, formatTime(&u.UpdatedAt, ""))
+		}
+		_ = w.Flush()
+	},
+}
+
+var userRoleCommand = &cobra.Command{
+	Use:   "role [username] [role]",
+	Short: "change the role of a user",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		updateUser(entity.SaveUser{Username: args[0], Role: &args[1]})
+	},
+}
+
+var userPasswordCommand = &cobra.Command{
+	Use:   "passwd [username]",
+	Short: "change the password of a user, read from stdin",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		password := readPassword()
+		updateUser(entity.SaveUser{Username: args[0], Password: &password})
+	},
+}
+
+var userDisableCommand = &cobra.Command{
+	Use:   "disable [username]",
+	Short: "disable a user, its tokens stop working",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		updateUser(entity.SaveUser{Username: args[0], Active: pkg.BoolPtr(false)})
+	},
+}
+
+var userEnableCommand = &cobra.Command{
+	Use:   "enable [username]",
+	Short: "enable a disabled user",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		updateUser(entity.SaveUser{Username: args[0], Active: pkg.BoolPtr(true)})
+	},
+}
+
+var userTokenCommand = &cobra.Command{
+	Use:   "token [username]",
+	Short: "issue a token of a user without its password",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		token, err := newAuth().IssueToken(context.Background(), args[0])
+		if err != nil {
+			log.Fatalf("failed to issue token: %v", err)
+		}
+
+		log.Printf("token of %q expires at %s", args[0], token.ExpiresAt.Format(time.RFC3339))
+		fmt.Println(token.Token)
+	},
+}
+
+func init() {
+	apiKeyCreateCommand.Flags().StringVar(&keyName, "name", "", "who the key is for, e.g. gate-north-1")
+	apiKeyCreateCommand.Flags().StringVar(&keyRole, "role", entity.RoleGate, "role: "+strings.Join(entity.Roles, ", "))
+	apiKeyCreateCommand.Flags().UintVar(&keyLotID, "lot", 0, "the only lot the key reaches, every lot when 0")
+	_ = apiKeyCreateCommand.MarkFlagRequired("name")
+
+	apiKeyCommand.AddCommand(apiKeyCreateCommand)
+	apiKeyCommand.AddCommand(apiKeyListCommand)
+	apiKeyCommand.AddCommand(apiKeyRevokeCommand)
+
+	userAddCommand.Flags().StringVar(&userRole, "role", entity.RoleAttendant, "role: "+strings.Join(entity.Roles, ", "))
+
+	userCommand.AddCommand(userAddCommand)
+	userCommand.AddCommand(userListCommand)
+	userCommand.AddCommand(userRoleCommand)
+	userCommand.AddCommand(userPasswordCommand)
+	userCommand.AddCommand(userDisableCommand)
+	userCommand.AddCommand(userEnableCommand)
+	userCommand.AddCommand(userTokenCommand)
+}
+
+// newAuth is the auth usecase on the database, signing with the key of
+// the server.
+func newAuth() authUc.UsecaseItf {
+	db := mustConnectDB()
+
+	signer, err := tokenSigner()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ttl, err := durationEnv("AUTH_JWT_TTL", 12*time.Hour)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return authUc.InitAuthUsecase(authUc.Option{
+		AuthDom:  authDom.InitAuthDomain(authDom.Option{DB: db}),
+		LotDom:   lotDom.InitLotDomain(lotDom.Option{DB: db}),
+		Signer:   signer,
+		TokenTTL: ttl,
+	})
+}
+
+// tokenSigner reads the AUTH_JWT_KEY_FILE key of AUTH_JWT_ALG, nil without
+// a key file: tokens are refused then.
+func tokenSigner() (*authUc.Signer, error) {
+	file := os.Getenv("AUTH_JWT_KEY_FILE")
+	if file == "" {
+		return nil, nil
+	}
+
+	return authUc.NewSigner(os.Getenv("AUTH_JWT_ALG"), file)
+}
+
+func updateUser(data entity.SaveUser) {
+	user, err := newAuth().UpdateUser(context.Background(), data)
+	if err != nil {
+		log.Fatalf("failed to update user: %v", err)
+	}
+
+	log.Printf("updated user %d %q (%s, active %t)", user.ID, user.Username, user.Role, user.Active)
+}
+
+// readPassword reads the first line of stdin, so scripts can pipe it in.
+func readPassword() string {
+	fmt.Fprint(os.Stderr, "Password: ")
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		log.Fatalf("failed to read password: %v", err)
+	}
+
+	return strings.TrimRight(line, "\r\n")
+}
+
+func formatTime(t *time.Time, empty string) string {
+	if t == nil {
+		return empty
+	}
+
+	return t.Format("2006-01-02 15:04:05")
+}
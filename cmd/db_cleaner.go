@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"log"
 
 	"github.com/spf13/cobra"
@@ -20,8 +21,10 @@ var cleanerCommand = &cobra.Command{
 
 func clean(db *gorm.DB) {
 
-	// migrate db
-	if err := db.Migrator().DropTable(&ParkingSpot{}, &Vehicle{}); err != nil {
-		log.Fatalf("failed to migrate tables: %v", err)
+	// revert every migration
+	done, err := newMigrator(db).To(context.Background(), 0)
+	printMigrations("reverted", done)
+	if err != nil {
+		log.Fatalf("failed to drop tables: %v", err)
 	}
 }
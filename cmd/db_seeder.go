@@ -1,41 +1,43 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"math/rand"
 	"os"
-	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-type ParkingSpot struct {
-	ID       uint `gorm:"primaryKey"`
-	Floor    int
-	Row      int
-	Col      int
-	Type     string `gorm:"size:1"` // 'B', 'M', 'A', 'X'
-	Active   bool
-	Occupied bool
-}
-
-type Vehicle struct {
-	ID            uint `gorm:"primaryKey"`
-	VehicleNumber string
-	VehicleType   string `gorm:"size:1"` // 'B', 'M', 'A'
-	SpotID        string
-	ParkedAt      time.Time
-	UnparkedAt    *time.Time
-}
+var (
+	lotFlag    uint
+	layoutFlag string
+	pruneFlag  bool
+	dryRunFlag bool
+)
 
 var seedCommand = &cobra.Command{
-	Use:  "seed [floors] [rows] [cols]",
-	Args: cobra.ExactArgs(3),
+	Use:   "seed [floors] [rows] [cols] | seed --layout lot.yaml",
+	Short: "create the spots of a lot (--lot, default 1), from a layout file or with random types",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if layoutFlag != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(3)(cmd, args)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
+		if layoutFlag != "" {
+			db := mustConnectDB()
+			migrateForSeed(db)
+			seedLayout(db, lotFlag, layoutFlag, pruneFlag, dryRunFlag)
+			return
+		}
+
 		var floors, rows, cols int
 		_, _ = fmt.Sscanf(args[0], "%d", &floors)
 		_, _ = fmt.Sscanf(args[1], "%d", &rows)
@@ -43,33 +45,40 @@ var seedCommand = &cobra.Command{
 
 		db, _ := connectDB()
 
-		seed(db, floors, rows, cols)
+		seed(db, lotFlag, floors, rows, cols)
 	},
 }
 
-func seed(db *gorm.DB, floors, rows, cols int) {
-	// migrate db
-	if err := db.AutoMigrate(&ParkingSpot{}, &Vehicle{}); err != nil {
-		log.Fatalf("failed to migrate tables: %v", err)
-	}
+func init() {
+	seedCommand.Flags().UintVar(&lotFlag, "lot", 1, "lot to seed")
+	seedCommand.Flags().StringVar(&layoutFlag, "layout", "", "YAML or JSON layout file to make the lot match")
+	seedCommand.Flags().BoolVar(&pruneFlag, "prune", false, "with --layout, delete spots missing from the layout")
+	seedCommand.Flags().BoolVar(&dryRunFlag, "dry-run", false, "with --layout, only show the diff")
+}
 
-	// add constraint
-	err := db.Exec(`
-		CREATE UNIQUE INDEX IF NOT EXISTS unique_active_spot 
-		ON vehicles(spot_id) 
-		WHERE unparked_at IS NULL
-	`).Error
+func seed(db *gorm.DB, lotID uint, floors, rows, cols int) {
+	migrateForSeed(db)
+
+	db.CreateInBatches(generateSpots(lotID, floors, rows, cols), 1000)
+}
+
+func migrateForSeed(db *gorm.DB) {
+	done, err := newMigrator(db).Up(context.Background())
+	printMigrations("applied", done)
 	if err != nil {
-		log.Fatalf("failed to add index table: %v", err)
+		log.Fatalf("failed to migrate tables: %v", err)
 	}
+}
 
-	var spots []ParkingSpot
+func generateSpots(lotID uint, floors, rows, cols int) []entity.ParkingSpot {
+	var spots []entity.ParkingSpot
 
 	for f := 1; f <= floors; f++ {
 		for r := 1; r <= rows; r++ {
 			for c := 1; c <= cols; c++ {
 				t := randomType()
-				spot := ParkingSpot{
+				spot := entity.ParkingSpot{
+					LotID:  lotID,
 					Floor:  f,
 					Row:    r,
 					Col:    c,
@@ -81,7 +90,7 @@ func seed(db *gorm.DB, floors, rows, cols int) {
 		}
 	}
 
-	db.CreateInBatches(spots, 1000)
+	return spots
 }
 
 func connectDB() (*gorm.DB, error) {
@@ -103,6 +112,20 @@ func connectDB() (*gorm.DB, error) {
 	return db, nil
 }
 
+// defaultLot mirrors the lot inserted by migration 0009.
+func defaultLot() entity.Lot {
+	return entity.Lot{ID: 1, Name: "Main"}
+}
+
+// defaultTariffs mirrors the rows inserted by migration 0003, for lot 1.
+func defaultTariffs() []entity.Tariff {
+	return []entity.Tariff{
+		{LotID: 1, VehicleType: "B", FirstHourPrice: 1000, HourlyPrice: 500, DailyCap: 5000, GracePeriodMinutes: 10, NightStartHour: 22, NightEndHour: 6},
+		{LotID: 1, VehicleType: "M", FirstHourPrice: 2000, HourlyPrice: 1000, DailyCap: 15000, GracePeriodMinutes: 10, NightHourlyPrice: 500, NightStartHour: 22, NightEndHour: 6, WeekendHourlyPrice: 1500},
+		{LotID: 1, VehicleType: "A", FirstHourPrice: 5000, HourlyPrice: 3000, DailyCap: 40000, GracePeriodMinutes: 10, NightHourlyPrice: 2000, NightStartHour: 22, NightEndHour: 6, WeekendHourlyPrice: 4000},
+	}
+}
+
 func randomType() string {
 	types := []string{"B", "M", "A", "X"}
 	return types[rand.Intn(len(types))]
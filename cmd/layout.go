@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zuhrulumam/go-parking-lot/business/domain"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/business/usecase"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+var exportLayoutCommand = &cobra.Command{
+	Use:   "export-layout [file]",
+	Short: "write a lot (--lot, default 1) as a layout file, YAML unless the file ends in .json",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := "-"
+		if len(args) == 1 {
+			path = args[0]
+		}
+
+		layout, err := layoutUsecase(mustConnectDB()).Admin.ExportLayout(context.Background(), exportLotFlag)
+		if err != nil {
+			log.Fatalf("failed to export layout: %v", err)
+		}
+
+		if err := writeLayout(path, layout); err != nil {
+			log.Fatalf("failed to write layout: %v", err)
+		}
+	},
+}
+
+var exportLotFlag uint
+
+func init() {
+	exportLayoutCommand.Flags().UintVar(&exportLotFlag, "lot", 1, "lot to export")
+}
+
+// seedLayout makes a lot match the layout file and prints what changed.
+func seedLayout(db *gorm.DB, lotID uint, path string, prune, dryRun bool) {
+	layout, err := readLayout(path)
+	if err != nil {
+		log.Fatalf("failed to read layout: %v", err)
+	}
+
+	diff, err := layoutUsecase(db).Admin.ApplyLayout(context.Background(), entity.ApplyLayout{
+		LotID:  lotID,
+		Actor:  "seed",
+		Layout: layout,
+		Prune:  prune,
+		DryRun: dryRun,
+	})
+	if err != nil {
+		log.Fatalf("failed to apply layout: %v", err)
+	}
+
+	printLayoutDiff(os.Stdout, diff)
+
+	switch {
+	case diff.Empty():
+		fmt.Println("lot already matches the layout")
+	case dryRun:
+		fmt.Println("dry run, nothing changed")
+	}
+}
+
+func layoutUsecase(db *gorm.DB) *usecase.Usecase {
+	return usecase.Init(domain.Init(domain.Option{
+		Store: domain.StorePostgres,
+		DB:    db,
+	}), usecase.Option{})
+}
+
+// readLayout parses a YAML or JSON layout file, JSON being valid YAML.
+// Unknown keys are refused so a typo doesn't silently drop a setting.
+func readLayout(path string) (entity.Layout, error) {
+	var layout entity.Layout
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return layout, err
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+
+	if err := dec.Decode(&layout); err != nil {
+		return layout, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return layout, nil
+}
+
+// writeLayout writes to stdout when path is "-".
+func writeLayout(path string, layout entity.Layout) error {
+	var buf bytes.Buffer
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(layout); err != nil {
+			return err
+		}
+	} else {
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(layout); err != nil {
+			return err
+		}
+		if err := enc.Close(); err != nil {
+			return err
+		}
+	}
+
+	if path == "-" {
+		_, err := os.Stdout.Write(buf.Bytes())
+		return err
+	}
+
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+func printLayoutDiff(w io.Writer, diff entity.LayoutDiff) {
+	for _, s := range diff.Create {
+		fmt.Fprintf(w, "+ %s %s\n", s.Position(), describeSpot(s))
+	}
+
+	for _, c := range diff.Update {
+		fmt.Fprintf(w, "~ %s %s -> %s\n", c.SpotID, describeSpot(c.Before), describeSpot(c.After))
+	}
+
+	for _, s := range diff.Delete {
+		fmt.Fprintf(w, "- %s %s\n", s.Position(), describeSpot(s))
+	}
+
+	fmt.Fprintf(w, "%d to create, %d to update, %d to delete", len(diff.Create), len(diff.Update), len(diff.Delete))
+	if len(diff.Unmanaged) > 0 {
+		fmt.Fprintf(w, ", %d not in the layout kept (use --prune to delete)", len(diff.Unmanaged))
+	}
+	fmt.Fprintln(w)
+}
+
+func describeSpot(s entity.ParkingSpot) string {
+	d := s.Type
+	if !s.Active {
+		d += " inactive"
+	}
+	if s.EVCharger {
+		d += " ev"
+	}
+	if s.Accessible {
+		d += " accessible"
+	}
+
+	return d
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/zuhrulumam/go-parking-lot/migrations"
+	"github.com/zuhrulumam/go-parking-lot/pkg/migration"
+	"gorm.io/gorm"
+)
+
+var migrateCommand = &cobra.Command{
+	Use:   "migrate",
+	Short: "manage database schema migrations",
+}
+
+var migrateUpCommand = &cobra.Command{
+	Use:   "up",
+	Short: "apply all pending migrations",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		done, err := newMigrator(mustConnectDB()).Up(context.Background())
+		printMigrations("applied", done)
+		if err != nil {
+			log.Fatalf("failed to migrate up: %v", err)
+		}
+	},
+}
+
+var migrateDownCommand = &cobra.Command{
+	Use:   "down [steps]",
+	Short: "revert the last applied migrations (default 1)",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		steps := 1
+		if len(args) == 1 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 1 {
+				log.Fatalf("invalid steps %q", args[0])
+			}
+			steps = n
+		}
+
+		done, err := newMigrator(mustConnectDB()).Down(context.Background(), steps)
+		printMigrations("reverted", done)
+		if err != nil {
+			log.Fatalf("failed to migrate down: %v", err)
+		}
+	},
+}
+
+var migrateToCommand = &cobra.Command{
+	Use:   "to [version]",
+	Short: "migrate up or down to the given version (0 reverts everything)",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		version, err := strconv.Atoi(args[0])
+		if err != nil || version < 0 {
+			log.Fatalf("invalid version %q", args[0])
+		}
+
+		done, err := newMigrator(mustConnectDB()).To(context.Background(), version)
+		printMigrations("migrated", done)
+		if err != nil {
+			log.Fatalf("failed to migrate to %d: %v", version, err)
+		}
+	},
+}
+
+var migrateStatusCommand = &cobra.Command{
+	Use:   "status",
+	Short: "show applied and pending migrations",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		status, err := newMigrator(mustConnectDB()).Status(context.Background())
+		if err != nil {
+			log.Fatalf("failed to read migration status: %v", err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range status {
+			appliedAt := "pending"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+		_ = w.Flush()
+	},
+}
+
+func init() {
+	migrateCommand.AddCommand(migrateUpCommand)
+	migrateCommand.AddCommand(migrateDownCommand)
+	migrateCommand.AddCommand(migrateToCommand)
+	migrateCommand.AddCommand(migrateStatusCommand)
+}
+
+func newMigrator(db *gorm.DB) *migration.Migrator {
+	return migration.New(migration.Option{
+		DB:         db,
+		Migrations: migrations.All(),
+	})
+}
+
+func mustConnectDB() *gorm.DB {
+	db, err := connectDB()
+	if err != nil {
+		log.Fatalf("failed to connect db: %v", err)
+	}
+
+	return db
+}
+
+func printMigrations(verb string, done []migration.Migration) {
+	for _, m := range done {
+		log.Printf("%s %d_%s", verb, m.Version, m.Name)
+	}
+}
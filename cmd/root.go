@@ -20,6 +20,11 @@ func init() {
 	rootCmd.AddCommand(serverCommand)
 	rootCmd.AddCommand(seedCommand)
 	rootCmd.AddCommand(cleanerCommand)
+	rootCmd.AddCommand(migrateCommand)
+	rootCmd.AddCommand(exportLayoutCommand)
+	rootCmd.AddCommand(apiKeyCommand)
+	rootCmd.AddCommand(userCommand)
+	rootCmd.AddCommand(sessionsCommand)
 }
 
 func Execute() {
@@ -1,16 +1,35 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	fiber "github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/cobra"
 	"github.com/zuhrulumam/go-parking-lot/business/domain"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
 	"github.com/zuhrulumam/go-parking-lot/business/usecase"
+	"github.com/zuhrulumam/go-parking-lot/business/usecase/outbox"
+	"github.com/zuhrulumam/go-parking-lot/business/usecase/parking"
 	"github.com/zuhrulumam/go-parking-lot/handler"
+	"github.com/zuhrulumam/go-parking-lot/handler/rpc"
+	"github.com/zuhrulumam/go-parking-lot/pkg/health"
 	"github.com/zuhrulumam/go-parking-lot/pkg/logger"
+	"github.com/zuhrulumam/go-parking-lot/pkg/metrics"
 	"github.com/zuhrulumam/go-parking-lot/pkg/middlewares"
+	"github.com/zuhrulumam/go-parking-lot/pkg/tracing"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 	"gorm.io/gorm"
 )
 
@@ -22,6 +41,24 @@ var serverCommand = &cobra.Command{
 	},
 }
 
+var (
+	storeFlag   string
+	addrFlag    string
+	memoryFloor int
+	memoryRow   int
+	memoryCol   int
+	grpcPort    int
+)
+
+func init() {
+	serverCommand.Flags().StringVar(&storeFlag, "store", domain.StorePostgres, "storage backend: postgres or memory")
+	serverCommand.Flags().StringVar(&addrFlag, "addr", ":8080", "address the REST API listens on, host:port")
+	serverCommand.Flags().IntVar(&memoryFloor, "floors", 5, "floors to seed when --store=memory")
+	serverCommand.Flags().IntVar(&memoryRow, "rows", 20, "rows to seed when --store=memory")
+	serverCommand.Flags().IntVar(&memoryCol, "cols", 20, "cols to seed when --store=memory")
+	serverCommand.Flags().IntVar(&grpcPort, "grpc-port", 0, "serve the gRPC API on this port too, off when 0")
+}
+
 var (
 	dom *domain.Domain
 	uc  *usecase.Usecase
@@ -33,33 +70,463 @@ func run() {
 
 	lg = logger.NewZapLogger()
 
-	app := fiber.New()
+	flushTraces, err := tracing.Init(context.Background(), os.Getenv("OTEL_TRACES_EXPORTER"), "parking-service")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	m := metrics.NewPrometheus(reg)
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Hooks().OnListen(func(ld fiber.ListenData) error {
+		lg.Info("serving HTTP", zap.String("addr", net.JoinHostPort(ld.Host, ld.Port)))
+		return nil
+	})
 	app.Use(middlewares.RequestContextMiddleware(lg))
+	app.Use(middlewares.MetricsMiddleware(m))
+
+	// what /readyz checks
+	h := health.New(2 * time.Second)
+
+	domOpt := domain.Option{
+		Store:   storeFlag,
+		Log:     lg,
+		Metrics: m,
+	}
+
+	switch storeFlag {
+	case domain.StoreMemory:
+		domOpt.MemoryLots = []entity.Lot{defaultLot()}
+		domOpt.MemorySpots = generateSpots(1, memoryFloor, memoryRow, memoryCol)
+		domOpt.MemoryTariffs = defaultTariffs()
+	case domain.StorePostgres:
+		// init sql
+		g, err := connectDB()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		db = g
+		domOpt.DB = db
+
+		// a child span per SQL statement
+		if err := db.Use(tracing.NewGormPlugin()); err != nil {
+			log.Fatal(err)
+		}
+
+		sqlDB, err := db.DB()
+		if err != nil {
+			log.Fatal(err)
+		}
+		reg.MustRegister(collectors.NewDBStatsCollector(sqlDB, "parking"))
+
+		// refuse to serve on a schema we don't know
+		pending, err := newMigrator(db).Pending(context.Background())
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(pending) > 0 {
+			printMigrations("pending", pending)
+			log.Fatalf("%d migration(s) pending, run `migrate up` first", len(pending))
+		}
+
+		h.Add("database", sqlDB.PingContext)
+		h.Add("migrations", checkMigrations)
+	default:
+		log.Fatalf("unknown store %q", storeFlag)
+	}
+
+	// init domain
+	dom = domain.Init(domOpt)
 
-	// init sql
-	g, err := connectDB()
+	reg.MustRegister(metrics.NewOccupancyCollector(countSpots))
+	h.Add("spots", checkSpots)
+
+	// init usecase
+	allocation, err := parking.NewAllocationStrategy(os.Getenv("ALLOCATION_STRATEGY"))
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	db = g
+	// fallback and reserve are set per lot
+	compatibility, err := parking.NewCompatibility(os.Getenv("SPOT_COMPATIBILITY"))
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	// init domain
-	dom = domain.Init(domain.Option{
-		DB: db,
+	claimTimeout, err := durationEnv("WAITLIST_CLAIM_TIMEOUT", 5*time.Minute)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	resync, err := durationEnv("OCCUPANCY_RESYNC_INTERVAL", 30*time.Second)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	sinks, err := outbox.NewSinks(os.Getenv("OUTBOX_SINKS"), outbox.SinkOption{
+		Log:           lg,
+		WebhookURL:    os.Getenv("OUTBOX_WEBHOOK_URL"),
+		WebhookSecret: os.Getenv("OUTBOX_WEBHOOK_SECRET"),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	signer, err := tokenSigner()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	tokenTTL, err := durationEnv("AUTH_JWT_TTL", 12*time.Hour)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	uc = usecase.Init(dom, usecase.Option{
+		Allocation:      allocation,
+		Compatibility:   compatibility,
+		ClaimTimeout:    claimTimeout,
+		OccupancyResync: resync,
+		EventSinks:      sinks,
+		TokenSigner:     signer,
+		TokenTTL:        tokenTTL,
+		Metrics:         m,
 	})
 
-	// init usecase
-	uc = usecase.Init(dom, usecase.Option{})
+	authDisabled := os.Getenv("AUTH_DISABLED") == "true"
+	if authDisabled {
+		lg.Warn("authentication is disabled, every request is admin")
+	}
+
+	shutdownTimeout, err := durationEnv("SHUTDOWN_TIMEOUT", 20*time.Second)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// how long /readyz fails before the server stops taking requests, for
+	// the load balancer to notice. Not waited when unset or 0.
+	shutdownDelay, err := delayEnv("SHUTDOWN_DELAY", 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// release reservations and waitlist offers nobody claimed in time
+	sweep, err := durationEnv("HOLD_SWEEP_INTERVAL", 30*time.Second)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// send the domain events on
+	relay, err := durationEnv("OUTBOX_RELAY_INTERVAL", time.Second)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// the occupancy streams end first on shutdown, they'd hold it up
+	streams, endStreams := context.WithCancel(context.Background())
+
+	srv := &server{
+		app:        app,
+		health:     h,
+		delay:      shutdownDelay,
+		endStreams: endStreams,
+		flush:      flushTraces,
+		workers: []*worker{
+			startWorker("hold_sweeper", sweep, sweepHolds),
+			startWorker("outbox_relay", relay, relayEvents),
+		},
+	}
+
+	for _, w := range srv.workers {
+		h.Add("worker_"+w.name, w.check)
+	}
 
 	// init rest
 	handler.Init(handler.Option{
-		Uc:  uc,
-		App: app,
-		Log: lg,
+		Uc:           uc,
+		App:          app,
+		Log:          lg,
+		AdminToken:   os.Getenv("ADMIN_TOKEN"),
+		AuthDisabled: authDisabled,
+		Metrics:      promhttp.HandlerFor(reg, promhttp.HandlerOpts{Registry: reg}),
+		Context:      streams,
+		Health:       h,
+	})
+
+	failed := make(chan error, 2)
+
+	go func() {
+		if err := app.Listen(addrFlag); err != nil {
+			failed <- fmt.Errorf("http: %w", err)
+		}
+	}()
+
+	// the same usecases over gRPC, for the gate controllers
+	if grpcPort > 0 {
+		lis, err := net.Listen("tcp", fmt.Sprintf(":%d", grpcPort))
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		srv.grpc, srv.rpc = newGRPC(streams, rpc.AuthOption{
+			Auth:       uc.Auth,
+			Log:        lg,
+			AdminToken: os.Getenv("ADMIN_TOKEN"),
+			Disabled:   authDisabled,
+		})
+
+		go func() {
+			lg.Info("serving gRPC", zap.String("addr", lis.Addr().String()))
+			if err := srv.grpc.Serve(lis); err != nil {
+				failed <- fmt.Errorf("grpc: %w", err)
+			}
+		}()
+	}
+
+	lg.Info("starting", zap.String("store", storeFlag), zap.String("addr", addrFlag))
+
+	// a second signal kills at once
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	select {
+	case <-ctx.Done():
+		stop()
+		lg.Info("shutting down", zap.Duration("timeout", shutdownTimeout), zap.Duration("delay", shutdownDelay))
+		srv.shutdown(shutdownTimeout)
+	case err := <-failed:
+		stop()
+		lg.Error("server failed, shutting down", zap.Error(err))
+		srv.shutdown(shutdownTimeout)
+		os.Exit(1)
+	}
+}
+
+// server is what the start command runs, shut down in order.
+type server struct {
+	app        *fiber.App
+	health     *health.Health
+	delay      time.Duration
+	grpc       *grpc.Server
+	rpc        rpc.RPC
+	workers    []*worker
+	endStreams context.CancelFunc
+	flush      func(context.Context) error
+}
+
+// shutdown fails the readiness for delay, stops taking requests and waits
+// for the ones under way, stops the workers and waits for the transactions
+// left, then closes the database. Whatever isn't done within timeout is
+// cut off, the delay not counted.
+func (s *server) shutdown(timeout time.Duration) {
+	start := time.Now()
+
+	// still serving, the load balancer takes us out first
+	s.health.ShutDown()
+	if s.rpc != nil {
+		s.rpc.Shutdown()
+	}
+	time.Sleep(s.delay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	s.endStreams()
+
+	if err := s.app.ShutdownWithContext(ctx); err != nil {
+		lg.Error("failed to finish HTTP requests in time", zap.Error(err))
+	}
+	lg.Info("stopped HTTP server")
+
+	if s.grpc != nil {
+		stopGRPC(ctx, s.grpc)
+		lg.Info("stopped gRPC server")
+	}
+
+	// in order, the sweeps emit events the relay sends
+	for _, w := range s.workers {
+		w.stop(ctx)
+	}
+
+	// the events of the last requests
+	if ctx.Err() == nil {
+		relayEvents(ctx)
+	}
+
+	if err := dom.Transaction.Drain(ctx); err != nil {
+		lg.Error("failed to finish transactions in time", zap.Error(err))
+	}
+
+	if db != nil {
+		if sqlDB, err := db.DB(); err == nil {
+			if err := sqlDB.Close(); err != nil {
+				lg.Error("failed to close database", zap.Error(err))
+			}
+		}
+		lg.Info("closed database")
+	}
+
+	// the spans left
+	if err := s.flush(ctx); err != nil {
+		lg.Error("failed to flush spans", zap.Error(err))
+	}
+
+	lg.Info("stopped", zap.Duration("took", time.Since(start)))
+	_ = lg.Sync()
+}
+
+func newGRPC(streams context.Context, auth rpc.AuthOption) (*grpc.Server, rpc.RPC) {
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(middlewares.UnaryRequestContextInterceptor(lg), rpc.UnaryAuthInterceptor(auth)),
+		grpc.ChainStreamInterceptor(middlewares.StreamRequestContextInterceptor(lg), rpc.StreamAuthInterceptor(auth)),
+	)
+
+	r := rpc.Init(rpc.Option{
+		Uc:      uc,
+		Server:  srv,
+		Log:     lg,
+		Context: streams,
 	})
 
-	log.Println(app.Listen(":8080"))
+	return srv, r
+}
+
+// stopGRPC lets the calls under way finish, and cuts them off once ctx is
+// done.
+func stopGRPC(ctx context.Context, srv *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		lg.Error("failed to finish gRPC calls in time")
+		srv.Stop()
+	}
+}
+
+// sweepHolds releases lapsed reservations and waitlist offers, the request
+// path also refuses them so a late sweep never lets a stale code in.
+func sweepHolds(ctx context.Context) {
+	n, err := uc.Reservation.ExpireReservations(ctx)
+	if err != nil {
+		lg.Error("failed to expire reservations", zap.Error(err))
+	} else if n > 0 {
+		lg.Info("expired reservations", zap.Int("count", n))
+	}
+
+	n, err = uc.Waitlist.ExpireOffers(ctx)
+	if err != nil {
+		lg.Error("failed to expire waitlist offers", zap.Error(err))
+	} else if n > 0 {
+		lg.Info("expired waitlist offers", zap.Int("count", n))
+	}
+}
+
+// relayEvents sends the outbox batch after batch while events keep going
+// out.
+func relayEvents(ctx context.Context) {
+	for {
+		n, err := uc.Outbox.Relay(ctx)
+		if err != nil {
+			lg.Error("failed to relay events", zap.Error(err))
+		}
+		if err != nil || n == 0 {
+			return
+		}
+	}
+}
+
+// checkMigrations fails while migrations are pending, as after deploying a
+// new schema before running migrate up.
+func checkMigrations(ctx context.Context) error {
+	pending, err := newMigrator(db).Pending(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(pending) > 0 {
+		return fmt.Errorf("%d migration(s) pending", len(pending))
+	}
+
+	return nil
+}
+
+// checkSpots fails until the spots are seeded, nothing could park.
+func checkSpots(ctx context.Context) error {
+	counts, err := countSpots(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, lot := range counts {
+		for _, c := range lot {
+			if c.Total > 0 {
+				return nil
+			}
+		}
+	}
+
+	return errors.New("no parking spots, run `seed` first")
+}
+
+// countSpots counts the spots of every lot for the occupancy gauges.
+func countSpots(ctx context.Context) (map[uint][]entity.SpotCount, error) {
+	lots, err := dom.Lot.GetLots(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint][]entity.SpotCount, len(lots))
+	for _, l := range lots {
+		c, err := dom.Parking.CountParkingSpots(ctx, l.ID)
+		if err != nil {
+			return nil, err
+		}
+		counts[l.ID] = c
+	}
+
+	return counts, nil
+}
+
+// durationEnv reads a positive duration, fallback when unset.
+func durationEnv(key string, fallback time.Duration) (time.Duration, error) {
+	d, err := delayEnv(key, fallback)
+	if err != nil {
+		return 0, err
+	}
+
+	if d == 0 {
+		return 0, fmt.Errorf("invalid %s: must be positive", key)
+	}
+
+	return d, nil
 }
 
-// TODO: Gracefull shutdown
+// delayEnv reads a duration that may be 0 to not wait at all, fallback
+// when unset.
+func delayEnv(key string, fallback time.Duration) (time.Duration, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback, nil
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+
+	if d < 0 {
+		return 0, fmt.Errorf("invalid %s: must not be negative", key)
+	}
+
+	return d, nil
+}
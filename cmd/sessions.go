@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	auditDom "github.com/zuhrulumam/go-parking-lot/business/domain/audit"
+	"github.com/zuhrulumam/go-parking-lot/business/domain/transaction"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"gorm.io/gorm"
+)
+
+var (
+	sessionsActor  string
+	sessionsReason string
+	sessionsDryRun bool
+)
+
+var sessionsCommand = &cobra.Command{
+	Use:   "sessions",
+	Short: "repair parking sessions",
+}
+
+var sessionsDuplicatesCommand = &cobra.Command{
+	Use:   "duplicates",
+	Short: "list the vehicles with more than one open session, they block migration 10",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		sessions, err := duplicateSessions(context.Background(), mustConnectDB())
+		if err != nil {
+			log.Fatalf("failed to list duplicate sessions: %v", err)
+		}
+
+		printSessions(sessions)
+	},
+}
+
+var sessionsCloseDuplicatesCommand = &cobra.Command{
+	Use:   "close-duplicates",
+	Short: "close all but the newest open session of each vehicle, written to the audit log",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		db := mustConnectDB()
+
+		sessions, err := duplicateSessions(context.Background(), db)
+		if err != nil {
+			log.Fatalf("failed to list duplicate sessions: %v", err)
+		}
+
+		printSessions(sessions)
+		if sessionsDryRun {
+			return
+		}
+
+		closed, err := closeDuplicateSessions(context.Background(), db, sessionsActor, sessionsReason)
+		if err != nil {
+			log.Fatalf("failed to close duplicate sessions: %v", err)
+		}
+
+		log.Printf("closed %d session(s), run `migrate up` again", closed)
+	},
+}
+
+func init() {
+	sessionsCloseDuplicatesCommand.Flags().StringVar(&sessionsActor, "actor", "", "who closes the sessions, recorded in the audit log")
+	sessionsCloseDuplicatesCommand.Flags().StringVar(&sessionsReason, "reason", "", "why, recorded in the audit log")
+	sessionsCloseDuplicatesCommand.Flags().BoolVar(&sessionsDryRun, "dry-run", false, "only list what would be closed")
+	_ = sessionsCloseDuplicatesCommand.MarkFlagRequired("actor")
+	_ = sessionsCloseDuplicatesCommand.MarkFlagRequired("reason")
+
+	sessionsCommand.AddCommand(sessionsDuplicatesCommand)
+	sessionsCommand.AddCommand(sessionsCloseDuplicatesCommand)
+}
+
+// duplicateSessions returns the open sessions of the vehicles having more
+// than one, newest first per vehicle.
+func duplicateSessions(ctx context.Context, db *gorm.DB) ([]entity.Vehicle, error) {
+	var result []entity.Vehicle
+
+	err := pkg.GetTransactionFromCtx(ctx, db).WithContext(ctx).
+		Where("unparked_at IS NULL").
+		Where("vehicle_number IN (?)", db.Model(&entity.Vehicle{}).
+			Select("vehicle_number").
+			Where("unparked_at IS NULL").
+			Group("vehicle_number").
+			Having("COUNT(*) > 1")).
+		Order("vehicle_number, id DESC").
+		Find(&result).Error
+
+	return result, err
+}
+
+// closeDuplicateSessions closes the older open sessions of each vehicle
+// as exited, without a fee, and frees their spots unless another open
+// session holds them. Each closure is written to the audit log.
+func closeDuplicateSessions(ctx context.Context, db *gorm.DB, actor, reason string) (int, error) {
+	var (
+		closed int
+		audit  = auditDom.InitAuditDomain(auditDom.Option{DB: db})
+	)
+
+	err := transaction.Init(transaction.Option{DB: db}).RunInTx(ctx, func(ctx context.Context) error {
+		tx := pkg.GetTransactionFromCtx(ctx, db).WithContext(ctx)
+
+		sessions, err := duplicateSessions(ctx, db)
+		if err != nil {
+			return err
+		}
+
+		newest := map[string]bool{}
+		for _, v := range sessions {
+			// newest first, that one is kept
+			if !newest[v.VehicleNumber] {
+				newest[v.VehicleNumber] = true
+				continue
+			}
+
+			before, _ := json.Marshal(v)
+
+			now := time.Now()
+			err := tx.Model(&entity.Vehicle{}).Where("id = ?", v.ID).
+				Updates(map[string]interface{}{"unparked_at": now, "status": entity.SessionExited}).Error
+			if err != nil {
+				return err
+			}
+
+			v.UnparkedAt = &now
+			v.Status = entity.SessionExited
+			after, _ := json.Marshal(v)
+
+			// free the spot, unless another open session holds it
+			var holders int64
+			err = tx.Model(&entity.Vehicle{}).
+				Where("spot_id = ? AND unparked_at IS NULL", v.SpotID).
+				Count(&holders).Error
+			if err != nil {
+				return err
+			}
+
+			if holders == 0 {
+				sp, err := pkg.ParseSpotID(v.SpotID)
+				if err != nil {
+					return err
+				}
+
+				err = tx.Model(&entity.ParkingSpot{}).
+					Where("lot_id = ? AND floor = ? AND row = ? AND col = ?", sp.Lot, sp.Floor, sp.Row, sp.Col).
+					Update("occupied", false).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			_, err = audit.InsertAuditLog(ctx, entity.AuditLog{
+				LotID:      v.LotID,
+				Actor:      actor,
+				Action:     entity.AuditSessionClosed,
+				Resource:   "vehicle",
+				ResourceID: strconv.FormatUint(uint64(v.ID), 10),
+				Before:     string(before),
+				After:      string(after),
+				Reason:     reason,
+			})
+			if err != nil {
+				return err
+			}
+
+			closed++
+		}
+
+		return nil
+	})
+
+	return closed, err
+}
+
+func printSessions(sessions []entity.Vehicle) {
+	if len(sessions) == 0 {
+		log.Printf("no vehicle has more than one open session")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VEHICLE\tSESSION\tLOT\tSPOT\tTICKET\tPARKED AT\t")
+
+	seen := map[string]bool{}
+	for _, v := range sessions {
+		keep := "close"
+		if !seen[v.VehicleNumber] {
+			seen[v.VehicleNumber] = true
+			keep = "keep"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\t%s\n", v.VehicleNumber, v.ID, v.LotID, v.SpotID, v.TicketID, formatTime(&v.ParkedAt, ""), keep)
+	}
+	_ = w.Flush()
+}
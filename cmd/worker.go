@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// worker runs a job every interval in the background until stopped.
+type worker struct {
+	name   string
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// startWorker runs job every interval. stop only keeps the next run from
+// starting, a run started goes to its end.
+func startWorker(name string, interval time.Duration, job func(ctx context.Context)) *worker {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	w := &worker{
+		name:   name,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		defer close(w.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				job(context.Background())
+			}
+		}
+	}()
+
+	lg.Info("started worker", zap.String("worker", name), zap.Duration("interval", interval))
+
+	return w
+}
+
+// stop cancels the worker and waits for its job to return, or for ctx to
+// be done.
+func (w *worker) stop(ctx context.Context) {
+	w.cancel()
+
+	select {
+	case <-w.done:
+		lg.Info("stopped worker", zap.String("worker", w.name))
+	case <-ctx.Done():
+		lg.Warn("worker didn't stop in time", zap.String("worker", w.name))
+	}
+}
+
+// check fails once the worker stopped, for the readiness of the server.
+func (w *worker) check(ctx context.Context) error {
+	select {
+	case <-w.done:
+		return errors.New("stopped")
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,284 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+// GetSpots godoc
+// @Summary      List spots
+// @Description  Returns every spot of the lot with its type and flags, optionally of one floor or type
+// @Tags         Admin
+// @Produce      json
+// @Param        lot_id path int true "Lot ID"
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        floor query int false "Floor"
+// @Param        type query string false "Spot Type (M, B, A, X)"
+// @Success      200 {object} handler.SpotsResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Router       /lots/{lot_id}/admin/spots [get]
+func (e *rest) GetSpots(c *fiber.Ctx) error {
+
+	ctx := c.Locals("ctx").(context.Context)
+
+	spots, err := e.uc.Admin.GetSpots(ctx, entity.GetAvailableParkingSpot{
+		LotID:       lotID(c),
+		Floor:       c.QueryInt("floor"),
+		VehicleType: entity.VehicleType(utils.CopyString(c.Query("type"))),
+	})
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(SpotsResponse{
+		Success: true,
+		Message: "Done get spots !",
+		Spots:   spots,
+	})
+}
+
+// CreateSpots godoc
+// @Summary      Create spots in bulk
+// @Description  Creates up to 1000 spots at free positions, on existing floors or new ones
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        lot_id path int true "Lot ID"
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        body body handler.CreateSpotsRequest true "Spots"
+// @Success      201 {object} handler.SpotsResponse
+// @Failure      400 {object} handler.ErrorResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Failure      409 {object} handler.ErrorResponse
+// @Router       /lots/{lot_id}/admin/spots [post]
+func (e *rest) CreateSpots(c *fiber.Ctx) error {
+
+	var (
+		input CreateSpotsRequest
+		ctx   = c.Locals("ctx").(context.Context)
+	)
+	if err := c.BodyParser(&input); err != nil {
+		return e.compileError(c, x.WrapWithCode(err, http.StatusBadRequest, "invalid input"))
+	}
+
+	if err := validate.Struct(input); err != nil {
+		return e.compileError(c, x.WrapWithCode(err, http.StatusBadRequest, "failed validation"))
+	}
+
+	spots := make([]entity.ParkingSpot, 0, len(input.Spots))
+	for _, s := range input.Spots {
+		spots = append(spots, entity.ParkingSpot{
+			Floor:      s.Floor,
+			Row:        s.Row,
+			Col:        s.Col,
+			Type:       s.Type,
+			Active:     s.Active == nil || *s.Active,
+			EVCharger:  s.EVCharger,
+			Accessible: s.Accessible,
+		})
+	}
+
+	created, err := e.uc.Admin.CreateSpots(ctx, entity.CreateSpots{
+		LotID: lotID(c),
+		Actor: c.Locals("actor").(string),
+		Spots: spots,
+	})
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(SpotsResponse{
+		Success: true,
+		Message: "Done creating spots !",
+		Spots:   created,
+	})
+}
+
+// UpdateSpot godoc
+// @Summary      Update a spot
+// @Description  Changes the type or features of a spot, or deactivates it for maintenance. Occupied or held spots keep their type and stay active
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        lot_id path int true "Lot ID"
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        spot_id path string true "Spot ID (lot-floor-row-col)"
+// @Param        body body handler.UpdateSpotRequest true "Spot changes"
+// @Success      200 {object} handler.SpotResponse
+// @Failure      400 {object} handler.ErrorResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Failure      404 {object} handler.ErrorResponse
+// @Failure      409 {object} handler.ErrorResponse
+// @Failure      422 {object} handler.ErrorResponse
+// @Router       /lots/{lot_id}/admin/spots/{spot_id} [patch]
+func (e *rest) UpdateSpot(c *fiber.Ctx) error {
+
+	var (
+		input UpdateSpotRequest
+		ctx   = c.Locals("ctx").(context.Context)
+	)
+	if err := c.BodyParser(&input); err != nil {
+		return e.compileError(c, x.WrapWithCode(err, http.StatusBadRequest, "invalid input"))
+	}
+
+	if err := validate.Struct(input); err != nil {
+		return e.compileError(c, x.WrapWithCode(err, http.StatusBadRequest, "failed validation"))
+	}
+
+	spot, err := e.uc.Admin.UpdateSpot(ctx, entity.UpdateSpot{
+		LotID:      lotID(c),
+		Actor:      c.Locals("actor").(string),
+		SpotID:     utils.CopyString(c.Params("spot_id")),
+		Type:       input.Type,
+		Active:     input.Active,
+		EVCharger:  input.EVCharger,
+		Accessible: input.Accessible,
+		Reason:     input.Reason,
+	})
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(SpotResponse{
+		Success: true,
+		Message: "Done updating spot !",
+		Spot:    &spot,
+	})
+}
+
+// AddFloor godoc
+// @Summary      Add a floor
+// @Description  Creates a rows x cols floor of spots of one vehicle type
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        lot_id path int true "Lot ID"
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        body body handler.AddFloorRequest true "Floor"
+// @Success      201 {object} handler.SpotsResponse
+// @Failure      400 {object} handler.ErrorResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Failure      409 {object} handler.ErrorResponse
+// @Router       /lots/{lot_id}/admin/floors [post]
+func (e *rest) AddFloor(c *fiber.Ctx) error {
+
+	var (
+		input AddFloorRequest
+		ctx   = c.Locals("ctx").(context.Context)
+	)
+	if err := c.BodyParser(&input); err != nil {
+		return e.compileError(c, x.WrapWithCode(err, http.StatusBadRequest, "invalid input"))
+	}
+
+	if err := validate.Struct(input); err != nil {
+		return e.compileError(c, x.WrapWithCode(err, http.StatusBadRequest, "failed validation"))
+	}
+
+	spots, err := e.uc.Admin.AddFloor(ctx, entity.AddFloor{
+		LotID: lotID(c),
+		Actor: c.Locals("actor").(string),
+		Floor: input.Floor,
+		Rows:  input.Rows,
+		Cols:  input.Cols,
+		Type:  input.Type,
+	})
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(SpotsResponse{
+		Success: true,
+		Message: "Done adding floor !",
+		Spots:   spots,
+	})
+}
+
+// RemoveFloor godoc
+// @Summary      Remove a floor
+// @Description  Deletes every spot of a floor, refused while any of them is occupied or held
+// @Tags         Admin
+// @Produce      json
+// @Param        lot_id path int true "Lot ID"
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        floor path int true "Floor"
+// @Param        reason query string false "Reason recorded in the audit trail"
+// @Success      200 {object} handler.RemoveFloorResponse
+// @Failure      400 {object} handler.ErrorResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Failure      404 {object} handler.ErrorResponse
+// @Failure      409 {object} handler.ErrorResponse
+// @Router       /lots/{lot_id}/admin/floors/{floor} [delete]
+func (e *rest) RemoveFloor(c *fiber.Ctx) error {
+
+	ctx := c.Locals("ctx").(context.Context)
+
+	floor, err := c.ParamsInt("floor")
+	if err != nil || floor <= 0 {
+		return e.compileError(c, x.NewWithCode(http.StatusBadRequest, "invalid floor"))
+	}
+
+	removed, err := e.uc.Admin.RemoveFloor(ctx, entity.RemoveFloor{
+		LotID:  lotID(c),
+		Actor:  c.Locals("actor").(string),
+		Floor:  floor,
+		Reason: utils.CopyString(c.Query("reason")),
+	})
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(RemoveFloorResponse{
+		Success: true,
+		Message: "Done removing floor !",
+		Floor:   floor,
+		Removed: removed,
+	})
+}
+
+// GetAuditLogs godoc
+// @Summary      Audit trail
+// @Description  Returns the newest admin changes of the lot first
+// @Tags         Admin
+// @Produce      json
+// @Param        lot_id path int true "Lot ID"
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        action query string false "Action (spots.create, spot.update, floor.add, floor.remove, layout.apply, lot.create, lot.update)"
+// @Param        resource query string false "Resource (spot, floor, layout, lot)"
+// @Param        resource_id query string false "Spot ID, floor or lot number"
+// @Param        limit query int false "Max entries, default 50, at most 500"
+// @Success      200 {object} handler.AuditLogsResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Router       /lots/{lot_id}/admin/audit [get]
+func (e *rest) GetAuditLogs(c *fiber.Ctx) error {
+
+	ctx := c.Locals("ctx").(context.Context)
+
+	logs, err := e.uc.Admin.GetAuditLogs(ctx, entity.GetAuditLogs{
+		LotID:      lotID(c),
+		Action:     utils.CopyString(c.Query("action")),
+		Resource:   utils.CopyString(c.Query("resource")),
+		ResourceID: utils.CopyString(c.Query("resource_id")),
+		Limit:      c.QueryInt("limit"),
+	})
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(AuditLogsResponse{
+		Success:   true,
+		Message:   "Done get audit logs !",
+		AuditLogs: logs,
+	})
+}
@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	authUc "github.com/zuhrulumam/go-parking-lot/business/usecase/auth"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+const defaultAdminActor = "admin"
+
+// Authenticator tells who sent a request. ok is false when the request
+// carries none of its credentials, the next authenticator is tried then.
+type Authenticator interface {
+	Authenticate(c *fiber.Ctx) (p entity.Principal, ok bool, err error)
+}
+
+// AuthenticatorFunc lets a function be an Authenticator.
+type AuthenticatorFunc func(c *fiber.Ctx) (entity.Principal, bool, error)
+
+func (f AuthenticatorFunc) Authenticate(c *fiber.Ctx) (entity.Principal, bool, error) {
+	return f(c)
+}
+
+// CredentialAuthenticator takes the API key or token of the Authorization
+// Bearer header, of X-API-Key, or of the access_token query for the
+// EventSource and WebSocket clients that can't set headers.
+func CredentialAuthenticator(auth authUc.UsecaseItf) Authenticator {
+	return AuthenticatorFunc(func(c *fiber.Ctx) (entity.Principal, bool, error) {
+		credential := c.Get("X-API-Key")
+
+		if h := c.Get(fiber.HeaderAuthorization); h != "" {
+			scheme, token, _ := strings.Cut(h, " ")
+			if !strings.EqualFold(scheme, "Bearer") {
+				return entity.Principal{}, false, x.NewWithCode(http.StatusUnauthorized, "authorization must be a bearer token")
+			}
+			credential = strings.TrimSpace(token)
+		}
+
+		if credential == "" {
+			credential = c.Query("access_token")
+		}
+
+		if credential == "" {
+			return entity.Principal{}, false, nil
+		}
+
+		p, err := auth.Authenticate(c.Locals("ctx").(context.Context), credential)
+		return p, err == nil, err
+	})
+}
+
+// AdminTokenAuthenticator keeps the shared ADMIN_TOKEN of the X-Admin-Token
+// header working as an admin, X-Admin-Actor names who made the change in
+// the audit trail. It authenticates nobody while token is empty.
+func AdminTokenAuthenticator(token string) Authenticator {
+	return AuthenticatorFunc(func(c *fiber.Ctx) (entity.Principal, bool, error) {
+		got := c.Get("X-Admin-Token")
+		if got == "" {
+			return entity.Principal{}, false, nil
+		}
+
+		if token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			return entity.Principal{}, false, x.NewWithCode(http.StatusUnauthorized, "invalid admin token")
+		}
+
+		return entity.Principal{
+			Subject: utils.CopyString(c.Get("X-Admin-Actor", defaultAdminActor)),
+			Role:    entity.RoleAdmin,
+			Method:  entity.AuthAdminToken,
+		}, true, nil
+	})
+}
+
+// authenticate finds the principal of the request with the first
+// authenticator it has credentials for, its subject is the actor of the
+// changes made.
+func (e *rest) authenticate(c *fiber.Ctx) error {
+	if e.authDisabled {
+		return e.signIn(c, entity.Principal{
+			Subject: utils.CopyString(c.Get("X-Admin-Actor", defaultAdminActor)),
+			Role:    entity.RoleAdmin,
+			Method:  entity.AuthDisabled,
+		})
+	}
+
+	for _, a := range e.authenticators {
+		p, ok, err := a.Authenticate(c)
+		if err != nil {
+			return e.compileError(c, err)
+		}
+
+		if ok {
+			return e.signIn(c, p)
+		}
+	}
+
+	return e.compileError(c, x.NewWithCode(http.StatusUnauthorized, "missing credentials"))
+}
+
+func (e *rest) signIn(c *fiber.Ctx, p entity.Principal) error {
+	c.Locals("principal", p)
+	c.Locals("actor", p.Subject)
+
+	return c.Next()
+}
+
+// principal is the caller of a route behind authenticate.
+func principal(c *fiber.Ctx) entity.Principal {
+	return c.Locals("principal").(entity.Principal)
+}
+
+// allow lets only the roles through.
+func (e *rest) allow(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if p := principal(c); !p.Can(roles...) {
+			return e.compileError(c, x.NewWithCode(http.StatusForbidden, "role %q may not %s %s", p.Role, c.Method(), c.Path()))
+		}
+
+		return c.Next()
+	}
+}
+
+// allLots lets only the principals not confined to one lot through, for
+// the routes acting on every lot.
+func (e *rest) allLots(c *fiber.Ctx) error {
+	if p := principal(c); p.LotID != 0 {
+		return e.compileError(c, x.NewWithCode(http.StatusForbidden, "%s may only reach lot %d", p.Subject, p.LotID))
+	}
+
+	return c.Next()
+}
+
+// Login godoc
+// @Summary      Log in
+// @Description  Exchanges the username and password of a user for a token, sent as Authorization: Bearer <token>
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        body body handler.LoginRequest true "Credentials"
+// @Success      200 {object} handler.TokenResponse
+// @Failure      400 {object} handler.ErrorResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Router       /auth/login [post]
+func (e *rest) Login(c *fiber.Ctx) error {
+
+	var (
+		input LoginRequest
+		ctx   = c.Locals("ctx").(context.Context)
+	)
+	if err := c.BodyParser(&input); err != nil {
+		return e.compileError(c, x.WrapWithCode(err, http.StatusBadRequest, "invalid input"))
+	}
+
+	if err := validate.Struct(input); err != nil {
+		return e.compileError(c, x.WrapWithCode(err, http.StatusBadRequest, "failed validation"))
+	}
+
+	token, err := e.uc.Auth.Login(ctx, entity.Login{
+		Username: input.Username,
+		Password: input.Password,
+	})
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(TokenResponse{
+		Success: true,
+		Message: "Done logging in !",
+		Token:   &token,
+	})
+}
+
+// Me godoc
+// @Summary      Who am I
+// @Description  Returns the principal the credentials of the request authenticate as
+// @Tags         Auth
+// @Produce      json
+// @Param        Authorization header string true "Bearer token or API key"
+// @Success      200 {object} handler.PrincipalResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Router       /auth/me [get]
+func (e *rest) Me(c *fiber.Ctx) error {
+	p := principal(c)
+
+	return c.Status(fiber.StatusOK).JSON(PrincipalResponse{
+		Success:   true,
+		Message:   "Done get principal !",
+		Principal: &p,
+	})
+}
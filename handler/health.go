@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/zuhrulumam/go-parking-lot/pkg/health"
+)
+
+// Healthz godoc
+// @Summary      Liveness
+// @Description  Answers while the process is alive, for liveness probes. It checks nothing else
+// @Tags         Health
+// @Produce      json
+// @Success      200 {object} health.Report
+// @Router       /healthz [get]
+func (e *rest) Healthz(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(health.Report{Status: health.StatusOK})
+}
+
+// Readyz godoc
+// @Summary      Readiness
+// @Description  Checks the database, the migrations, the seeded spots and the background workers, for readiness probes and load balancers
+// @Description  503 when one of them fails or doesn't answer in time, and while shutting down
+// @Tags         Health
+// @Produce      json
+// @Success      200 {object} health.Report
+// @Failure      503 {object} health.Report
+// @Router       /readyz [get]
+func (e *rest) Readyz(c *fiber.Ctx) error {
+	report := e.health.Ready(c.Locals("ctx").(context.Context))
+	if !report.Ready() {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(report)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(report)
+}
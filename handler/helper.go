@@ -21,9 +21,27 @@ func (e *rest) compileError(c *fiber.Ctx, err error) error {
 	case 400:
 		httpStatus = http.StatusBadRequest
 		he = errors.EM.Message("EN", "badrequest")
+	case 401:
+		httpStatus = http.StatusUnauthorized
+		he = errors.EM.Message("EN", "unauthorized")
+	case 402:
+		httpStatus = http.StatusPaymentRequired
+		he = errors.EM.Message("EN", "paymentrequired")
+	case 403:
+		httpStatus = http.StatusForbidden
+		he = errors.EM.Message("EN", "forbidden")
 	case 404:
 		httpStatus = http.StatusNotFound
 		he = errors.EM.Message("EN", "notfound")
+	case 409:
+		httpStatus = http.StatusConflict
+		he = errors.EM.Message("EN", "conflict")
+	case 422:
+		httpStatus = http.StatusUnprocessableEntity
+		he = errors.EM.Message("EN", "unprocessable")
+	case 503:
+		httpStatus = http.StatusServiceUnavailable
+		he = errors.EM.Message("EN", "unavailable")
 	default:
 		httpStatus = http.StatusInternalServerError
 		he = errors.EM.Message("EN", "internal")
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+// withLot reads the lot of the /lots/:lot_id routes, see lotID.
+func (e *rest) withLot(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("lot_id")
+	if err != nil || id <= 0 {
+		return e.compileError(c, x.NewWithCode(http.StatusBadRequest, "invalid lot id"))
+	}
+
+	// a key of one lot reaches no other
+	if p := principal(c); !p.Reaches(uint(id)) {
+		return e.compileError(c, x.NewWithCode(http.StatusForbidden, "%s may not reach lot %d", p.Subject, id))
+	}
+
+	c.Locals("lot_id", uint(id))
+
+	return c.Next()
+}
+
+// lotID is the lot of a route behind withLot.
+func lotID(c *fiber.Ctx) uint {
+	return c.Locals("lot_id").(uint)
+}
+
+// GetLots godoc
+// @Summary      List lots
+// @Description  Returns every lot with its settings
+// @Tags         Lot
+// @Produce      json
+// @Param        Authorization header string true "Bearer token or API key"
+// @Success      200 {object} handler.LotsResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Failure      500 {object} handler.ErrorResponse
+// @Router       /lots [get]
+func (e *rest) GetLots(c *fiber.Ctx) error {
+
+	ctx := c.Locals("ctx").(context.Context)
+
+	lots, err := e.uc.Admin.GetLots(ctx)
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	// a key of one lot sees only that lot
+	p := principal(c)
+	lots = slices.DeleteFunc(lots, func(l entity.Lot) bool {
+		return !p.Reaches(l.ID)
+	})
+
+	return c.Status(fiber.StatusOK).JSON(LotsResponse{
+		Success: true,
+		Message: "Done get lots !",
+		Lots:    lots,
+	})
+}
+
+// GetLot godoc
+// @Summary      Get a lot
+// @Description  Returns a lot with its settings
+// @Tags         Lot
+// @Produce      json
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        lot_id path int true "Lot ID"
+// @Success      200 {object} handler.LotResponse
+// @Failure      400 {object} handler.ErrorResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Failure      404 {object} handler.ErrorResponse
+// @Router       /lots/{lot_id} [get]
+func (e *rest) GetLot(c *fiber.Ctx) error {
+
+	ctx := c.Locals("ctx").(context.Context)
+
+	lot, err := e.uc.Admin.GetLot(ctx, entity.GetLot{
+		ID: lotID(c),
+	})
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(LotResponse{
+		Success: true,
+		Message: "Done get lot !",
+		Lot:     &lot,
+	})
+}
+
+// CreateLot godoc
+// @Summary      Create a lot
+// @Description  Creates an empty lot, add its spots with the admin floor and spot routes
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        body body handler.LotRequest true "Lot"
+// @Success      201 {object} handler.LotResponse
+// @Failure      400 {object} handler.ErrorResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Router       /admin/lots [post]
+func (e *rest) CreateLot(c *fiber.Ctx) error {
+
+	var (
+		input LotRequest
+		ctx   = c.Locals("ctx").(context.Context)
+	)
+	if err := c.BodyParser(&input); err != nil {
+		return e.compileError(c, x.WrapWithCode(err, http.StatusBadRequest, "invalid input"))
+	}
+
+	if err := validate.Struct(input); err != nil {
+		return e.compileError(c, x.WrapWithCode(err, http.StatusBadRequest, "failed validation"))
+	}
+
+	lot, err := e.uc.Admin.CreateLot(ctx, entity.SaveLot{
+		Actor: c.Locals("actor").(string),
+		Lot:   toLot(input),
+	})
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(LotResponse{
+		Success: true,
+		Message: "Done creating lot !",
+		Lot:     &lot,
+	})
+}
+
+// UpdateLot godoc
+// @Summary      Update a lot
+// @Description  Replaces the name, allocation strategy, opening hours, timezone and fallback policy of a lot
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        lot_id path int true "Lot ID"
+// @Param        body body handler.LotRequest true "Lot"
+// @Success      200 {object} handler.LotResponse
+// @Failure      400 {object} handler.ErrorResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Failure      404 {object} handler.ErrorResponse
+// @Router       /admin/lots/{lot_id} [put]
+func (e *rest) UpdateLot(c *fiber.Ctx) error {
+
+	var (
+		input LotRequest
+		ctx   = c.Locals("ctx").(context.Context)
+	)
+
+	if err := c.BodyParser(&input); err != nil {
+		return e.compileError(c, x.WrapWithCode(err, http.StatusBadRequest, "invalid input"))
+	}
+
+	if err := validate.Struct(input); err != nil {
+		return e.compileError(c, x.WrapWithCode(err, http.StatusBadRequest, "failed validation"))
+	}
+
+	data := toLot(input)
+	data.ID = lotID(c)
+
+	lot, err := e.uc.Admin.UpdateLot(ctx, entity.SaveLot{
+		Actor: c.Locals("actor").(string),
+		Lot:   data,
+	})
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(LotResponse{
+		Success: true,
+		Message: "Done updating lot !",
+		Lot:     &lot,
+	})
+}
+
+func toLot(input LotRequest) entity.Lot {
+	return entity.Lot{
+		Name:       input.Name,
+		Allocation: input.Allocation,
+		OpensAt:    input.OpensAt,
+		ClosesAt:   input.ClosesAt,
+		Timezone:   input.Timezone,
+		Fallback:   input.Fallback,
+		Reserve:    input.Reserve,
+	}
+}
@@ -0,0 +1,100 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/zuhrulumam/go-parking-lot/business/domain"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/business/usecase"
+	"github.com/zuhrulumam/go-parking-lot/handler"
+	"github.com/zuhrulumam/go-parking-lot/pkg/middlewares"
+	"go.uber.org/zap"
+)
+
+func TestLotScopedAdmin(t *testing.T) {
+	ctx := context.Background()
+
+	dom := domain.Init(domain.Option{
+		Store:      domain.StoreMemory,
+		Log:        zap.NewNop(),
+		MemoryLots: []entity.Lot{{ID: 1, Name: "Main"}, {ID: 2, Name: "Annex"}},
+	})
+	uc := usecase.Init(dom, usecase.Option{})
+
+	_, global, err := uc.Auth.CreateAPIKey(ctx, entity.CreateAPIKey{Name: "ops", Role: entity.RoleAdmin})
+	assert.NoError(t, err)
+	_, scoped, err := uc.Auth.CreateAPIKey(ctx, entity.CreateAPIKey{Name: "main-admin", Role: entity.RoleAdmin, LotID: 1})
+	assert.NoError(t, err)
+
+	app := fiber.New()
+	app.Use(middlewares.RequestContextMiddleware(zap.NewNop()))
+	handler.Init(handler.Option{Uc: uc, App: app, Log: zap.NewNop()})
+
+	send := func(key, method, path, body string) (int, map[string]interface{}) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+key)
+
+		res, err := app.Test(req)
+		assert.NoError(t, err)
+
+		var out map[string]interface{}
+		_ = json.NewDecoder(res.Body).Decode(&out)
+
+		return res.StatusCode, out
+	}
+
+	lot := `{"name":"Renamed"}`
+
+	// lots
+	code, _ := send(scoped, "POST", "/admin/lots", `{"name":"Mine"}`)
+	assert.Equal(t, fiber.StatusForbidden, code)
+
+	code, _ = send(scoped, "PUT", "/admin/lots/2", lot)
+	assert.Equal(t, fiber.StatusForbidden, code)
+
+	code, _ = send(scoped, "PUT", "/admin/lots/1", lot)
+	assert.Equal(t, fiber.StatusOK, code)
+
+	_, out := send(scoped, "GET", "/lots", "")
+	assert.Len(t, out["lots"], 1)
+
+	_, out = send(global, "GET", "/lots", "")
+	assert.Len(t, out["lots"], 2)
+
+	// webhooks, those of every lot are out of reach too
+	code, _ = send(scoped, "POST", "/admin/webhooks", `{"url":"https://example.com/all","lot_id":0}`)
+	assert.Equal(t, fiber.StatusForbidden, code)
+
+	code, _ = send(scoped, "POST", "/admin/webhooks", `{"url":"https://example.com/main","lot_id":1}`)
+	assert.Equal(t, fiber.StatusCreated, code)
+
+	code, out = send(global, "POST", "/admin/webhooks", `{"url":"https://example.com/all","lot_id":0}`)
+	assert.Equal(t, fiber.StatusCreated, code)
+	all := fmt.Sprintf("/admin/webhooks/%v", out["webhook"].(map[string]interface{})["id"])
+
+	code, _ = send(scoped, "GET", all, "")
+	assert.Equal(t, fiber.StatusForbidden, code)
+
+	code, _ = send(scoped, "PATCH", all, `{"active":false}`)
+	assert.Equal(t, fiber.StatusForbidden, code)
+
+	code, _ = send(scoped, "DELETE", all, "")
+	assert.Equal(t, fiber.StatusForbidden, code)
+
+	code, _ = send(scoped, "POST", all+"/deliveries/1/replay", "")
+	assert.Equal(t, fiber.StatusForbidden, code)
+
+	_, out = send(scoped, "GET", "/admin/webhooks", "")
+	assert.Len(t, out["webhooks"], 1)
+
+	_, out = send(global, "GET", "/admin/webhooks", "")
+	assert.Len(t, out["webhooks"], 2)
+}
@@ -2,12 +2,12 @@ package handler
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/business/usecase/parking"
 
 	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
 )
@@ -22,16 +22,31 @@ var (
 // @Tags         Parking
 // @Accept       json
 // @Produce      json
-// @Param        vehicle_number query string true "Vehicle Number"
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        lot_id path int true "Lot ID"
+// @Param        vehicle_number query string false "Vehicle Number"
+// @Param        ticket_id query string false "Ticket ID returned by park"
 // @Success      200 {object} handler.SearchVehicleResponse
 // @Failure      400 {object} handler.ErrorResponse
-// @Router       /vehicle/search [get]
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Router       /lots/{lot_id}/vehicle/search [get]
 func (e *rest) SearchVehicle(c *fiber.Ctx) error {
 
-	vehicleNumber := c.Query("vehicle_number")
+	var (
+		vehicleNumber = c.Query("vehicle_number")
+		ticketID      = c.Query("ticket_id")
+		ctx           = c.Locals("ctx").(context.Context)
+	)
+
+	if vehicleNumber == "" && ticketID == "" {
+		return e.compileError(c, x.NewWithCode(http.StatusBadRequest, "vehicle_number or ticket_id is required"))
+	}
 
-	veh, err := e.uc.Parking.SearchVehicle(c.Context(), entity.SearchVehicle{
+	veh, err := e.uc.Parking.SearchVehicle(ctx, entity.SearchVehicle{
+		LotID:         lotID(c),
 		VehicleNumber: vehicleNumber,
+		TicketID:      ticketID,
 	})
 	if err != nil {
 		return e.compileError(c, err)
@@ -46,22 +61,28 @@ func (e *rest) SearchVehicle(c *fiber.Ctx) error {
 
 // AvailableSpot godoc
 // @Summary      Get available parking spots
-// @Description  Returns a list of available spots for a specific vehicle type
+// @Description  Returns the free spots a vehicle type would be parked in: spots of its own type, or larger ones when none is free and the lot allows fallback
 // @Tags         Parking
 // @Accept       json
 // @Produce      json
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        lot_id path int true "Lot ID"
 // @Param        vehicle_type query string true "Vehicle Type (M, B, A)"
 // @Success      200 {object} handler.AvailableSpotResponse
 // @Failure      400 {object} handler.ErrorResponse
-// @Router       /spot/available [get]
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Router       /lots/{lot_id}/spot/available [get]
 func (e *rest) AvailableSpot(c *fiber.Ctx) error {
 
 	var (
 		res         []ParkingSpotBrief
 		vehicleType = c.Query("vehicle_type")
+		ctx         = c.Locals("ctx").(context.Context)
 	)
 
-	spots, err := e.uc.Parking.AvailableSpot(c.Context(), entity.GetAvailablePark{
+	spots, err := e.uc.Parking.AvailableSpot(ctx, entity.GetAvailablePark{
+		LotID:       lotID(c),
 		VehicleType: entity.VehicleType(vehicleType),
 	})
 	if err != nil {
@@ -70,7 +91,7 @@ func (e *rest) AvailableSpot(c *fiber.Ctx) error {
 
 	for _, s := range spots {
 		res = append(res, ParkingSpotBrief{
-			SpotID: fmt.Sprintf("%d-%d-%d", s.Floor, s.Row, s.Col),
+			SpotID: s.Position().String(),
 			Floor:  s.Floor,
 			Row:    s.Row,
 			Column: s.Col,
@@ -85,16 +106,56 @@ func (e *rest) AvailableSpot(c *fiber.Ctx) error {
 	})
 }
 
+// Occupancy godoc
+// @Summary      Occupancy of the lot
+// @Description  Counts the spots of the lot that are active, occupied, reserved and free, in total and per floor and spot type. Free spots are active, neither occupied nor reserved
+// @Description  The response carries an ETag, send it back in If-None-Match to get 304 Not Modified while nothing changed
+// @Tags         Parking
+// @Produce      json
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        lot_id path int true "Lot ID"
+// @Param        If-None-Match header string false "ETag of the last response"
+// @Success      200 {object} handler.OccupancyResponse
+// @Success      304 "Not Modified"
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Failure      404 {object} handler.ErrorResponse
+// @Router       /lots/{lot_id}/occupancy [get]
+func (e *rest) Occupancy(c *fiber.Ctx) error {
+
+	ctx := c.Locals("ctx").(context.Context)
+
+	occupancy, err := e.uc.Parking.Occupancy(ctx, lotID(c))
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(OccupancyResponse{
+		Success:   true,
+		Message:   "Done get occupancy !",
+		Occupancy: &occupancy,
+	})
+}
+
 // Park godoc
 // @Summary      Park a vehicle
-// @Description  Parks a vehicle into an available spot
+// @Description  Parks a vehicle into an available spot of the lot and returns the ticket for it, refused with 409 outside opening hours. With a reservation_code the vehicle checks in on the held spot, with a waitlist_code it claims the spot offered from the waitlist
+// @Description  When no spot is free and wait is true, the vehicle joins the waitlist of its type instead and 202 is returned with the queue position
 // @Tags         Parking
 // @Accept       json
 // @Produce      json
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        lot_id path int true "Lot ID"
 // @Param        body body handler.ParkRequest true "Vehicle Info"
 // @Success      200 {object} handler.ParkResponse
+// @Success      202 {object} handler.ParkResponse
 // @Failure      400 {object} handler.ErrorResponse
-// @Router       /vehicle/park [post]
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Failure      404 {object} handler.ErrorResponse
+// @Failure      409 {object} handler.ErrorResponse
+// @Failure      422 {object} handler.ErrorResponse
+// @Router       /lots/{lot_id}/vehicle/park [post]
 func (e *rest) Park(c *fiber.Ctx) error {
 
 	var (
@@ -109,10 +170,27 @@ func (e *rest) Park(c *fiber.Ctx) error {
 		return e.compileError(c, x.WrapWithCode(err, http.StatusBadRequest, "failed validation"))
 	}
 
-	err := e.uc.Parking.Park(ctx, entity.Park{
-		VehicleType:   entity.VehicleType(input.VehicleType),
-		VehicleNumber: input.VehicleNumber,
-	})
+	park := entity.Park{
+		LotID:           lotID(c),
+		VehicleType:     entity.VehicleType(input.VehicleType),
+		VehicleNumber:   input.VehicleNumber,
+		ReservationCode: input.ReservationCode,
+		WaitlistCode:    input.WaitlistCode,
+	}
+
+	ticket, err := e.uc.Parking.Park(ctx, park)
+	if err != nil && input.Wait && x.RootCause(err) == parking.ErrNoAvailableParking {
+		entry, err := e.uc.Waitlist.Join(ctx, park)
+		if err != nil {
+			return e.compileError(c, err)
+		}
+
+		return c.Status(fiber.StatusAccepted).JSON(ParkResponse{
+			Success:  true,
+			Message:  "No spot available, added to waitlist !",
+			Waitlist: &entry,
+		})
+	}
 	if err != nil {
 		return e.compileError(c, err)
 	}
@@ -120,22 +198,36 @@ func (e *rest) Park(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(ParkResponse{
 		Success: true,
 		Message: "Done parking vehicle !",
+		SpotID:  ticket.SpotID,
+		Ticket:  &ticket,
 	})
 }
 
 // UnPark godoc
 // @Summary      Unpark a vehicle
-// @Description  Removes a vehicle from the parking lot
+// @Description  Requests the exit of a vehicle, by ticket_id, spot_id or vehicle_number, and quotes the fee for the stay. The spot is freed once the fee is paid, right away when nothing is due
+// @Description  When several are given they must name the same session: 422 when they disagree, 409 when nothing is parked at spot_id
+// @Description  Calling it again while the fee is unpaid returns the same quote
 // @Tags         Parking
 // @Accept       json
 // @Produce      json
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        lot_id path int true "Lot ID"
 // @Param        body body handler.UnparkRequest true "Unpark Info"
 // @Success      200 {object} handler.UnparkResponse
 // @Failure      400 {object} handler.ErrorResponse
-// @Router       /vehicle/unpark [post]
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Failure      404 {object} handler.ErrorResponse
+// @Failure      409 {object} handler.ErrorResponse
+// @Failure      422 {object} handler.ErrorResponse
+// @Router       /lots/{lot_id}/vehicle/unpark [post]
 func (e *rest) UnPark(c *fiber.Ctx) error {
 
-	var input UnparkRequest
+	var (
+		input UnparkRequest
+		ctx   = c.Locals("ctx").(context.Context)
+	)
 	if err := c.BodyParser(&input); err != nil {
 		return e.compileError(c, x.WrapWithCode(err, http.StatusBadRequest, "invalid input"))
 	}
@@ -144,9 +236,64 @@ func (e *rest) UnPark(c *fiber.Ctx) error {
 		return e.compileError(c, x.WrapWithCode(err, http.StatusBadRequest, "failed validation"))
 	}
 
-	err := e.uc.Parking.Unpark(c.Context(), entity.UnPark{
+	receipt, err := e.uc.Parking.Unpark(ctx, entity.UnPark{
+		LotID:         lotID(c),
 		SpotID:        input.SpotID,
 		VehicleNumber: input.VehicleNumber,
+		TicketID:      input.TicketID,
+	})
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	message := "Done unparking vehicle !"
+	if receipt.Status == entity.SessionAwaitingPayment {
+		message = "Exit requested, awaiting payment !"
+	}
+
+	return c.Status(fiber.StatusOK).JSON(UnparkResponse{
+		Success: true,
+		Message: message,
+		Fee:     receipt.Fee,
+		Receipt: &receipt,
+	})
+}
+
+// ExitOverride godoc
+// @Summary      Let a vehicle out without full payment
+// @Description  Closes a session awaiting payment, recording the outstanding amount as an operator override in the ledger
+// @Tags         Parking
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        lot_id path int true "Lot ID"
+// @Param        body body handler.ExitOverrideRequest true "Override Info"
+// @Success      200 {object} handler.UnparkResponse
+// @Failure      400 {object} handler.ErrorResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Failure      409 {object} handler.ErrorResponse
+// @Router       /lots/{lot_id}/vehicle/exit/override [post]
+func (e *rest) ExitOverride(c *fiber.Ctx) error {
+
+	var (
+		input ExitOverrideRequest
+		ctx   = c.Locals("ctx").(context.Context)
+	)
+	if err := c.BodyParser(&input); err != nil {
+		return e.compileError(c, x.WrapWithCode(err, http.StatusBadRequest, "invalid input"))
+	}
+
+	if err := validate.Struct(input); err != nil {
+		return e.compileError(c, x.WrapWithCode(err, http.StatusBadRequest, "failed validation"))
+	}
+
+	receipt, err := e.uc.Parking.AuthorizeExit(ctx, entity.AuthorizeExit{
+		LotID:    lotID(c),
+		TicketID: input.TicketID,
+		Override: true,
+		Operator: input.Operator,
+		Reason:   input.Reason,
 	})
 	if err != nil {
 		return e.compileError(c, err)
@@ -155,5 +302,7 @@ func (e *rest) UnPark(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(UnparkResponse{
 		Success: true,
 		Message: "Done unparking vehicle !",
+		Fee:     receipt.Fee,
+		Receipt: &receipt,
 	})
 }
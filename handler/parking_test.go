@@ -0,0 +1,74 @@
+package handler_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/zuhrulumam/go-parking-lot/business/domain"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/business/usecase"
+	"github.com/zuhrulumam/go-parking-lot/handler"
+	"github.com/zuhrulumam/go-parking-lot/pkg/middlewares"
+	"github.com/zuhrulumam/go-parking-lot/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+)
+
+func TestTraceReachesUsecase(t *testing.T) {
+	_, err := tracing.Init(context.Background(), tracing.ExporterNone, "test")
+	assert.NoError(t, err)
+
+	rec := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	defer otel.SetTracerProvider(prev)
+
+	dom := domain.Init(domain.Option{
+		Store:         domain.StoreMemory,
+		Log:           zap.NewNop(),
+		MemoryLots:    []entity.Lot{{ID: 1, Name: "Main"}},
+		MemorySpots:   []entity.ParkingSpot{{LotID: 1, Floor: 1, Row: 1, Col: 1, Type: "M", Active: true}},
+		MemoryTariffs: []entity.Tariff{{LotID: 1, VehicleType: "M"}},
+	})
+
+	app := fiber.New()
+	app.Use(middlewares.RequestContextMiddleware(zap.NewNop()))
+	handler.Init(handler.Option{
+		Uc:           usecase.Init(dom, usecase.Option{}),
+		App:          app,
+		Log:          zap.NewNop(),
+		AuthDisabled: true,
+	})
+
+	send := func(path, body string) {
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+		res, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, res.StatusCode)
+	}
+
+	send("/lots/1/vehicle/park", `{"vehicle_number":"B1XYZ","vehicle_type":"M"}`)
+	send("/lots/1/vehicle/unpark", `{"vehicle_number":"B1XYZ"}`)
+
+	// the usecase spans are children of the request, not traces of their own
+	found := map[string]bool{}
+	for _, s := range rec.Ended() {
+		if s.Name() != "parking.Park" && s.Name() != "parking.Unpark" {
+			continue
+		}
+
+		found[s.Name()] = true
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", s.SpanContext().TraceID().String(), s.Name())
+		assert.True(t, s.Parent().IsValid(), s.Name())
+	}
+	assert.Equal(t, map[string]bool{"parking.Park": true, "parking.Unpark": true}, found)
+}
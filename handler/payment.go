@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+// Pay godoc
+// @Summary      Pay for a parking session
+// @Description  Charges the provider and records the payment against a session awaiting payment. Partial payments are allowed, the payment settling the fee frees the spot
+// @Description  The card provider is simulated, sources ending in 0002 are declined
+// @Tags         Payment
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        lot_id path int true "Lot ID"
+// @Param        body body handler.PayRequest true "Payment Info"
+// @Success      200 {object} handler.PaymentResponse
+// @Failure      400 {object} handler.ErrorResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      402 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Failure      409 {object} handler.ErrorResponse
+// @Failure      422 {object} handler.ErrorResponse
+// @Router       /lots/{lot_id}/payments [post]
+func (e *rest) Pay(c *fiber.Ctx) error {
+
+	var (
+		input PayRequest
+		ctx   = c.Locals("ctx").(context.Context)
+	)
+	if err := c.BodyParser(&input); err != nil {
+		return e.compileError(c, x.WrapWithCode(err, http.StatusBadRequest, "invalid input"))
+	}
+
+	if err := validate.Struct(input); err != nil {
+		return e.compileError(c, x.WrapWithCode(err, http.StatusBadRequest, "failed validation"))
+	}
+
+	result, err := e.uc.Payment.Pay(ctx, entity.Pay{
+		LotID:    lotID(c),
+		TicketID: input.TicketID,
+		Amount:   input.Amount,
+		Provider: input.Provider,
+		Source:   input.Source,
+	})
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(PaymentResponse{
+		Success: true,
+		Message: "Done recording payment !",
+		Payment: &result.Payment,
+		Receipt: &result.Receipt,
+	})
+}
+
+// Refund godoc
+// @Summary      Refund a payment
+// @Description  Refunds part or all of a succeeded charge, up to what has not been refunded yet
+// @Tags         Payment
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        lot_id path int true "Lot ID"
+// @Param        id path int true "Payment ID"
+// @Param        body body handler.RefundRequest true "Refund Info"
+// @Success      200 {object} handler.PaymentResponse
+// @Failure      400 {object} handler.ErrorResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Failure      404 {object} handler.ErrorResponse
+// @Failure      422 {object} handler.ErrorResponse
+// @Router       /lots/{lot_id}/payments/{id}/refund [post]
+func (e *rest) Refund(c *fiber.Ctx) error {
+
+	var (
+		input RefundRequest
+		ctx   = c.Locals("ctx").(context.Context)
+	)
+
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return e.compileError(c, x.NewWithCode(http.StatusBadRequest, "invalid payment id"))
+	}
+
+	if err := c.BodyParser(&input); err != nil {
+		return e.compileError(c, x.WrapWithCode(err, http.StatusBadRequest, "invalid input"))
+	}
+
+	if err := validate.Struct(input); err != nil {
+		return e.compileError(c, x.WrapWithCode(err, http.StatusBadRequest, "failed validation"))
+	}
+
+	refund, err := e.uc.Payment.Refund(ctx, entity.Refund{
+		LotID:     lotID(c),
+		PaymentID: uint(id),
+		Amount:    input.Amount,
+		Reason:    input.Reason,
+	})
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(PaymentResponse{
+		Success: true,
+		Message: "Done refunding payment !",
+		Payment: &refund,
+	})
+}
+
+// GetLedger godoc
+// @Summary      Payment ledger of a ticket
+// @Description  Returns the fee, amount paid and due, and every charge, refund and override of a parking session
+// @Tags         Payment
+// @Produce      json
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        lot_id path int true "Lot ID"
+// @Param        ticket_id query string true "Ticket ID"
+// @Success      200 {object} handler.LedgerResponse
+// @Failure      400 {object} handler.ErrorResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Failure      404 {object} handler.ErrorResponse
+// @Router       /lots/{lot_id}/payments [get]
+func (e *rest) GetLedger(c *fiber.Ctx) error {
+
+	ctx := c.Locals("ctx").(context.Context)
+
+	ledger, err := e.uc.Payment.GetLedger(ctx, entity.GetPayments{
+		LotID:    lotID(c),
+		TicketID: utils.CopyString(c.Query("ticket_id")),
+	})
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(LedgerResponse{
+		Success: true,
+		Message: "Done get ledger !",
+		Ledger:  &ledger,
+	})
+}
@@ -1,11 +1,113 @@
 package handler
 
+import "time"
+
+type LotRequest struct {
+	Name       string `json:"name" validate:"required"`
+	Allocation string `json:"allocation"`
+	OpensAt    string `json:"opens_at"`
+	ClosesAt   string `json:"closes_at"`
+	Timezone   string `json:"timezone"`
+	Fallback   bool   `json:"fallback"`
+	Reserve    string `json:"reserve"`
+}
+
 type ParkRequest struct {
-	VehicleType   string `json:"vehicle_type" validate:"required,oneof=M B A"`
-	VehicleNumber string `json:"vehicle_number" validate:"required"`
+	VehicleType     string `json:"vehicle_type" validate:"required,oneof=M B A"`
+	VehicleNumber   string `json:"vehicle_number" validate:"required"`
+	ReservationCode string `json:"reservation_code"`
+	WaitlistCode    string `json:"waitlist_code"`
+	Wait            bool   `json:"wait"`
 }
 
 type UnparkRequest struct {
 	SpotID        string `json:"spot_id"`
-	VehicleNumber string `json:"vehicle_number" validate:"required"`
+	VehicleNumber string `json:"vehicle_number" validate:"required_without_all=TicketID SpotID"`
+	TicketID      string `json:"ticket_id"`
+}
+
+type TariffRequest struct {
+	FirstHourPrice     int64 `json:"first_hour_price" validate:"gte=0"`
+	HourlyPrice        int64 `json:"hourly_price" validate:"gte=0"`
+	DailyCap           int64 `json:"daily_cap" validate:"gte=0"`
+	GracePeriodMinutes int   `json:"grace_period_minutes" validate:"gte=0"`
+	NightHourlyPrice   int64 `json:"night_hourly_price" validate:"gte=0"`
+	NightStartHour     int   `json:"night_start_hour" validate:"gte=0,lte=23"`
+	NightEndHour       int   `json:"night_end_hour" validate:"gte=0,lte=23"`
+	WeekendHourlyPrice int64 `json:"weekend_hourly_price" validate:"gte=0"`
+}
+
+type ReservationRequest struct {
+	SpotID        string     `json:"spot_id"`
+	VehicleType   string     `json:"vehicle_type" validate:"required_without=SpotID,omitempty,oneof=M B A"`
+	VehicleNumber string     `json:"vehicle_number"`
+	StartsAt      *time.Time `json:"starts_at"`
+	EndsAt        time.Time  `json:"ends_at" validate:"required"`
+}
+
+type PayRequest struct {
+	TicketID string `json:"ticket_id" validate:"required"`
+	Amount   int64  `json:"amount" validate:"gt=0"`
+	Provider string `json:"provider" validate:"required,oneof=cash card"`
+	Source   string `json:"source"`
+}
+
+type RefundRequest struct {
+	Amount int64  `json:"amount" validate:"gt=0"`
+	Reason string `json:"reason"`
+}
+
+type ExitOverrideRequest struct {
+	TicketID string `json:"ticket_id" validate:"required"`
+	Operator string `json:"operator" validate:"required"`
+	Reason   string `json:"reason" validate:"required"`
+}
+
+type SpotRequest struct {
+	Floor      int    `json:"floor" validate:"gte=1"`
+	Row        int    `json:"row" validate:"gte=1"`
+	Col        int    `json:"col" validate:"gte=1"`
+	Type       string `json:"type" validate:"required,oneof=M B A X"`
+	Active     *bool  `json:"active"` // defaults to true
+	EVCharger  bool   `json:"ev_charger"`
+	Accessible bool   `json:"accessible"`
+}
+
+type CreateSpotsRequest struct {
+	Spots []SpotRequest `json:"spots" validate:"required,min=1,max=1000,dive"`
+}
+
+type UpdateSpotRequest struct {
+	Type       string `json:"type" validate:"omitempty,oneof=M B A X"`
+	Active     *bool  `json:"active"`
+	EVCharger  *bool  `json:"ev_charger"`
+	Accessible *bool  `json:"accessible"`
+	Reason     string `json:"reason"`
+}
+
+type AddFloorRequest struct {
+	Floor int    `json:"floor" validate:"gte=1"`
+	Rows  int    `json:"rows" validate:"gte=1"`
+	Cols  int    `json:"cols" validate:"gte=1"`
+	Type  string `json:"type" validate:"required,oneof=M B A"`
+}
+
+type WebhookRequest struct {
+	URL        string   `json:"url" validate:"required,url"`
+	LotID      uint     `json:"lot_id"`
+	EventTypes []string `json:"event_types"`
+	Secret     string   `json:"secret"` // generated when empty
+}
+
+type UpdateWebhookRequest struct {
+	URL        *string   `json:"url" validate:"omitempty,url"`
+	LotID      *uint     `json:"lot_id"`
+	EventTypes *[]string `json:"event_types"`
+	Secret     *string   `json:"secret"`
+	Active     *bool     `json:"active"`
+}
+
+type LoginRequest struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
 }
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+// Reserve godoc
+// @Summary      Reserve a spot
+// @Description  Holds a specific spot (spot_id) or any spot of a vehicle type until ends_at. Park with the returned code between starts_at and ends_at, the hold is released when ends_at passes
+// @Tags         Reservation
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        lot_id path int true "Lot ID"
+// @Param        body body handler.ReservationRequest true "Reservation Info"
+// @Success      200 {object} handler.ReservationResponse
+// @Failure      400 {object} handler.ErrorResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Failure      409 {object} handler.ErrorResponse
+// @Router       /lots/{lot_id}/reservation [post]
+func (e *rest) Reserve(c *fiber.Ctx) error {
+
+	var (
+		input ReservationRequest
+		ctx   = c.Locals("ctx").(context.Context)
+	)
+	if err := c.BodyParser(&input); err != nil {
+		return e.compileError(c, x.WrapWithCode(err, http.StatusBadRequest, "invalid input"))
+	}
+
+	if err := validate.Struct(input); err != nil {
+		return e.compileError(c, x.WrapWithCode(err, http.StatusBadRequest, "failed validation"))
+	}
+
+	data := entity.Reserve{
+		LotID:         lotID(c),
+		SpotID:        input.SpotID,
+		VehicleType:   entity.VehicleType(input.VehicleType),
+		VehicleNumber: input.VehicleNumber,
+		EndsAt:        input.EndsAt,
+	}
+	if input.StartsAt != nil {
+		data.StartsAt = *input.StartsAt
+	}
+
+	reservation, err := e.uc.Reservation.Reserve(ctx, data)
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(ReservationResponse{
+		Success:     true,
+		Message:     "Done reserving spot !",
+		Reservation: &reservation,
+	})
+}
+
+// GetReservation godoc
+// @Summary      Get a reservation
+// @Description  Returns a reservation and its status by code
+// @Tags         Reservation
+// @Produce      json
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        lot_id path int true "Lot ID"
+// @Param        code path string true "Reservation Code"
+// @Success      200 {object} handler.ReservationResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Failure      404 {object} handler.ErrorResponse
+// @Router       /lots/{lot_id}/reservation/{code} [get]
+func (e *rest) GetReservation(c *fiber.Ctx) error {
+
+	ctx := c.Locals("ctx").(context.Context)
+
+	reservation, err := e.uc.Reservation.GetReservation(ctx, entity.GetReservations{
+		LotID: lotID(c),
+		Code:  utils.CopyString(c.Params("code")),
+	})
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(ReservationResponse{
+		Success:     true,
+		Message:     "Done get reservation !",
+		Reservation: &reservation,
+	})
+}
+
+// CancelReservation godoc
+// @Summary      Cancel a reservation
+// @Description  Releases the spot held by a reservation that has not been checked in yet
+// @Tags         Reservation
+// @Produce      json
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        lot_id path int true "Lot ID"
+// @Param        code path string true "Reservation Code"
+// @Success      200 {object} handler.ReservationResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Failure      404 {object} handler.ErrorResponse
+// @Failure      409 {object} handler.ErrorResponse
+// @Router       /lots/{lot_id}/reservation/{code} [delete]
+func (e *rest) CancelReservation(c *fiber.Ctx) error {
+
+	ctx := c.Locals("ctx").(context.Context)
+
+	reservation, err := e.uc.Reservation.CancelReservation(ctx, entity.GetReservations{
+		LotID: lotID(c),
+		Code:  utils.CopyString(c.Params("code")),
+	})
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(ReservationResponse{
+		Success:     true,
+		Message:     "Done cancelling reservation !",
+		Reservation: &reservation,
+	})
+}
@@ -2,15 +2,68 @@ package handler
 
 import "github.com/zuhrulumam/go-parking-lot/business/entity"
 
+type LotsResponse struct {
+	Success bool         `json:"success"`
+	Message string       `json:"message,omitempty"`
+	Lots    []entity.Lot `json:"lots"`
+}
+
+type LotResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message,omitempty"`
+	Lot     *entity.Lot `json:"lot,omitempty"`
+}
+
 type ParkResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"message,omitempty"`
-	SpotID  string `json:"spot_id,omitempty"`
+	Success  bool                  `json:"success"`
+	Message  string                `json:"message,omitempty"`
+	SpotID   string                `json:"spot_id,omitempty"`
+	Ticket   *entity.Ticket        `json:"ticket,omitempty"`
+	Waitlist *entity.WaitlistEntry `json:"waitlist,omitempty"`
+}
+
+type WaitlistResponse struct {
+	Success  bool                  `json:"success"`
+	Message  string                `json:"message,omitempty"`
+	Waitlist *entity.WaitlistEntry `json:"waitlist,omitempty"`
 }
 
 type UnparkResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"message,omitempty"`
+	Success bool            `json:"success"`
+	Message string          `json:"message,omitempty"`
+	Fee     int64           `json:"fee"`
+	Receipt *entity.Receipt `json:"receipt,omitempty"`
+}
+
+type ReservationResponse struct {
+	Success     bool                `json:"success"`
+	Message     string              `json:"message,omitempty"`
+	Reservation *entity.Reservation `json:"reservation,omitempty"`
+}
+
+type PaymentResponse struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message,omitempty"`
+	Payment *entity.Payment `json:"payment,omitempty"`
+	Receipt *entity.Receipt `json:"receipt,omitempty"`
+}
+
+type LedgerResponse struct {
+	Success bool           `json:"success"`
+	Message string         `json:"message,omitempty"`
+	Ledger  *entity.Ledger `json:"ledger,omitempty"`
+}
+
+type TariffsResponse struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message,omitempty"`
+	Tariffs []entity.Tariff `json:"tariffs"`
+}
+
+type TariffResponse struct {
+	Success bool           `json:"success"`
+	Message string         `json:"message,omitempty"`
+	Tariff  *entity.Tariff `json:"tariff,omitempty"`
 }
 
 type AvailableSpotResponse struct {
@@ -33,8 +86,85 @@ type SearchVehicleResponse struct {
 	Vehicle *entity.Vehicle `json:"vehicle,omitempty"`
 }
 
+type SpotsResponse struct {
+	Success bool                 `json:"success"`
+	Message string               `json:"message,omitempty"`
+	Spots   []entity.ParkingSpot `json:"spots"`
+}
+
+type SpotResponse struct {
+	Success bool                `json:"success"`
+	Message string              `json:"message,omitempty"`
+	Spot    *entity.ParkingSpot `json:"spot,omitempty"`
+}
+
+type RemoveFloorResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message,omitempty"`
+	Floor   int    `json:"floor"`
+	Removed int    `json:"removed"`
+}
+
+type AuditLogsResponse struct {
+	Success   bool              `json:"success"`
+	Message   string            `json:"message,omitempty"`
+	AuditLogs []entity.AuditLog `json:"audit_logs"`
+}
+
 type ErrorResponse struct {
 	Success    bool   `json:"success"`
 	HumanError string `json:"human_error"`
 	DebugError string `json:"debug_error"`
 }
+
+type SessionsResponse struct {
+	Success    bool             `json:"success"`
+	Message    string           `json:"message,omitempty"`
+	Sessions   []entity.Vehicle `json:"sessions"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+type OccupancyResponse struct {
+	Success   bool              `json:"success"`
+	Message   string            `json:"message,omitempty"`
+	Occupancy *entity.Occupancy `json:"occupancy,omitempty"`
+}
+
+type WebhookResponse struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message,omitempty"`
+	Webhook *entity.Webhook `json:"webhook,omitempty"`
+
+	// Secret is only returned when the webhook is created.
+	Secret string `json:"secret,omitempty"`
+}
+
+type WebhooksResponse struct {
+	Success  bool             `json:"success"`
+	Message  string           `json:"message,omitempty"`
+	Webhooks []entity.Webhook `json:"webhooks"`
+}
+
+type DeliveriesResponse struct {
+	Success    bool                   `json:"success"`
+	Message    string                 `json:"message,omitempty"`
+	Deliveries []entity.EventDelivery `json:"deliveries"`
+}
+
+type DeliveryResponse struct {
+	Success  bool                  `json:"success"`
+	Message  string                `json:"message,omitempty"`
+	Delivery *entity.EventDelivery `json:"delivery,omitempty"`
+}
+
+type TokenResponse struct {
+	Success bool          `json:"success"`
+	Message string        `json:"message,omitempty"`
+	Token   *entity.Token `json:"token,omitempty"`
+}
+
+type PrincipalResponse struct {
+	Success   bool              `json:"success"`
+	Message   string            `json:"message,omitempty"`
+	Principal *entity.Principal `json:"principal,omitempty"`
+}
@@ -1,10 +1,18 @@
 package handler
 
 import (
+	"context"
+	"net/http"
+
+	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/gofiber/fiber/v2/middleware/etag"
 	"github.com/gofiber/swagger"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
 	"github.com/zuhrulumam/go-parking-lot/business/usecase"
 	_ "github.com/zuhrulumam/go-parking-lot/docs" // replace with your module
+	"github.com/zuhrulumam/go-parking-lot/pkg/health"
 	"go.uber.org/zap"
 )
 
@@ -15,19 +23,66 @@ type Option struct {
 	Uc  *usecase.Usecase
 	App *fiber.App
 	Log *zap.Logger
+
+	// AdminToken is the shared admin token still accepted in the
+	// X-Admin-Token header, nobody is admin by it while it is empty.
+	AdminToken string
+
+	// Authenticators are tried in order on every request, nil are the API
+	// keys and tokens of Uc.Auth then AdminToken.
+	Authenticators []Authenticator
+
+	// AuthDisabled lets every request in as admin, for local development.
+	AuthDisabled bool
+
+	// Metrics is served on /metrics without credentials, for the scraper,
+	// when set.
+	Metrics http.Handler
+
+	// Context ends the occupancy streams when done, so they don't hold up
+	// a shutdown. context.Background when nil.
+	Context context.Context
+
+	// Health checks the readiness of /readyz, always ready when nil.
+	Health *health.Health
 }
 
 type rest struct {
-	uc  *usecase.Usecase
-	app *fiber.App
-	log *zap.Logger
+	uc             *usecase.Usecase
+	app            *fiber.App
+	log            *zap.Logger
+	authenticators []Authenticator
+	authDisabled   bool
+	metrics        http.Handler
+	ctx            context.Context
+	health         *health.Health
 }
 
 func Init(opt Option) Rest {
+	if opt.Context == nil {
+		opt.Context = context.Background()
+	}
+
+	if opt.Health == nil {
+		opt.Health = health.New(0)
+	}
+
+	if opt.Authenticators == nil {
+		opt.Authenticators = []Authenticator{
+			CredentialAuthenticator(opt.Uc.Auth),
+			AdminTokenAuthenticator(opt.AdminToken),
+		}
+	}
+
 	e := &rest{
-		uc:  opt.Uc,
-		app: opt.App,
-		log: opt.Log,
+		uc:             opt.Uc,
+		app:            opt.App,
+		log:            opt.Log,
+		authenticators: opt.Authenticators,
+		authDisabled:   opt.AuthDisabled,
+		metrics:        opt.Metrics,
+		ctx:            opt.Context,
+		health:         opt.Health,
 	}
 
 	e.Serve()
@@ -39,13 +94,94 @@ func (r rest) Serve() {
 	// swagger
 	r.app.Get("/swagger/*", swagger.HandlerDefault)
 
+	r.app.Post("/auth/login", r.Login)
+
+	// probes
+	r.app.Get("/healthz", r.Healthz)
+	r.app.Get("/readyz", r.Readyz)
+
+	if r.metrics != nil {
+		r.app.Get("/metrics", adaptor.HTTPHandler(r.metrics))
+	}
+
+	// everything below needs credentials, and one of the roles per route
+	r.app.Use(r.authenticate)
+
+	var (
+		read  = r.allow(entity.Roles...)
+		gate  = r.allow(entity.RoleGate, entity.RoleAttendant, entity.RoleAdmin)
+		staff = r.allow(entity.RoleAttendant, entity.RoleAdmin)
+		admin = r.allow(entity.RoleAdmin)
+	)
+
+	r.app.Get("/auth/me", read, r.Me)
+
+	// lots
+	r.app.Get("/lots", read, r.GetLots)
+	r.app.Post("/admin/lots", admin, r.allLots, r.CreateLot)
+	r.app.Put("/admin/lots/:lot_id", r.withLot, admin, r.UpdateLot)
+
+	// webhook subscriptions, a key of one lot only sees that lot's
+	webhooks := r.app.Group("/admin/webhooks", admin)
+	webhooks.Post("", r.CreateWebhook)
+	webhooks.Get("", r.GetWebhooks)
+	webhooks.Get("/:id", r.GetWebhook)
+	webhooks.Patch("/:id", r.UpdateWebhook)
+	webhooks.Delete("/:id", r.DeleteWebhook)
+	webhooks.Get("/:id/deliveries", r.GetWebhookDeliveries)
+	webhooks.Post("/:id/deliveries/:delivery_id/replay", r.ReplayDelivery)
+
+	// everything else belongs to one lot
+	lot := r.app.Group("/lots/:lot_id", r.withLot)
+	lot.Get("", read, r.GetLot)
+
 	// search vehicle
-	r.app.Get("/vehicle/search", r.SearchVehicle)
+	lot.Get("/vehicle/search", read, r.SearchVehicle)
 
 	// available spots
-	r.app.Get("/spot/available", r.AvailableSpot)
+	lot.Get("/spot/available", read, r.AvailableSpot)
+
+	// occupancy, the ETag lets display boards poll it cheaply
+	lot.Get("/occupancy", read, etag.New(), r.Occupancy)
+
+	// live occupancy, a snapshot then every change
+	lot.Get("/occupancy/stream", read, r.OccupancyStream)
+	lot.Get("/occupancy/ws", read, r.occupancyUpgrade, websocket.New(r.OccupancySocket))
+
+	lot.Post("/vehicle/park", gate, r.Park)
+
+	lot.Post("/vehicle/unpark", gate, r.UnPark)
+
+	lot.Post("/vehicle/exit/override", staff, r.ExitOverride)
+
+	// session history
+	lot.Get("/vehicle/:vehicle_number/sessions", read, r.GetVehicleSessions)
+
+	// reservations
+	lot.Post("/reservation", staff, r.Reserve)
+	lot.Get("/reservation/:code", read, r.GetReservation)
+	lot.Delete("/reservation/:code", staff, r.CancelReservation)
+
+	// waitlist
+	lot.Get("/waitlist/:code", read, r.GetWaitlistEntry)
+	lot.Delete("/waitlist/:code", staff, r.LeaveWaitlist)
+
+	// payments, taken at the exit by the gates
+	lot.Get("/payments", read, r.GetLedger)
+	lot.Post("/payments", gate, r.Pay)
+	lot.Post("/payments/:id/refund", staff, r.Refund)
 
-	r.app.Post("/vehicle/park", r.Park)
+	// tariffs
+	lot.Get("/tariffs", read, r.GetTariffs)
+	lot.Put("/tariffs/:vehicle_type", admin, r.UpdateTariff)
 
-	r.app.Post("/vehicle/unpark", r.UnPark)
+	// layout administration
+	layout := lot.Group("/admin", admin)
+	layout.Get("/spots", r.GetSpots)
+	layout.Post("/spots", r.CreateSpots)
+	layout.Patch("/spots/:spot_id", r.UpdateSpot)
+	layout.Post("/floors", r.AddFloor)
+	layout.Delete("/floors/:floor", r.RemoveFloor)
+	layout.Get("/audit", r.GetAuditLogs)
+	layout.Get("/sessions", r.GetSessions)
 }
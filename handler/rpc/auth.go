@@ -0,0 +1,154 @@
+package rpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	authUc "github.com/zuhrulumam/go-parking-lot/business/usecase/auth"
+	"github.com/zuhrulumam/go-parking-lot/pkg/ctxkeys"
+	"github.com/zuhrulumam/go-parking-lot/pkg/errors"
+	parkingv1 "github.com/zuhrulumam/go-parking-lot/proto/parking/v1"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// AuthOption configures the interceptors authenticating the calls the way
+// the REST API does.
+type AuthOption struct {
+	Auth authUc.UsecaseItf
+	Log  *zap.Logger
+
+	// AdminToken is the shared admin token of the x-admin-token metadata.
+	AdminToken string
+
+	// Disabled lets every call in as admin, for local development.
+	Disabled bool
+}
+
+var gateRoles = []string{entity.RoleGate, entity.RoleAttendant, entity.RoleAdmin}
+
+// methodRoles are the roles that may call each method. A method missing
+// is refused, so a method added later isn't served without credentials.
+var methodRoles = map[string][]string{
+	parkingv1.ParkingService_Park_FullMethodName:           gateRoles,
+	parkingv1.ParkingService_Unpark_FullMethodName:         gateRoles,
+	parkingv1.ParkingService_AvailableSpot_FullMethodName:  entity.Roles,
+	parkingv1.ParkingService_SearchVehicle_FullMethodName:  entity.Roles,
+	parkingv1.ParkingService_WatchOccupancy_FullMethodName: entity.Roles,
+}
+
+// openServices are served without credentials, for the probes and
+// grpcurl.
+var openServices = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.v1.ServerReflection/",
+	"/grpc.reflection.v1alpha.ServerReflection/",
+}
+
+// UnaryAuthInterceptor authenticates the API key or token of the
+// authorization bearer or x-api-key metadata. It goes after the request
+// context interceptor.
+func UnaryAuthInterceptor(opt AuthOption) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, opt, info.FullMethod)
+		if err != nil {
+			return nil, toStatus(ctx, opt.Log, err)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthInterceptor is UnaryAuthInterceptor for streaming calls.
+func StreamAuthInterceptor(opt AuthOption) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), opt, info.FullMethod)
+		if err != nil {
+			return toStatus(ctx, opt.Log, err)
+		}
+
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// contextStream replaces the context of a server stream.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+func authenticate(ctx context.Context, opt AuthOption, method string) (context.Context, error) {
+	for _, prefix := range openServices {
+		if strings.HasPrefix(method, prefix) {
+			return ctx, nil
+		}
+	}
+
+	roles, ok := methodRoles[method]
+	if !ok {
+		return ctx, errors.NewWithCode(http.StatusForbidden, "%s has no roles that may call it", method)
+	}
+
+	p, err := principalOf(ctx, opt)
+	if err != nil {
+		return ctx, err
+	}
+
+	if !p.Can(roles...) {
+		return ctx, errors.NewWithCode(http.StatusForbidden, "role %q may not call %s", p.Role, method)
+	}
+
+	return context.WithValue(ctx, ctxkeys.CtxKeyPrincipal, p), nil
+}
+
+func principalOf(ctx context.Context, opt AuthOption) (entity.Principal, error) {
+	if opt.Disabled {
+		return entity.Principal{Subject: "admin", Role: entity.RoleAdmin, Method: entity.AuthDisabled}, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	credential := first(md, "x-api-key")
+	if h := first(md, "authorization"); h != "" {
+		scheme, token, _ := strings.Cut(h, " ")
+		if !strings.EqualFold(scheme, "Bearer") {
+			return entity.Principal{}, errors.NewWithCode(http.StatusUnauthorized, "authorization must be a bearer token")
+		}
+		credential = strings.TrimSpace(token)
+	}
+
+	if credential != "" {
+		return opt.Auth.Authenticate(ctx, credential)
+	}
+
+	if token := first(md, "x-admin-token"); token != "" {
+		if opt.AdminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(opt.AdminToken)) != 1 {
+			return entity.Principal{}, errors.NewWithCode(http.StatusUnauthorized, "invalid admin token")
+		}
+
+		actor := first(md, "x-admin-actor")
+		if actor == "" {
+			actor = "admin"
+		}
+
+		return entity.Principal{Subject: actor, Role: entity.RoleAdmin, Method: entity.AuthAdminToken}, nil
+	}
+
+	return entity.Principal{}, errors.NewWithCode(http.StatusUnauthorized, "missing credentials")
+}
+
+func first(md metadata.MD, key string) string {
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+
+	return ""
+}
@@ -0,0 +1,219 @@
+package rpc
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/business/usecase/parking"
+	"github.com/zuhrulumam/go-parking-lot/pkg/ctxkeys"
+	"github.com/zuhrulumam/go-parking-lot/pkg/errors"
+	"github.com/zuhrulumam/go-parking-lot/pkg/logger"
+	parkingv1 "github.com/zuhrulumam/go-parking-lot/proto/parking/v1"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// compileError logs err and turns its code into a gRPC status, with the
+// message of the outermost error.
+func (r *rpc) compileError(ctx context.Context, err error) error {
+	return toStatus(ctx, r.log, err)
+}
+
+func toStatus(ctx context.Context, log *zap.Logger, err error) error {
+	logger.LogWithCtx(ctx, log, err.Error())
+
+	msg, _, _ := strings.Cut(err.Error(), "\n")
+
+	return status.Error(statusCode(err), msg)
+}
+
+// statusCode maps the HTTP status carried by err to a gRPC code.
+func statusCode(err error) codes.Code {
+	if errors.RootCause(err) == parking.ErrNoAvailableParking {
+		return codes.ResourceExhausted
+	}
+
+	switch errors.ErrCode(err) {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusPaymentRequired, http.StatusConflict:
+		return codes.FailedPrecondition
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
+
+func invalid(msg string) error {
+	return errors.NewWithCode(http.StatusBadRequest, msg)
+}
+
+// lotID checks the lot_id of a request, like the withLot of the REST
+// routes.
+func lotID(ctx context.Context, id uint32) (uint, error) {
+	if id == 0 {
+		return 0, invalid("lot_id is required")
+	}
+
+	// a key of one lot reaches no other
+	if p, ok := ctx.Value(ctxkeys.CtxKeyPrincipal).(entity.Principal); ok && !p.Reaches(uint(id)) {
+		return 0, errors.NewWithCode(http.StatusForbidden, "%s may not reach lot %d", p.Subject, id)
+	}
+
+	return uint(id), nil
+}
+
+var vehicleTypes = map[parkingv1.VehicleType]entity.VehicleType{
+	parkingv1.VehicleType_VEHICLE_TYPE_BICYCLE:    entity.Bicycle,
+	parkingv1.VehicleType_VEHICLE_TYPE_MOTORCYCLE: entity.Motorcycle,
+	parkingv1.VehicleType_VEHICLE_TYPE_AUTOMOBILE: entity.Automobile,
+}
+
+func toVehicleType(t parkingv1.VehicleType) (entity.VehicleType, error) {
+	v, ok := vehicleTypes[t]
+	if !ok {
+		return "", invalid("vehicle_type is required")
+	}
+
+	return v, nil
+}
+
+func fromVehicleType[T ~string](t T) parkingv1.VehicleType {
+	for k, v := range vehicleTypes {
+		if string(v) == string(t) {
+			return k
+		}
+	}
+
+	return parkingv1.VehicleType_VEHICLE_TYPE_UNSPECIFIED
+}
+
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+
+	return timestamppb.New(*t)
+}
+
+func toTicket(t entity.Ticket) *parkingv1.Ticket {
+	return &parkingv1.Ticket{
+		TicketId:      t.TicketID,
+		LotId:         uint32(t.LotID),
+		SpotId:        t.SpotID,
+		Floor:         int32(t.Floor),
+		Row:           int32(t.Row),
+		Col:           int32(t.Col),
+		VehicleType:   fromVehicleType(t.VehicleType),
+		SpotType:      t.SpotType,
+		VehicleNumber: t.VehicleNumber,
+		ParkedAt:      timestamp(&t.ParkedAt),
+	}
+}
+
+func toReceipt(r entity.Receipt) *parkingv1.Receipt {
+	return &parkingv1.Receipt{
+		TicketId:      r.TicketID,
+		LotId:         uint32(r.LotID),
+		VehicleNumber: r.VehicleNumber,
+		VehicleType:   fromVehicleType(r.VehicleType),
+		SpotId:        r.SpotID,
+		ParkedAt:      timestamp(&r.ParkedAt),
+		UnparkedAt:    timestamp(r.UnparkedAt),
+		Fee:           r.Fee,
+		AmountPaid:    r.AmountPaid,
+		AmountDue:     r.AmountDue,
+		Status:        r.Status,
+	}
+}
+
+func toSpot(s *entity.ParkingSpot) *parkingv1.Spot {
+	if s == nil {
+		return nil
+	}
+
+	return &parkingv1.Spot{
+		SpotId:     s.Position().String(),
+		LotId:      uint32(s.LotID),
+		Floor:      int32(s.Floor),
+		Row:        int32(s.Row),
+		Col:        int32(s.Col),
+		Type:       s.Type,
+		Active:     s.Active,
+		Occupied:   s.Occupied,
+		Reserved:   s.Reserved,
+		EvCharger:  s.EVCharger,
+		Accessible: s.Accessible,
+	}
+}
+
+func toVehicle(v entity.Vehicle) *parkingv1.Vehicle {
+	return &parkingv1.Vehicle{
+		Id:              uint32(v.ID),
+		LotId:           uint32(v.LotID),
+		VehicleNumber:   v.VehicleNumber,
+		VehicleType:     fromVehicleType(v.VehicleType),
+		SpotId:          v.SpotID,
+		TicketId:        v.TicketID,
+		ParkedAt:        timestamp(&v.ParkedAt),
+		UnparkedAt:      timestamp(v.UnparkedAt),
+		Fee:             v.Fee,
+		Status:          v.Status,
+		ExitRequestedAt: timestamp(v.ExitRequestedAt),
+	}
+}
+
+func toSpotCount(c entity.SpotCount) *parkingv1.SpotCount {
+	return &parkingv1.SpotCount{
+		Floor:    int32(c.Floor),
+		Type:     c.Type,
+		Total:    int32(c.Total),
+		Active:   int32(c.Active),
+		Occupied: int32(c.Occupied),
+		Reserved: int32(c.Reserved),
+		Free:     int32(c.Free),
+	}
+}
+
+func toSpotCounts(counts []entity.SpotCount) []*parkingv1.SpotCount {
+	result := make([]*parkingv1.SpotCount, 0, len(counts))
+	for _, c := range counts {
+		result = append(result, toSpotCount(c))
+	}
+
+	return result
+}
+
+func toOccupancyEvent(ev entity.OccupancyEvent) *parkingv1.OccupancyEvent {
+	result := &parkingv1.OccupancyEvent{Delta: toSpotCounts(ev.Delta)}
+
+	switch {
+	case ev.Snapshot != nil:
+		result.Event = &parkingv1.OccupancyEvent_Snapshot{Snapshot: &parkingv1.Occupancy{
+			LotId:  uint32(ev.Snapshot.LotID),
+			Total:  toSpotCount(ev.Snapshot.SpotCount),
+			Floors: toSpotCounts(ev.Snapshot.Floors),
+			Types:  toSpotCounts(ev.Snapshot.Types),
+		}}
+	case ev.Change != nil:
+		result.Event = &parkingv1.OccupancyEvent_Change{Change: &parkingv1.SpotChange{
+			LotId: uint32(ev.Change.LotID),
+			Old:   toSpot(ev.Change.Old),
+			New:   toSpot(ev.Change.New),
+		}}
+	}
+
+	return result
+}
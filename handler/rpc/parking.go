@@ -0,0 +1,143 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	parkingv1 "github.com/zuhrulumam/go-parking-lot/proto/parking/v1"
+)
+
+func (r *rpc) Park(ctx context.Context, req *parkingv1.ParkRequest) (*parkingv1.ParkResponse, error) {
+
+	lot, err := lotID(ctx, req.GetLotId())
+	if err != nil {
+		return nil, r.compileError(ctx, err)
+	}
+
+	vehicleType, err := toVehicleType(req.GetVehicleType())
+	if err != nil {
+		return nil, r.compileError(ctx, err)
+	}
+
+	if req.GetVehicleNumber() == "" {
+		return nil, r.compileError(ctx, invalid("vehicle_number is required"))
+	}
+
+	ticket, err := r.uc.Parking.Park(ctx, entity.Park{
+		LotID:           lot,
+		VehicleType:     vehicleType,
+		VehicleNumber:   req.GetVehicleNumber(),
+		ReservationCode: req.GetReservationCode(),
+		WaitlistCode:    req.GetWaitlistCode(),
+	})
+	if err != nil {
+		return nil, r.compileError(ctx, err)
+	}
+
+	return &parkingv1.ParkResponse{Ticket: toTicket(ticket)}, nil
+}
+
+func (r *rpc) Unpark(ctx context.Context, req *parkingv1.UnparkRequest) (*parkingv1.UnparkResponse, error) {
+
+	lot, err := lotID(ctx, req.GetLotId())
+	if err != nil {
+		return nil, r.compileError(ctx, err)
+	}
+
+	if req.GetTicketId() == "" && req.GetSpotId() == "" && req.GetVehicleNumber() == "" {
+		return nil, r.compileError(ctx, invalid("ticket_id, spot_id or vehicle_number is required"))
+	}
+
+	receipt, err := r.uc.Parking.Unpark(ctx, entity.UnPark{
+		LotID:         lot,
+		SpotID:        req.GetSpotId(),
+		VehicleNumber: req.GetVehicleNumber(),
+		TicketID:      req.GetTicketId(),
+	})
+	if err != nil {
+		return nil, r.compileError(ctx, err)
+	}
+
+	return &parkingv1.UnparkResponse{Receipt: toReceipt(receipt)}, nil
+}
+
+func (r *rpc) AvailableSpot(ctx context.Context, req *parkingv1.AvailableSpotRequest) (*parkingv1.AvailableSpotResponse, error) {
+
+	lot, err := lotID(ctx, req.GetLotId())
+	if err != nil {
+		return nil, r.compileError(ctx, err)
+	}
+
+	vehicleType, err := toVehicleType(req.GetVehicleType())
+	if err != nil {
+		return nil, r.compileError(ctx, err)
+	}
+
+	spots, err := r.uc.Parking.AvailableSpot(ctx, entity.GetAvailablePark{
+		LotID:       lot,
+		VehicleType: vehicleType,
+	})
+	if err != nil {
+		return nil, r.compileError(ctx, err)
+	}
+
+	res := &parkingv1.AvailableSpotResponse{Spots: make([]*parkingv1.Spot, 0, len(spots))}
+	for i := range spots {
+		res.Spots = append(res.Spots, toSpot(&spots[i]))
+	}
+
+	return res, nil
+}
+
+func (r *rpc) SearchVehicle(ctx context.Context, req *parkingv1.SearchVehicleRequest) (*parkingv1.SearchVehicleResponse, error) {
+
+	lot, err := lotID(ctx, req.GetLotId())
+	if err != nil {
+		return nil, r.compileError(ctx, err)
+	}
+
+	if req.GetVehicleNumber() == "" && req.GetTicketId() == "" {
+		return nil, r.compileError(ctx, invalid("vehicle_number or ticket_id is required"))
+	}
+
+	veh, err := r.uc.Parking.SearchVehicle(ctx, entity.SearchVehicle{
+		LotID:         lot,
+		VehicleNumber: req.GetVehicleNumber(),
+		TicketID:      req.GetTicketId(),
+	})
+	if err != nil {
+		return nil, r.compileError(ctx, err)
+	}
+
+	return &parkingv1.SearchVehicleResponse{Vehicle: toVehicle(veh)}, nil
+}
+
+// WatchOccupancy sends the occupancy events of the lot until the client
+// goes away.
+func (r *rpc) WatchOccupancy(req *parkingv1.WatchOccupancyRequest, stream parkingv1.ParkingService_WatchOccupancyServer) error {
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	// or the server stops
+	stop := context.AfterFunc(r.ctx, cancel)
+	defer stop()
+
+	lot, err := lotID(ctx, req.GetLotId())
+	if err != nil {
+		return r.compileError(ctx, err)
+	}
+
+	events, err := r.uc.Parking.WatchOccupancy(ctx, lot)
+	if err != nil {
+		return r.compileError(ctx, err)
+	}
+
+	for ev := range events {
+		if err := stream.Send(toOccupancyEvent(ev)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/zuhrulumam/go-parking-lot/business/usecase"
+	parkingv1 "github.com/zuhrulumam/go-parking-lot/proto/parking/v1"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+type RPC interface {
+	// Shutdown reports the server as not serving to the health checks.
+	Shutdown()
+}
+
+type Option struct {
+	Uc     *usecase.Usecase
+	Server *grpc.Server
+	Log    *zap.Logger
+
+	// Context ends the occupancy streams when done, so they don't hold up
+	// a graceful stop. context.Background when nil.
+	Context context.Context
+}
+
+type rpc struct {
+	parkingv1.UnimplementedParkingServiceServer
+
+	uc     *usecase.Usecase
+	log    *zap.Logger
+	health *health.Server
+	ctx    context.Context
+}
+
+// Init registers the parking service on the server, with the standard
+// health service and server reflection for grpcurl and the like.
+func Init(opt Option) RPC {
+	if opt.Context == nil {
+		opt.Context = context.Background()
+	}
+
+	r := &rpc{
+		uc:     opt.Uc,
+		log:    opt.Log,
+		health: health.NewServer(),
+		ctx:    opt.Context,
+	}
+
+	parkingv1.RegisterParkingServiceServer(opt.Server, r)
+
+	// "" is the server as a whole
+	r.health.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	r.health.SetServingStatus(parkingv1.ParkingService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(opt.Server, r.health)
+
+	reflection.Register(opt.Server)
+
+	return r
+}
+
+func (r *rpc) Shutdown() {
+	r.health.Shutdown()
+}
@@ -0,0 +1,237 @@
+package rpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zuhrulumam/go-parking-lot/business/domain"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/business/usecase"
+	"github.com/zuhrulumam/go-parking-lot/handler/rpc"
+	"github.com/zuhrulumam/go-parking-lot/pkg/middlewares"
+	parkingv1 "github.com/zuhrulumam/go-parking-lot/proto/parking/v1"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dial serves the API of an in-memory lot of one motorcycle spot.
+func dial(t *testing.T) *grpc.ClientConn {
+	conn, _ := dialWith(t, false)
+	return conn
+}
+
+// dialWith is dial, authenticating the calls when auth is set.
+func dialWith(t *testing.T, auth bool) (*grpc.ClientConn, *usecase.Usecase) {
+	dom := domain.Init(domain.Option{
+		Store:         domain.StoreMemory,
+		Log:           zap.NewNop(),
+		MemoryLots:    []entity.Lot{{ID: 1, Name: "Main"}, {ID: 2, Name: "Annex"}},
+		MemorySpots:   []entity.ParkingSpot{{LotID: 1, Floor: 1, Row: 1, Col: 1, Type: "M", Active: true}},
+		MemoryTariffs: []entity.Tariff{{LotID: 1, VehicleType: "M", FirstHourPrice: 2000, GracePeriodMinutes: 10}},
+	})
+
+	uc := usecase.Init(dom, usecase.Option{})
+
+	unary := []grpc.UnaryServerInterceptor{middlewares.UnaryRequestContextInterceptor(zap.NewNop())}
+	stream := []grpc.StreamServerInterceptor{middlewares.StreamRequestContextInterceptor(zap.NewNop())}
+	if auth {
+		opt := rpc.AuthOption{Auth: uc.Auth, Log: zap.NewNop(), AdminToken: "s3cret"}
+		unary = append(unary, rpc.UnaryAuthInterceptor(opt))
+		stream = append(stream, rpc.StreamAuthInterceptor(opt))
+	}
+
+	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(unary...), grpc.ChainStreamInterceptor(stream...))
+	rpc.Init(rpc.Option{Uc: uc, Server: srv, Log: zap.NewNop()})
+
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn, uc
+}
+
+func TestParkingService(t *testing.T) {
+	ctx := context.Background()
+	client := parkingv1.NewParkingServiceClient(dial(t))
+
+	spots, err := client.AvailableSpot(ctx, &parkingv1.AvailableSpotRequest{LotId: 1, VehicleType: parkingv1.VehicleType_VEHICLE_TYPE_MOTORCYCLE})
+	assert.NoError(t, err)
+	assert.Len(t, spots.GetSpots(), 1)
+	assert.Equal(t, "1-1-1-1", spots.GetSpots()[0].GetSpotId())
+
+	parked, err := client.Park(ctx, &parkingv1.ParkRequest{LotId: 1, VehicleType: parkingv1.VehicleType_VEHICLE_TYPE_MOTORCYCLE, VehicleNumber: "B1234XYZ"})
+	assert.NoError(t, err)
+	ticket := parked.GetTicket()
+	assert.Equal(t, "1-1-1-1", ticket.GetSpotId())
+	assert.Equal(t, parkingv1.VehicleType_VEHICLE_TYPE_MOTORCYCLE, ticket.GetVehicleType())
+	assert.WithinDuration(t, time.Now(), ticket.GetParkedAt().AsTime(), time.Minute)
+
+	found, err := client.SearchVehicle(ctx, &parkingv1.SearchVehicleRequest{LotId: 1, TicketId: ticket.GetTicketId()})
+	assert.NoError(t, err)
+	assert.Equal(t, "B1234XYZ", found.GetVehicle().GetVehicleNumber())
+	assert.Nil(t, found.GetVehicle().UnparkedAt)
+
+	unparked, err := client.Unpark(ctx, &parkingv1.UnparkRequest{LotId: 1, TicketId: ticket.GetTicketId()})
+	assert.NoError(t, err)
+	assert.Equal(t, ticket.GetTicketId(), unparked.GetReceipt().GetTicketId())
+	assert.NotNil(t, unparked.GetReceipt().GetUnparkedAt())
+}
+
+func TestParkingServiceErrors(t *testing.T) {
+	ctx := context.Background()
+	client := parkingv1.NewParkingServiceClient(dial(t))
+
+	park := func(plate string) error {
+		_, err := client.Park(ctx, &parkingv1.ParkRequest{LotId: 1, VehicleType: parkingv1.VehicleType_VEHICLE_TYPE_MOTORCYCLE, VehicleNumber: plate})
+		return err
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		code codes.Code
+	}{
+		{"no lot", func() error {
+			_, err := client.SearchVehicle(ctx, &parkingv1.SearchVehicleRequest{TicketId: "t1"})
+			return err
+		}(), codes.InvalidArgument},
+		{"no vehicle type", func() error {
+			_, err := client.Park(ctx, &parkingv1.ParkRequest{LotId: 1, VehicleNumber: "B1"})
+			return err
+		}(), codes.InvalidArgument},
+		{"unknown lot", func() error {
+			_, err := client.AvailableSpot(ctx, &parkingv1.AvailableSpotRequest{LotId: 9, VehicleType: parkingv1.VehicleType_VEHICLE_TYPE_AUTOMOBILE})
+			return err
+		}(), codes.NotFound},
+		{"not parked", func() error {
+			_, err := client.Unpark(ctx, &parkingv1.UnparkRequest{LotId: 1, TicketId: "t1"})
+			return err
+		}(), codes.NotFound},
+		{"parks", park("B1"), codes.OK},
+		{"already parked", park("B1"), codes.FailedPrecondition},
+		{"lot full", park("B2"), codes.ResourceExhausted},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.code, status.Code(tt.err), tt.name)
+	}
+
+	// the message is the error, without its stack
+	assert.NotContains(t, status.Convert(tests[2].err).Message(), "---")
+}
+
+func TestWatchOccupancy(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := parkingv1.NewParkingServiceClient(dial(t))
+
+	stream, err := client.WatchOccupancy(ctx, &parkingv1.WatchOccupancyRequest{LotId: 1})
+	assert.NoError(t, err)
+
+	ev, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), ev.GetSnapshot().GetTotal().GetFree())
+
+	_, err = client.Park(ctx, &parkingv1.ParkRequest{LotId: 1, VehicleType: parkingv1.VehicleType_VEHICLE_TYPE_MOTORCYCLE, VehicleNumber: "B1"})
+	assert.NoError(t, err)
+
+	ev, err = stream.Recv()
+	assert.NoError(t, err)
+	assert.True(t, ev.GetChange().GetNew().GetOccupied())
+	assert.Equal(t, []int32{-1}, []int32{ev.GetDelta()[0].GetFree()})
+
+	// unknown lots end the stream right away
+	stream, err = client.WatchOccupancy(ctx, &parkingv1.WatchOccupancyRequest{LotId: 9})
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestHealth(t *testing.T) {
+	client := healthpb.NewHealthClient(dial(t))
+
+	for _, service := range []string{"", parkingv1.ParkingService_ServiceDesc.ServiceName} {
+		res, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		assert.NoError(t, err)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, res.GetStatus())
+	}
+}
+
+func TestAuth(t *testing.T) {
+	ctx := context.Background()
+	conn, uc := dialWith(t, true)
+	client := parkingv1.NewParkingServiceClient(conn)
+
+	key := func(role string, lot uint) context.Context {
+		_, plain, err := uc.Auth.CreateAPIKey(ctx, entity.CreateAPIKey{Name: role, Role: role, LotID: lot})
+		assert.NoError(t, err)
+		return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+plain)
+	}
+
+	park := func(ctx context.Context, plate string) error {
+		_, err := client.Park(ctx, &parkingv1.ParkRequest{LotId: 1, VehicleType: parkingv1.VehicleType_VEHICLE_TYPE_MOTORCYCLE, VehicleNumber: plate})
+		return err
+	}
+
+	readonly := key(entity.RoleReadonly, 0)
+	_, err := client.AvailableSpot(readonly, &parkingv1.AvailableSpotRequest{LotId: 1, VehicleType: parkingv1.VehicleType_VEHICLE_TYPE_MOTORCYCLE})
+	assert.NoError(t, err)
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(park(ctx, "B1")))
+	assert.Equal(t, codes.Unauthenticated, status.Code(park(metadata.AppendToOutgoingContext(ctx, "x-api-key", "pk_nope"), "B1")))
+	assert.Equal(t, codes.PermissionDenied, status.Code(park(readonly, "B1")))
+	assert.Equal(t, codes.PermissionDenied, status.Code(park(key(entity.RoleGate, 2), "B1")))
+	assert.Equal(t, codes.OK, status.Code(park(key(entity.RoleGate, 1), "B1")))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(park(metadata.AppendToOutgoingContext(ctx, "x-admin-token", "s3cret"), "B2")))
+
+	// streams too
+	stream, err := client.WatchOccupancy(ctx, &parkingv1.WatchOccupancyRequest{LotId: 1})
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// health stays open for the probes
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err)
+}
+
+func TestAuthUnmappedMethod(t *testing.T) {
+	requestContext := middlewares.UnaryRequestContextInterceptor(zap.NewNop())
+	auth := rpc.UnaryAuthInterceptor(rpc.AuthOption{Log: zap.NewNop(), Disabled: true})
+
+	called := false
+	call := func(method string) error {
+		info := &grpc.UnaryServerInfo{FullMethod: method}
+		_, err := requestContext(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return auth(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				called = true
+				return nil, nil
+			})
+		})
+		return err
+	}
+
+	// refused even to an admin, until given roles
+	assert.Equal(t, codes.PermissionDenied, status.Code(call("/parking.v1.ParkingService/Refund")))
+	assert.False(t, called)
+
+	assert.NoError(t, call("/grpc.health.v1.Health/Check"))
+	assert.True(t, called)
+}
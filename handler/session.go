@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+// GetVehicleSessions godoc
+// @Summary      Parking history of a vehicle
+// @Description  Returns the sessions of a vehicle in the lot a page at a time, newest first unless sorted otherwise. Pass next_cursor back as cursor, with the same sort and order, for the next page
+// @Tags         Parking
+// @Produce      json
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        lot_id path int true "Lot ID"
+// @Param        vehicle_number path string true "Vehicle Number"
+// @Param        vehicle_type query string false "Vehicle Type (M, B, A)"
+// @Param        floor query int false "Floor of the spot"
+// @Param        from query string false "In the lot at or after, RFC 3339"
+// @Param        to query string false "In the lot before, RFC 3339"
+// @Param        status query string false "open or closed"
+// @Param        min_fee query int false "Lowest fee"
+// @Param        max_fee query int false "Highest fee"
+// @Param        sort query string false "parked_at (default) or duration"
+// @Param        order query string false "desc (default) or asc"
+// @Param        cursor query string false "next_cursor of the previous page"
+// @Param        limit query int false "Max sessions, default 50, at most 500"
+// @Success      200 {object} handler.SessionsResponse
+// @Failure      400 {object} handler.ErrorResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Failure      404 {object} handler.ErrorResponse
+// @Router       /lots/{lot_id}/vehicle/{vehicle_number}/sessions [get]
+func (e *rest) GetVehicleSessions(c *fiber.Ctx) error {
+
+	filter, err := sessionFilter(c)
+	if err != nil {
+		return e.compileError(c, err)
+	}
+	filter.VehicleNumber = utils.CopyString(c.Params("vehicle_number"))
+
+	return e.getSessions(c, filter)
+}
+
+// GetSessions godoc
+// @Summary      Parking sessions
+// @Description  Returns the sessions of the lot a page at a time, newest first unless sorted otherwise. Pass next_cursor back as cursor, with the same sort and order, for the next page
+// @Tags         Admin
+// @Produce      json
+// @Param        lot_id path int true "Lot ID"
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        vehicle_number query string false "Vehicle Number"
+// @Param        vehicle_type query string false "Vehicle Type (M, B, A)"
+// @Param        floor query int false "Floor of the spot"
+// @Param        from query string false "In the lot at or after, RFC 3339"
+// @Param        to query string false "In the lot before, RFC 3339"
+// @Param        status query string false "open or closed"
+// @Param        min_fee query int false "Lowest fee"
+// @Param        max_fee query int false "Highest fee"
+// @Param        sort query string false "parked_at (default) or duration"
+// @Param        order query string false "desc (default) or asc"
+// @Param        cursor query string false "next_cursor of the previous page"
+// @Param        limit query int false "Max sessions, default 50, at most 500"
+// @Success      200 {object} handler.SessionsResponse
+// @Failure      400 {object} handler.ErrorResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Router       /lots/{lot_id}/admin/sessions [get]
+func (e *rest) GetSessions(c *fiber.Ctx) error {
+
+	filter, err := sessionFilter(c)
+	if err != nil {
+		return e.compileError(c, err)
+	}
+	filter.VehicleNumber = utils.CopyString(c.Query("vehicle_number"))
+
+	return e.getSessions(c, filter)
+}
+
+func (e *rest) getSessions(c *fiber.Ctx, filter entity.GetSessions) error {
+
+	ctx := c.Locals("ctx").(context.Context)
+
+	page, err := e.uc.Parking.GetSessions(ctx, filter)
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(SessionsResponse{
+		Success:    true,
+		Message:    "Done get sessions !",
+		Sessions:   page.Sessions,
+		NextCursor: page.NextCursor,
+	})
+}
+
+// sessionFilter reads the query of the session listings.
+func sessionFilter(c *fiber.Ctx) (entity.GetSessions, error) {
+
+	filter := entity.GetSessions{
+		LotID:       lotID(c),
+		VehicleType: entity.VehicleType(utils.CopyString(c.Query("vehicle_type"))),
+		Floor:       c.QueryInt("floor"),
+		Sort:        utils.CopyString(c.Query("sort")),
+		Cursor:      utils.CopyString(c.Query("cursor")),
+		Limit:       c.QueryInt("limit"),
+	}
+
+	switch c.Query("order", "desc") {
+	case "desc":
+		filter.Desc = true
+	case "asc":
+	default:
+		return filter, x.NewWithCode(http.StatusBadRequest, "order must be asc or desc")
+	}
+
+	switch c.Query("status") {
+	case "":
+	case "open":
+		filter.Open = pkg.BoolPtr(true)
+	case "closed":
+		filter.Open = pkg.BoolPtr(false)
+	default:
+		return filter, x.NewWithCode(http.StatusBadRequest, "status must be open or closed")
+	}
+
+	for name, dst := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		if v := c.Query(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return filter, x.WrapWithCode(err, http.StatusBadRequest, "invalid "+name)
+			}
+			*dst = &t
+		}
+	}
+
+	for name, dst := range map[string]**int64{"min_fee": &filter.MinFee, "max_fee": &filter.MaxFee} {
+		if v := c.Query(name); v != "" {
+			fee, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return filter, x.WrapWithCode(err, http.StatusBadRequest, "invalid "+name)
+			}
+			*dst = &fee
+		}
+	}
+
+	return filter, nil
+}
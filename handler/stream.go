@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// OccupancyStream godoc
+// @Summary      Occupancy stream
+// @Description  Server-sent events of the occupancy of the lot. A snapshot event carries the whole occupancy, a change event the spot that changed and the delta it makes to the counts of its floor and type
+// @Description  A fresh snapshot is sent periodically and whenever changes may have been missed, clients replace their counts with it
+// @Tags         Parking
+// @Produce      text/event-stream
+// @Param        Authorization header string false "Bearer token or API key"
+// @Param        access_token query string false "Token or API key, for clients that can't set headers"
+// @Param        lot_id path int true "Lot ID"
+// @Success      200 {object} entity.OccupancyEvent
+// @Failure      400 {object} handler.ErrorResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Failure      404 {object} handler.ErrorResponse
+// @Router       /lots/{lot_id}/occupancy/stream [get]
+func (e *rest) OccupancyStream(c *fiber.Ctx) error {
+
+	// the stream outlives the request context, not the server
+	ctx, cancel := context.WithCancel(e.ctx)
+
+	events, err := e.uc.Parking.WatchOccupancy(ctx, lotID(c))
+	if err != nil {
+		cancel()
+		return e.compileError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+
+		for ev := range events {
+			data, err := json.Marshal(ev)
+			if err != nil {
+				e.log.Error("failed to encode occupancy event", zap.Error(err))
+				return
+			}
+
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Event, data)
+
+			// the client is gone
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+
+	return nil
+}
+
+// occupancyUpgrade lets only WebSocket upgrades through to OccupancySocket.
+func (e *rest) occupancyUpgrade(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return fiber.ErrUpgradeRequired
+	}
+
+	return c.Next()
+}
+
+// OccupancySocket godoc
+// @Summary      Occupancy WebSocket
+// @Description  The occupancy stream over a WebSocket, one JSON message per event. The socket is closed with a policy violation when the lot can't be watched
+// @Tags         Parking
+// @Param        Authorization header string false "Bearer token or API key"
+// @Param        access_token query string false "Token or API key, for clients that can't set headers"
+// @Param        lot_id path int true "Lot ID"
+// @Success      101 {object} entity.OccupancyEvent
+// @Failure      400 {object} handler.ErrorResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Failure      426 {string} string "Upgrade Required"
+// @Router       /lots/{lot_id}/occupancy/ws [get]
+func (e *rest) OccupancySocket(conn *websocket.Conn) {
+
+	ctx, cancel := context.WithCancel(e.ctx)
+	defer cancel()
+
+	events, err := e.uc.Parking.WatchOccupancy(ctx, conn.Locals("lot_id").(uint))
+	if err != nil {
+		_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, err.Error()))
+		return
+	}
+
+	// nothing is expected from the client, reading notices it leaving
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for ev := range events {
+		if err := conn.WriteJSON(ev); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+// GetTariffs godoc
+// @Summary      List tariffs
+// @Description  Returns the tariff of every vehicle type in the lot
+// @Tags         Tariff
+// @Produce      json
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        lot_id path int true "Lot ID"
+// @Success      200 {object} handler.TariffsResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Failure      404 {object} handler.ErrorResponse
+// @Failure      500 {object} handler.ErrorResponse
+// @Router       /lots/{lot_id}/tariffs [get]
+func (e *rest) GetTariffs(c *fiber.Ctx) error {
+
+	ctx := c.Locals("ctx").(context.Context)
+
+	tariffs, err := e.uc.Tariff.GetTariffs(ctx, entity.GetTariffs{
+		LotID: lotID(c),
+	})
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(TariffsResponse{
+		Success: true,
+		Message: "Done get tariffs !",
+		Tariffs: tariffs,
+	})
+}
+
+// UpdateTariff godoc
+// @Summary      Create or update a tariff
+// @Description  Replaces the tariff of a vehicle type in the lot, used for fees computed from now on
+// @Tags         Tariff
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        lot_id path int true "Lot ID"
+// @Param        vehicle_type path string true "Vehicle Type (M, B, A)"
+// @Param        body body handler.TariffRequest true "Tariff"
+// @Success      200 {object} handler.TariffResponse
+// @Failure      400 {object} handler.ErrorResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Router       /lots/{lot_id}/tariffs/{vehicle_type} [put]
+func (e *rest) UpdateTariff(c *fiber.Ctx) error {
+
+	var (
+		input TariffRequest
+		ctx   = c.Locals("ctx").(context.Context)
+	)
+	if err := c.BodyParser(&input); err != nil {
+		return e.compileError(c, x.WrapWithCode(err, http.StatusBadRequest, "invalid input"))
+	}
+
+	if err := validate.Struct(input); err != nil {
+		return e.compileError(c, x.WrapWithCode(err, http.StatusBadRequest, "failed validation"))
+	}
+
+	tariff, err := e.uc.Tariff.UpdateTariff(ctx, entity.Tariff{
+		LotID: lotID(c),
+		// params point into fiber's reused buffer, copy before storing
+		VehicleType:        utils.CopyString(c.Params("vehicle_type")),
+		FirstHourPrice:     input.FirstHourPrice,
+		HourlyPrice:        input.HourlyPrice,
+		DailyCap:           input.DailyCap,
+		GracePeriodMinutes: input.GracePeriodMinutes,
+		NightHourlyPrice:   input.NightHourlyPrice,
+		NightStartHour:     input.NightStartHour,
+		NightEndHour:       input.NightEndHour,
+		WeekendHourlyPrice: input.WeekendHourlyPrice,
+	})
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(TariffResponse{
+		Success: true,
+		Message: "Done update tariff !",
+		Tariff:  &tariff,
+	})
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+)
+
+// GetWaitlistEntry godoc
+// @Summary      Waitlist position
+// @Description  Returns a waitlist entry with its position in the queue, or the offered spot and the claim deadline once a spot is held for it
+// @Tags         Waitlist
+// @Produce      json
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        lot_id path int true "Lot ID"
+// @Param        code path string true "Waitlist Code"
+// @Success      200 {object} handler.WaitlistResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Failure      404 {object} handler.ErrorResponse
+// @Router       /lots/{lot_id}/waitlist/{code} [get]
+func (e *rest) GetWaitlistEntry(c *fiber.Ctx) error {
+
+	ctx := c.Locals("ctx").(context.Context)
+
+	entry, err := e.uc.Waitlist.GetEntry(ctx, entity.GetWaitlist{
+		LotID: lotID(c),
+		Code:  utils.CopyString(c.Params("code")),
+	})
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(WaitlistResponse{
+		Success:  true,
+		Message:  "Done get waitlist entry !",
+		Waitlist: &entry,
+	})
+}
+
+// LeaveWaitlist godoc
+// @Summary      Leave the waitlist
+// @Description  Takes a vehicle off the waitlist, a spot offered to it goes to the next vehicle in the queue
+// @Tags         Waitlist
+// @Produce      json
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        lot_id path int true "Lot ID"
+// @Param        code path string true "Waitlist Code"
+// @Success      200 {object} handler.WaitlistResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Failure      404 {object} handler.ErrorResponse
+// @Failure      409 {object} handler.ErrorResponse
+// @Router       /lots/{lot_id}/waitlist/{code} [delete]
+func (e *rest) LeaveWaitlist(c *fiber.Ctx) error {
+
+	ctx := c.Locals("ctx").(context.Context)
+
+	entry, err := e.uc.Waitlist.Leave(ctx, entity.GetWaitlist{
+		LotID: lotID(c),
+		Code:  utils.CopyString(c.Params("code")),
+	})
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(WaitlistResponse{
+		Success:  true,
+		Message:  "Done leaving waitlist !",
+		Waitlist: &entry,
+	})
+}
@@ -0,0 +1,330 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+
+	x "github.com/zuhrulumam/go-parking-lot/pkg/errors"
+)
+
+// webhookID reads the :id of the webhook routes.
+func webhookID(c *fiber.Ctx) (uint, error) {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return 0, x.NewWithCode(http.StatusBadRequest, "invalid webhook id")
+	}
+
+	return uint(id), nil
+}
+
+// reachWebhook gets the webhook of the :id of the webhook routes, refusing
+// one of a lot the principal may not reach. Webhooks of every lot, lot 0,
+// are only reached by principals not confined to one lot.
+func (e *rest) reachWebhook(c *fiber.Ctx) (entity.Webhook, error) {
+	id, err := webhookID(c)
+	if err != nil {
+		return entity.Webhook{}, err
+	}
+
+	hook, err := e.uc.Webhook.GetWebhook(c.Locals("ctx").(context.Context), id)
+	if err != nil {
+		return entity.Webhook{}, err
+	}
+
+	if p := principal(c); !p.Reaches(hook.LotID) {
+		return entity.Webhook{}, x.NewWithCode(http.StatusForbidden, "%s may not reach webhook %d", p.Subject, id)
+	}
+
+	return hook, nil
+}
+
+// CreateWebhook godoc
+// @Summary      Subscribe a webhook
+// @Description  Subscribes an endpoint to the domain events of one lot, or of every lot when lot_id is 0, and of the listed types, or all of them. Deliveries are signed with the secret, generated when none is given and only returned here
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        body body handler.WebhookRequest true "Webhook"
+// @Success      201 {object} handler.WebhookResponse
+// @Failure      400 {object} handler.ErrorResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Failure      404 {object} handler.ErrorResponse
+// @Router       /admin/webhooks [post]
+func (e *rest) CreateWebhook(c *fiber.Ctx) error {
+
+	var (
+		input WebhookRequest
+		ctx   = c.Locals("ctx").(context.Context)
+	)
+	if err := c.BodyParser(&input); err != nil {
+		return e.compileError(c, x.WrapWithCode(err, http.StatusBadRequest, "invalid input"))
+	}
+
+	if err := validate.Struct(input); err != nil {
+		return e.compileError(c, x.WrapWithCode(err, http.StatusBadRequest, "failed validation"))
+	}
+
+	if p := principal(c); !p.Reaches(input.LotID) {
+		return e.compileError(c, x.NewWithCode(http.StatusForbidden, "%s may not reach lot %d", p.Subject, input.LotID))
+	}
+
+	data := entity.SaveWebhook{
+		URL:    &input.URL,
+		LotID:  &input.LotID,
+		Secret: &input.Secret,
+	}
+	if input.EventTypes != nil {
+		data.EventTypes = &input.EventTypes
+	}
+
+	hook, err := e.uc.Webhook.CreateWebhook(ctx, data)
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(WebhookResponse{
+		Success: true,
+		Message: "Done creating webhook !",
+		Webhook: &hook,
+		Secret:  hook.Secret,
+	})
+}
+
+// GetWebhooks godoc
+// @Summary      List webhooks
+// @Description  Returns every webhook subscription, without its secret
+// @Tags         Webhooks
+// @Produce      json
+// @Param        Authorization header string true "Bearer token or API key"
+// @Success      200 {object} handler.WebhooksResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Router       /admin/webhooks [get]
+func (e *rest) GetWebhooks(c *fiber.Ctx) error {
+
+	ctx := c.Locals("ctx").(context.Context)
+
+	hooks, err := e.uc.Webhook.GetWebhooks(ctx)
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	p := principal(c)
+	hooks = slices.DeleteFunc(hooks, func(h entity.Webhook) bool {
+		return !p.Reaches(h.LotID)
+	})
+
+	return c.Status(fiber.StatusOK).JSON(WebhooksResponse{
+		Success:  true,
+		Message:  "Done get webhooks !",
+		Webhooks: hooks,
+	})
+}
+
+// GetWebhook godoc
+// @Summary      Get a webhook
+// @Tags         Webhooks
+// @Produce      json
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        id path int true "Webhook ID"
+// @Success      200 {object} handler.WebhookResponse
+// @Failure      400 {object} handler.ErrorResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Failure      404 {object} handler.ErrorResponse
+// @Router       /admin/webhooks/{id} [get]
+func (e *rest) GetWebhook(c *fiber.Ctx) error {
+
+	hook, err := e.reachWebhook(c)
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(WebhookResponse{
+		Success: true,
+		Message: "Done get webhook !",
+		Webhook: &hook,
+	})
+}
+
+// UpdateWebhook godoc
+// @Summary      Update a webhook
+// @Description  Changes the fields given. An inactive webhook is sent nothing, its pending deliveries included
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        id path int true "Webhook ID"
+// @Param        body body handler.UpdateWebhookRequest true "Webhook changes"
+// @Success      200 {object} handler.WebhookResponse
+// @Failure      400 {object} handler.ErrorResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Failure      404 {object} handler.ErrorResponse
+// @Router       /admin/webhooks/{id} [patch]
+func (e *rest) UpdateWebhook(c *fiber.Ctx) error {
+
+	var (
+		input UpdateWebhookRequest
+		ctx   = c.Locals("ctx").(context.Context)
+	)
+
+	hook, err := e.reachWebhook(c)
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	if err := c.BodyParser(&input); err != nil {
+		return e.compileError(c, x.WrapWithCode(err, http.StatusBadRequest, "invalid input"))
+	}
+
+	if err := validate.Struct(input); err != nil {
+		return e.compileError(c, x.WrapWithCode(err, http.StatusBadRequest, "failed validation"))
+	}
+
+	// nor move it to a lot out of reach
+	if p := principal(c); input.LotID != nil && !p.Reaches(*input.LotID) {
+		return e.compileError(c, x.NewWithCode(http.StatusForbidden, "%s may not reach lot %d", p.Subject, *input.LotID))
+	}
+
+	hook, err = e.uc.Webhook.UpdateWebhook(ctx, entity.SaveWebhook{
+		ID:         hook.ID,
+		LotID:      input.LotID,
+		URL:        input.URL,
+		EventTypes: input.EventTypes,
+		Secret:     input.Secret,
+		Active:     input.Active,
+	})
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(WebhookResponse{
+		Success: true,
+		Message: "Done updating webhook !",
+		Webhook: &hook,
+	})
+}
+
+// DeleteWebhook godoc
+// @Summary      Delete a webhook
+// @Description  Unsubscribes the webhook, its delivery log is kept
+// @Tags         Webhooks
+// @Produce      json
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        id path int true "Webhook ID"
+// @Success      200 {object} handler.WebhookResponse
+// @Failure      400 {object} handler.ErrorResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Failure      404 {object} handler.ErrorResponse
+// @Router       /admin/webhooks/{id} [delete]
+func (e *rest) DeleteWebhook(c *fiber.Ctx) error {
+
+	ctx := c.Locals("ctx").(context.Context)
+
+	hook, err := e.reachWebhook(c)
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	if err := e.uc.Webhook.DeleteWebhook(ctx, hook.ID); err != nil {
+		return e.compileError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(WebhookResponse{
+		Success: true,
+		Message: "Done deleting webhook !",
+	})
+}
+
+// GetWebhookDeliveries godoc
+// @Summary      Webhook deliveries
+// @Description  Returns the newest delivery attempts to the webhook first
+// @Tags         Webhooks
+// @Produce      json
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        id path int true "Webhook ID"
+// @Param        status query string false "Status (succeeded, failed)"
+// @Param        limit query int false "Max entries, default 50, at most 500"
+// @Success      200 {object} handler.DeliveriesResponse
+// @Failure      400 {object} handler.ErrorResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Failure      404 {object} handler.ErrorResponse
+// @Router       /admin/webhooks/{id}/deliveries [get]
+func (e *rest) GetWebhookDeliveries(c *fiber.Ctx) error {
+
+	ctx := c.Locals("ctx").(context.Context)
+
+	hook, err := e.reachWebhook(c)
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	deliveries, err := e.uc.Webhook.GetDeliveries(ctx, entity.GetWebhookDeliveries{
+		WebhookID: hook.ID,
+		Status:    utils.CopyString(c.Query("status")),
+		Limit:     c.QueryInt("limit"),
+	})
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(DeliveriesResponse{
+		Success:    true,
+		Message:    "Done get deliveries !",
+		Deliveries: deliveries,
+	})
+}
+
+// ReplayDelivery godoc
+// @Summary      Replay a delivery
+// @Description  Sends the event of a failed delivery to the webhook again right away, also when the relay gave up on it, and returns the new attempt. A failed replay is a 200 with a failed delivery
+// @Tags         Webhooks
+// @Produce      json
+// @Param        Authorization header string true "Bearer token or API key"
+// @Param        id path int true "Webhook ID"
+// @Param        delivery_id path int true "Delivery ID"
+// @Success      200 {object} handler.DeliveryResponse
+// @Failure      400 {object} handler.ErrorResponse
+// @Failure      401 {object} handler.ErrorResponse
+// @Failure      403 {object} handler.ErrorResponse
+// @Failure      404 {object} handler.ErrorResponse
+// @Failure      409 {object} handler.ErrorResponse
+// @Router       /admin/webhooks/{id}/deliveries/{delivery_id}/replay [post]
+func (e *rest) ReplayDelivery(c *fiber.Ctx) error {
+
+	ctx := c.Locals("ctx").(context.Context)
+
+	hook, err := e.reachWebhook(c)
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	deliveryID, err := c.ParamsInt("delivery_id")
+	if err != nil || deliveryID <= 0 {
+		return e.compileError(c, x.NewWithCode(http.StatusBadRequest, "invalid delivery id"))
+	}
+
+	delivery, err := e.uc.Webhook.Replay(ctx, entity.ReplayDelivery{
+		WebhookID:  hook.ID,
+		DeliveryID: uint(deliveryID),
+	})
+	if err != nil {
+		return e.compileError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(DeliveryResponse{
+		Success:  true,
+		Message:  "Done replaying delivery !",
+		Delivery: &delivery,
+	})
+}
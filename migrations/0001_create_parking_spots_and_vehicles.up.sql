@@ -0,0 +1,24 @@
+-- IF NOT EXISTS lets databases created by the old AutoMigrate seed adopt this
+-- migration without losing data.
+CREATE TABLE IF NOT EXISTS parking_spots (
+    id BIGSERIAL PRIMARY KEY,
+    floor BIGINT,
+    "row" BIGINT,
+    col BIGINT,
+    type VARCHAR(1),
+    active BOOLEAN,
+    occupied BOOLEAN
+);
+
+CREATE TABLE IF NOT EXISTS vehicles (
+    id BIGSERIAL PRIMARY KEY,
+    vehicle_number TEXT,
+    vehicle_type VARCHAR(1),
+    spot_id TEXT,
+    parked_at TIMESTAMPTZ,
+    unparked_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_active_spot
+    ON vehicles (spot_id)
+    WHERE unparked_at IS NULL;
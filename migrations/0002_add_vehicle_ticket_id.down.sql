@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS unique_ticket_id;
+
+ALTER TABLE vehicles DROP COLUMN IF EXISTS ticket_id;
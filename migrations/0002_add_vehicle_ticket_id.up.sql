@@ -0,0 +1,4 @@
+ALTER TABLE vehicles ADD COLUMN IF NOT EXISTS ticket_id TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_ticket_id
+    ON vehicles (ticket_id);
@@ -0,0 +1,3 @@
+ALTER TABLE vehicles DROP COLUMN IF EXISTS fee;
+
+DROP TABLE IF EXISTS tariffs;
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS tariffs (
+    vehicle_type VARCHAR(1) PRIMARY KEY,
+    first_hour_price BIGINT NOT NULL DEFAULT 0,
+    hourly_price BIGINT NOT NULL DEFAULT 0,
+    daily_cap BIGINT NOT NULL DEFAULT 0,
+    grace_period_minutes BIGINT NOT NULL DEFAULT 0,
+    night_hourly_price BIGINT NOT NULL DEFAULT 0,
+    night_start_hour BIGINT NOT NULL DEFAULT 0,
+    night_end_hour BIGINT NOT NULL DEFAULT 0,
+    weekend_hourly_price BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO tariffs (vehicle_type, first_hour_price, hourly_price, daily_cap, grace_period_minutes, night_hourly_price, night_start_hour, night_end_hour, weekend_hourly_price)
+VALUES
+    ('B', 1000, 500, 5000, 10, 0, 22, 6, 0),
+    ('M', 2000, 1000, 15000, 10, 500, 22, 6, 1500),
+    ('A', 5000, 3000, 40000, 10, 2000, 22, 6, 4000)
+ON CONFLICT (vehicle_type) DO NOTHING;
+
+ALTER TABLE vehicles ADD COLUMN IF NOT EXISTS fee BIGINT;
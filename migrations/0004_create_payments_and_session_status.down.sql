@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS payments;
+
+ALTER TABLE vehicles DROP COLUMN IF EXISTS exit_requested_at;
+ALTER TABLE vehicles DROP COLUMN IF EXISTS status;
@@ -0,0 +1,21 @@
+ALTER TABLE vehicles ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'parked';
+ALTER TABLE vehicles ADD COLUMN IF NOT EXISTS exit_requested_at TIMESTAMPTZ;
+
+UPDATE vehicles SET status = 'exited' WHERE unparked_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS payments (
+    id BIGSERIAL PRIMARY KEY,
+    vehicle_id BIGINT NOT NULL REFERENCES vehicles (id),
+    ticket_id TEXT,
+    kind TEXT NOT NULL,
+    status TEXT NOT NULL,
+    provider TEXT NOT NULL,
+    amount BIGINT NOT NULL,
+    reference TEXT,
+    refund_of BIGINT REFERENCES payments (id),
+    note TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_payments_vehicle_id ON payments (vehicle_id);
+CREATE INDEX IF NOT EXISTS idx_payments_ticket_id ON payments (ticket_id);
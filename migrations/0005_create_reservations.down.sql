@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS reservations;
+
+ALTER TABLE parking_spots DROP COLUMN IF EXISTS reserved;
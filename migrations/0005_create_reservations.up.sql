@@ -0,0 +1,23 @@
+ALTER TABLE parking_spots ADD COLUMN IF NOT EXISTS reserved BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS reservations (
+    id BIGSERIAL PRIMARY KEY,
+    code TEXT NOT NULL,
+    parking_spot_id BIGINT NOT NULL REFERENCES parking_spots (id),
+    spot_id TEXT NOT NULL,
+    vehicle_type VARCHAR(1) NOT NULL,
+    vehicle_number TEXT,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    status TEXT NOT NULL,
+    vehicle_id BIGINT REFERENCES vehicles (id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    released_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_reservation_code ON reservations (code);
+
+-- a spot carries at most one live hold
+CREATE UNIQUE INDEX IF NOT EXISTS unique_held_spot ON reservations (parking_spot_id) WHERE status = 'held';
+
+CREATE INDEX IF NOT EXISTS idx_reservations_held_ends_at ON reservations (ends_at) WHERE status = 'held';
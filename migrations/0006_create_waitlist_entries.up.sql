@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS waitlist_entries (
+    id BIGSERIAL PRIMARY KEY,
+    code TEXT NOT NULL,
+    vehicle_type VARCHAR(1) NOT NULL,
+    vehicle_number TEXT NOT NULL,
+    status TEXT NOT NULL,
+    parking_spot_id BIGINT REFERENCES parking_spots (id),
+    spot_id TEXT,
+    offered_at TIMESTAMPTZ,
+    offer_expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    closed_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_waitlist_code ON waitlist_entries (code);
+
+-- a vehicle waits in one queue at a time
+CREATE UNIQUE INDEX IF NOT EXISTS unique_open_waitlist_vehicle
+    ON waitlist_entries (vehicle_number)
+    WHERE status IN ('waiting', 'offered');
+
+CREATE INDEX IF NOT EXISTS idx_waitlist_queue
+    ON waitlist_entries (vehicle_type, id)
+    WHERE status = 'waiting';
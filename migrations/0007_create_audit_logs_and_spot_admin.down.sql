@@ -0,0 +1,13 @@
+ALTER TABLE waitlist_entries DROP CONSTRAINT IF EXISTS waitlist_entries_parking_spot_id_fkey;
+ALTER TABLE waitlist_entries ADD CONSTRAINT waitlist_entries_parking_spot_id_fkey
+    FOREIGN KEY (parking_spot_id) REFERENCES parking_spots (id);
+
+-- fails once a floor with reservations was removed, those rows have no spot
+ALTER TABLE reservations DROP CONSTRAINT IF EXISTS reservations_parking_spot_id_fkey;
+ALTER TABLE reservations ADD CONSTRAINT reservations_parking_spot_id_fkey
+    FOREIGN KEY (parking_spot_id) REFERENCES parking_spots (id);
+ALTER TABLE reservations ALTER COLUMN parking_spot_id SET NOT NULL;
+
+DROP INDEX IF EXISTS unique_spot_position;
+
+DROP TABLE IF EXISTS audit_logs;
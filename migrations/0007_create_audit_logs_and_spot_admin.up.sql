@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id BIGSERIAL PRIMARY KEY,
+    actor TEXT NOT NULL,
+    action TEXT NOT NULL,
+    resource TEXT NOT NULL,
+    resource_id TEXT NOT NULL,
+    before TEXT,
+    after TEXT,
+    reason TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_resource ON audit_logs (resource, resource_id);
+
+-- spots are now created through the admin API, a position exists once
+CREATE UNIQUE INDEX IF NOT EXISTS unique_spot_position ON parking_spots (floor, "row", col);
+
+-- removing a floor keeps the history of its reservations and offers, they
+-- still carry the spot_id text
+ALTER TABLE reservations ALTER COLUMN parking_spot_id DROP NOT NULL;
+ALTER TABLE reservations DROP CONSTRAINT IF EXISTS reservations_parking_spot_id_fkey;
+ALTER TABLE reservations ADD CONSTRAINT reservations_parking_spot_id_fkey
+    FOREIGN KEY (parking_spot_id) REFERENCES parking_spots (id) ON DELETE SET NULL;
+
+ALTER TABLE waitlist_entries DROP CONSTRAINT IF EXISTS waitlist_entries_parking_spot_id_fkey;
+ALTER TABLE waitlist_entries ADD CONSTRAINT waitlist_entries_parking_spot_id_fkey
+    FOREIGN KEY (parking_spot_id) REFERENCES parking_spots (id) ON DELETE SET NULL;
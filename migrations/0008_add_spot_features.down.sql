@@ -0,0 +1,2 @@
+ALTER TABLE parking_spots DROP COLUMN IF EXISTS accessible;
+ALTER TABLE parking_spots DROP COLUMN IF EXISTS ev_charger;
@@ -0,0 +1,2 @@
+ALTER TABLE parking_spots ADD COLUMN IF NOT EXISTS ev_charger BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE parking_spots ADD COLUMN IF NOT EXISTS accessible BOOLEAN NOT NULL DEFAULT FALSE;
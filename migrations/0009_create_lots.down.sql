@@ -0,0 +1,35 @@
+-- fails once a second lot has spots, tariffs or sessions clashing with the
+-- first one, remove them before going back to a single lot
+DROP INDEX IF EXISTS idx_audit_logs_lot;
+DROP INDEX IF EXISTS idx_vehicles_lot_number;
+
+DROP INDEX IF EXISTS idx_waitlist_queue;
+CREATE INDEX IF NOT EXISTS idx_waitlist_queue
+    ON waitlist_entries (vehicle_type, id)
+    WHERE status = 'waiting';
+
+ALTER TABLE tariffs DROP CONSTRAINT IF EXISTS tariffs_pkey;
+ALTER TABLE tariffs ADD PRIMARY KEY (vehicle_type);
+
+DROP INDEX IF EXISTS unique_spot_position;
+CREATE UNIQUE INDEX IF NOT EXISTS unique_spot_position ON parking_spots (floor, "row", col);
+
+UPDATE audit_logs SET resource_id = substring(resource_id from position('-' in resource_id) + 1) WHERE resource = 'spot';
+UPDATE waitlist_entries SET spot_id = substring(spot_id from position('-' in spot_id) + 1) WHERE spot_id IS NOT NULL AND spot_id <> '';
+UPDATE reservations SET spot_id = substring(spot_id from position('-' in spot_id) + 1);
+UPDATE vehicles SET spot_id = substring(spot_id from position('-' in spot_id) + 1) WHERE spot_id IS NOT NULL;
+
+DROP INDEX IF EXISTS unique_active_spot;
+CREATE UNIQUE INDEX IF NOT EXISTS unique_active_spot
+    ON vehicles (spot_id)
+    WHERE unparked_at IS NULL;
+
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS lot_id;
+ALTER TABLE waitlist_entries DROP COLUMN IF EXISTS lot_id;
+ALTER TABLE reservations DROP COLUMN IF EXISTS lot_id;
+ALTER TABLE payments DROP COLUMN IF EXISTS lot_id;
+ALTER TABLE tariffs DROP COLUMN IF EXISTS lot_id;
+ALTER TABLE vehicles DROP COLUMN IF EXISTS lot_id;
+ALTER TABLE parking_spots DROP COLUMN IF EXISTS lot_id;
+
+DROP TABLE IF EXISTS lots;
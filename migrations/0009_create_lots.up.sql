@@ -0,0 +1,58 @@
+CREATE TABLE IF NOT EXISTS lots (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    allocation TEXT NOT NULL DEFAULT '',
+    opens_at TEXT NOT NULL DEFAULT '',
+    closes_at TEXT NOT NULL DEFAULT '',
+    timezone TEXT NOT NULL DEFAULT '',
+    fallback BOOLEAN NOT NULL DEFAULT FALSE,
+    reserve TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- everything recorded so far belongs to the one garage there was
+INSERT INTO lots (id, name) VALUES (1, 'Main') ON CONFLICT (id) DO NOTHING;
+SELECT setval(pg_get_serial_sequence('lots', 'id'), (SELECT MAX(id) FROM lots));
+
+ALTER TABLE parking_spots ADD COLUMN IF NOT EXISTS lot_id BIGINT NOT NULL DEFAULT 1 REFERENCES lots (id);
+ALTER TABLE vehicles ADD COLUMN IF NOT EXISTS lot_id BIGINT NOT NULL DEFAULT 1 REFERENCES lots (id);
+ALTER TABLE tariffs ADD COLUMN IF NOT EXISTS lot_id BIGINT NOT NULL DEFAULT 1 REFERENCES lots (id);
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS lot_id BIGINT NOT NULL DEFAULT 1 REFERENCES lots (id);
+ALTER TABLE reservations ADD COLUMN IF NOT EXISTS lot_id BIGINT NOT NULL DEFAULT 1 REFERENCES lots (id);
+ALTER TABLE waitlist_entries ADD COLUMN IF NOT EXISTS lot_id BIGINT NOT NULL DEFAULT 1 REFERENCES lots (id);
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS lot_id BIGINT NOT NULL DEFAULT 1 REFERENCES lots (id);
+
+-- the default only adopts existing rows, new ones must name their lot
+ALTER TABLE parking_spots ALTER COLUMN lot_id DROP DEFAULT;
+ALTER TABLE vehicles ALTER COLUMN lot_id DROP DEFAULT;
+ALTER TABLE tariffs ALTER COLUMN lot_id DROP DEFAULT;
+ALTER TABLE payments ALTER COLUMN lot_id DROP DEFAULT;
+ALTER TABLE reservations ALTER COLUMN lot_id DROP DEFAULT;
+ALTER TABLE waitlist_entries ALTER COLUMN lot_id DROP DEFAULT;
+ALTER TABLE audit_logs ALTER COLUMN lot_id DROP DEFAULT;
+
+-- spot ids are now lot-floor-row-col
+UPDATE vehicles SET spot_id = '1-' || spot_id WHERE spot_id IS NOT NULL;
+UPDATE reservations SET spot_id = '1-' || spot_id;
+UPDATE waitlist_entries SET spot_id = '1-' || spot_id WHERE spot_id IS NOT NULL AND spot_id <> '';
+UPDATE audit_logs SET resource_id = '1-' || resource_id WHERE resource = 'spot';
+
+DROP INDEX IF EXISTS unique_active_spot;
+CREATE UNIQUE INDEX IF NOT EXISTS unique_active_spot
+    ON vehicles (lot_id, spot_id)
+    WHERE unparked_at IS NULL;
+
+DROP INDEX IF EXISTS unique_spot_position;
+CREATE UNIQUE INDEX IF NOT EXISTS unique_spot_position ON parking_spots (lot_id, floor, "row", col);
+
+ALTER TABLE tariffs DROP CONSTRAINT IF EXISTS tariffs_pkey;
+ALTER TABLE tariffs ADD PRIMARY KEY (lot_id, vehicle_type);
+
+DROP INDEX IF EXISTS idx_waitlist_queue;
+CREATE INDEX IF NOT EXISTS idx_waitlist_queue
+    ON waitlist_entries (lot_id, vehicle_type, id)
+    WHERE status = 'waiting';
+
+CREATE INDEX IF NOT EXISTS idx_vehicles_lot_number ON vehicles (lot_id, vehicle_number);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_lot ON audit_logs (lot_id, id);
@@ -0,0 +1,25 @@
+-- double parks may have left older open sessions of a vehicle behind.
+-- Which of them is real is for an operator to decide, so refuse to go on
+-- until `sessions close-duplicates` has closed them, audited.
+DO $$
+DECLARE
+    duplicates TEXT;
+BEGIN
+    SELECT string_agg(vehicle_number, ', ' ORDER BY vehicle_number) INTO duplicates
+    FROM (
+        SELECT vehicle_number FROM vehicles
+        WHERE unparked_at IS NULL
+        GROUP BY vehicle_number
+        HAVING COUNT(*) > 1
+    ) d;
+
+    IF duplicates IS NOT NULL THEN
+        RAISE EXCEPTION 'vehicles with more than one open session: %', duplicates
+            USING HINT = 'list them with `sessions duplicates`, close them with `sessions close-duplicates --actor ... --reason ...`, then migrate again';
+    END IF;
+END $$;
+
+-- a vehicle is parked in one place at a time, across lots
+CREATE UNIQUE INDEX IF NOT EXISTS unique_open_vehicle
+    ON vehicles (vehicle_number)
+    WHERE unparked_at IS NULL;
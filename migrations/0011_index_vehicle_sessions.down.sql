@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_vehicles_lot_number_parked_at;
+CREATE INDEX IF NOT EXISTS idx_vehicles_lot_number ON vehicles (lot_id, vehicle_number);
+
+DROP INDEX IF EXISTS idx_vehicles_lot_parked_at;
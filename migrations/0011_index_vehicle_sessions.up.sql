@@ -0,0 +1,5 @@
+-- session listings page through a lot, or one vehicle of it, on (parked_at, id)
+CREATE INDEX IF NOT EXISTS idx_vehicles_lot_parked_at ON vehicles (lot_id, parked_at, id);
+
+DROP INDEX IF EXISTS idx_vehicles_lot_number;
+CREATE INDEX IF NOT EXISTS idx_vehicles_lot_number_parked_at ON vehicles (lot_id, vehicle_number, parked_at, id);
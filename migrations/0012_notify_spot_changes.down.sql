@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS spot_changes ON parking_spots;
+DROP FUNCTION IF EXISTS notify_spot_change();
@@ -0,0 +1,21 @@
+-- every instance LISTENs on spot_changes to push occupancy to its clients;
+-- notifications go out on commit, rolled back changes are never seen
+CREATE OR REPLACE FUNCTION notify_spot_change() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND OLD IS NOT DISTINCT FROM NEW THEN
+        RETURN NULL;
+    END IF;
+
+    PERFORM pg_notify('spot_changes', json_build_object(
+        'lot_id', CASE WHEN TG_OP = 'DELETE' THEN OLD.lot_id ELSE NEW.lot_id END,
+        'old', CASE WHEN TG_OP = 'INSERT' THEN NULL ELSE row_to_json(OLD) END,
+        'new', CASE WHEN TG_OP = 'DELETE' THEN NULL ELSE row_to_json(NEW) END
+    )::text);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER spot_changes
+AFTER INSERT OR UPDATE OR DELETE ON parking_spots
+FOR EACH ROW EXECUTE FUNCTION notify_spot_change();
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_outbox_events_pending;
+ALTER TABLE outbox_events DROP COLUMN IF EXISTS failed_at;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events (next_attempt_at)
+    WHERE dispatched_at IS NULL;
+
+DROP TABLE IF EXISTS webhooks;
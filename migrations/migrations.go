@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"embed"
+
+	"github.com/zuhrulumam/go-parking-lot/pkg/migration"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// All returns every schema migration shipped with the binary, in version order.
+func All() []migration.Migration {
+	m, err := migration.Load(files)
+	if err != nil {
+		panic("invalid embedded migrations: " + err.Error())
+	}
+
+	return m
+}
@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: business/domain/lot/lot.go
+//
+// Generated by this command:
+//
+//	mockgen -source=business/domain/lot/lot.go -destination=mocks/domain/lot/mock_lot.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/zuhrulumam/go-parking-lot/business/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDomainItf is a mock of DomainItf interface.
+type MockDomainItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockDomainItfMockRecorder
+	isgomock struct{}
+}
+
+// MockDomainItfMockRecorder is the mock recorder for MockDomainItf.
+type MockDomainItfMockRecorder struct {
+	mock *MockDomainItf
+}
+
+// NewMockDomainItf creates a new mock instance.
+func NewMockDomainItf(ctrl *gomock.Controller) *MockDomainItf {
+	mock := &MockDomainItf{ctrl: ctrl}
+	mock.recorder = &MockDomainItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDomainItf) EXPECT() *MockDomainItfMockRecorder {
+	return m.recorder
+}
+
+// GetLot mocks base method.
+func (m *MockDomainItf) GetLot(ctx context.Context, data entity.GetLot) (entity.Lot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLot", ctx, data)
+	ret0, _ := ret[0].(entity.Lot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLot indicates an expected call of GetLot.
+func (mr *MockDomainItfMockRecorder) GetLot(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLot", reflect.TypeOf((*MockDomainItf)(nil).GetLot), ctx, data)
+}
+
+// GetLots mocks base method.
+func (m *MockDomainItf) GetLots(ctx context.Context) ([]entity.Lot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLots", ctx)
+	ret0, _ := ret[0].([]entity.Lot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLots indicates an expected call of GetLots.
+func (mr *MockDomainItfMockRecorder) GetLots(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLots", reflect.TypeOf((*MockDomainItf)(nil).GetLots), ctx)
+}
+
+// InsertLot mocks base method.
+func (m *MockDomainItf) InsertLot(ctx context.Context, data entity.Lot) (entity.Lot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertLot", ctx, data)
+	ret0, _ := ret[0].(entity.Lot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertLot indicates an expected call of InsertLot.
+func (mr *MockDomainItfMockRecorder) InsertLot(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertLot", reflect.TypeOf((*MockDomainItf)(nil).InsertLot), ctx, data)
+}
+
+// UpdateLot mocks base method.
+func (m *MockDomainItf) UpdateLot(ctx context.Context, data entity.Lot) (entity.Lot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLot", ctx, data)
+	ret0, _ := ret[0].(entity.Lot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateLot indicates an expected call of UpdateLot.
+func (mr *MockDomainItfMockRecorder) UpdateLot(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLot", reflect.TypeOf((*MockDomainItf)(nil).UpdateLot), ctx, data)
+}
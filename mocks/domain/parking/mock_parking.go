@@ -41,6 +41,36 @@ func (m *MockDomainItf) EXPECT() *MockDomainItfMockRecorder {
 	return m.recorder
 }
 
+// CountParkingSpots mocks base method.
+func (m *MockDomainItf) CountParkingSpots(ctx context.Context, lotID uint) ([]entity.SpotCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountParkingSpots", ctx, lotID)
+	ret0, _ := ret[0].([]entity.SpotCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountParkingSpots indicates an expected call of CountParkingSpots.
+func (mr *MockDomainItfMockRecorder) CountParkingSpots(ctx, lotID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountParkingSpots", reflect.TypeOf((*MockDomainItf)(nil).CountParkingSpots), ctx, lotID)
+}
+
+// DeleteParkingSpots mocks base method.
+func (m *MockDomainItf) DeleteParkingSpots(ctx context.Context, data entity.DeleteParkingSpots) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteParkingSpots", ctx, data)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteParkingSpots indicates an expected call of DeleteParkingSpots.
+func (mr *MockDomainItfMockRecorder) DeleteParkingSpots(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteParkingSpots", reflect.TypeOf((*MockDomainItf)(nil).DeleteParkingSpots), ctx, data)
+}
+
 // GetAvailableParkingSpot mocks base method.
 func (m *MockDomainItf) GetAvailableParkingSpot(ctx context.Context, data entity.GetAvailableParkingSpot) ([]entity.ParkingSpot, error) {
 	m.ctrl.T.Helper()
@@ -56,6 +86,21 @@ func (mr *MockDomainItfMockRecorder) GetAvailableParkingSpot(ctx, data any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailableParkingSpot", reflect.TypeOf((*MockDomainItf)(nil).GetAvailableParkingSpot), ctx, data)
 }
 
+// GetSessions mocks base method.
+func (m *MockDomainItf) GetSessions(ctx context.Context, data entity.GetSessions) ([]entity.Vehicle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessions", ctx, data)
+	ret0, _ := ret[0].([]entity.Vehicle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessions indicates an expected call of GetSessions.
+func (mr *MockDomainItfMockRecorder) GetSessions(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessions", reflect.TypeOf((*MockDomainItf)(nil).GetSessions), ctx, data)
+}
+
 // GetVehicle mocks base method.
 func (m *MockDomainItf) GetVehicle(ctx context.Context, data entity.SearchVehicle) (entity.Vehicle, error) {
 	m.ctrl.T.Helper()
@@ -71,12 +116,28 @@ func (mr *MockDomainItfMockRecorder) GetVehicle(ctx, data any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVehicle", reflect.TypeOf((*MockDomainItf)(nil).GetVehicle), ctx, data)
 }
 
+// InsertParkingSpots mocks base method.
+func (m *MockDomainItf) InsertParkingSpots(ctx context.Context, data []entity.ParkingSpot) ([]entity.ParkingSpot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertParkingSpots", ctx, data)
+	ret0, _ := ret[0].([]entity.ParkingSpot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertParkingSpots indicates an expected call of InsertParkingSpots.
+func (mr *MockDomainItfMockRecorder) InsertParkingSpots(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertParkingSpots", reflect.TypeOf((*MockDomainItf)(nil).InsertParkingSpots), ctx, data)
+}
+
 // InsertVehicle mocks base method.
-func (m *MockDomainItf) InsertVehicle(ctx context.Context, data entity.InsertVehicle) error {
+func (m *MockDomainItf) InsertVehicle(ctx context.Context, data entity.InsertVehicle) (entity.Vehicle, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "InsertVehicle", ctx, data)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(entity.Vehicle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // InsertVehicle indicates an expected call of InsertVehicle.
@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: business/domain/payment/payment.go
+//
+// Generated by this command:
+//
+//	mockgen -source=business/domain/payment/payment.go -destination=mocks/domain/payment/mock_payment.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/zuhrulumam/go-parking-lot/business/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDomainItf is a mock of DomainItf interface.
+type MockDomainItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockDomainItfMockRecorder
+	isgomock struct{}
+}
+
+// MockDomainItfMockRecorder is the mock recorder for MockDomainItf.
+type MockDomainItfMockRecorder struct {
+	mock *MockDomainItf
+}
+
+// NewMockDomainItf creates a new mock instance.
+func NewMockDomainItf(ctrl *gomock.Controller) *MockDomainItf {
+	mock := &MockDomainItf{ctrl: ctrl}
+	mock.recorder = &MockDomainItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDomainItf) EXPECT() *MockDomainItfMockRecorder {
+	return m.recorder
+}
+
+// GetPayments mocks base method.
+func (m *MockDomainItf) GetPayments(ctx context.Context, data entity.GetPayments) ([]entity.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPayments", ctx, data)
+	ret0, _ := ret[0].([]entity.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPayments indicates an expected call of GetPayments.
+func (mr *MockDomainItfMockRecorder) GetPayments(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPayments", reflect.TypeOf((*MockDomainItf)(nil).GetPayments), ctx, data)
+}
+
+// InsertPayment mocks base method.
+func (m *MockDomainItf) InsertPayment(ctx context.Context, data entity.Payment) (entity.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertPayment", ctx, data)
+	ret0, _ := ret[0].(entity.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertPayment indicates an expected call of InsertPayment.
+func (mr *MockDomainItfMockRecorder) InsertPayment(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertPayment", reflect.TypeOf((*MockDomainItf)(nil).InsertPayment), ctx, data)
+}
+
+// UpdatePayment mocks base method.
+func (m *MockDomainItf) UpdatePayment(ctx context.Context, data entity.UpdatePayment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePayment", ctx, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePayment indicates an expected call of UpdatePayment.
+func (mr *MockDomainItfMockRecorder) UpdatePayment(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePayment", reflect.TypeOf((*MockDomainItf)(nil).UpdatePayment), ctx, data)
+}
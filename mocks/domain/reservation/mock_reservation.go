@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: business/domain/reservation/reservation.go
+//
+// Generated by this command:
+//
+//	mockgen -source=business/domain/reservation/reservation.go -destination=mocks/domain/reservation/mock_reservation.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/zuhrulumam/go-parking-lot/business/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDomainItf is a mock of DomainItf interface.
+type MockDomainItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockDomainItfMockRecorder
+	isgomock struct{}
+}
+
+// MockDomainItfMockRecorder is the mock recorder for MockDomainItf.
+type MockDomainItfMockRecorder struct {
+	mock *MockDomainItf
+}
+
+// NewMockDomainItf creates a new mock instance.
+func NewMockDomainItf(ctrl *gomock.Controller) *MockDomainItf {
+	mock := &MockDomainItf{ctrl: ctrl}
+	mock.recorder = &MockDomainItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDomainItf) EXPECT() *MockDomainItfMockRecorder {
+	return m.recorder
+}
+
+// GetReservations mocks base method.
+func (m *MockDomainItf) GetReservations(ctx context.Context, data entity.GetReservations) ([]entity.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReservations", ctx, data)
+	ret0, _ := ret[0].([]entity.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReservations indicates an expected call of GetReservations.
+func (mr *MockDomainItfMockRecorder) GetReservations(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReservations", reflect.TypeOf((*MockDomainItf)(nil).GetReservations), ctx, data)
+}
+
+// InsertReservation mocks base method.
+func (m *MockDomainItf) InsertReservation(ctx context.Context, data entity.Reservation) (entity.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertReservation", ctx, data)
+	ret0, _ := ret[0].(entity.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertReservation indicates an expected call of InsertReservation.
+func (mr *MockDomainItfMockRecorder) InsertReservation(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertReservation", reflect.TypeOf((*MockDomainItf)(nil).InsertReservation), ctx, data)
+}
+
+// UpdateReservation mocks base method.
+func (m *MockDomainItf) UpdateReservation(ctx context.Context, data entity.UpdateReservation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReservation", ctx, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateReservation indicates an expected call of UpdateReservation.
+func (mr *MockDomainItfMockRecorder) UpdateReservation(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReservation", reflect.TypeOf((*MockDomainItf)(nil).UpdateReservation), ctx, data)
+}
@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: business/domain/tariff/tariff.go
+//
+// Generated by this command:
+//
+//	mockgen -source=business/domain/tariff/tariff.go -destination=mocks/domain/tariff/mock_tariff.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/zuhrulumam/go-parking-lot/business/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDomainItf is a mock of DomainItf interface.
+type MockDomainItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockDomainItfMockRecorder
+	isgomock struct{}
+}
+
+// MockDomainItfMockRecorder is the mock recorder for MockDomainItf.
+type MockDomainItfMockRecorder struct {
+	mock *MockDomainItf
+}
+
+// NewMockDomainItf creates a new mock instance.
+func NewMockDomainItf(ctrl *gomock.Controller) *MockDomainItf {
+	mock := &MockDomainItf{ctrl: ctrl}
+	mock.recorder = &MockDomainItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDomainItf) EXPECT() *MockDomainItfMockRecorder {
+	return m.recorder
+}
+
+// GetTariff mocks base method.
+func (m *MockDomainItf) GetTariff(ctx context.Context, data entity.GetTariff) (entity.Tariff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTariff", ctx, data)
+	ret0, _ := ret[0].(entity.Tariff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTariff indicates an expected call of GetTariff.
+func (mr *MockDomainItfMockRecorder) GetTariff(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTariff", reflect.TypeOf((*MockDomainItf)(nil).GetTariff), ctx, data)
+}
+
+// GetTariffs mocks base method.
+func (m *MockDomainItf) GetTariffs(ctx context.Context, data entity.GetTariffs) ([]entity.Tariff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTariffs", ctx, data)
+	ret0, _ := ret[0].([]entity.Tariff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTariffs indicates an expected call of GetTariffs.
+func (mr *MockDomainItfMockRecorder) GetTariffs(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTariffs", reflect.TypeOf((*MockDomainItf)(nil).GetTariffs), ctx, data)
+}
+
+// UpsertTariff mocks base method.
+func (m *MockDomainItf) UpsertTariff(ctx context.Context, data entity.Tariff) (entity.Tariff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTariff", ctx, data)
+	ret0, _ := ret[0].(entity.Tariff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertTariff indicates an expected call of UpsertTariff.
+func (mr *MockDomainItfMockRecorder) UpsertTariff(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTariff", reflect.TypeOf((*MockDomainItf)(nil).UpsertTariff), ctx, data)
+}
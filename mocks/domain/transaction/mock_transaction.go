@@ -40,6 +40,20 @@ func (m *MockDomainItf) EXPECT() *MockDomainItfMockRecorder {
 	return m.recorder
 }
 
+// Drain mocks base method.
+func (m *MockDomainItf) Drain(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Drain", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Drain indicates an expected call of Drain.
+func (mr *MockDomainItfMockRecorder) Drain(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Drain", reflect.TypeOf((*MockDomainItf)(nil).Drain), ctx)
+}
+
 // RunInTx mocks base method.
 func (m *MockDomainItf) RunInTx(ctx context.Context, fn func(context.Context) error) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: business/domain/waitlist/waitlist.go
+//
+// Generated by this command:
+//
+//	mockgen -source=business/domain/waitlist/waitlist.go -destination=mocks/domain/waitlist/mock_waitlist.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/zuhrulumam/go-parking-lot/business/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDomainItf is a mock of DomainItf interface.
+type MockDomainItf struct {
+	ctrl     *gomock.Controller
+	recorder *MockDomainItfMockRecorder
+	isgomock struct{}
+}
+
+// MockDomainItfMockRecorder is the mock recorder for MockDomainItf.
+type MockDomainItfMockRecorder struct {
+	mock *MockDomainItf
+}
+
+// NewMockDomainItf creates a new mock instance.
+func NewMockDomainItf(ctrl *gomock.Controller) *MockDomainItf {
+	mock := &MockDomainItf{ctrl: ctrl}
+	mock.recorder = &MockDomainItfMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDomainItf) EXPECT() *MockDomainItfMockRecorder {
+	return m.recorder
+}
+
+// CountEntries mocks base method.
+func (m *MockDomainItf) CountEntries(ctx context.Context, data entity.GetWaitlist) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountEntries", ctx, data)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountEntries indicates an expected call of CountEntries.
+func (mr *MockDomainItfMockRecorder) CountEntries(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountEntries", reflect.TypeOf((*MockDomainItf)(nil).CountEntries), ctx, data)
+}
+
+// GetEntries mocks base method.
+func (m *MockDomainItf) GetEntries(ctx context.Context, data entity.GetWaitlist) ([]entity.WaitlistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntries", ctx, data)
+	ret0, _ := ret[0].([]entity.WaitlistEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntries indicates an expected call of GetEntries.
+func (mr *MockDomainItfMockRecorder) GetEntries(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntries", reflect.TypeOf((*MockDomainItf)(nil).GetEntries), ctx, data)
+}
+
+// InsertEntry mocks base method.
+func (m *MockDomainItf) InsertEntry(ctx context.Context, data entity.WaitlistEntry) (entity.WaitlistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertEntry", ctx, data)
+	ret0, _ := ret[0].(entity.WaitlistEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertEntry indicates an expected call of InsertEntry.
+func (mr *MockDomainItfMockRecorder) InsertEntry(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertEntry", reflect.TypeOf((*MockDomainItf)(nil).InsertEntry), ctx, data)
+}
+
+// UpdateEntry mocks base method.
+func (m *MockDomainItf) UpdateEntry(ctx context.Context, data entity.UpdateWaitlist) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEntry", ctx, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEntry indicates an expected call of UpdateEntry.
+func (mr *MockDomainItfMockRecorder) UpdateEntry(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEntry", reflect.TypeOf((*MockDomainItf)(nil).UpdateEntry), ctx, data)
+}
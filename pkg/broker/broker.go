@@ -0,0 +1,70 @@
+// Package broker fans spot changes out to the subscribers of this process.
+package broker
+
+import (
+	"context"
+	"sync"
+
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+)
+
+// bufferSize is how many changes a subscriber may fall behind before it is
+// dropped.
+const bufferSize = 256
+
+type Broker struct {
+	mu   sync.Mutex
+	subs map[chan entity.SpotChange]uint
+}
+
+func New() *Broker {
+	return &Broker{subs: map[chan entity.SpotChange]uint{}}
+}
+
+// Subscribe returns the changes of a lot, every lot when lotID is 0, until
+// ctx is done. The channel is closed then, or as soon as the subscriber
+// falls behind so that it never counts from a gap.
+func (b *Broker) Subscribe(ctx context.Context, lotID uint) <-chan entity.SpotChange {
+	ch := make(chan entity.SpotChange, bufferSize)
+
+	b.mu.Lock()
+	b.subs[ch] = lotID
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.drop(ch)
+	}()
+
+	return ch
+}
+
+// Publish hands the change to the subscribers of its lot without waiting
+// on any of them. Changes of lot 0 reach everyone.
+func (b *Broker) Publish(c entity.SpotChange) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch, lotID := range b.subs {
+		if c.LotID != 0 && lotID != 0 && lotID != c.LotID {
+			continue
+		}
+
+		select {
+		case ch <- c:
+		default:
+			delete(b.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+func (b *Broker) drop(ch chan entity.SpotChange) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subs[ch]; ok {
+		delete(b.subs, ch)
+		close(ch)
+	}
+}
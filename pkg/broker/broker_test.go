@@ -0,0 +1,46 @@
+package broker_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg/broker"
+)
+
+func TestBroker(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	b := broker.New()
+
+	lot1 := b.Subscribe(ctx, 1)
+	all := b.Subscribe(ctx, 0)
+
+	b.Publish(entity.SpotChange{LotID: 2})
+	b.Publish(entity.SpotChange{LotID: 1})
+	b.Publish(entity.SpotChange{Resync: true})
+
+	assert.Equal(t, entity.SpotChange{LotID: 1}, <-lot1)
+	assert.Equal(t, entity.SpotChange{Resync: true}, <-lot1)
+	assert.Equal(t, entity.SpotChange{LotID: 2}, <-all)
+
+	cancel()
+	for range lot1 {
+	}
+}
+
+func TestBrokerDropsSlowSubscriber(t *testing.T) {
+	b := broker.New()
+	slow := b.Subscribe(context.Background(), 1)
+
+	// nobody reads, the buffer fills and the subscriber is dropped
+	for i := 0; i < 1000; i++ {
+		b.Publish(entity.SpotChange{LotID: 1})
+	}
+
+	n := 0
+	for range slow {
+		n++
+	}
+	assert.Less(t, n, 1000)
+}
@@ -21,4 +21,5 @@ const (
 	CtxKeyError          ctxKey = "error"
 	CtxKeyTraceID        ctxKey = "trace_id"
 	CtxKeyAdditionalData ctxKey = "additional_data"
+	CtxKeyPrincipal      ctxKey = "principal"
 )
@@ -25,6 +25,26 @@ var (
 			EN: `Unauthorized Access. You are not authorized to access this resource.`,
 			ID: `Akses Ditolak. Anda Belum Diijinkan Untuk Mengakses Aplikasi.`,
 		},
+		"forbidden": ErrorMessage{
+			EN: `Access Forbidden. Your Role Does Not Allow This Action.`,
+			ID: `Akses Dilarang. Peran Anda Tidak Diijinkan Untuk Tindakan Ini.`,
+		},
+		"paymentrequired": ErrorMessage{
+			EN: `Payment Required. Please Settle The Outstanding Amount.`,
+			ID: `Pembayaran Diperlukan. Mohon Lunasi Sisa Tagihan.`,
+		},
+		"conflict": ErrorMessage{
+			EN: `Request Conflicts With The Current State. Please Refresh And Try Again.`,
+			ID: `Permintaan Bertentangan Dengan Kondisi Saat Ini. Mohon Muat Ulang Dan Coba Lagi.`,
+		},
+		"unprocessable": ErrorMessage{
+			EN: `Request Cannot Be Processed. Please Validate Your Input.`,
+			ID: `Permintaan Tidak Dapat Diproses. Mohon Cek Kembali Masukkan Anda.`,
+		},
+		"unavailable": ErrorMessage{
+			EN: `Service Is Shutting Down. Please Try Again.`,
+			ID: `Layanan Sedang Berhenti. Mohon Coba Lagi.`,
+		},
 		"uniqueconst": ErrorMessage{
 			EN: `Record has existed and must be unique. Please Validate Your Input Or Contact Administrator.`,
 			ID: `Data sudah ada. Mohon Cek Kembali Masukkan Anda Atau Hubungi Administrator.`,
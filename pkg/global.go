@@ -1,11 +1,13 @@
 package pkg
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/zuhrulumam/go-parking-lot/business/entity"
 )
 
@@ -23,28 +25,58 @@ func TimePtr(b time.Time) *time.Time {
 	return &b
 }
 
+func Int64Ptr(b int64) *int64 {
+	return &b
+}
+
+func IntPtr(b int) *int {
+	return &b
+}
+
+func StringPtr(b string) *string {
+	return &b
+}
+
+func UintPtr(b uint) *uint {
+	return &b
+}
+
+// IsUniqueViolation reports whether err is Postgres refusing a row that
+// breaks the named unique index or constraint.
+func IsUniqueViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == constraint
+}
+
+// ParseSpotID reads a lot-floor-row-col spot id.
 func ParseSpotID(spotID string) (*entity.SpotID, error) {
 	parts := strings.Split(spotID, "-")
-	if len(parts) != 3 {
+	if len(parts) != 4 {
 		return nil, fmt.Errorf("invalid spotID format")
 	}
 
-	floor, err := strconv.Atoi(parts[0])
+	lot, err := strconv.ParseUint(parts[0], 10, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lot: %w", err)
+	}
+
+	floor, err := strconv.Atoi(parts[1])
 	if err != nil {
 		return nil, fmt.Errorf("invalid floor: %w", err)
 	}
 
-	row, err := strconv.Atoi(parts[1])
+	row, err := strconv.Atoi(parts[2])
 	if err != nil {
-		return nil, fmt.Errorf("invalid col: %w", err)
+		return nil, fmt.Errorf("invalid row: %w", err)
 	}
 
-	col, err := strconv.Atoi(parts[2])
+	col, err := strconv.Atoi(parts[3])
 	if err != nil {
 		return nil, fmt.Errorf("invalid col: %w", err)
 	}
 
 	return &entity.SpotID{
+		Lot:   uint(lot),
 		Floor: floor,
 		Row:   row,
 		Col:   col,
@@ -0,0 +1,137 @@
+// Package health tells whether the server is ready for traffic, from the
+// checks of its components.
+package health
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Statuses of a Report and its components.
+const (
+	StatusOK           = "ok"
+	StatusFailing      = "failing"
+	StatusShuttingDown = "shutting_down"
+)
+
+// Check returns nil when its component is ready. It is given a context
+// with the timeout of the checks.
+type Check func(ctx context.Context) error
+
+// Component is the result of the check of a component.
+type Component struct {
+	Status   string  `json:"status"`
+	Error    string  `json:"error,omitempty"`
+	Duration float64 `json:"duration_ms"`
+}
+
+type Report struct {
+	Status     string               `json:"status"`
+	Components map[string]Component `json:"components,omitempty"`
+}
+
+// Ready reports whether every component is.
+func (r Report) Ready() bool {
+	return r.Status == StatusOK
+}
+
+type named struct {
+	name  string
+	check Check
+}
+
+// Health runs the checks of the components, added before serving.
+type Health struct {
+	timeout      time.Duration
+	checks       []named
+	shuttingDown atomic.Bool
+}
+
+// New gives each check timeout to answer, 2s when 0.
+func New(timeout time.Duration) *Health {
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+
+	return &Health{
+		timeout: timeout,
+	}
+}
+
+// Add checks the component name on every Ready.
+func (h *Health) Add(name string, check Check) {
+	h.checks = append(h.checks, named{name: name, check: check})
+}
+
+// ShutDown makes Ready fail from now on, so load balancers stop sending
+// requests while the ones under way finish.
+func (h *Health) ShutDown() {
+	h.shuttingDown.Store(true)
+}
+
+// Ready runs the checks side by side, the report fails when one of them
+// fails or doesn't answer in time. Nothing is checked once shutting down.
+func (h *Health) Ready(ctx context.Context) Report {
+	if h.shuttingDown.Load() {
+		return Report{Status: StatusShuttingDown}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		report = Report{
+			Status:     StatusOK,
+			Components: make(map[string]Component, len(h.checks)),
+		}
+	)
+
+	for _, c := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			start := time.Now()
+			err := run(ctx, c.check)
+
+			component := Component{
+				Status:   StatusOK,
+				Duration: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if err != nil {
+				component.Status = StatusFailing
+				component.Error, _, _ = strings.Cut(err.Error(), "\n")
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			report.Components[c.name] = component
+			if err != nil {
+				report.Status = StatusFailing
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return report
+}
+
+// run is the error of check, or of ctx when check doesn't return in time.
+func run(ctx context.Context, check Check) error {
+	done := make(chan error, 1)
+	go func() { done <- check(ctx) }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,48 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zuhrulumam/go-parking-lot/pkg/health"
+)
+
+func TestReady(t *testing.T) {
+	ctx := context.Background()
+	ok := func(ctx context.Context) error { return nil }
+
+	h := health.New(50 * time.Millisecond)
+	h.Add("database", ok)
+	h.Add("spots", ok)
+
+	r := h.Ready(ctx)
+	assert.True(t, r.Ready())
+	assert.Equal(t, health.StatusOK, r.Components["database"].Status)
+	assert.Equal(t, health.StatusOK, r.Components["spots"].Status)
+
+	// one failing or hanging component fails the report, the others still
+	// report
+	h.Add("migrations", func(ctx context.Context) error { return errors.New("2 migration(s) pending\nstack") })
+	h.Add("hold_sweeper", func(ctx context.Context) error { time.Sleep(200 * time.Millisecond); return nil })
+
+	r = h.Ready(ctx)
+	assert.False(t, r.Ready())
+	assert.Equal(t, health.StatusFailing, r.Status)
+	assert.Equal(t, health.StatusOK, r.Components["database"].Status)
+	assert.Equal(t, health.Component{Status: health.StatusFailing, Error: "2 migration(s) pending"}, withoutDuration(r.Components["migrations"]))
+	assert.Equal(t, health.Component{Status: health.StatusFailing, Error: context.DeadlineExceeded.Error()}, withoutDuration(r.Components["hold_sweeper"]))
+
+	h.ShutDown()
+
+	r = h.Ready(ctx)
+	assert.False(t, r.Ready())
+	assert.Equal(t, health.Report{Status: health.StatusShuttingDown}, r)
+}
+
+func withoutDuration(c health.Component) health.Component {
+	c.Duration = 0
+	return c
+}
@@ -40,8 +40,12 @@ func NewZapLogger() *zap.Logger {
 }
 
 func LogWithCtx(ctx context.Context, logger *zap.Logger, msg string, fields ...zap.Field) {
+	// empty when the request isn't traced
+	traceID, _ := ctx.Value(ctxkeys.CtxKeyTraceID).(string)
+
 	logger.With(
 		zap.String("correlation_id", ctx.Value(ctxkeys.CtxKeyCorrelationID).(string)),
+		zap.String("trace_id", traceID),
 		zap.String("path", ctx.Value(ctxkeys.CtxKeyPath).(string)),
 		zap.String("method", ctx.Value(ctxkeys.CtxKeyMethod).(string)),
 	).Info(msg, fields...)
@@ -0,0 +1,101 @@
+package memstore
+
+import (
+	"context"
+	"sync"
+)
+
+// Table is any piece of in-memory state owned by a domain. Snapshot must
+// return a function that puts the state back exactly as it was when
+// Snapshot was called.
+type Table interface {
+	Snapshot() (restore func())
+}
+
+type txKey struct{}
+
+// tx is the transaction RunInTx puts in the context.
+type tx struct {
+	store       *Store
+	afterCommit []func()
+}
+
+// Store serializes access to every registered table and gives RunInTx real
+// rollback semantics by snapshotting all tables before the transaction body
+// runs.
+type Store struct {
+	mu     sync.Mutex
+	tables []Table
+}
+
+func New() *Store {
+	return &Store{}
+}
+
+// Register adds a table to the store. It must be called before the store is
+// used concurrently.
+func (s *Store) Register(t Table) {
+	s.tables = append(s.tables, t)
+}
+
+// RunInTx runs fn while holding the store lock. If fn returns an error every
+// registered table is restored to its state before fn ran. Nested calls join
+// the outer transaction.
+func (s *Store) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if s.inTx(ctx) {
+		return fn(ctx)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	restores := make([]func(), 0, len(s.tables))
+	for _, t := range s.tables {
+		restores = append(restores, t.Snapshot())
+	}
+
+	t := &tx{store: s}
+
+	err := fn(context.WithValue(ctx, txKey{}, t))
+	if err != nil {
+		for _, restore := range restores {
+			restore()
+		}
+		return err
+	}
+
+	for _, hook := range t.afterCommit {
+		hook()
+	}
+
+	return nil
+}
+
+// AfterCommit runs fn once the transaction of ctx commits, and never if it
+// rolls back. Outside RunInTx fn runs right away.
+func (s *Store) AfterCommit(ctx context.Context, fn func()) {
+	if t, ok := ctx.Value(txKey{}).(*tx); ok && t.store == s {
+		t.afterCommit = append(t.afterCommit, fn)
+		return
+	}
+
+	fn()
+}
+
+// Do runs fn with exclusive access to the store. Inside RunInTx the lock is
+// already held, so fn is called directly.
+func (s *Store) Do(ctx context.Context, fn func() error) error {
+	if s.inTx(ctx) {
+		return fn()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return fn()
+}
+
+func (s *Store) inTx(ctx context.Context) bool {
+	t, ok := ctx.Value(txKey{}).(*tx)
+	return ok && t.store == s
+}
@@ -0,0 +1,40 @@
+// Package metrics is what the handlers, usecases and domains report
+// through. Prometheus exports it, Noop drops it.
+package metrics
+
+import (
+	"time"
+)
+
+type Metrics interface {
+	// ObserveRequest records an HTTP request by its route pattern.
+	ObserveRequest(method, route string, status int, d time.Duration)
+
+	// Park and Unpark count how parking a vehicle type ended, outcome is
+	// OutcomeOK or why it failed.
+	Park(vehicleType, outcome string)
+	Unpark(vehicleType, outcome string)
+
+	// Tx records a database transaction, rolledBack when it didn't commit.
+	Tx(d time.Duration, rolledBack bool)
+}
+
+// OutcomeOK is the outcome of a park or unpark that went through.
+const OutcomeOK = "ok"
+
+// Noop records nothing, the default of every layer.
+type Noop struct{}
+
+func (Noop) ObserveRequest(method, route string, status int, d time.Duration) {}
+func (Noop) Park(vehicleType, outcome string)                                 {}
+func (Noop) Unpark(vehicleType, outcome string)                               {}
+func (Noop) Tx(d time.Duration, rolledBack bool)                              {}
+
+// OrNoop is m, or Noop when m is nil.
+func OrNoop(m Metrics) Metrics {
+	if m == nil {
+		return Noop{}
+	}
+
+	return m
+}
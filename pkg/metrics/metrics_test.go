@@ -0,0 +1,78 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg/metrics"
+)
+
+func TestPrometheus(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := metrics.NewPrometheus(reg)
+
+	m.ObserveRequest("POST", "/lots/:lot_id/park", 201, 20*time.Millisecond)
+	m.Park("M", metrics.OutcomeOK)
+	m.Park("M", metrics.OutcomeOK)
+	m.Park("B", "full")
+	m.Unpark("M", metrics.OutcomeOK)
+	m.Tx(time.Millisecond, false)
+	m.Tx(time.Millisecond, true)
+
+	err := testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP parking_park_total Vehicles parking by vehicle type and outcome, ok or why it failed.
+# TYPE parking_park_total counter
+parking_park_total{outcome="full",vehicle_type="B"} 1
+parking_park_total{outcome="ok",vehicle_type="M"} 2
+# HELP parking_unpark_total Vehicles leaving by vehicle type and outcome, ok or why it failed.
+# TYPE parking_unpark_total counter
+parking_unpark_total{outcome="ok",vehicle_type="M"} 1
+# HELP parking_tx_rollbacks_total Database transactions rolled back.
+# TYPE parking_tx_rollbacks_total counter
+parking_tx_rollbacks_total 1
+`), "parking_park_total", "parking_unpark_total", "parking_tx_rollbacks_total")
+	assert.NoError(t, err)
+
+	n, err := testutil.GatherAndCount(reg, "parking_http_request_duration_seconds", "parking_tx_duration_seconds")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	// the no-op default takes the same calls
+	metrics.OrNoop(nil).Park("M", metrics.OutcomeOK)
+}
+
+func TestOccupancyCollector(t *testing.T) {
+	c := metrics.NewOccupancyCollector(func(ctx context.Context) (map[uint][]entity.SpotCount, error) {
+		return map[uint][]entity.SpotCount{
+			1: {{Floor: 1, Type: "M", Total: 4, Active: 3, Occupied: 1, Reserved: 1, Free: 1}},
+		}, nil
+	})
+
+	err := testutil.CollectAndCompare(c, strings.NewReader(`
+# HELP parking_spots Spots by lot, floor, type and state.
+# TYPE parking_spots gauge
+parking_spots{floor="1",lot="1",state="active",type="M"} 3
+parking_spots{floor="1",lot="1",state="free",type="M"} 1
+parking_spots{floor="1",lot="1",state="occupied",type="M"} 1
+parking_spots{floor="1",lot="1",state="reserved",type="M"} 1
+parking_spots{floor="1",lot="1",state="total",type="M"} 4
+`))
+	assert.NoError(t, err)
+
+	// a failed count fails the scrape of the gauges, not the rest
+	failing := metrics.NewOccupancyCollector(func(ctx context.Context) (map[uint][]entity.SpotCount, error) {
+		return nil, errors.New("db down")
+	})
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(failing)
+	_, err = reg.Gather()
+	assert.ErrorContains(t, err, "db down")
+}
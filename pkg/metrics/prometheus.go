@@ -0,0 +1,133 @@
+package metrics
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+)
+
+const namespace = "parking"
+
+// Prometheus exports the metrics to Prometheus.
+type Prometheus struct {
+	requests  *prometheus.HistogramVec
+	parks     *prometheus.CounterVec
+	unparks   *prometheus.CounterVec
+	txs       prometheus.Histogram
+	rollbacks prometheus.Counter
+}
+
+// NewPrometheus registers the metrics on reg.
+func NewPrometheus(reg prometheus.Registerer) *Prometheus {
+	p := &Prometheus{
+		requests: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of the HTTP requests by route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		parks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "park_total",
+			Help:      "Vehicles parking by vehicle type and outcome, ok or why it failed.",
+		}, []string{"vehicle_type", "outcome"}),
+		unparks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "unpark_total",
+			Help:      "Vehicles leaving by vehicle type and outcome, ok or why it failed.",
+		}, []string{"vehicle_type", "outcome"}),
+		txs: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "tx_duration_seconds",
+			Help:      "Duration of the database transactions.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		rollbacks: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "tx_rollbacks_total",
+			Help:      "Database transactions rolled back.",
+		}),
+	}
+
+	reg.MustRegister(p.requests, p.parks, p.unparks, p.txs, p.rollbacks)
+
+	return p
+}
+
+func (p *Prometheus) ObserveRequest(method, route string, status int, d time.Duration) {
+	p.requests.WithLabelValues(method, route, strconv.Itoa(status)).Observe(d.Seconds())
+}
+
+func (p *Prometheus) Park(vehicleType, outcome string) {
+	p.parks.WithLabelValues(vehicleType, outcome).Inc()
+}
+
+func (p *Prometheus) Unpark(vehicleType, outcome string) {
+	p.unparks.WithLabelValues(vehicleType, outcome).Inc()
+}
+
+func (p *Prometheus) Tx(d time.Duration, rolledBack bool) {
+	p.txs.Observe(d.Seconds())
+	if rolledBack {
+		p.rollbacks.Inc()
+	}
+}
+
+// SpotCounter counts the spots of every lot by floor and type.
+type SpotCounter func(ctx context.Context) (map[uint][]entity.SpotCount, error)
+
+// occupancyCollector reads the spot counts on every scrape, so the gauges
+// are as live as the database.
+type occupancyCollector struct {
+	count   SpotCounter
+	timeout time.Duration
+	spots   *prometheus.Desc
+}
+
+// NewOccupancyCollector exports the spots of count per lot, floor, type
+// and state (total, active, occupied, reserved, free).
+func NewOccupancyCollector(count SpotCounter) prometheus.Collector {
+	return &occupancyCollector{
+		count:   count,
+		timeout: 5 * time.Second,
+		spots: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "spots"),
+			"Spots by lot, floor, type and state.",
+			[]string{"lot", "floor", "type", "state"}, nil,
+		),
+	}
+}
+
+func (o *occupancyCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- o.spots
+}
+
+func (o *occupancyCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), o.timeout)
+	defer cancel()
+
+	lots, err := o.count(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(o.spots, err)
+		return
+	}
+
+	for lot, counts := range lots {
+		for _, c := range counts {
+			labels := []string{strconv.FormatUint(uint64(lot), 10), strconv.Itoa(c.Floor), c.Type}
+
+			for state, n := range map[string]int{
+				"total":    c.Total,
+				"active":   c.Active,
+				"occupied": c.Occupied,
+				"reserved": c.Reserved,
+				"free":     c.Free,
+			} {
+				ch <- prometheus.MustNewConstMetric(o.spots, prometheus.GaugeValue, float64(n), append(labels, state)...)
+			}
+		}
+	}
+}
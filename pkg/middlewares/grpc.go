@@ -0,0 +1,152 @@
+package middlewares
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zuhrulumam/go-parking-lot/pkg/ctxkeys"
+	"github.com/zuhrulumam/go-parking-lot/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryRequestContextInterceptor is RequestContextMiddleware for gRPC
+// calls. The correlation id comes from the x-correlation-id metadata.
+func UnaryRequestContextInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+
+		ctx, span := requestContext(ctx, info.FullMethod)
+		resp, err := handler(ctx, req)
+
+		endRPC(span, err)
+		logRPC(logger, ctx, info.FullMethod, start, err)
+
+		return resp, err
+	}
+}
+
+// StreamRequestContextInterceptor is UnaryRequestContextInterceptor for
+// streaming calls, logged once the stream ends.
+func StreamRequestContextInterceptor(logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+
+		ctx, span := requestContext(ss.Context(), info.FullMethod)
+		err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+
+		endRPC(span, err)
+		logRPC(logger, ctx, info.FullMethod, start, err)
+
+		return err
+	}
+}
+
+// contextStream replaces the context of a server stream.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// requestContext is the context of a call, in a span continuing the trace
+// of the traceparent metadata.
+func requestContext(ctx context.Context, method string) (context.Context, trace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	service, name, _ := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+	ctx, span := tracing.Start(
+		otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md)),
+		method,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(semconv.RPCSystemGRPC, semconv.RPCService(service), semconv.RPCMethod(name)),
+	)
+
+	// Generate correlation ID if not present
+	correlationID := uuid.New().String()
+	if v := md.Get("x-correlation-id"); len(v) > 0 && v[0] != "" {
+		correlationID = v[0]
+	}
+
+	var ip, port, src string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		src = p.Addr.String()
+		ip, port, _ = net.SplitHostPort(src)
+	}
+
+	ctx = context.WithValue(ctx, ctxkeys.CtxKeyCorrelationID, correlationID)
+	ctx = context.WithValue(ctx, ctxkeys.CtxKeyApp, "parking-service")
+	ctx = context.WithValue(ctx, ctxkeys.CtxKeyRuntime, "go")
+	ctx = context.WithValue(ctx, ctxkeys.CtxKeyEnv, "production") // or from env
+	ctx = context.WithValue(ctx, ctxkeys.CtxKeyAppVersion, "v1.0.0")
+	ctx = context.WithValue(ctx, ctxkeys.CtxKeyPath, method)
+	ctx = context.WithValue(ctx, ctxkeys.CtxKeyMethod, "GRPC")
+	ctx = context.WithValue(ctx, ctxkeys.CtxKeyIP, ip)
+	ctx = context.WithValue(ctx, ctxkeys.CtxKeyPort, port)
+	ctx = context.WithValue(ctx, ctxkeys.CtxKeySrcIP, src)
+	ctx = context.WithValue(ctx, ctxkeys.CtxKeyHeader, map[string][]string(md))
+	ctx = context.WithValue(ctx, ctxkeys.CtxKeyTraceID, tracing.TraceID(ctx))
+
+	return ctx, span
+}
+
+// endRPC ends the span of a call, failed on the codes of server errors.
+func endRPC(span trace.Span, err error) {
+	code := status.Code(err)
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(code)))
+
+	switch code {
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented, codes.Internal, codes.Unavailable, codes.DataLoss:
+		span.SetStatus(otelcodes.Error, status.Convert(err).Message())
+	}
+
+	span.End()
+}
+
+func logRPC(logger *zap.Logger, ctx context.Context, method string, start time.Time, err error) {
+	logger.Info("gRPC Request",
+		zap.String("path", method),
+		zap.String("correlation_id", ctx.Value(ctxkeys.CtxKeyCorrelationID).(string)),
+		zap.String("trace_id", ctx.Value(ctxkeys.CtxKeyTraceID).(string)),
+		zap.String("code", status.Code(err).String()),
+		zap.String("duration", time.Since(start).String()),
+	)
+}
+
+// metadataCarrier reads the trace context of the call metadata.
+type metadataCarrier metadata.MD
+
+func (m metadataCarrier) Get(key string) string {
+	if v := metadata.MD(m).Get(key); len(v) > 0 {
+		return v[0]
+	}
+
+	return ""
+}
+
+func (m metadataCarrier) Set(key, value string) {
+	metadata.MD(m).Set(key, value)
+}
+
+func (m metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
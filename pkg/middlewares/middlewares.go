@@ -2,14 +2,24 @@ package middlewares
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
 	"github.com/google/uuid"
 	"github.com/zuhrulumam/go-parking-lot/pkg/ctxkeys"
+	"github.com/zuhrulumam/go-parking-lot/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
+// credentialHeaders are logged as redacted.
+var credentialHeaders = []string{fiber.HeaderAuthorization, "X-Api-Key", "X-Admin-Token"}
+
 func RequestContextMiddleware(logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		start := time.Now()
@@ -17,8 +27,22 @@ func RequestContextMiddleware(logger *zap.Logger) fiber.Handler {
 		// Generate correlation ID if not present
 		correlationID := c.Get("X-Correlation-ID", uuid.New().String())
 
+		// continue the trace of the traceparent header, the route is only
+		// known once matched
+		method := utils.CopyString(c.Method())
+		ctx, span := tracing.Start(
+			otel.GetTextMapPropagator().Extract(c.Context(), headerCarrier{c}),
+			method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(method),
+				semconv.URLPath(utils.CopyString(c.Path())),
+			),
+		)
+		traceID := tracing.TraceID(ctx)
+
 		// Create context with values
-		ctx := context.WithValue(c.Context(), ctxkeys.CtxKeyCorrelationID, correlationID)
+		ctx = context.WithValue(ctx, ctxkeys.CtxKeyCorrelationID, correlationID)
 		ctx = context.WithValue(ctx, ctxkeys.CtxKeyApp, "parking-service")
 		ctx = context.WithValue(ctx, ctxkeys.CtxKeyRuntime, "go")
 		ctx = context.WithValue(ctx, ctxkeys.CtxKeyEnv, "production") // or from env
@@ -29,6 +53,7 @@ func RequestContextMiddleware(logger *zap.Logger) fiber.Handler {
 		ctx = context.WithValue(ctx, ctxkeys.CtxKeyPort, c.Port())
 		ctx = context.WithValue(ctx, ctxkeys.CtxKeySrcIP, c.Context().RemoteAddr().String())
 		ctx = context.WithValue(ctx, ctxkeys.CtxKeyHeader, c.GetReqHeaders())
+		ctx = context.WithValue(ctx, ctxkeys.CtxKeyTraceID, traceID)
 
 		// Store context
 		c.Locals("ctx", ctx)
@@ -39,16 +64,75 @@ func RequestContextMiddleware(logger *zap.Logger) fiber.Handler {
 		// End time
 		duration := time.Since(start)
 
+		status := responseStatus(c, err)
+		route := c.Route().Path
+
+		span.SetName(method + " " + route)
+		span.SetAttributes(semconv.HTTPRoute(route), semconv.HTTPResponseStatusCode(status))
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, "")
+		}
+		span.End()
+
 		// Response status and logging
 		logger.Info("HTTP Request",
 			zap.String("path", c.Path()),
 			zap.String("method", c.Method()),
 			zap.String("correlation_id", correlationID),
+			zap.String("trace_id", traceID),
 			zap.Int("status", c.Response().StatusCode()),
 			zap.String("duration", duration.String()),
-			zap.Any("header", c.GetReqHeaders()),
+			zap.Any("header", redact(c.GetReqHeaders())),
 		)
 
 		return err
 	}
 }
+
+// responseStatus is the status of the response, an error left to the fiber
+// error handler has none yet.
+func responseStatus(c *fiber.Ctx, err error) int {
+	if err == nil {
+		return c.Response().StatusCode()
+	}
+
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		return fe.Code
+	}
+
+	return fiber.StatusInternalServerError
+}
+
+// headerCarrier reads the trace context of the request headers.
+type headerCarrier struct {
+	c *fiber.Ctx
+}
+
+func (h headerCarrier) Get(key string) string {
+	return utils.CopyString(h.c.Get(key))
+}
+
+func (h headerCarrier) Set(key, value string) {
+	h.c.Request().Header.Set(key, value)
+}
+
+func (h headerCarrier) Keys() []string {
+	var keys []string
+	h.c.Request().Header.VisitAll(func(k, _ []byte) {
+		keys = append(keys, string(k))
+	})
+
+	return keys
+}
+
+// redact hides the credentials of the headers.
+func redact(headers map[string][]string) map[string][]string {
+	for _, h := range credentialHeaders {
+		if _, ok := headers[h]; ok {
+			headers[h] = []string{"REDACTED"}
+		}
+	}
+
+	return headers
+}
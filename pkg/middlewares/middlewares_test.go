@@ -0,0 +1,52 @@
+package middlewares_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/zuhrulumam/go-parking-lot/pkg/ctxkeys"
+	"github.com/zuhrulumam/go-parking-lot/pkg/middlewares"
+	"github.com/zuhrulumam/go-parking-lot/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+)
+
+func TestRequestContextMiddlewareTrace(t *testing.T) {
+	_, err := tracing.Init(context.Background(), tracing.ExporterNone, "test")
+	assert.NoError(t, err)
+
+	rec := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	defer otel.SetTracerProvider(prev)
+
+	var traceID string
+
+	app := fiber.New()
+	app.Use(middlewares.RequestContextMiddleware(zap.NewNop()))
+	app.Get("/lots/:lot_id", func(c *fiber.Ctx) error {
+		traceID = c.Locals("ctx").(context.Context).Value(ctxkeys.CtxKeyTraceID).(string)
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	req := httptest.NewRequest("GET", "/lots/1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+
+	// the trace of the caller goes on
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceID)
+
+	spans := rec.Ended()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, "GET /lots/:lot_id", spans[0].Name())
+		assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+	}
+}
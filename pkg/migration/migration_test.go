@@ -0,0 +1,184 @@
+package migration_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"github.com/zuhrulumam/go-parking-lot/pkg/migration"
+)
+
+var testMigrations = []migration.Migration{
+	{Version: 2, Name: "add_b", Up: "CREATE TABLE b (id INT)", Down: "DROP TABLE b"},
+	{Version: 1, Name: "add_a", Up: "CREATE TABLE a (id INT)", Down: "DROP TABLE a"},
+}
+
+func expectApplied(mock sqlmock.Sqlmock, versions ...int) {
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS schema_migrations`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	rows := sqlmock.NewRows([]string{"version", "name", "applied_at"})
+	for _, v := range versions {
+		rows.AddRow(v, "applied", time.Now())
+	}
+	mock.ExpectQuery(`SELECT \* FROM "schema_migrations" ORDER BY version`).
+		WillReturnRows(rows)
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       fstest.MapFS
+		expectError bool
+		expected    []migration.Migration
+	}{
+		{
+			name: "ordered by version",
+			files: fstest.MapFS{
+				"0002_add_b.up.sql":   {Data: []byte("up b")},
+				"0002_add_b.down.sql": {Data: []byte("down b")},
+				"0001_add_a.up.sql":   {Data: []byte("up a")},
+			},
+			expected: []migration.Migration{
+				{Version: 1, Name: "add_a", Up: "up a"},
+				{Version: 2, Name: "add_b", Up: "up b", Down: "down b"},
+			},
+		},
+		{
+			name: "invalid file name",
+			files: fstest.MapFS{
+				"add_a.sql": {Data: []byte("up a")},
+			},
+			expectError: true,
+		},
+		{
+			name: "missing up file",
+			files: fstest.MapFS{
+				"0001_add_a.down.sql": {Data: []byte("down a")},
+			},
+			expectError: true,
+		},
+		{
+			name: "conflicting names",
+			files: fstest.MapFS{
+				"0001_add_a.up.sql":   {Data: []byte("up a")},
+				"0001_add_b.down.sql": {Data: []byte("down b")},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := migration.Load(tt.files)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestUp(t *testing.T) {
+	db, mock, cleanup := pkg.SetupMockDB(t)
+	defer cleanup()
+
+	// 1 is applied, only 2 must run
+	expectApplied(mock, 1)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE b (id INT)")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO "schema_migrations"`).
+		WithArgs(2, "add_b", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	m := migration.New(migration.Option{DB: db, Migrations: testMigrations})
+	done, err := m.Up(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, done, 1)
+	assert.Equal(t, 2, done[0].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpFailureRollsBack(t *testing.T) {
+	db, mock, cleanup := pkg.SetupMockDB(t)
+	defer cleanup()
+
+	expectApplied(mock)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE a (id INT)")).
+		WillReturnError(assert.AnError)
+	mock.ExpectRollback()
+
+	m := migration.New(migration.Option{DB: db, Migrations: testMigrations})
+	done, err := m.Up(context.Background())
+
+	assert.Error(t, err)
+	assert.Empty(t, done)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDown(t *testing.T) {
+	db, mock, cleanup := pkg.SetupMockDB(t)
+	defer cleanup()
+
+	expectApplied(mock, 1, 2)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DROP TABLE b")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM "schema_migrations" WHERE version = \$1`).
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	m := migration.New(migration.Option{DB: db, Migrations: testMigrations})
+	done, err := m.Down(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, done, 1)
+	assert.Equal(t, 2, done[0].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestToUnknownVersion(t *testing.T) {
+	db, _, cleanup := pkg.SetupMockDB(t)
+	defer cleanup()
+
+	m := migration.New(migration.Option{DB: db, Migrations: testMigrations})
+	_, err := m.To(context.Background(), 3)
+
+	assert.Error(t, err)
+}
+
+func TestStatusAndPending(t *testing.T) {
+	db, mock, cleanup := pkg.SetupMockDB(t)
+	defer cleanup()
+
+	expectApplied(mock, 1)
+	expectApplied(mock, 1)
+
+	m := migration.New(migration.Option{DB: db, Migrations: testMigrations})
+
+	status, err := m.Status(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, status, 2)
+	assert.True(t, status[0].Applied)
+	assert.False(t, status[1].Applied)
+
+	pending, err := m.Pending(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+	assert.Equal(t, 2, pending[0].Version)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
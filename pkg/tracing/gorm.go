@@ -0,0 +1,99 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// parentKey keeps the context of a statement from before its span.
+const parentKey = "tracing:parent"
+
+// gormPlugin makes a child span of every SQL statement, named after the
+// operation and table. The query text carries placeholders, never values.
+type gormPlugin struct{}
+
+// NewGormPlugin traces the SQL statements of the db it is used on.
+func NewGormPlugin() gorm.Plugin {
+	return gormPlugin{}
+}
+
+func (gormPlugin) Name() string {
+	return "tracing"
+}
+
+func (p gormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+
+	for _, err := range []error{
+		cb.Create().Before("*").Register("tracing:before_create", p.before("INSERT")),
+		cb.Create().After("*").Register("tracing:after_create", p.after("INSERT")),
+		cb.Query().Before("*").Register("tracing:before_query", p.before("SELECT")),
+		cb.Query().After("*").Register("tracing:after_query", p.after("SELECT")),
+		cb.Update().Before("*").Register("tracing:before_update", p.before("UPDATE")),
+		cb.Update().After("*").Register("tracing:after_update", p.after("UPDATE")),
+		cb.Delete().Before("*").Register("tracing:before_delete", p.before("DELETE")),
+		cb.Delete().After("*").Register("tracing:after_delete", p.after("DELETE")),
+		cb.Row().Before("*").Register("tracing:before_row", p.before("ROW")),
+		cb.Row().After("*").Register("tracing:after_row", p.after("ROW")),
+		cb.Raw().Before("*").Register("tracing:before_raw", p.before("RAW")),
+		cb.Raw().After("*").Register("tracing:after_raw", p.after("RAW")),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (gormPlugin) before(op string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx := db.Statement.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		db.InstanceSet(parentKey, ctx)
+
+		db.Statement.Context, _ = Start(ctx, op,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemNamePostgreSQL,
+				semconv.DBOperationName(op),
+			),
+		)
+	}
+}
+
+func (gormPlugin) after(op string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		span := trace.SpanFromContext(db.Statement.Context)
+
+		if table := db.Statement.Table; table != "" {
+			span.SetName(op + " " + table)
+			span.SetAttributes(semconv.DBCollectionName(table))
+		}
+		span.SetAttributes(
+			semconv.DBQueryText(db.Statement.SQL.String()),
+			attribute.Int64("db.rows_affected", db.RowsAffected),
+		)
+
+		// a missing row is an answer, not a failure
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			span.RecordError(db.Error)
+			span.SetStatus(codes.Error, db.Error.Error())
+		}
+
+		span.End()
+
+		if parent, ok := db.InstanceGet(parentKey); ok {
+			db.Statement.Context = parent.(context.Context)
+		}
+	}
+}
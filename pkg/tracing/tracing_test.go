@@ -0,0 +1,81 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zuhrulumam/go-parking-lot/business/entity"
+	"github.com/zuhrulumam/go-parking-lot/pkg"
+	"github.com/zuhrulumam/go-parking-lot/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// record makes the global tracer provider record the ended spans.
+func record(t *testing.T) *tracetest.SpanRecorder {
+	rec := tracetest.NewSpanRecorder()
+
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	return rec
+}
+
+func attr(span sdktrace.ReadOnlySpan, key attribute.Key) string {
+	for _, a := range span.Attributes() {
+		if a.Key == key {
+			return a.Value.Emit()
+		}
+	}
+
+	return ""
+}
+
+func TestGormPlugin(t *testing.T) {
+	rec := record(t)
+
+	db, mock, cleanup := pkg.SetupMockDB(t)
+	defer cleanup()
+
+	assert.NoError(t, db.Use(tracing.NewGormPlugin()))
+
+	mock.ExpectQuery(`SELECT \* FROM "lots"`).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Main"))
+	mock.ExpectQuery(`SELECT \* FROM "lots"`).WillReturnError(errors.New("db error"))
+
+	ctx, parent := tracing.Start(context.Background(), "parent")
+
+	var lots []entity.Lot
+	assert.NoError(t, db.WithContext(ctx).Find(&lots).Error)
+	assert.Error(t, db.WithContext(ctx).Find(&lots).Error)
+
+	parent.End()
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	spans := rec.Ended()
+	if assert.Len(t, spans, 3) {
+		for _, s := range spans[:2] {
+			assert.Equal(t, "SELECT lots", s.Name())
+			assert.Equal(t, parent.SpanContext().SpanID(), s.Parent().SpanID(), "a child of the span of the context")
+			assert.Equal(t, `SELECT * FROM "lots"`, attr(s, "db.query.text"))
+		}
+
+		assert.Equal(t, codes.Unset, spans[0].Status().Code)
+		assert.Equal(t, codes.Error, spans[1].Status().Code)
+	}
+}
+
+func TestInit(t *testing.T) {
+	shutdown, err := tracing.Init(context.Background(), tracing.ExporterNone, "test")
+	assert.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	_, err = tracing.Init(context.Background(), "jaeger", "test")
+	assert.Error(t, err)
+}
@@ -0,0 +1,5 @@
+// Package parkingv1 holds the protobuf messages and gRPC service of the
+// parking API, generated from parking.proto.
+package parkingv1
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative parking.proto
@@ -0,0 +1,1596 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: parking.proto
+
+package parkingv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type VehicleType int32
+
+const (
+	VehicleType_VEHICLE_TYPE_UNSPECIFIED VehicleType = 0
+	VehicleType_VEHICLE_TYPE_BICYCLE     VehicleType = 1
+	VehicleType_VEHICLE_TYPE_MOTORCYCLE  VehicleType = 2
+	VehicleType_VEHICLE_TYPE_AUTOMOBILE  VehicleType = 3
+)
+
+// Enum value maps for VehicleType.
+var (
+	VehicleType_name = map[int32]string{
+		0: "VEHICLE_TYPE_UNSPECIFIED",
+		1: "VEHICLE_TYPE_BICYCLE",
+		2: "VEHICLE_TYPE_MOTORCYCLE",
+		3: "VEHICLE_TYPE_AUTOMOBILE",
+	}
+	VehicleType_value = map[string]int32{
+		"VEHICLE_TYPE_UNSPECIFIED": 0,
+		"VEHICLE_TYPE_BICYCLE":     1,
+		"VEHICLE_TYPE_MOTORCYCLE":  2,
+		"VEHICLE_TYPE_AUTOMOBILE":  3,
+	}
+)
+
+func (x VehicleType) Enum() *VehicleType {
+	p := new(VehicleType)
+	*p = x
+	return p
+}
+
+func (x VehicleType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (VehicleType) Descriptor() protoreflect.EnumDescriptor {
+	return file_parking_proto_enumTypes[0].Descriptor()
+}
+
+func (VehicleType) Type() protoreflect.EnumType {
+	return &file_parking_proto_enumTypes[0]
+}
+
+func (x VehicleType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use VehicleType.Descriptor instead.
+func (VehicleType) EnumDescriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{0}
+}
+
+type ParkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LotId         uint32                 `protobuf:"varint,1,opt,name=lot_id,json=lotId,proto3" json:"lot_id,omitempty"`
+	VehicleType   VehicleType            `protobuf:"varint,2,opt,name=vehicle_type,json=vehicleType,proto3,enum=parking.v1.VehicleType" json:"vehicle_type,omitempty"`
+	VehicleNumber string                 `protobuf:"bytes,3,opt,name=vehicle_number,json=vehicleNumber,proto3" json:"vehicle_number,omitempty"`
+	// reservation_code checks in a reservation, waitlist_code claims a spot
+	// offered from the waitlist.
+	ReservationCode string `protobuf:"bytes,4,opt,name=reservation_code,json=reservationCode,proto3" json:"reservation_code,omitempty"`
+	WaitlistCode    string `protobuf:"bytes,5,opt,name=waitlist_code,json=waitlistCode,proto3" json:"waitlist_code,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ParkRequest) Reset() {
+	*x = ParkRequest{}
+	mi := &file_parking_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParkRequest) ProtoMessage() {}
+
+func (x *ParkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParkRequest.ProtoReflect.Descriptor instead.
+func (*ParkRequest) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ParkRequest) GetLotId() uint32 {
+	if x != nil {
+		return x.LotId
+	}
+	return 0
+}
+
+func (x *ParkRequest) GetVehicleType() VehicleType {
+	if x != nil {
+		return x.VehicleType
+	}
+	return VehicleType_VEHICLE_TYPE_UNSPECIFIED
+}
+
+func (x *ParkRequest) GetVehicleNumber() string {
+	if x != nil {
+		return x.VehicleNumber
+	}
+	return ""
+}
+
+func (x *ParkRequest) GetReservationCode() string {
+	if x != nil {
+		return x.ReservationCode
+	}
+	return ""
+}
+
+func (x *ParkRequest) GetWaitlistCode() string {
+	if x != nil {
+		return x.WaitlistCode
+	}
+	return ""
+}
+
+type ParkResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ticket        *Ticket                `protobuf:"bytes,1,opt,name=ticket,proto3" json:"ticket,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ParkResponse) Reset() {
+	*x = ParkResponse{}
+	mi := &file_parking_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParkResponse) ProtoMessage() {}
+
+func (x *ParkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParkResponse.ProtoReflect.Descriptor instead.
+func (*ParkResponse) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ParkResponse) GetTicket() *Ticket {
+	if x != nil {
+		return x.Ticket
+	}
+	return nil
+}
+
+type Ticket struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	TicketId    string                 `protobuf:"bytes,1,opt,name=ticket_id,json=ticketId,proto3" json:"ticket_id,omitempty"`
+	LotId       uint32                 `protobuf:"varint,2,opt,name=lot_id,json=lotId,proto3" json:"lot_id,omitempty"`
+	SpotId      string                 `protobuf:"bytes,3,opt,name=spot_id,json=spotId,proto3" json:"spot_id,omitempty"`
+	Floor       int32                  `protobuf:"varint,4,opt,name=floor,proto3" json:"floor,omitempty"`
+	Row         int32                  `protobuf:"varint,5,opt,name=row,proto3" json:"row,omitempty"`
+	Col         int32                  `protobuf:"varint,6,opt,name=col,proto3" json:"col,omitempty"`
+	VehicleType VehicleType            `protobuf:"varint,7,opt,name=vehicle_type,json=vehicleType,proto3,enum=parking.v1.VehicleType" json:"vehicle_type,omitempty"`
+	// spot_type is B, M or A, bigger than the vehicle when the lot fell back.
+	SpotType      string                 `protobuf:"bytes,8,opt,name=spot_type,json=spotType,proto3" json:"spot_type,omitempty"`
+	VehicleNumber string                 `protobuf:"bytes,9,opt,name=vehicle_number,json=vehicleNumber,proto3" json:"vehicle_number,omitempty"`
+	ParkedAt      *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=parked_at,json=parkedAt,proto3" json:"parked_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Ticket) Reset() {
+	*x = Ticket{}
+	mi := &file_parking_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Ticket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ticket) ProtoMessage() {}
+
+func (x *Ticket) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ticket.ProtoReflect.Descriptor instead.
+func (*Ticket) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Ticket) GetTicketId() string {
+	if x != nil {
+		return x.TicketId
+	}
+	return ""
+}
+
+func (x *Ticket) GetLotId() uint32 {
+	if x != nil {
+		return x.LotId
+	}
+	return 0
+}
+
+func (x *Ticket) GetSpotId() string {
+	if x != nil {
+		return x.SpotId
+	}
+	return ""
+}
+
+func (x *Ticket) GetFloor() int32 {
+	if x != nil {
+		return x.Floor
+	}
+	return 0
+}
+
+func (x *Ticket) GetRow() int32 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+func (x *Ticket) GetCol() int32 {
+	if x != nil {
+		return x.Col
+	}
+	return 0
+}
+
+func (x *Ticket) GetVehicleType() VehicleType {
+	if x != nil {
+		return x.VehicleType
+	}
+	return VehicleType_VEHICLE_TYPE_UNSPECIFIED
+}
+
+func (x *Ticket) GetSpotType() string {
+	if x != nil {
+		return x.SpotType
+	}
+	return ""
+}
+
+func (x *Ticket) GetVehicleNumber() string {
+	if x != nil {
+		return x.VehicleNumber
+	}
+	return ""
+}
+
+func (x *Ticket) GetParkedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ParkedAt
+	}
+	return nil
+}
+
+// UnparkRequest names the session by any of ticket_id, spot_id and
+// vehicle_number; when several are given they must agree.
+type UnparkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LotId         uint32                 `protobuf:"varint,1,opt,name=lot_id,json=lotId,proto3" json:"lot_id,omitempty"`
+	TicketId      string                 `protobuf:"bytes,2,opt,name=ticket_id,json=ticketId,proto3" json:"ticket_id,omitempty"`
+	SpotId        string                 `protobuf:"bytes,3,opt,name=spot_id,json=spotId,proto3" json:"spot_id,omitempty"`
+	VehicleNumber string                 `protobuf:"bytes,4,opt,name=vehicle_number,json=vehicleNumber,proto3" json:"vehicle_number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnparkRequest) Reset() {
+	*x = UnparkRequest{}
+	mi := &file_parking_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnparkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnparkRequest) ProtoMessage() {}
+
+func (x *UnparkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnparkRequest.ProtoReflect.Descriptor instead.
+func (*UnparkRequest) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UnparkRequest) GetLotId() uint32 {
+	if x != nil {
+		return x.LotId
+	}
+	return 0
+}
+
+func (x *UnparkRequest) GetTicketId() string {
+	if x != nil {
+		return x.TicketId
+	}
+	return ""
+}
+
+func (x *UnparkRequest) GetSpotId() string {
+	if x != nil {
+		return x.SpotId
+	}
+	return ""
+}
+
+func (x *UnparkRequest) GetVehicleNumber() string {
+	if x != nil {
+		return x.VehicleNumber
+	}
+	return ""
+}
+
+type UnparkResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Receipt       *Receipt               `protobuf:"bytes,1,opt,name=receipt,proto3" json:"receipt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnparkResponse) Reset() {
+	*x = UnparkResponse{}
+	mi := &file_parking_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnparkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnparkResponse) ProtoMessage() {}
+
+func (x *UnparkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnparkResponse.ProtoReflect.Descriptor instead.
+func (*UnparkResponse) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UnparkResponse) GetReceipt() *Receipt {
+	if x != nil {
+		return x.Receipt
+	}
+	return nil
+}
+
+type Receipt struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TicketId      string                 `protobuf:"bytes,1,opt,name=ticket_id,json=ticketId,proto3" json:"ticket_id,omitempty"`
+	LotId         uint32                 `protobuf:"varint,2,opt,name=lot_id,json=lotId,proto3" json:"lot_id,omitempty"`
+	VehicleNumber string                 `protobuf:"bytes,3,opt,name=vehicle_number,json=vehicleNumber,proto3" json:"vehicle_number,omitempty"`
+	VehicleType   VehicleType            `protobuf:"varint,4,opt,name=vehicle_type,json=vehicleType,proto3,enum=parking.v1.VehicleType" json:"vehicle_type,omitempty"`
+	SpotId        string                 `protobuf:"bytes,5,opt,name=spot_id,json=spotId,proto3" json:"spot_id,omitempty"`
+	ParkedAt      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=parked_at,json=parkedAt,proto3" json:"parked_at,omitempty"`
+	UnparkedAt    *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=unparked_at,json=unparkedAt,proto3" json:"unparked_at,omitempty"`
+	Fee           int64                  `protobuf:"varint,8,opt,name=fee,proto3" json:"fee,omitempty"`
+	AmountPaid    int64                  `protobuf:"varint,9,opt,name=amount_paid,json=amountPaid,proto3" json:"amount_paid,omitempty"`
+	AmountDue     int64                  `protobuf:"varint,10,opt,name=amount_due,json=amountDue,proto3" json:"amount_due,omitempty"`
+	// status is awaiting_payment until the fee is paid, then closed.
+	Status        string `protobuf:"bytes,11,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Receipt) Reset() {
+	*x = Receipt{}
+	mi := &file_parking_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Receipt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Receipt) ProtoMessage() {}
+
+func (x *Receipt) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Receipt.ProtoReflect.Descriptor instead.
+func (*Receipt) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Receipt) GetTicketId() string {
+	if x != nil {
+		return x.TicketId
+	}
+	return ""
+}
+
+func (x *Receipt) GetLotId() uint32 {
+	if x != nil {
+		return x.LotId
+	}
+	return 0
+}
+
+func (x *Receipt) GetVehicleNumber() string {
+	if x != nil {
+		return x.VehicleNumber
+	}
+	return ""
+}
+
+func (x *Receipt) GetVehicleType() VehicleType {
+	if x != nil {
+		return x.VehicleType
+	}
+	return VehicleType_VEHICLE_TYPE_UNSPECIFIED
+}
+
+func (x *Receipt) GetSpotId() string {
+	if x != nil {
+		return x.SpotId
+	}
+	return ""
+}
+
+func (x *Receipt) GetParkedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ParkedAt
+	}
+	return nil
+}
+
+func (x *Receipt) GetUnparkedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UnparkedAt
+	}
+	return nil
+}
+
+func (x *Receipt) GetFee() int64 {
+	if x != nil {
+		return x.Fee
+	}
+	return 0
+}
+
+func (x *Receipt) GetAmountPaid() int64 {
+	if x != nil {
+		return x.AmountPaid
+	}
+	return 0
+}
+
+func (x *Receipt) GetAmountDue() int64 {
+	if x != nil {
+		return x.AmountDue
+	}
+	return 0
+}
+
+func (x *Receipt) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type AvailableSpotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LotId         uint32                 `protobuf:"varint,1,opt,name=lot_id,json=lotId,proto3" json:"lot_id,omitempty"`
+	VehicleType   VehicleType            `protobuf:"varint,2,opt,name=vehicle_type,json=vehicleType,proto3,enum=parking.v1.VehicleType" json:"vehicle_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AvailableSpotRequest) Reset() {
+	*x = AvailableSpotRequest{}
+	mi := &file_parking_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AvailableSpotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AvailableSpotRequest) ProtoMessage() {}
+
+func (x *AvailableSpotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AvailableSpotRequest.ProtoReflect.Descriptor instead.
+func (*AvailableSpotRequest) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AvailableSpotRequest) GetLotId() uint32 {
+	if x != nil {
+		return x.LotId
+	}
+	return 0
+}
+
+func (x *AvailableSpotRequest) GetVehicleType() VehicleType {
+	if x != nil {
+		return x.VehicleType
+	}
+	return VehicleType_VEHICLE_TYPE_UNSPECIFIED
+}
+
+type AvailableSpotResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Spots         []*Spot                `protobuf:"bytes,1,rep,name=spots,proto3" json:"spots,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AvailableSpotResponse) Reset() {
+	*x = AvailableSpotResponse{}
+	mi := &file_parking_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AvailableSpotResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AvailableSpotResponse) ProtoMessage() {}
+
+func (x *AvailableSpotResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AvailableSpotResponse.ProtoReflect.Descriptor instead.
+func (*AvailableSpotResponse) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AvailableSpotResponse) GetSpots() []*Spot {
+	if x != nil {
+		return x.Spots
+	}
+	return nil
+}
+
+type Spot struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	SpotId string                 `protobuf:"bytes,1,opt,name=spot_id,json=spotId,proto3" json:"spot_id,omitempty"`
+	LotId  uint32                 `protobuf:"varint,2,opt,name=lot_id,json=lotId,proto3" json:"lot_id,omitempty"`
+	Floor  int32                  `protobuf:"varint,3,opt,name=floor,proto3" json:"floor,omitempty"`
+	Row    int32                  `protobuf:"varint,4,opt,name=row,proto3" json:"row,omitempty"`
+	Col    int32                  `protobuf:"varint,5,opt,name=col,proto3" json:"col,omitempty"`
+	// type is B, M, A or X for a spot no vehicle fits.
+	Type          string `protobuf:"bytes,6,opt,name=type,proto3" json:"type,omitempty"`
+	Active        bool   `protobuf:"varint,7,opt,name=active,proto3" json:"active,omitempty"`
+	Occupied      bool   `protobuf:"varint,8,opt,name=occupied,proto3" json:"occupied,omitempty"`
+	Reserved      bool   `protobuf:"varint,9,opt,name=reserved,proto3" json:"reserved,omitempty"`
+	EvCharger     bool   `protobuf:"varint,10,opt,name=ev_charger,json=evCharger,proto3" json:"ev_charger,omitempty"`
+	Accessible    bool   `protobuf:"varint,11,opt,name=accessible,proto3" json:"accessible,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Spot) Reset() {
+	*x = Spot{}
+	mi := &file_parking_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Spot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Spot) ProtoMessage() {}
+
+func (x *Spot) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Spot.ProtoReflect.Descriptor instead.
+func (*Spot) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Spot) GetSpotId() string {
+	if x != nil {
+		return x.SpotId
+	}
+	return ""
+}
+
+func (x *Spot) GetLotId() uint32 {
+	if x != nil {
+		return x.LotId
+	}
+	return 0
+}
+
+func (x *Spot) GetFloor() int32 {
+	if x != nil {
+		return x.Floor
+	}
+	return 0
+}
+
+func (x *Spot) GetRow() int32 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+func (x *Spot) GetCol() int32 {
+	if x != nil {
+		return x.Col
+	}
+	return 0
+}
+
+func (x *Spot) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Spot) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *Spot) GetOccupied() bool {
+	if x != nil {
+		return x.Occupied
+	}
+	return false
+}
+
+func (x *Spot) GetReserved() bool {
+	if x != nil {
+		return x.Reserved
+	}
+	return false
+}
+
+func (x *Spot) GetEvCharger() bool {
+	if x != nil {
+		return x.EvCharger
+	}
+	return false
+}
+
+func (x *Spot) GetAccessible() bool {
+	if x != nil {
+		return x.Accessible
+	}
+	return false
+}
+
+type SearchVehicleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LotId         uint32                 `protobuf:"varint,1,opt,name=lot_id,json=lotId,proto3" json:"lot_id,omitempty"`
+	VehicleNumber string                 `protobuf:"bytes,2,opt,name=vehicle_number,json=vehicleNumber,proto3" json:"vehicle_number,omitempty"`
+	TicketId      string                 `protobuf:"bytes,3,opt,name=ticket_id,json=ticketId,proto3" json:"ticket_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchVehicleRequest) Reset() {
+	*x = SearchVehicleRequest{}
+	mi := &file_parking_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchVehicleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchVehicleRequest) ProtoMessage() {}
+
+func (x *SearchVehicleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchVehicleRequest.ProtoReflect.Descriptor instead.
+func (*SearchVehicleRequest) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SearchVehicleRequest) GetLotId() uint32 {
+	if x != nil {
+		return x.LotId
+	}
+	return 0
+}
+
+func (x *SearchVehicleRequest) GetVehicleNumber() string {
+	if x != nil {
+		return x.VehicleNumber
+	}
+	return ""
+}
+
+func (x *SearchVehicleRequest) GetTicketId() string {
+	if x != nil {
+		return x.TicketId
+	}
+	return ""
+}
+
+type SearchVehicleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Vehicle       *Vehicle               `protobuf:"bytes,1,opt,name=vehicle,proto3" json:"vehicle,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchVehicleResponse) Reset() {
+	*x = SearchVehicleResponse{}
+	mi := &file_parking_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchVehicleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchVehicleResponse) ProtoMessage() {}
+
+func (x *SearchVehicleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchVehicleResponse.ProtoReflect.Descriptor instead.
+func (*SearchVehicleResponse) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SearchVehicleResponse) GetVehicle() *Vehicle {
+	if x != nil {
+		return x.Vehicle
+	}
+	return nil
+}
+
+// Vehicle is a parking session.
+type Vehicle struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	LotId           uint32                 `protobuf:"varint,2,opt,name=lot_id,json=lotId,proto3" json:"lot_id,omitempty"`
+	VehicleNumber   string                 `protobuf:"bytes,3,opt,name=vehicle_number,json=vehicleNumber,proto3" json:"vehicle_number,omitempty"`
+	VehicleType     VehicleType            `protobuf:"varint,4,opt,name=vehicle_type,json=vehicleType,proto3,enum=parking.v1.VehicleType" json:"vehicle_type,omitempty"`
+	SpotId          string                 `protobuf:"bytes,5,opt,name=spot_id,json=spotId,proto3" json:"spot_id,omitempty"`
+	TicketId        string                 `protobuf:"bytes,6,opt,name=ticket_id,json=ticketId,proto3" json:"ticket_id,omitempty"`
+	ParkedAt        *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=parked_at,json=parkedAt,proto3" json:"parked_at,omitempty"`
+	UnparkedAt      *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=unparked_at,json=unparkedAt,proto3" json:"unparked_at,omitempty"`
+	Fee             *int64                 `protobuf:"varint,9,opt,name=fee,proto3,oneof" json:"fee,omitempty"`
+	Status          string                 `protobuf:"bytes,10,opt,name=status,proto3" json:"status,omitempty"`
+	ExitRequestedAt *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=exit_requested_at,json=exitRequestedAt,proto3" json:"exit_requested_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Vehicle) Reset() {
+	*x = Vehicle{}
+	mi := &file_parking_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Vehicle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Vehicle) ProtoMessage() {}
+
+func (x *Vehicle) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Vehicle.ProtoReflect.Descriptor instead.
+func (*Vehicle) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Vehicle) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Vehicle) GetLotId() uint32 {
+	if x != nil {
+		return x.LotId
+	}
+	return 0
+}
+
+func (x *Vehicle) GetVehicleNumber() string {
+	if x != nil {
+		return x.VehicleNumber
+	}
+	return ""
+}
+
+func (x *Vehicle) GetVehicleType() VehicleType {
+	if x != nil {
+		return x.VehicleType
+	}
+	return VehicleType_VEHICLE_TYPE_UNSPECIFIED
+}
+
+func (x *Vehicle) GetSpotId() string {
+	if x != nil {
+		return x.SpotId
+	}
+	return ""
+}
+
+func (x *Vehicle) GetTicketId() string {
+	if x != nil {
+		return x.TicketId
+	}
+	return ""
+}
+
+func (x *Vehicle) GetParkedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ParkedAt
+	}
+	return nil
+}
+
+func (x *Vehicle) GetUnparkedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UnparkedAt
+	}
+	return nil
+}
+
+func (x *Vehicle) GetFee() int64 {
+	if x != nil && x.Fee != nil {
+		return *x.Fee
+	}
+	return 0
+}
+
+func (x *Vehicle) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Vehicle) GetExitRequestedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExitRequestedAt
+	}
+	return nil
+}
+
+type WatchOccupancyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LotId         uint32                 `protobuf:"varint,1,opt,name=lot_id,json=lotId,proto3" json:"lot_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchOccupancyRequest) Reset() {
+	*x = WatchOccupancyRequest{}
+	mi := &file_parking_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchOccupancyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchOccupancyRequest) ProtoMessage() {}
+
+func (x *WatchOccupancyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchOccupancyRequest.ProtoReflect.Descriptor instead.
+func (*WatchOccupancyRequest) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *WatchOccupancyRequest) GetLotId() uint32 {
+	if x != nil {
+		return x.LotId
+	}
+	return 0
+}
+
+// SpotCount tallies the spots sharing a floor and type, or the whole lot.
+// Free spots are active, neither occupied nor reserved.
+type SpotCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Floor         int32                  `protobuf:"varint,1,opt,name=floor,proto3" json:"floor,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Total         int32                  `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	Active        int32                  `protobuf:"varint,4,opt,name=active,proto3" json:"active,omitempty"`
+	Occupied      int32                  `protobuf:"varint,5,opt,name=occupied,proto3" json:"occupied,omitempty"`
+	Reserved      int32                  `protobuf:"varint,6,opt,name=reserved,proto3" json:"reserved,omitempty"`
+	Free          int32                  `protobuf:"varint,7,opt,name=free,proto3" json:"free,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpotCount) Reset() {
+	*x = SpotCount{}
+	mi := &file_parking_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpotCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpotCount) ProtoMessage() {}
+
+func (x *SpotCount) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpotCount.ProtoReflect.Descriptor instead.
+func (*SpotCount) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SpotCount) GetFloor() int32 {
+	if x != nil {
+		return x.Floor
+	}
+	return 0
+}
+
+func (x *SpotCount) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *SpotCount) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *SpotCount) GetActive() int32 {
+	if x != nil {
+		return x.Active
+	}
+	return 0
+}
+
+func (x *SpotCount) GetOccupied() int32 {
+	if x != nil {
+		return x.Occupied
+	}
+	return 0
+}
+
+func (x *SpotCount) GetReserved() int32 {
+	if x != nil {
+		return x.Reserved
+	}
+	return 0
+}
+
+func (x *SpotCount) GetFree() int32 {
+	if x != nil {
+		return x.Free
+	}
+	return 0
+}
+
+type Occupancy struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LotId         uint32                 `protobuf:"varint,1,opt,name=lot_id,json=lotId,proto3" json:"lot_id,omitempty"`
+	Total         *SpotCount             `protobuf:"bytes,2,opt,name=total,proto3" json:"total,omitempty"`
+	Floors        []*SpotCount           `protobuf:"bytes,3,rep,name=floors,proto3" json:"floors,omitempty"`
+	Types         []*SpotCount           `protobuf:"bytes,4,rep,name=types,proto3" json:"types,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Occupancy) Reset() {
+	*x = Occupancy{}
+	mi := &file_parking_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Occupancy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Occupancy) ProtoMessage() {}
+
+func (x *Occupancy) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Occupancy.ProtoReflect.Descriptor instead.
+func (*Occupancy) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Occupancy) GetLotId() uint32 {
+	if x != nil {
+		return x.LotId
+	}
+	return 0
+}
+
+func (x *Occupancy) GetTotal() *SpotCount {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *Occupancy) GetFloors() []*SpotCount {
+	if x != nil {
+		return x.Floors
+	}
+	return nil
+}
+
+func (x *Occupancy) GetTypes() []*SpotCount {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+// SpotChange is a spot changing state, old is unset for a new spot and new
+// for a removed one.
+type SpotChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LotId         uint32                 `protobuf:"varint,1,opt,name=lot_id,json=lotId,proto3" json:"lot_id,omitempty"`
+	Old           *Spot                  `protobuf:"bytes,2,opt,name=old,proto3" json:"old,omitempty"`
+	New           *Spot                  `protobuf:"bytes,3,opt,name=new,proto3" json:"new,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpotChange) Reset() {
+	*x = SpotChange{}
+	mi := &file_parking_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpotChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpotChange) ProtoMessage() {}
+
+func (x *SpotChange) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpotChange.ProtoReflect.Descriptor instead.
+func (*SpotChange) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SpotChange) GetLotId() uint32 {
+	if x != nil {
+		return x.LotId
+	}
+	return 0
+}
+
+func (x *SpotChange) GetOld() *Spot {
+	if x != nil {
+		return x.Old
+	}
+	return nil
+}
+
+func (x *SpotChange) GetNew() *Spot {
+	if x != nil {
+		return x.New
+	}
+	return nil
+}
+
+type OccupancyEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*OccupancyEvent_Snapshot
+	//	*OccupancyEvent_Change
+	Event isOccupancyEvent_Event `protobuf_oneof:"event"`
+	// delta is what a change adds to the counts of its floor and type.
+	Delta         []*SpotCount `protobuf:"bytes,3,rep,name=delta,proto3" json:"delta,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OccupancyEvent) Reset() {
+	*x = OccupancyEvent{}
+	mi := &file_parking_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OccupancyEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OccupancyEvent) ProtoMessage() {}
+
+func (x *OccupancyEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OccupancyEvent.ProtoReflect.Descriptor instead.
+func (*OccupancyEvent) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *OccupancyEvent) GetEvent() isOccupancyEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *OccupancyEvent) GetSnapshot() *Occupancy {
+	if x != nil {
+		if x, ok := x.Event.(*OccupancyEvent_Snapshot); ok {
+			return x.Snapshot
+		}
+	}
+	return nil
+}
+
+func (x *OccupancyEvent) GetChange() *SpotChange {
+	if x != nil {
+		if x, ok := x.Event.(*OccupancyEvent_Change); ok {
+			return x.Change
+		}
+	}
+	return nil
+}
+
+func (x *OccupancyEvent) GetDelta() []*SpotCount {
+	if x != nil {
+		return x.Delta
+	}
+	return nil
+}
+
+type isOccupancyEvent_Event interface {
+	isOccupancyEvent_Event()
+}
+
+type OccupancyEvent_Snapshot struct {
+	Snapshot *Occupancy `protobuf:"bytes,1,opt,name=snapshot,proto3,oneof"`
+}
+
+type OccupancyEvent_Change struct {
+	Change *SpotChange `protobuf:"bytes,2,opt,name=change,proto3,oneof"`
+}
+
+func (*OccupancyEvent_Snapshot) isOccupancyEvent_Event() {}
+
+func (*OccupancyEvent_Change) isOccupancyEvent_Event() {}
+
+var File_parking_proto protoreflect.FileDescriptor
+
+const file_parking_proto_rawDesc = "" +
+	"\n" +
+	"\rparking.proto\x12\n" +
+	"parking.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xd7\x01\n" +
+	"\vParkRequest\x12\x15\n" +
+	"\x06lot_id\x18\x01 \x01(\rR\x05lotId\x12:\n" +
+	"\fvehicle_type\x18\x02 \x01(\x0e2\x17.parking.v1.VehicleTypeR\vvehicleType\x12%\n" +
+	"\x0evehicle_number\x18\x03 \x01(\tR\rvehicleNumber\x12)\n" +
+	"\x10reservation_code\x18\x04 \x01(\tR\x0freservationCode\x12#\n" +
+	"\rwaitlist_code\x18\x05 \x01(\tR\fwaitlistCode\":\n" +
+	"\fParkResponse\x12*\n" +
+	"\x06ticket\x18\x01 \x01(\v2\x12.parking.v1.TicketR\x06ticket\"\xc8\x02\n" +
+	"\x06Ticket\x12\x1b\n" +
+	"\tticket_id\x18\x01 \x01(\tR\bticketId\x12\x15\n" +
+	"\x06lot_id\x18\x02 \x01(\rR\x05lotId\x12\x17\n" +
+	"\aspot_id\x18\x03 \x01(\tR\x06spotId\x12\x14\n" +
+	"\x05floor\x18\x04 \x01(\x05R\x05floor\x12\x10\n" +
+	"\x03row\x18\x05 \x01(\x05R\x03row\x12\x10\n" +
+	"\x03col\x18\x06 \x01(\x05R\x03col\x12:\n" +
+	"\fvehicle_type\x18\a \x01(\x0e2\x17.parking.v1.VehicleTypeR\vvehicleType\x12\x1b\n" +
+	"\tspot_type\x18\b \x01(\tR\bspotType\x12%\n" +
+	"\x0evehicle_number\x18\t \x01(\tR\rvehicleNumber\x127\n" +
+	"\tparked_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\bparkedAt\"\x83\x01\n" +
+	"\rUnparkRequest\x12\x15\n" +
+	"\x06lot_id\x18\x01 \x01(\rR\x05lotId\x12\x1b\n" +
+	"\tticket_id\x18\x02 \x01(\tR\bticketId\x12\x17\n" +
+	"\aspot_id\x18\x03 \x01(\tR\x06spotId\x12%\n" +
+	"\x0evehicle_number\x18\x04 \x01(\tR\rvehicleNumber\"?\n" +
+	"\x0eUnparkResponse\x12-\n" +
+	"\areceipt\x18\x01 \x01(\v2\x13.parking.v1.ReceiptR\areceipt\"\x99\x03\n" +
+	"\aReceipt\x12\x1b\n" +
+	"\tticket_id\x18\x01 \x01(\tR\bticketId\x12\x15\n" +
+	"\x06lot_id\x18\x02 \x01(\rR\x05lotId\x12%\n" +
+	"\x0evehicle_number\x18\x03 \x01(\tR\rvehicleNumber\x12:\n" +
+	"\fvehicle_type\x18\x04 \x01(\x0e2\x17.parking.v1.VehicleTypeR\vvehicleType\x12\x17\n" +
+	"\aspot_id\x18\x05 \x01(\tR\x06spotId\x127\n" +
+	"\tparked_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\bparkedAt\x12;\n" +
+	"\vunparked_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"unparkedAt\x12\x10\n" +
+	"\x03fee\x18\b \x01(\x03R\x03fee\x12\x1f\n" +
+	"\vamount_paid\x18\t \x01(\x03R\n" +
+	"amountPaid\x12\x1d\n" +
+	"\n" +
+	"amount_due\x18\n" +
+	" \x01(\x03R\tamountDue\x12\x16\n" +
+	"\x06status\x18\v \x01(\tR\x06status\"i\n" +
+	"\x14AvailableSpotRequest\x12\x15\n" +
+	"\x06lot_id\x18\x01 \x01(\rR\x05lotId\x12:\n" +
+	"\fvehicle_type\x18\x02 \x01(\x0e2\x17.parking.v1.VehicleTypeR\vvehicleType\"?\n" +
+	"\x15AvailableSpotResponse\x12&\n" +
+	"\x05spots\x18\x01 \x03(\v2\x10.parking.v1.SpotR\x05spots\"\x93\x02\n" +
+	"\x04Spot\x12\x17\n" +
+	"\aspot_id\x18\x01 \x01(\tR\x06spotId\x12\x15\n" +
+	"\x06lot_id\x18\x02 \x01(\rR\x05lotId\x12\x14\n" +
+	"\x05floor\x18\x03 \x01(\x05R\x05floor\x12\x10\n" +
+	"\x03row\x18\x04 \x01(\x05R\x03row\x12\x10\n" +
+	"\x03col\x18\x05 \x01(\x05R\x03col\x12\x12\n" +
+	"\x04type\x18\x06 \x01(\tR\x04type\x12\x16\n" +
+	"\x06active\x18\a \x01(\bR\x06active\x12\x1a\n" +
+	"\boccupied\x18\b \x01(\bR\boccupied\x12\x1a\n" +
+	"\breserved\x18\t \x01(\bR\breserved\x12\x1d\n" +
+	"\n" +
+	"ev_charger\x18\n" +
+	" \x01(\bR\tevCharger\x12\x1e\n" +
+	"\n" +
+	"accessible\x18\v \x01(\bR\n" +
+	"accessible\"q\n" +
+	"\x14SearchVehicleRequest\x12\x15\n" +
+	"\x06lot_id\x18\x01 \x01(\rR\x05lotId\x12%\n" +
+	"\x0evehicle_number\x18\x02 \x01(\tR\rvehicleNumber\x12\x1b\n" +
+	"\tticket_id\x18\x03 \x01(\tR\bticketId\"F\n" +
+	"\x15SearchVehicleResponse\x12-\n" +
+	"\avehicle\x18\x01 \x01(\v2\x13.parking.v1.VehicleR\avehicle\"\xbe\x03\n" +
+	"\aVehicle\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x15\n" +
+	"\x06lot_id\x18\x02 \x01(\rR\x05lotId\x12%\n" +
+	"\x0evehicle_number\x18\x03 \x01(\tR\rvehicleNumber\x12:\n" +
+	"\fvehicle_type\x18\x04 \x01(\x0e2\x17.parking.v1.VehicleTypeR\vvehicleType\x12\x17\n" +
+	"\aspot_id\x18\x05 \x01(\tR\x06spotId\x12\x1b\n" +
+	"\tticket_id\x18\x06 \x01(\tR\bticketId\x127\n" +
+	"\tparked_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\bparkedAt\x12;\n" +
+	"\vunparked_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"unparkedAt\x12\x15\n" +
+	"\x03fee\x18\t \x01(\x03H\x00R\x03fee\x88\x01\x01\x12\x16\n" +
+	"\x06status\x18\n" +
+	" \x01(\tR\x06status\x12F\n" +
+	"\x11exit_requested_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\x0fexitRequestedAtB\x06\n" +
+	"\x04_fee\".\n" +
+	"\x15WatchOccupancyRequest\x12\x15\n" +
+	"\x06lot_id\x18\x01 \x01(\rR\x05lotId\"\xaf\x01\n" +
+	"\tSpotCount\x12\x14\n" +
+	"\x05floor\x18\x01 \x01(\x05R\x05floor\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x05R\x05total\x12\x16\n" +
+	"\x06active\x18\x04 \x01(\x05R\x06active\x12\x1a\n" +
+	"\boccupied\x18\x05 \x01(\x05R\boccupied\x12\x1a\n" +
+	"\breserved\x18\x06 \x01(\x05R\breserved\x12\x12\n" +
+	"\x04free\x18\a \x01(\x05R\x04free\"\xab\x01\n" +
+	"\tOccupancy\x12\x15\n" +
+	"\x06lot_id\x18\x01 \x01(\rR\x05lotId\x12+\n" +
+	"\x05total\x18\x02 \x01(\v2\x15.parking.v1.SpotCountR\x05total\x12-\n" +
+	"\x06floors\x18\x03 \x03(\v2\x15.parking.v1.SpotCountR\x06floors\x12+\n" +
+	"\x05types\x18\x04 \x03(\v2\x15.parking.v1.SpotCountR\x05types\"k\n" +
+	"\n" +
+	"SpotChange\x12\x15\n" +
+	"\x06lot_id\x18\x01 \x01(\rR\x05lotId\x12\"\n" +
+	"\x03old\x18\x02 \x01(\v2\x10.parking.v1.SpotR\x03old\x12\"\n" +
+	"\x03new\x18\x03 \x01(\v2\x10.parking.v1.SpotR\x03new\"\xad\x01\n" +
+	"\x0eOccupancyEvent\x123\n" +
+	"\bsnapshot\x18\x01 \x01(\v2\x15.parking.v1.OccupancyH\x00R\bsnapshot\x120\n" +
+	"\x06change\x18\x02 \x01(\v2\x16.parking.v1.SpotChangeH\x00R\x06change\x12+\n" +
+	"\x05delta\x18\x03 \x03(\v2\x15.parking.v1.SpotCountR\x05deltaB\a\n" +
+	"\x05event*\x7f\n" +
+	"\vVehicleType\x12\x1c\n" +
+	"\x18VEHICLE_TYPE_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14VEHICLE_TYPE_BICYCLE\x10\x01\x12\x1b\n" +
+	"\x17VEHICLE_TYPE_MOTORCYCLE\x10\x02\x12\x1b\n" +
+	"\x17VEHICLE_TYPE_AUTOMOBILE\x10\x032\x8b\x03\n" +
+	"\x0eParkingService\x129\n" +
+	"\x04Park\x12\x17.parking.v1.ParkRequest\x1a\x18.parking.v1.ParkResponse\x12?\n" +
+	"\x06Unpark\x12\x19.parking.v1.UnparkRequest\x1a\x1a.parking.v1.UnparkResponse\x12T\n" +
+	"\rAvailableSpot\x12 .parking.v1.AvailableSpotRequest\x1a!.parking.v1.AvailableSpotResponse\x12T\n" +
+	"\rSearchVehicle\x12 .parking.v1.SearchVehicleRequest\x1a!.parking.v1.SearchVehicleResponse\x12Q\n" +
+	"\x0eWatchOccupancy\x12!.parking.v1.WatchOccupancyRequest\x1a\x1a.parking.v1.OccupancyEvent0\x01BAZ?github.com/zuhrulumam/go-parking-lot/proto/parking/v1;parkingv1b\x06proto3"
+
+var (
+	file_parking_proto_rawDescOnce sync.Once
+	file_parking_proto_rawDescData []byte
+)
+
+func file_parking_proto_rawDescGZIP() []byte {
+	file_parking_proto_rawDescOnce.Do(func() {
+		file_parking_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_parking_proto_rawDesc), len(file_parking_proto_rawDesc)))
+	})
+	return file_parking_proto_rawDescData
+}
+
+var file_parking_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_parking_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_parking_proto_goTypes = []any{
+	(VehicleType)(0),              // 0: parking.v1.VehicleType
+	(*ParkRequest)(nil),           // 1: parking.v1.ParkRequest
+	(*ParkResponse)(nil),          // 2: parking.v1.ParkResponse
+	(*Ticket)(nil),                // 3: parking.v1.Ticket
+	(*UnparkRequest)(nil),         // 4: parking.v1.UnparkRequest
+	(*UnparkResponse)(nil),        // 5: parking.v1.UnparkResponse
+	(*Receipt)(nil),               // 6: parking.v1.Receipt
+	(*AvailableSpotRequest)(nil),  // 7: parking.v1.AvailableSpotRequest
+	(*AvailableSpotResponse)(nil), // 8: parking.v1.AvailableSpotResponse
+	(*Spot)(nil),                  // 9: parking.v1.Spot
+	(*SearchVehicleRequest)(nil),  // 10: parking.v1.SearchVehicleRequest
+	(*SearchVehicleResponse)(nil), // 11: parking.v1.SearchVehicleResponse
+	(*Vehicle)(nil),               // 12: parking.v1.Vehicle
+	(*WatchOccupancyRequest)(nil), // 13: parking.v1.WatchOccupancyRequest
+	(*SpotCount)(nil),             // 14: parking.v1.SpotCount
+	(*Occupancy)(nil),             // 15: parking.v1.Occupancy
+	(*SpotChange)(nil),            // 16: parking.v1.SpotChange
+	(*OccupancyEvent)(nil),        // 17: parking.v1.OccupancyEvent
+	(*timestamppb.Timestamp)(nil), // 18: google.protobuf.Timestamp
+}
+var file_parking_proto_depIdxs = []int32{
+	0,  // 0: parking.v1.ParkRequest.vehicle_type:type_name -> parking.v1.VehicleType
+	3,  // 1: parking.v1.ParkResponse.ticket:type_name -> parking.v1.Ticket
+	0,  // 2: parking.v1.Ticket.vehicle_type:type_name -> parking.v1.VehicleType
+	18, // 3: parking.v1.Ticket.parked_at:type_name -> google.protobuf.Timestamp
+	6,  // 4: parking.v1.UnparkResponse.receipt:type_name -> parking.v1.Receipt
+	0,  // 5: parking.v1.Receipt.vehicle_type:type_name -> parking.v1.VehicleType
+	18, // 6: parking.v1.Receipt.parked_at:type_name -> google.protobuf.Timestamp
+	18, // 7: parking.v1.Receipt.unparked_at:type_name -> google.protobuf.Timestamp
+	0,  // 8: parking.v1.AvailableSpotRequest.vehicle_type:type_name -> parking.v1.VehicleType
+	9,  // 9: parking.v1.AvailableSpotResponse.spots:type_name -> parking.v1.Spot
+	12, // 10: parking.v1.SearchVehicleResponse.vehicle:type_name -> parking.v1.Vehicle
+	0,  // 11: parking.v1.Vehicle.vehicle_type:type_name -> parking.v1.VehicleType
+	18, // 12: parking.v1.Vehicle.parked_at:type_name -> google.protobuf.Timestamp
+	18, // 13: parking.v1.Vehicle.unparked_at:type_name -> google.protobuf.Timestamp
+	18, // 14: parking.v1.Vehicle.exit_requested_at:type_name -> google.protobuf.Timestamp
+	14, // 15: parking.v1.Occupancy.total:type_name -> parking.v1.SpotCount
+	14, // 16: parking.v1.Occupancy.floors:type_name -> parking.v1.SpotCount
+	14, // 17: parking.v1.Occupancy.types:type_name -> parking.v1.SpotCount
+	9,  // 18: parking.v1.SpotChange.old:type_name -> parking.v1.Spot
+	9,  // 19: parking.v1.SpotChange.new:type_name -> parking.v1.Spot
+	15, // 20: parking.v1.OccupancyEvent.snapshot:type_name -> parking.v1.Occupancy
+	16, // 21: parking.v1.OccupancyEvent.change:type_name -> parking.v1.SpotChange
+	14, // 22: parking.v1.OccupancyEvent.delta:type_name -> parking.v1.SpotCount
+	1,  // 23: parking.v1.ParkingService.Park:input_type -> parking.v1.ParkRequest
+	4,  // 24: parking.v1.ParkingService.Unpark:input_type -> parking.v1.UnparkRequest
+	7,  // 25: parking.v1.ParkingService.AvailableSpot:input_type -> parking.v1.AvailableSpotRequest
+	10, // 26: parking.v1.ParkingService.SearchVehicle:input_type -> parking.v1.SearchVehicleRequest
+	13, // 27: parking.v1.ParkingService.WatchOccupancy:input_type -> parking.v1.WatchOccupancyRequest
+	2,  // 28: parking.v1.ParkingService.Park:output_type -> parking.v1.ParkResponse
+	5,  // 29: parking.v1.ParkingService.Unpark:output_type -> parking.v1.UnparkResponse
+	8,  // 30: parking.v1.ParkingService.AvailableSpot:output_type -> parking.v1.AvailableSpotResponse
+	11, // 31: parking.v1.ParkingService.SearchVehicle:output_type -> parking.v1.SearchVehicleResponse
+	17, // 32: parking.v1.ParkingService.WatchOccupancy:output_type -> parking.v1.OccupancyEvent
+	28, // [28:33] is the sub-list for method output_type
+	23, // [23:28] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
+}
+
+func init() { file_parking_proto_init() }
+func file_parking_proto_init() {
+	if File_parking_proto != nil {
+		return
+	}
+	file_parking_proto_msgTypes[11].OneofWrappers = []any{}
+	file_parking_proto_msgTypes[16].OneofWrappers = []any{
+		(*OccupancyEvent_Snapshot)(nil),
+		(*OccupancyEvent_Change)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_parking_proto_rawDesc), len(file_parking_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_parking_proto_goTypes,
+		DependencyIndexes: file_parking_proto_depIdxs,
+		EnumInfos:         file_parking_proto_enumTypes,
+		MessageInfos:      file_parking_proto_msgTypes,
+	}.Build()
+	File_parking_proto = out.File
+	file_parking_proto_goTypes = nil
+	file_parking_proto_depIdxs = nil
+}
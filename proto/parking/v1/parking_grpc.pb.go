@@ -0,0 +1,305 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: parking.proto
+
+package parkingv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ParkingService_Park_FullMethodName           = "/parking.v1.ParkingService/Park"
+	ParkingService_Unpark_FullMethodName         = "/parking.v1.ParkingService/Unpark"
+	ParkingService_AvailableSpot_FullMethodName  = "/parking.v1.ParkingService/AvailableSpot"
+	ParkingService_SearchVehicle_FullMethodName  = "/parking.v1.ParkingService/SearchVehicle"
+	ParkingService_WatchOccupancy_FullMethodName = "/parking.v1.ParkingService/WatchOccupancy"
+)
+
+// ParkingServiceClient is the client API for ParkingService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ParkingService is the parking API for gate controllers, the typed twin of
+// the REST routes under /lots/{lot_id}. Errors carry the gRPC status of the
+// REST status: 400 and 422 INVALID_ARGUMENT, 401 UNAUTHENTICATED, 402 and
+// 409 FAILED_PRECONDITION, 404 NOT_FOUND, others INTERNAL. A full lot is
+// RESOURCE_EXHAUSTED.
+type ParkingServiceClient interface {
+	// Park assigns a free spot to the vehicle and returns its ticket.
+	Park(ctx context.Context, in *ParkRequest, opts ...grpc.CallOption) (*ParkResponse, error)
+	// Unpark requests the exit of a vehicle and quotes its fee. The spot is
+	// freed once the fee is paid, right away when nothing is due.
+	Unpark(ctx context.Context, in *UnparkRequest, opts ...grpc.CallOption) (*UnparkResponse, error)
+	// AvailableSpot lists the free spots a vehicle type would be parked in.
+	AvailableSpot(ctx context.Context, in *AvailableSpotRequest, opts ...grpc.CallOption) (*AvailableSpotResponse, error)
+	// SearchVehicle finds the session of a vehicle by plate or ticket.
+	SearchVehicle(ctx context.Context, in *SearchVehicleRequest, opts ...grpc.CallOption) (*SearchVehicleResponse, error)
+	// WatchOccupancy streams a snapshot of the occupancy of the lot, then the
+	// changes. A fresh snapshot is sent periodically and whenever changes may
+	// have been missed, clients replace their counts with it.
+	WatchOccupancy(ctx context.Context, in *WatchOccupancyRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OccupancyEvent], error)
+}
+
+type parkingServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewParkingServiceClient(cc grpc.ClientConnInterface) ParkingServiceClient {
+	return &parkingServiceClient{cc}
+}
+
+func (c *parkingServiceClient) Park(ctx context.Context, in *ParkRequest, opts ...grpc.CallOption) (*ParkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ParkResponse)
+	err := c.cc.Invoke(ctx, ParkingService_Park_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *parkingServiceClient) Unpark(ctx context.Context, in *UnparkRequest, opts ...grpc.CallOption) (*UnparkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnparkResponse)
+	err := c.cc.Invoke(ctx, ParkingService_Unpark_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *parkingServiceClient) AvailableSpot(ctx context.Context, in *AvailableSpotRequest, opts ...grpc.CallOption) (*AvailableSpotResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AvailableSpotResponse)
+	err := c.cc.Invoke(ctx, ParkingService_AvailableSpot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *parkingServiceClient) SearchVehicle(ctx context.Context, in *SearchVehicleRequest, opts ...grpc.CallOption) (*SearchVehicleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchVehicleResponse)
+	err := c.cc.Invoke(ctx, ParkingService_SearchVehicle_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *parkingServiceClient) WatchOccupancy(ctx context.Context, in *WatchOccupancyRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OccupancyEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ParkingService_ServiceDesc.Streams[0], ParkingService_WatchOccupancy_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchOccupancyRequest, OccupancyEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ParkingService_WatchOccupancyClient = grpc.ServerStreamingClient[OccupancyEvent]
+
+// ParkingServiceServer is the server API for ParkingService service.
+// All implementations must embed UnimplementedParkingServiceServer
+// for forward compatibility.
+//
+// ParkingService is the parking API for gate controllers, the typed twin of
+// the REST routes under /lots/{lot_id}. Errors carry the gRPC status of the
+// REST status: 400 and 422 INVALID_ARGUMENT, 401 UNAUTHENTICATED, 402 and
+// 409 FAILED_PRECONDITION, 404 NOT_FOUND, others INTERNAL. A full lot is
+// RESOURCE_EXHAUSTED.
+type ParkingServiceServer interface {
+	// Park assigns a free spot to the vehicle and returns its ticket.
+	Park(context.Context, *ParkRequest) (*ParkResponse, error)
+	// Unpark requests the exit of a vehicle and quotes its fee. The spot is
+	// freed once the fee is paid, right away when nothing is due.
+	Unpark(context.Context, *UnparkRequest) (*UnparkResponse, error)
+	// AvailableSpot lists the free spots a vehicle type would be parked in.
+	AvailableSpot(context.Context, *AvailableSpotRequest) (*AvailableSpotResponse, error)
+	// SearchVehicle finds the session of a vehicle by plate or ticket.
+	SearchVehicle(context.Context, *SearchVehicleRequest) (*SearchVehicleResponse, error)
+	// WatchOccupancy streams a snapshot of the occupancy of the lot, then the
+	// changes. A fresh snapshot is sent periodically and whenever changes may
+	// have been missed, clients replace their counts with it.
+	WatchOccupancy(*WatchOccupancyRequest, grpc.ServerStreamingServer[OccupancyEvent]) error
+	mustEmbedUnimplementedParkingServiceServer()
+}
+
+// UnimplementedParkingServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedParkingServiceServer struct{}
+
+func (UnimplementedParkingServiceServer) Park(context.Context, *ParkRequest) (*ParkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Park not implemented")
+}
+func (UnimplementedParkingServiceServer) Unpark(context.Context, *UnparkRequest) (*UnparkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unpark not implemented")
+}
+func (UnimplementedParkingServiceServer) AvailableSpot(context.Context, *AvailableSpotRequest) (*AvailableSpotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AvailableSpot not implemented")
+}
+func (UnimplementedParkingServiceServer) SearchVehicle(context.Context, *SearchVehicleRequest) (*SearchVehicleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchVehicle not implemented")
+}
+func (UnimplementedParkingServiceServer) WatchOccupancy(*WatchOccupancyRequest, grpc.ServerStreamingServer[OccupancyEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchOccupancy not implemented")
+}
+func (UnimplementedParkingServiceServer) mustEmbedUnimplementedParkingServiceServer() {}
+func (UnimplementedParkingServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeParkingServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ParkingServiceServer will
+// result in compilation errors.
+type UnsafeParkingServiceServer interface {
+	mustEmbedUnimplementedParkingServiceServer()
+}
+
+func RegisterParkingServiceServer(s grpc.ServiceRegistrar, srv ParkingServiceServer) {
+	// If the following call pancis, it indicates UnimplementedParkingServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ParkingService_ServiceDesc, srv)
+}
+
+func _ParkingService_Park_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ParkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ParkingServiceServer).Park(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ParkingService_Park_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ParkingServiceServer).Park(ctx, req.(*ParkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ParkingService_Unpark_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnparkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ParkingServiceServer).Unpark(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ParkingService_Unpark_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ParkingServiceServer).Unpark(ctx, req.(*UnparkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ParkingService_AvailableSpot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AvailableSpotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ParkingServiceServer).AvailableSpot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ParkingService_AvailableSpot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ParkingServiceServer).AvailableSpot(ctx, req.(*AvailableSpotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ParkingService_SearchVehicle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchVehicleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ParkingServiceServer).SearchVehicle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ParkingService_SearchVehicle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ParkingServiceServer).SearchVehicle(ctx, req.(*SearchVehicleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ParkingService_WatchOccupancy_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchOccupancyRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ParkingServiceServer).WatchOccupancy(m, &grpc.GenericServerStream[WatchOccupancyRequest, OccupancyEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ParkingService_WatchOccupancyServer = grpc.ServerStreamingServer[OccupancyEvent]
+
+// ParkingService_ServiceDesc is the grpc.ServiceDesc for ParkingService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ParkingService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "parking.v1.ParkingService",
+	HandlerType: (*ParkingServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Park",
+			Handler:    _ParkingService_Park_Handler,
+		},
+		{
+			MethodName: "Unpark",
+			Handler:    _ParkingService_Unpark_Handler,
+		},
+		{
+			MethodName: "AvailableSpot",
+			Handler:    _ParkingService_AvailableSpot_Handler,
+		},
+		{
+			MethodName: "SearchVehicle",
+			Handler:    _ParkingService_SearchVehicle_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchOccupancy",
+			Handler:       _ParkingService_WatchOccupancy_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "parking.proto",
+}
@@ -14,6 +14,9 @@ export let options = {
   ],
 };
 
+// a gate key of `apikey create --role gate`: k6 run -e API_KEY=pk_... test.js
+const apiKey = __ENV.API_KEY;
+
 function getRandomVehicleNumber() {
   const chars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ";
   const nums = Math.floor(Math.random() * 9000 + 1000); // random 4-digit
@@ -33,11 +36,14 @@ function parkVehicle() {
     vehicle_number: vehicleNumber,
   });
 
-  const headers = { "Content-Type": "application/json" };
-  // const res = http.post("http://parking.localhost/vehicle/park", payload, {
+  const headers = {
+    "Content-Type": "application/json",
+    Authorization: `Bearer ${apiKey}`,
+  };
+  // const res = http.post("http://parking.localhost/lots/1/vehicle/park", payload, {
   //   headers,
   // });
-  const res = http.post("http://localhost:8080/vehicle/park", payload, {
+  const res = http.post("http://localhost:8080/lots/1/vehicle/park", payload, {
     headers,
   });
 
@@ -65,11 +71,14 @@ function unparkVehicle() {
 
   const vehicleNumber = parkedVehicles.shift(); // remove first parked
   const payload = JSON.stringify({ vehicle_number: vehicleNumber });
-  const headers = { "Content-Type": "application/json" };
-  // const res = http.post("http://parking.localhost/vehicle/unpark", payload, {
+  const headers = {
+    "Content-Type": "application/json",
+    Authorization: `Bearer ${apiKey}`,
+  };
+  // const res = http.post("http://parking.localhost/lots/1/vehicle/unpark", payload, {
   //   headers,
   // });
-  const res = http.post("http://localhost:8080/vehicle/unpark", payload, {
+  const res = http.post("http://localhost:8080/lots/1/vehicle/unpark", payload, {
     headers,
   });
 